@@ -1,22 +1,37 @@
 package main
 
 import (
+	"context"
+
 	dbgen "money-buddy-backend/db/generated"
 	"money-buddy-backend/infra/repository"
+	"money-buddy-backend/infra/storage"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/config"
 	"money-buddy-backend/internal/db"
 	"money-buddy-backend/internal/handlers"
+	"money-buddy-backend/internal/jobs"
+	"money-buddy-backend/internal/repositories"
 	"money-buddy-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		panic(err)
+	}
+
 	r := gin.Default()
 
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, Idempotency-Key")
+		// ブラウザから ETag を読めるようにする（If-Match で送り返す）
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -31,23 +46,175 @@ func main() {
 		panic(err)
 	}
 
+	// 利用者の認証方式は設定で選ぶ。どちらの場合も以降の処理にはユーザー ID だけが渡る
+	var verifier auth.TokenVerifier
+	var localTokens *auth.LocalTokens
+	switch cfg.AuthProvider {
+	case config.AuthProviderLocal:
+		localTokens = auth.NewLocalTokens(cfg.LocalAuthSigningKey, cfg.LocalAuthAccessTokenTTL)
+		verifier = localTokens
+	default:
+		var keys auth.KeySource
+		if cfg.FirebaseJWKSFile != "" {
+			keys = auth.NewJWKSFileSource(cfg.FirebaseJWKSFile, 0)
+		} else {
+			jwksURL := cfg.FirebaseJWKSURL
+			if jwksURL == "" {
+				jwksURL = auth.GoogleSecureTokenJWKSURL
+			}
+			keys = auth.NewJWKSURLSource(jwksURL, nil)
+		}
+		verifier = auth.NewFirebaseVerifier(cfg.FirebaseProjectID, keys)
+	}
+
+	// ユーザーを横断する処理（運用者 API・未認証のローカル認証など）は行レベルセキュリティの対象外で実行する
+	systemIdentity := func(c *gin.Context) {
+		c.Request = c.Request.WithContext(transaction.WithSystemIdentity(c.Request.Context()))
+		c.Next()
+	}
+
 	queries := dbgen.New(dbConn)
+	txManager := db.NewSQLTxManager(dbConn)
+	tokenRepo := repository.NewPersonalAccessTokenRepositorySQLC(queries)
+	tokenService := services.NewPersonalAccessTokenService(tokenRepo)
+	userRepo := repository.NewUserRepositorySQLC(queries)
+	ledgerRepo := repository.NewLedgerRepositorySQLC(queries)
+	userService := services.NewUserService(userRepo, ledgerRepo)
+	idempotencyService := services.NewIdempotencyService(repository.NewIdempotencyKeyRepositorySQLC(queries), txManager, cfg.IdempotencyKeyTTL)
+	// 認証済みのリクエストは呼び出し元の権限（行レベルセキュリティ）で 1 つのトランザクションとして処理する。
+	// 初回アクセスのユーザーはここで作成し、運用者が利用を停止したアカウントは拒否する。Idempotency-Key 付きの POST は再送されても 1 回だけ処理する
+	authed := r.Group("",
+		handlers.AuthMiddleware(verifier, tokenService),
+		handlers.RequestTransaction(txManager),
+		handlers.ProvisionUser(userService),
+		handlers.RejectDisabledAccounts(userService),
+		handlers.Idempotency(idempotencyService, handlers.AttachmentRequestMaxBytes(cfg.AttachmentMaxBytes)),
+	)
+	// 期限切れのキーの削除はユーザーを横断するため、行レベルセキュリティの対象外で実行する
+	go jobs.RunPeriodically(transaction.WithSystemIdentity(context.Background()), "purge-idempotency-keys", cfg.IdempotencyPurgeInterval, func(ctx context.Context) error {
+		_, err := idempotencyService.PurgeExpired(ctx)
+		return err
+	})
+	handlers.NewPersonalAccessTokenHandler(authed, tokenService)
+
+	if localTokens != nil {
+		localAuthService := services.NewLocalAuthService(
+			repository.NewLocalAccountRepositorySQLC(queries),
+			repository.NewRefreshTokenRepositorySQLC(queries),
+			userRepo,
+			localTokens,
+			txManager,
+			cfg.LocalAuthRefreshTokenTTL,
+		)
+		// サインアップ・ログインの時点では呼び出し元が未確定のため、サービスがトランザクションを管理する
+		handlers.NewLocalAuthHandler(r.Group("", systemIdentity), authed, localAuthService)
+	}
+
+	ledgerService := services.NewLedgerService(ledgerRepo, txManager, cfg.LedgerInviteTTL)
+	handlers.NewLedgerHandler(authed, ledgerService)
+
+	auditRepo := repository.NewAuditRepositorySQLC(queries)
+	handlers.NewAuditHandler(authed, services.NewAuditService(auditRepo))
+
 	repo := repository.NewExpenseRepositorySQLC(queries)
 	categoryRepo := repository.NewCategoryRepositorySQLC(queries)
-	service := services.NewExpenseService(repo, categoryRepo)
-	handlers.NewExpenseHandler(r, service)
+	tagRepo := repository.NewTagRepositorySQLC(queries)
+	payeeRepo := repository.NewPayeeRepositorySQLC(queries)
+	paymentAccountRepo := repository.NewPaymentAccountRepositorySQLC(queries)
+	service := services.NewExpenseService(repo, categoryRepo, tagRepo, payeeRepo, paymentAccountRepo, ledgerRepo, auditRepo, txManager)
+	handlers.NewExpenseHandler(authed, service)
+	handlers.NewTagHandler(authed, services.NewTagService(tagRepo, ledgerRepo, txManager))
+	handlers.NewPayeeHandler(authed, services.NewPayeeService(payeeRepo, ledgerRepo, txManager))
+	handlers.NewPaymentAccountHandler(authed, services.NewPaymentAccountService(paymentAccountRepo, ledgerRepo, txManager))
+	incomeRepo := repository.NewIncomeRepositorySQLC(queries)
+	handlers.NewIncomeHandler(authed, services.NewIncomeService(incomeRepo, ledgerRepo, auditRepo, txManager))
+	handlers.NewDashboardHandler(authed, services.NewDashboardService(repository.NewDashboardRepositorySQLC(queries), incomeRepo, ledgerRepo))
+	handlers.NewRefundHandler(authed, services.NewRefundService(repository.NewRefundRepositorySQLC(queries), repo, ledgerRepo, auditRepo, txManager))
+
+	expenseTrashService := services.NewExpenseTrashService(
+		repository.NewExpenseTrashRepositorySQLC(queries),
+		ledgerRepo,
+		auditRepo,
+		txManager,
+		cfg.ExpenseTrashRetention,
+	)
+	handlers.NewExpenseTrashHandler(authed, expenseTrashService)
+	// ゴミ箱の掃除は家計簿を横断するため、行レベルセキュリティの対象外で実行する
+	go jobs.RunPeriodically(transaction.WithSystemIdentity(context.Background()), "purge-trashed-expenses", cfg.ExpensePurgeInterval, func(ctx context.Context) error {
+		_, err := expenseTrashService.PurgeExpired(ctx)
+		return err
+	})
+
+	attachmentStorage, err := newAttachmentStorage(cfg)
+	if err != nil {
+		panic(err)
+	}
+	attachmentService := services.NewAttachmentService(
+		repository.NewAttachmentRepositorySQLC(queries),
+		repo,
+		ledgerRepo,
+		attachmentStorage,
+		txManager,
+		cfg.AttachmentMaxBytes,
+	)
+	handlers.NewAttachmentHandler(authed, attachmentService, cfg.AttachmentMaxBytes)
+	// 支出の完全な削除・退会などで使われなくなったファイルは家計簿を横断して消すため、行レベルセキュリティの対象外で実行する
+	go jobs.RunPeriodically(transaction.WithSystemIdentity(context.Background()), "purge-orphaned-attachments", cfg.AttachmentPurgeInterval, func(ctx context.Context) error {
+		_, err := attachmentService.PurgeOrphanedFiles(ctx)
+		return err
+	})
 
 	categoryService := services.NewCategoryService(categoryRepo)
 	handlers.NewCategoryHandler(r, categoryService)
 
-	userRepo := repository.NewUserRepositorySQLC(queries)
 	fixedCostRepo := repository.NewFixedCostRepositorySQLC(queries)
-	txManager := db.NewSQLTxManager(dbConn)
-	initialSetupService := services.NewInitialSetupService(userRepo, fixedCostRepo, txManager)
-	handlers.NewInitialSetupHandler(r, initialSetupService)
+	initialSetupService := services.NewInitialSetupService(userRepo, fixedCostRepo, ledgerRepo, auditRepo, txManager)
+	handlers.NewInitialSetupHandler(authed, initialSetupService)
+
+	handlers.NewUserHandler(authed, userService)
+
+	accountDeletionService := services.NewAccountDeletionService(
+		userRepo,
+		repository.NewAccountDeletionAuditRepositorySQLC(queries),
+		repository.NewUserDataPurgersSQLC(queries),
+		txManager,
+		cfg.AccountDeletionGracePeriod,
+	)
+	handlers.NewAccountDeletionHandler(authed, accountDeletionService)
+	// 退会データの削除はユーザーを横断するため、行レベルセキュリティの対象外で実行する
+	go jobs.RunPeriodically(transaction.WithSystemIdentity(context.Background()), "purge-deleted-accounts", cfg.AccountPurgeInterval, func(ctx context.Context) error {
+		_, err := accountDeletionService.PurgeDueAccounts(ctx)
+		return err
+	})
 
-	userService := services.NewUserService(userRepo)
-	handlers.NewUserHandler(r, userService)
+	adminTokens := auth.NewAdminTokens(cfg.AdminTokens)
+	if adminTokens.Enabled() {
+		admin := r.Group("/admin",
+			handlers.AdminAuthMiddleware(adminTokens),
+			systemIdentity,
+			handlers.RequestTransaction(txManager),
+		)
+		adminService := services.NewAdminService(
+			repository.NewAdminRepositorySQLC(queries),
+			repository.NewAdminAuditLogRepositorySQLC(queries),
+			txManager,
+		)
+		handlers.NewAdminHandler(admin, adminService)
+	}
 
 	r.Run() // デフォルトで:8080で起動
 }
+
+// newAttachmentStorage は設定に応じた添付ファイルの保存先を返します。
+func newAttachmentStorage(cfg config.Config) (repositories.FileStorage, error) {
+	if cfg.AttachmentStorage == config.AttachmentStorageS3 {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.AttachmentS3.Endpoint,
+			Region:          cfg.AttachmentS3.Region,
+			Bucket:          cfg.AttachmentS3.Bucket,
+			AccessKeyID:     cfg.AttachmentS3.AccessKeyID,
+			SecretAccessKey: cfg.AttachmentS3.SecretAccessKey,
+		}, nil)
+	}
+	return storage.NewLocalStorage(cfg.AttachmentLocalDir)
+}
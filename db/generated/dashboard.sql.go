@@ -7,49 +7,115 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
+const getMonthlyBudget = `-- name: GetMonthlyBudget :one
+SELECT
+  (SELECT COALESCE(SUM(u.saving_goal), 0)
+   FROM ledger_members m
+   JOIN users u ON u.id = m.user_id
+   WHERE m.ledger_id = $1)::bigint AS saving_goal,
+  (SELECT COALESCE(SUM(fc.amount), 0)
+   FROM fixed_costs fc
+   WHERE fc.ledger_id = $1)::bigint AS fixed_costs
+`
+
+type GetMonthlyBudgetRow struct {
+	SavingGoal int64
+	FixedCosts int64
+}
+
+// 共有家計簿では全メンバーの貯金目標を合算する。収入は incomes.sql の GetMonthlyIncomeSummary で集計する
+func (q *Queries) GetMonthlyBudget(ctx context.Context, ledgerID int32) (GetMonthlyBudgetRow, error) {
+	row := q.db.QueryRowContext(ctx, getMonthlyBudget, ledgerID)
+	var i GetMonthlyBudgetRow
+	err := row.Scan(&i.SavingGoal, &i.FixedCosts)
+	return i, err
+}
+
 const getMonthlyExpensesSummary = `-- name: GetMonthlyExpensesSummary :one
 SELECT
-  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount ELSE 0 END), 0) AS confirmed_expenses,
-  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0) AS pending_expenses
+  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount - e.refunded_amount ELSE 0 END), 0)::bigint AS confirmed_expenses,
+  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0)::bigint AS pending_expenses
 FROM expenses e
-WHERE e.user_id = $1
-  AND DATE_TRUNC('month', e.spent_at) = DATE_TRUNC('month', CURRENT_DATE)
+WHERE e.ledger_id = $1
+  AND e.deleted_at IS NULL
+  AND DATE_TRUNC('month', e.spent_at) = DATE_TRUNC('month', $2::date)
 `
 
+type GetMonthlyExpensesSummaryParams struct {
+	LedgerID int32
+	Month    time.Time
+}
+
 type GetMonthlyExpensesSummaryRow struct {
-	ConfirmedExpenses interface{}
-	PendingExpenses   interface{}
+	ConfirmedExpenses int64
+	PendingExpenses   int64
 }
 
-func (q *Queries) GetMonthlyExpensesSummary(ctx context.Context, userID string) (GetMonthlyExpensesSummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getMonthlyExpensesSummary, userID)
+// month を含む月の支出を集計する。確定済みの支出は返金を差し引いた額を合計する
+func (q *Queries) GetMonthlyExpensesSummary(ctx context.Context, arg GetMonthlyExpensesSummaryParams) (GetMonthlyExpensesSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getMonthlyExpensesSummary, arg.LedgerID, arg.Month)
 	var i GetMonthlyExpensesSummaryRow
 	err := row.Scan(&i.ConfirmedExpenses, &i.PendingExpenses)
 	return i, err
 }
 
-const getMonthlySummary = `-- name: GetMonthlySummary :one
+const listMonthlyPaymentMethodTotals = `-- name: ListMonthlyPaymentMethodTotals :many
 SELECT
-  u.income,
-  u.saving_goal,
-  COALESCE(SUM(fc.amount), 0) AS fixed_costs
-FROM users u
-LEFT JOIN fixed_costs fc ON fc.user_id = u.id
-WHERE u.id = $1
-GROUP BY u.id
+  COALESCE(pa.type, 'unspecified') AS payment_method,
+  COUNT(*)::int AS expense_count,
+  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount - e.refunded_amount ELSE 0 END), 0)::bigint AS confirmed_expenses,
+  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0)::bigint AS pending_expenses
+FROM expenses e
+LEFT JOIN expense_payment_accounts epa ON epa.expense_id = e.id
+LEFT JOIN payment_accounts pa ON pa.id = epa.payment_account_id
+WHERE e.ledger_id = $1
+  AND e.deleted_at IS NULL
+  AND DATE_TRUNC('month', e.spent_at) = DATE_TRUNC('month', $2::date)
+GROUP BY COALESCE(pa.type, 'unspecified')
+ORDER BY confirmed_expenses DESC, payment_method
 `
 
-type GetMonthlySummaryRow struct {
-	Income     int32
-	SavingGoal int32
-	FixedCosts interface{}
+type ListMonthlyPaymentMethodTotalsParams struct {
+	LedgerID int32
+	Month    time.Time
 }
 
-func (q *Queries) GetMonthlySummary(ctx context.Context, id string) (GetMonthlySummaryRow, error) {
-	row := q.db.QueryRowContext(ctx, getMonthlySummary, id)
-	var i GetMonthlySummaryRow
-	err := row.Scan(&i.Income, &i.SavingGoal, &i.FixedCosts)
-	return i, err
+type ListMonthlyPaymentMethodTotalsRow struct {
+	PaymentMethod     string
+	ExpenseCount      int32
+	ConfirmedExpenses int64
+	PendingExpenses   int64
+}
+
+// month を含む月の支出を支払い手段の種類ごとに集計する。支払い手段の無い支出は 'unspecified' にまとめる。
+// 確定済みの合計は返金を差し引いた額
+func (q *Queries) ListMonthlyPaymentMethodTotals(ctx context.Context, arg ListMonthlyPaymentMethodTotalsParams) ([]ListMonthlyPaymentMethodTotalsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMonthlyPaymentMethodTotals, arg.LedgerID, arg.Month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMonthlyPaymentMethodTotalsRow
+	for rows.Next() {
+		var i ListMonthlyPaymentMethodTotalsRow
+		if err := rows.Scan(
+			&i.PaymentMethod,
+			&i.ExpenseCount,
+			&i.ConfirmedExpenses,
+			&i.PendingExpenses,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
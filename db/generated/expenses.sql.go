@@ -9,8 +9,61 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+const anonymizeExpensesByUser = `-- name: AnonymizeExpensesByUser :execrows
+UPDATE expenses
+SET user_id = NULL
+WHERE user_id = $1
+`
+
+// 共有中の家計簿の支出は残し、登録したユーザーとの紐付けだけを外す
+func (q *Queries) AnonymizeExpensesByUser(ctx context.Context, userID sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, anonymizeExpensesByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const confirmExpense = `-- name: ConfirmExpense :execrows
+UPDATE expenses
+SET
+  status = 'confirmed',
+  confirmed_at = now(),
+  planned_amount = amount,
+  planned_spent_at = spent_at,
+  amount = COALESCE($1, amount),
+  spent_at = COALESCE($2, spent_at),
+  version = version + 1,
+  update_at = now()
+WHERE id = $3 AND ledger_id = $4 AND status = 'planned' AND deleted_at IS NULL
+`
+
+type ConfirmExpenseParams struct {
+	Amount   sql.NullInt32
+	SpentAt  sql.NullTime
+	ID       int32
+	LedgerID int32
+}
+
+// planned の支出を confirmed にし、確定日時と予定していた金額・日付を記録する。
+// 実際の金額・日付（amount・spent_at）を指定した場合はそれで置き換える。planned 以外の行は変更しない
+func (q *Queries) ConfirmExpense(ctx context.Context, arg ConfirmExpenseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, confirmExpense,
+		arg.Amount,
+		arg.SpentAt,
+		arg.ID,
+		arg.LedgerID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createExpense = `-- name: CreateExpense :one
 INSERT INTO expenses (
   user_id,
@@ -18,20 +71,22 @@ INSERT INTO expenses (
   category_id,
   memo,
   spent_at,
-  status
+  status,
+  ledger_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7
 )
 RETURNING id
 `
 
 type CreateExpenseParams struct {
-	UserID     string
+	UserID     sql.NullString
 	Amount     int32
 	CategoryID int32
 	Memo       sql.NullString
 	SpentAt    time.Time
 	Status     string
+	LedgerID   int32
 }
 
 func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (int32, error) {
@@ -42,27 +97,13 @@ func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (i
 		arg.Memo,
 		arg.SpentAt,
 		arg.Status,
+		arg.LedgerID,
 	)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
-const deleteExpense = `-- name: DeleteExpense :exec
-DELETE FROM expenses
-WHERE id = $1 AND user_id = $2
-`
-
-type DeleteExpenseParams struct {
-	ID     int32
-	UserID string
-}
-
-func (q *Queries) DeleteExpense(ctx context.Context, arg DeleteExpenseParams) error {
-	_, err := q.db.ExecContext(ctx, deleteExpense, arg.ID, arg.UserID)
-	return err
-}
-
 const getExpenseByID = `-- name: GetExpenseByID :one
 SELECT
   id,
@@ -72,12 +113,12 @@ SELECT
   spent_at,
   status
 FROM expenses
-WHERE user_id = $1 AND id = $2
+WHERE ledger_id = $1 AND id = $2 AND deleted_at IS NULL
 `
 
 type GetExpenseByIDParams struct {
-	UserID string
-	ID     int32
+	LedgerID int32
+	ID       int32
 }
 
 type GetExpenseByIDRow struct {
@@ -90,7 +131,7 @@ type GetExpenseByIDRow struct {
 }
 
 func (q *Queries) GetExpenseByID(ctx context.Context, arg GetExpenseByIDParams) (GetExpenseByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getExpenseByID, arg.UserID, arg.ID)
+	row := q.db.QueryRowContext(ctx, getExpenseByID, arg.LedgerID, arg.ID)
 	var i GetExpenseByIDRow
 	err := row.Scan(
 		&i.ID,
@@ -110,30 +151,40 @@ SELECT
   e.memo,
   e.spent_at,
   e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
   c.id AS category_id,
   c.name AS category_name
 FROM expenses e
 JOIN categories c ON e.category_id = c.id
-WHERE e.user_id = $1 AND e.id = $2
+WHERE e.ledger_id = $1 AND e.id = $2 AND e.deleted_at IS NULL
 `
 
 type GetExpenseWithCategoryByIDParams struct {
-	UserID string
-	ID     int32
+	LedgerID int32
+	ID       int32
 }
 
 type GetExpenseWithCategoryByIDRow struct {
-	ID           int32
-	Amount       int32
-	Memo         sql.NullString
-	SpentAt      time.Time
-	Status       string
-	CategoryID   int32
-	CategoryName string
+	ID             int32
+	Amount         int32
+	Memo           sql.NullString
+	SpentAt        time.Time
+	Status         string
+	ConfirmedAt    sql.NullTime
+	PlannedAmount  sql.NullInt32
+	PlannedSpentAt sql.NullTime
+	Version        int32
+	RefundedAmount int32
+	CategoryID     int32
+	CategoryName   string
 }
 
 func (q *Queries) GetExpenseWithCategoryByID(ctx context.Context, arg GetExpenseWithCategoryByIDParams) (GetExpenseWithCategoryByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getExpenseWithCategoryByID, arg.UserID, arg.ID)
+	row := q.db.QueryRowContext(ctx, getExpenseWithCategoryByID, arg.LedgerID, arg.ID)
 	var i GetExpenseWithCategoryByIDRow
 	err := row.Scan(
 		&i.ID,
@@ -141,6 +192,10 @@ func (q *Queries) GetExpenseWithCategoryByID(ctx context.Context, arg GetExpense
 		&i.Memo,
 		&i.SpentAt,
 		&i.Status,
+		&i.ConfirmedAt,
+		&i.PlannedAmount,
+		&i.PlannedSpentAt,
+		&i.Version,
 		&i.CategoryID,
 		&i.CategoryName,
 	)
@@ -148,32 +203,106 @@ func (q *Queries) GetExpenseWithCategoryByID(ctx context.Context, arg GetExpense
 }
 
 const listExpenses = `-- name: ListExpenses :many
-SELECT 
+SELECT
   e.id,
   e.amount,
   e.memo,
   e.spent_at,
   e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
   c.id AS category_id,
   c.name AS category_name
 FROM expenses e
 JOIN categories c ON e.category_id = c.id
-WHERE e.user_id = $1
-ORDER BY spent_at DESC
+WHERE e.ledger_id = $1
+  AND e.deleted_at IS NULL
+  AND ($2::date IS NULL OR e.spent_at >= $2)
+  AND ($3::date IS NULL OR e.spent_at <= $3)
+  AND (
+    $4::int[] IS NULL
+    OR EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id AND s.category_id = ANY($4::int[]))
+    OR (e.category_id = ANY($4::int[]) AND NOT EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id))
+  )
+  AND (
+    $5::int[] IS NULL
+    OR EXISTS (SELECT 1 FROM expense_tags et WHERE et.expense_id = e.id AND et.tag_id = ANY($5::int[]))
+  )
+  AND ($6::text IS NULL OR e.status = $6)
+  AND ($7::int IS NULL OR e.amount >= $7)
+  AND ($8::int IS NULL OR e.amount <= $8)
+  AND ($9::text IS NULL OR strpos(lower(e.memo), lower($9)) > 0)
+  AND (
+    $10::int IS NULL
+    OR ($11::text = '-spent_at' AND (e.spent_at, e.id) < ($12::date, $10))
+    OR ($11 = 'spent_at' AND (e.spent_at, e.id) > ($12, $10))
+    OR ($11 = '-amount' AND (e.amount, e.id) < ($13::int, $10))
+    OR ($11 = 'amount' AND (e.amount, e.id) > ($13, $10))
+  )
+ORDER BY
+  CASE WHEN $11 = 'spent_at' THEN e.spent_at END ASC,
+  CASE WHEN $11 = '-spent_at' THEN e.spent_at END DESC,
+  CASE WHEN $11 = 'amount' THEN e.amount END ASC,
+  CASE WHEN $11 = '-amount' THEN e.amount END DESC,
+  CASE WHEN $11 IN ('spent_at', 'amount') THEN e.id END ASC,
+  CASE WHEN $11 IN ('-spent_at', '-amount') THEN e.id END DESC
+LIMIT $14
 `
 
+type ListExpensesParams struct {
+	LedgerID      int32
+	FromDate      sql.NullTime
+	ToDate        sql.NullTime
+	CategoryIds   []int32
+	TagIds        []int32
+	Status        sql.NullString
+	MinAmount     sql.NullInt32
+	MaxAmount     sql.NullInt32
+	Memo          sql.NullString
+	CursorID      sql.NullInt32
+	SortKey       string
+	CursorSpentAt sql.NullTime
+	CursorAmount  sql.NullInt32
+	PageLimit     int32
+}
+
 type ListExpensesRow struct {
-	ID           int32
-	Amount       int32
-	Memo         sql.NullString
-	SpentAt      time.Time
-	Status       string
-	CategoryID   int32
-	CategoryName string
+	ID             int32
+	Amount         int32
+	Memo           sql.NullString
+	SpentAt        time.Time
+	Status         string
+	ConfirmedAt    sql.NullTime
+	PlannedAmount  sql.NullInt32
+	PlannedSpentAt sql.NullTime
+	Version        int32
+	RefundedAmount int32
+	CategoryID     int32
+	CategoryName   string
 }
 
-func (q *Queries) ListExpenses(ctx context.Context, userID string) ([]ListExpensesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listExpenses, userID)
+// ゴミ箱の支出は含めない。絞り込み条件は NULL なら無視する。内訳のある支出は内訳のカテゴリで絞り込む。sort_key は -spent_at / spent_at / -amount / amount で、
+// 同じ値の行は id で順序を決める。cursor_id があれば (並び順のキー, id) がその行より後のものを返す
+func (q *Queries) ListExpenses(ctx context.Context, arg ListExpensesParams) ([]ListExpensesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listExpenses,
+		arg.LedgerID,
+		arg.FromDate,
+		arg.ToDate,
+		pq.Array(arg.CategoryIds),
+		pq.Array(arg.TagIds),
+		arg.Status,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Memo,
+		arg.CursorID,
+		arg.SortKey,
+		arg.CursorSpentAt,
+		arg.CursorAmount,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -187,8 +316,313 @@ func (q *Queries) ListExpenses(ctx context.Context, userID string) ([]ListExpens
 			&i.Memo,
 			&i.SpentAt,
 			&i.Status,
+			&i.ConfirmedAt,
+			&i.PlannedAmount,
+			&i.PlannedSpentAt,
+			&i.Version,
+			&i.RefundedAmount,
+			&i.CategoryID,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpensesByUser = `-- name: ListExpensesByUser :many
+SELECT
+  id,
+  user_id,
+  amount,
+  category_id,
+  memo,
+  spent_at,
+  status,
+  created_at,
+  update_at,
+  ledger_id,
+  confirmed_at,
+  planned_amount,
+  planned_spent_at,
+  deleted_at,
+  version,
+  refunded_amount
+FROM expenses
+WHERE user_id = $1
+ORDER BY spent_at ASC, id ASC
+`
+
+// 運用者によるデータの書き出し用。家計簿を問わず user が登録した支出を返す
+func (q *Queries) ListExpensesByUser(ctx context.Context, userID sql.NullString) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listExpensesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Amount,
+			&i.CategoryID,
+			&i.Memo,
+			&i.SpentAt,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdateAt,
+			&i.LedgerID,
+			&i.ConfirmedAt,
+			&i.PlannedAmount,
+			&i.PlannedSpentAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.RefundedAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedExpenses = `-- name: ListTrashedExpenses :many
+SELECT
+  e.id,
+  e.amount,
+  e.memo,
+  e.spent_at,
+  e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
+  e.deleted_at,
+  c.id AS category_id,
+  c.name AS category_name
+FROM expenses e
+JOIN categories c ON e.category_id = c.id
+WHERE e.ledger_id = $1 AND e.deleted_at IS NOT NULL
+ORDER BY e.deleted_at DESC, e.id DESC
+`
+
+type ListTrashedExpensesRow struct {
+	ID             int32
+	Amount         int32
+	Memo           sql.NullString
+	SpentAt        time.Time
+	Status         string
+	ConfirmedAt    sql.NullTime
+	PlannedAmount  sql.NullInt32
+	PlannedSpentAt sql.NullTime
+	Version        int32
+	RefundedAmount int32
+	DeletedAt      sql.NullTime
+	CategoryID     int32
+	CategoryName   string
+}
+
+// ゴミ箱の支出を、ゴミ箱に移した日時が新しい順に返す
+func (q *Queries) ListTrashedExpenses(ctx context.Context, ledgerID int32) ([]ListTrashedExpensesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedExpenses, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrashedExpensesRow
+	for rows.Next() {
+		var i ListTrashedExpensesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Memo,
+			&i.SpentAt,
+			&i.Status,
+			&i.ConfirmedAt,
+			&i.PlannedAmount,
+			&i.PlannedSpentAt,
+			&i.Version,
+			&i.RefundedAmount,
+			&i.DeletedAt,
+			&i.CategoryID,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeExpensesByUser = `-- name: PurgeExpensesByUser :execrows
+DELETE FROM expenses e
+WHERE e.user_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = e.ledger_id AND m.user_id <> $1
+  )
+`
+
+// 他のメンバーがいない家計簿の支出だけを削除する（共有中の家計簿の支出は AnonymizeExpensesByUser で残す）
+func (q *Queries) PurgeExpensesByUser(ctx context.Context, userID sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeExpensesByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeTrashedExpenses = `-- name: PurgeTrashedExpenses :execrows
+DELETE FROM expenses
+WHERE deleted_at < $1
+`
+
+// ゴミ箱に移してから保存期間を過ぎた支出を家計簿を問わず削除する
+func (q *Queries) PurgeTrashedExpenses(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeTrashedExpenses, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreExpense = `-- name: RestoreExpense :execrows
+UPDATE expenses
+SET
+  deleted_at = NULL,
+  version = version + 1,
+  update_at = now()
+WHERE id = $1 AND ledger_id = $2 AND deleted_at IS NOT NULL
+`
+
+type RestoreExpenseParams struct {
+	ID       int32
+	LedgerID int32
+}
+
+func (q *Queries) RestoreExpense(ctx context.Context, arg RestoreExpenseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreExpense, arg.ID, arg.LedgerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchExpenses = `-- name: SearchExpenses :many
+WITH q AS (
+  SELECT COALESCE(array_agg(DISTINCT g), '{}')::text[] AS grams
+  FROM unnest($1::text[]) AS t, unnest(expense_memo_ngrams(t)) AS g
+)
+SELECT
+  e.id,
+  e.amount,
+  e.memo,
+  e.spent_at,
+  e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
+  c.id AS category_id,
+  c.name AS category_name,
+  (cardinality(q.grams)::float8 / GREATEST(cardinality(expense_memo_ngrams(e.memo)), 1))::float8 AS score
+FROM expenses e
+JOIN categories c ON e.category_id = c.id
+CROSS JOIN q
+WHERE e.ledger_id = $2
+  AND e.deleted_at IS NULL
+  AND expense_memo_ngrams(e.memo) @> q.grams
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($1::text[]) AS t
+    WHERE strpos(expense_memo_normalize(e.memo), expense_memo_normalize(t)) = 0
+  )
+  AND (
+    $3::int[] IS NULL
+    OR EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id AND s.category_id = ANY($3::int[]))
+    OR (e.category_id = ANY($3::int[]) AND NOT EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id))
+  )
+  AND ($4::text IS NULL OR e.status = $4)
+ORDER BY score DESC, e.spent_at DESC, e.id DESC
+LIMIT $5
+`
+
+type SearchExpensesParams struct {
+	Terms       []string
+	LedgerID    int32
+	CategoryIds []int32
+	Status      sql.NullString
+	PageLimit   int32
+}
+
+type SearchExpensesRow struct {
+	ID             int32
+	Amount         int32
+	Memo           sql.NullString
+	SpentAt        time.Time
+	Status         string
+	ConfirmedAt    sql.NullTime
+	PlannedAmount  sql.NullInt32
+	PlannedSpentAt sql.NullTime
+	Version        int32
+	RefundedAmount int32
+	CategoryID     int32
+	CategoryName   string
+	Score          float64
+}
+
+// terms をすべて含むメモの支出を、メモに占める一致部分の割合（score）が高い順に返す。
+// 文字の 1-gram・2-gram の索引で候補を絞り込み、各語が連続して含まれるかを確かめる
+func (q *Queries) SearchExpenses(ctx context.Context, arg SearchExpensesParams) ([]SearchExpensesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchExpenses,
+		pq.Array(arg.Terms),
+		arg.LedgerID,
+		pq.Array(arg.CategoryIds),
+		arg.Status,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchExpensesRow
+	for rows.Next() {
+		var i SearchExpensesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Memo,
+			&i.SpentAt,
+			&i.Status,
+			&i.ConfirmedAt,
+			&i.PlannedAmount,
+			&i.PlannedSpentAt,
+			&i.Version,
+			&i.RefundedAmount,
 			&i.CategoryID,
 			&i.CategoryName,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -203,56 +637,75 @@ func (q *Queries) ListExpenses(ctx context.Context, userID string) ([]ListExpens
 	return items, nil
 }
 
-const updateExpense = `-- name: UpdateExpense :exec
+const trashExpense = `-- name: TrashExpense :execrows
 UPDATE expenses
 SET
-  amount = $2,
-  category_id = $3,
-  memo = $4,
-  spent_at = $5,
-  status = $6,
+  deleted_at = now(),
+  version = version + 1,
   update_at = now()
-WHERE id = $1 AND user_id = $7
+WHERE id = $1 AND ledger_id = $2 AND deleted_at IS NULL
+  AND ($3::int IS NULL OR version = $3)
+`
+
+type TrashExpenseParams struct {
+	ID       int32
+	LedgerID int32
+	Version  sql.NullInt32
+}
+
+// 支出をゴミ箱に移す。行は PurgeTrashedExpenses で保存期間を過ぎてから削除する。
+// version を指定した場合は、現在の version と一致する行だけを移す
+func (q *Queries) TrashExpense(ctx context.Context, arg TrashExpenseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashExpense, arg.ID, arg.LedgerID, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateExpense = `-- name: UpdateExpense :execrows
+UPDATE expenses
+SET
+  amount = $1,
+  category_id = $2,
+  memo = $3,
+  spent_at = $4,
+  status = $5,
+  confirmed_at = CASE WHEN status = 'planned' AND $5 = 'confirmed' THEN now() ELSE confirmed_at END,
+  planned_amount = CASE WHEN status = 'planned' AND $5 = 'confirmed' THEN amount ELSE planned_amount END,
+  planned_spent_at = CASE WHEN status = 'planned' AND $5 = 'confirmed' THEN spent_at ELSE planned_spent_at END,
+  version = version + 1,
+  update_at = now()
+WHERE id = $6 AND ledger_id = $7 AND deleted_at IS NULL
+  AND ($8::int IS NULL OR version = $8)
 `
 
 type UpdateExpenseParams struct {
-	ID         int32
 	Amount     int32
 	CategoryID int32
 	Memo       sql.NullString
 	SpentAt    time.Time
 	Status     string
-	UserID     string
+	ID         int32
+	LedgerID   int32
+	Version    sql.NullInt32
 }
 
-func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) error {
-	_, err := q.db.ExecContext(ctx, updateExpense,
-		arg.ID,
+// planned から confirmed に変わる場合は ConfirmExpense と同じく確定日時と予定時の金額・日付を記録する。
+// version を指定した場合は、現在の version と一致する行だけを更新する
+func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateExpense,
 		arg.Amount,
 		arg.CategoryID,
 		arg.Memo,
 		arg.SpentAt,
 		arg.Status,
-		arg.UserID,
+		arg.ID,
+		arg.LedgerID,
+		arg.Version,
 	)
-	return err
-}
-
-const updateExpenseStatus = `-- name: UpdateExpenseStatus :exec
-UPDATE expenses
-SET
-  status = $2,
-  update_at = now()
-WHERE id = $1 AND user_id = $3
-`
-
-type UpdateExpenseStatusParams struct {
-	ID     int32
-	Status string
-	UserID string
-}
-
-func (q *Queries) UpdateExpenseStatus(ctx context.Context, arg UpdateExpenseStatusParams) error {
-	_, err := q.db.ExecContext(ctx, updateExpenseStatus, arg.ID, arg.Status, arg.UserID)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
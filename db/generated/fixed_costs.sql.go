@@ -7,30 +7,54 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/lib/pq"
 )
 
+const anonymizeFixedCostsByUser = `-- name: AnonymizeFixedCostsByUser :execrows
+UPDATE fixed_costs
+SET user_id = NULL
+WHERE user_id = $1
+`
+
+// 共有中の家計簿の固定費は残し、登録したユーザーとの紐付けだけを外す
+func (q *Queries) AnonymizeFixedCostsByUser(ctx context.Context, userID sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, anonymizeFixedCostsByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const bulkCreateFixedCosts = `-- name: BulkCreateFixedCosts :exec
 INSERT INTO fixed_costs (
+  ledger_id,
   user_id,
   name,
   amount
 )
-SELECT u.user_id, n.name, a.amount
-FROM UNNEST($1::text[]) WITH ORDINALITY AS u(user_id, ord)
-JOIN UNNEST($2::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
-JOIN UNNEST($3::int[]) WITH ORDINALITY AS a(amount, ord) USING (ord)
+SELECT l.ledger_id, u.user_id, n.name, a.amount
+FROM UNNEST($1::int[]) WITH ORDINALITY AS l(ledger_id, ord)
+JOIN UNNEST($2::text[]) WITH ORDINALITY AS u(user_id, ord) USING (ord)
+JOIN UNNEST($3::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+JOIN UNNEST($4::int[]) WITH ORDINALITY AS a(amount, ord) USING (ord)
 `
 
 type BulkCreateFixedCostsParams struct {
-	Column1 []string
+	Column1 []int32
 	Column2 []string
-	Column3 []int32
+	Column3 []string
+	Column4 []int32
 }
 
 func (q *Queries) BulkCreateFixedCosts(ctx context.Context, arg BulkCreateFixedCostsParams) error {
-	_, err := q.db.ExecContext(ctx, bulkCreateFixedCosts, pq.Array(arg.Column1), pq.Array(arg.Column2), pq.Array(arg.Column3))
+	_, err := q.db.ExecContext(ctx, bulkCreateFixedCosts,
+		pq.Array(arg.Column1),
+		pq.Array(arg.Column2),
+		pq.Array(arg.Column3),
+		pq.Array(arg.Column4),
+	)
 	return err
 }
 
@@ -38,21 +62,28 @@ const createFixedCost = `-- name: CreateFixedCost :one
 INSERT INTO fixed_costs (
   user_id,
   name,
-  amount
+  amount,
+  ledger_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
-RETURNING id, user_id, name, amount, created_at, updated_at
+RETURNING id, user_id, name, amount, created_at, updated_at, ledger_id
 `
 
 type CreateFixedCostParams struct {
-	UserID string
-	Name   string
-	Amount int32
+	UserID   sql.NullString
+	Name     string
+	Amount   int32
+	LedgerID int32
 }
 
 func (q *Queries) CreateFixedCost(ctx context.Context, arg CreateFixedCostParams) (FixedCost, error) {
-	row := q.db.QueryRowContext(ctx, createFixedCost, arg.UserID, arg.Name, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createFixedCost,
+		arg.UserID,
+		arg.Name,
+		arg.Amount,
+		arg.LedgerID,
+	)
 	var i FixedCost
 	err := row.Scan(
 		&i.ID,
@@ -61,49 +92,97 @@ func (q *Queries) CreateFixedCost(ctx context.Context, arg CreateFixedCostParams
 		&i.Amount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LedgerID,
 	)
 	return i, err
 }
 
 const deleteFixedCost = `-- name: DeleteFixedCost :exec
 DELETE FROM fixed_costs
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND ledger_id = $2
 `
 
 type DeleteFixedCostParams struct {
-	ID     int32
-	UserID string
+	ID       int32
+	LedgerID int32
 }
 
 func (q *Queries) DeleteFixedCost(ctx context.Context, arg DeleteFixedCostParams) error {
-	_, err := q.db.ExecContext(ctx, deleteFixedCost, arg.ID, arg.UserID)
+	_, err := q.db.ExecContext(ctx, deleteFixedCost, arg.ID, arg.LedgerID)
 	return err
 }
 
-const deleteFixedCostsByUser = `-- name: DeleteFixedCostsByUser :exec
+const deleteFixedCostsByLedger = `-- name: DeleteFixedCostsByLedger :exec
 DELETE FROM fixed_costs
-WHERE user_id = $1
+WHERE ledger_id = $1
 `
 
-func (q *Queries) DeleteFixedCostsByUser(ctx context.Context, userID string) error {
-	_, err := q.db.ExecContext(ctx, deleteFixedCostsByUser, userID)
+func (q *Queries) DeleteFixedCostsByLedger(ctx context.Context, ledgerID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteFixedCostsByLedger, ledgerID)
 	return err
 }
 
-const listFixedCostsByUser = `-- name: ListFixedCostsByUser :many
+const listFixedCostsByLedger = `-- name: ListFixedCostsByLedger :many
 SELECT 
   id,
   user_id,
   name,
   amount,
   created_at,
-  updated_at
+  updated_at,
+  ledger_id
+FROM fixed_costs
+WHERE ledger_id = $1
+ORDER BY id ASC
+`
+
+func (q *Queries) ListFixedCostsByLedger(ctx context.Context, ledgerID int32) ([]FixedCost, error) {
+	rows, err := q.db.QueryContext(ctx, listFixedCostsByLedger, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FixedCost
+	for rows.Next() {
+		var i FixedCost
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LedgerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFixedCostsByUser = `-- name: ListFixedCostsByUser :many
+SELECT
+  id,
+  user_id,
+  name,
+  amount,
+  created_at,
+  updated_at,
+  ledger_id
 FROM fixed_costs
 WHERE user_id = $1
 ORDER BY id ASC
 `
 
-func (q *Queries) ListFixedCostsByUser(ctx context.Context, userID string) ([]FixedCost, error) {
+// 運用者によるデータの書き出し用。家計簿を問わず user が登録した固定費を返す
+func (q *Queries) ListFixedCostsByUser(ctx context.Context, userID sql.NullString) ([]FixedCost, error) {
 	rows, err := q.db.QueryContext(ctx, listFixedCostsByUser, userID)
 	if err != nil {
 		return nil, err
@@ -119,6 +198,7 @@ func (q *Queries) ListFixedCostsByUser(ctx context.Context, userID string) ([]Fi
 			&i.Amount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LedgerID,
 		); err != nil {
 			return nil, err
 		}
@@ -133,20 +213,37 @@ func (q *Queries) ListFixedCostsByUser(ctx context.Context, userID string) ([]Fi
 	return items, nil
 }
 
+const purgeFixedCostsByUser = `-- name: PurgeFixedCostsByUser :execrows
+DELETE FROM fixed_costs fc
+WHERE fc.user_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = fc.ledger_id AND m.user_id <> $1
+  )
+`
+
+// 他のメンバーがいない家計簿の固定費だけを削除する（共有中の家計簿の固定費は AnonymizeFixedCostsByUser で残す）
+func (q *Queries) PurgeFixedCostsByUser(ctx context.Context, userID sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeFixedCostsByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateFixedCost = `-- name: UpdateFixedCost :exec
 UPDATE fixed_costs
 SET
   name = $2,
   amount = $3,
   updated_at = now()
-WHERE id = $1 AND user_id = $4
+WHERE id = $1 AND ledger_id = $4
 `
 
 type UpdateFixedCostParams struct {
-	ID     int32
-	Name   string
-	Amount int32
-	UserID string
+	ID       int32
+	Name     string
+	Amount   int32
+	LedgerID int32
 }
 
 func (q *Queries) UpdateFixedCost(ctx context.Context, arg UpdateFixedCostParams) error {
@@ -154,7 +251,7 @@ func (q *Queries) UpdateFixedCost(ctx context.Context, arg UpdateFixedCostParams
 		arg.ID,
 		arg.Name,
 		arg.Amount,
-		arg.UserID,
+		arg.LedgerID,
 	)
 	return err
 }
@@ -6,9 +6,47 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type AccountDeletionAudit struct {
+	ID          int32
+	RequestedAt time.Time
+	PurgedAt    time.Time
+	PurgedRows  json.RawMessage
+}
+
+type AdminAuditLog struct {
+	ID           int32
+	Operator     string
+	Action       string
+	TargetUserID sql.NullString
+	Detail       json.RawMessage
+	CreatedAt    time.Time
+}
+
+type AttachmentOrphan struct {
+	StorageKey string
+	CreatedAt  time.Time
+}
+
+type AttachmentUpload struct {
+	StorageKey string
+	CreatedAt  time.Time
+}
+
+type AuditEvent struct {
+	ID         int32
+	LedgerID   sql.NullInt32
+	ActorID    sql.NullString
+	Action     string
+	EntityType string
+	EntityID   string
+	Changes    json.RawMessage
+	CreatedAt  time.Time
+}
+
 type Category struct {
 	ID        int32
 	Name      string
@@ -16,30 +54,198 @@ type Category struct {
 }
 
 type Expense struct {
+	ID             int32
+	UserID         sql.NullString
+	Amount         int32
+	CategoryID     int32
+	Memo           sql.NullString
+	SpentAt        time.Time
+	Status         string
+	CreatedAt      time.Time
+	UpdateAt       time.Time
+	LedgerID       int32
+	ConfirmedAt    sql.NullTime
+	PlannedAmount  sql.NullInt32
+	PlannedSpentAt sql.NullTime
+	DeletedAt      sql.NullTime
+	Version        int32
+	RefundedAmount int32
+}
+
+type ExpenseAttachment struct {
+	ID           int32
+	ExpenseID    int32
+	FileName     string
+	ContentType  string
+	SizeBytes    int64
+	StorageKey   string
+	ThumbnailKey sql.NullString
+	CreatedAt    time.Time
+}
+
+type ExpensePayee struct {
+	ExpenseID int32
+	PayeeID   int32
+}
+
+type ExpensePaymentAccount struct {
+	ExpenseID        int32
+	PaymentAccountID int32
+}
+
+type ExpenseRefund struct {
 	ID         int32
-	UserID     string
+	ExpenseID  int32
 	Amount     int32
-	CategoryID int32
-	Memo       sql.NullString
-	SpentAt    time.Time
-	Status     string
+	RefundedAt time.Time
+	Memo       string
 	CreatedAt  time.Time
-	UpdateAt   time.Time
+}
+
+type ExpenseSplit struct {
+	ExpenseID  int32
+	Position   int32
+	CategoryID int32
+	Amount     int32
+	Memo       string
+}
+
+type ExpenseTag struct {
+	ExpenseID int32
+	TagID     int32
 }
 
 type FixedCost struct {
 	ID        int32
-	UserID    string
+	UserID    sql.NullString
 	Name      string
 	Amount    int32
 	CreatedAt sql.NullTime
 	UpdatedAt sql.NullTime
+	LedgerID  int32
+}
+
+type IdempotencyKey struct {
+	UserID          string
+	IdempotencyKey  string
+	RequestHash     string
+	ResponseStatus  int32
+	ResponseHeaders json.RawMessage
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+type Income struct {
+	ID         int32
+	LedgerID   int32
+	UserID     sql.NullString
+	Source     string
+	Amount     int32
+	ReceivedAt time.Time
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type Ledger struct {
+	ID        int32
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type LedgerInvite struct {
+	ID        int32
+	LedgerID  int32
+	CodeHash  string
+	CreatedBy string
+	ExpiresAt time.Time
+	UsedBy    sql.NullString
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type LedgerMember struct {
+	LedgerID int32
+	UserID   string
+	Role     string
+	JoinedAt time.Time
+}
+
+type LocalAccount struct {
+	UserID            string
+	Email             string
+	PasswordHash      string
+	CreatedAt         time.Time
+	PasswordChangedAt time.Time
+}
+
+type Payee struct {
+	ID        int32
+	UserID    string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PaymentAccount struct {
+	ID             int32
+	UserID         string
+	Name           string
+	Type           string
+	OpeningBalance int32
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PersonalAccessToken struct {
+	ID         int32
+	UserID     string
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type RefreshToken struct {
+	ID        int32
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+}
+
+type Tag struct {
+	ID        int32
+	UserID    string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type User struct {
-	ID         string
-	Income     int32
-	SavingGoal int32
-	CreatedAt  sql.NullTime
-	UpdatedAt  sql.NullTime
+	ID                  string
+	Income              int32
+	SavingGoal          int32
+	CreatedAt           sql.NullTime
+	UpdatedAt           sql.NullTime
+	DeletionRequestedAt sql.NullTime
+	DeletionScheduledAt sql.NullTime
+	ActiveLedgerID      sql.NullInt32
+	SetupCompletedAt    sql.NullTime
+	DisabledAt          sql.NullTime
+}
+
+type UserDataExport struct {
+	ID          int32
+	UserID      string
+	RequestedBy string
+	Payload     json.RawMessage
+	CreatedAt   time.Time
 }
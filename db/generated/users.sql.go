@@ -7,8 +7,27 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const cancelUserDeletion = `-- name: CancelUserDeletion :execrows
+UPDATE users
+SET
+    deletion_requested_at = NULL,
+    deletion_scheduled_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NOT NULL
+`
+
+func (q *Queries) CancelUserDeletion(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelUserDeletion, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :exec
 INSERT INTO users (
     id,
@@ -30,13 +49,104 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 	return err
 }
 
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const disableUser = `-- name: DisableUser :execrows
+UPDATE users
+SET disabled_at = COALESCE(disabled_at, now())
+WHERE id = $1
+`
+
+func (q *Queries) DisableUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, disableUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const enableUser = `-- name: EnableUser :execrows
+UPDATE users
+SET disabled_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) EnableUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enableUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getUserActivity = `-- name: GetUserActivity :one
+SELECT
+    u.id,
+    u.created_at,
+    u.disabled_at,
+    u.deletion_scheduled_at,
+    u.setup_completed_at,
+    (SELECT COUNT(*) FROM expenses e WHERE e.user_id = u.id) AS expense_count,
+    (SELECT COUNT(*) FROM fixed_costs f WHERE f.user_id = u.id) AS fixed_cost_count,
+    GREATEST(
+        u.created_at,
+        u.updated_at,
+        (SELECT MAX(e.update_at) FROM expenses e WHERE e.user_id = u.id),
+        (SELECT MAX(f.updated_at) FROM fixed_costs f WHERE f.user_id = u.id),
+        (SELECT MAX(t.last_used_at) FROM personal_access_tokens t WHERE t.user_id = u.id)
+    )::timestamp AS last_activity_at
+FROM users u
+WHERE u.id = $1
+`
+
+type GetUserActivityRow struct {
+	ID                  string
+	CreatedAt           sql.NullTime
+	DisabledAt          sql.NullTime
+	DeletionScheduledAt sql.NullTime
+	SetupCompletedAt    sql.NullTime
+	ExpenseCount        int64
+	FixedCostCount      int64
+	LastActivityAt      time.Time
+}
+
+// SearchUsers と同じ項目を 1 ユーザー分返す
+func (q *Queries) GetUserActivity(ctx context.Context, id string) (GetUserActivityRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserActivity, id)
+	var i GetUserActivityRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.DisabledAt,
+		&i.DeletionScheduledAt,
+		&i.SetupCompletedAt,
+		&i.ExpenseCount,
+		&i.FixedCostCount,
+		&i.LastActivityAt,
+	)
+	return i, err
+}
+
 const getUserByID = `-- name: GetUserByID :one
 SELECT
     id,
     income,
     saving_goal,
     created_at,
-    updated_at
+    updated_at,
+    deletion_requested_at,
+    deletion_scheduled_at,
+    active_ledger_id,
+    setup_completed_at,
+    disabled_at
 FROM users
 WHERE id = $1
 `
@@ -50,10 +160,241 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
 		&i.SavingGoal,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletionRequestedAt,
+		&i.DeletionScheduledAt,
+		&i.ActiveLedgerID,
+		&i.SetupCompletedAt,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
+const getUserDisabledAt = `-- name: GetUserDisabledAt :one
+SELECT disabled_at
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserDisabledAt(ctx context.Context, id string) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, getUserDisabledAt, id)
+	var disabled_at sql.NullTime
+	err := row.Scan(&disabled_at)
+	return disabled_at, err
+}
+
+const getUserOnboarding = `-- name: GetUserOnboarding :one
+SELECT
+    u.setup_completed_at,
+    (u.active_ledger_id IS NOT NULL)::boolean AS has_ledger,
+    EXISTS (
+        SELECT 1 FROM fixed_costs f WHERE f.ledger_id = u.active_ledger_id
+    ) AS has_fixed_costs,
+    EXISTS (
+        SELECT 1 FROM expenses e WHERE e.user_id = u.id AND e.deleted_at IS NULL
+    ) AS has_expenses
+FROM users u
+WHERE u.id = $1
+`
+
+type GetUserOnboardingRow struct {
+	SetupCompletedAt sql.NullTime
+	HasLedger        bool
+	HasFixedCosts    bool
+	HasExpenses      bool
+}
+
+func (q *Queries) GetUserOnboarding(ctx context.Context, id string) (GetUserOnboardingRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserOnboarding, id)
+	var i GetUserOnboardingRow
+	err := row.Scan(
+		&i.SetupCompletedAt,
+		&i.HasLedger,
+		&i.HasFixedCosts,
+		&i.HasExpenses,
+	)
+	return i, err
+}
+
+const listUsersDueForDeletion = `-- name: ListUsersDueForDeletion :many
+SELECT id
+FROM users
+WHERE deletion_scheduled_at <= $1
+ORDER BY deletion_scheduled_at ASC
+LIMIT $2
+`
+
+type ListUsersDueForDeletionParams struct {
+	DeletionScheduledAt sql.NullTime
+	Limit               int32
+}
+
+func (q *Queries) ListUsersDueForDeletion(ctx context.Context, arg ListUsersDueForDeletionParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersDueForDeletion, arg.DeletionScheduledAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUserForDeletion = `-- name: LockUserForDeletion :one
+SELECT deletion_requested_at
+FROM users
+WHERE id = $1 AND deletion_scheduled_at <= $2
+FOR UPDATE
+`
+
+type LockUserForDeletionParams struct {
+	ID                  string
+	DeletionScheduledAt sql.NullTime
+}
+
+func (q *Queries) LockUserForDeletion(ctx context.Context, arg LockUserForDeletionParams) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, lockUserForDeletion, arg.ID, arg.DeletionScheduledAt)
+	var deletion_requested_at sql.NullTime
+	err := row.Scan(&deletion_requested_at)
+	return deletion_requested_at, err
+}
+
+const markUserSetupCompleted = `-- name: MarkUserSetupCompleted :exec
+UPDATE users
+SET
+    setup_completed_at = COALESCE(setup_completed_at, now()),
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkUserSetupCompleted(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, markUserSetupCompleted, id)
+	return err
+}
+
+const provisionUser = `-- name: ProvisionUser :execrows
+INSERT INTO users (
+    id
+) VALUES (
+    $1
+)
+ON CONFLICT (id) DO NOTHING
+`
+
+// 認証済みユーザーの初回アクセス時に行を作成する（既に存在する場合は何もしない）
+func (q *Queries) ProvisionUser(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, provisionUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requestUserDeletion = `-- name: RequestUserDeletion :execrows
+UPDATE users
+SET
+    deletion_requested_at = now(),
+    deletion_scheduled_at = $2,
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NULL
+`
+
+type RequestUserDeletionParams struct {
+	ID                  string
+	DeletionScheduledAt sql.NullTime
+}
+
+func (q *Queries) RequestUserDeletion(ctx context.Context, arg RequestUserDeletionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestUserDeletion, arg.ID, arg.DeletionScheduledAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT
+    u.id,
+    u.created_at,
+    u.disabled_at,
+    u.deletion_scheduled_at,
+    u.setup_completed_at,
+    (SELECT COUNT(*) FROM expenses e WHERE e.user_id = u.id) AS expense_count,
+    (SELECT COUNT(*) FROM fixed_costs f WHERE f.user_id = u.id) AS fixed_cost_count,
+    GREATEST(
+        u.created_at,
+        u.updated_at,
+        (SELECT MAX(e.update_at) FROM expenses e WHERE e.user_id = u.id),
+        (SELECT MAX(f.updated_at) FROM fixed_costs f WHERE f.user_id = u.id),
+        (SELECT MAX(t.last_used_at) FROM personal_access_tokens t WHERE t.user_id = u.id)
+    )::timestamp AS last_activity_at
+FROM users u
+WHERE strpos(u.id, $1::text) > 0
+ORDER BY u.created_at DESC, u.id ASC
+LIMIT $2 OFFSET $3
+`
+
+type SearchUsersParams struct {
+	Query      string
+	PageLimit  int32
+	PageOffset int32
+}
+
+type SearchUsersRow struct {
+	ID                  string
+	CreatedAt           sql.NullTime
+	DisabledAt          sql.NullTime
+	DeletionScheduledAt sql.NullTime
+	SetupCompletedAt    sql.NullTime
+	ExpenseCount        int64
+	FixedCostCount      int64
+	LastActivityAt      time.Time
+}
+
+// 運用者向け。ID の部分一致で検索し、ユーザーごとの件数と最終操作日時を返す
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers, arg.Query, arg.PageLimit, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.DisabledAt,
+			&i.DeletionScheduledAt,
+			&i.SetupCompletedAt,
+			&i.ExpenseCount,
+			&i.FixedCostCount,
+			&i.LastActivityAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUserSettings = `-- name: UpdateUserSettings :exec
 UPDATE users
 SET
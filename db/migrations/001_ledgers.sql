@@ -0,0 +1,47 @@
+-- 家計簿（ledgers）を導入する前に作成したデータベースを移行する。
+-- psql で 1 つのトランザクションとして 1 回だけ実行する:
+--   psql "$DATABASE_URL" -v ON_ERROR_STOP=1 --single-transaction -f db/migrations/001_ledgers.sql
+--
+-- 既存のユーザーごとに個人の家計簿を作り、支出・固定費の ledger_id と users.active_ledger_id を埋めてから
+-- ledger_id を NOT NULL にする。
+
+ALTER TABLE users ADD COLUMN active_ledger_id INTEGER;
+
+-- 家計簿・メンバー・招待コードのテーブルと users.active_ledger_id の外部キー
+\ir ../schema/ledgers.sql
+
+ALTER TABLE expenses ADD COLUMN ledger_id INTEGER REFERENCES ledgers(id) ON DELETE CASCADE;
+ALTER TABLE fixed_costs ADD COLUMN ledger_id INTEGER REFERENCES ledgers(id) ON DELETE CASCADE;
+
+-- 個人の家計簿（初期設定で作るものと同じ名前）を作り、ユーザーを owner にしてアクティブにする
+CREATE TEMPORARY TABLE personal_ledgers ON COMMIT DROP AS
+SELECT u.id AS user_id, nextval(pg_get_serial_sequence('ledgers', 'id'))::integer AS ledger_id
+FROM users u;
+
+INSERT INTO ledgers (id, name)
+SELECT p.ledger_id, 'My ledger' FROM personal_ledgers p;
+
+INSERT INTO ledger_members (ledger_id, user_id, role)
+SELECT p.ledger_id, p.user_id, 'owner' FROM personal_ledgers p;
+
+UPDATE users u
+SET active_ledger_id = p.ledger_id
+FROM personal_ledgers p
+WHERE p.user_id = u.id;
+
+-- これまでの支出・固定費は登録したユーザーの個人の家計簿に移す
+UPDATE expenses e
+SET ledger_id = p.ledger_id
+FROM personal_ledgers p
+WHERE p.user_id = e.user_id;
+
+UPDATE fixed_costs f
+SET ledger_id = p.ledger_id
+FROM personal_ledgers p
+WHERE p.user_id = f.user_id;
+
+ALTER TABLE expenses ALTER COLUMN ledger_id SET NOT NULL;
+ALTER TABLE fixed_costs ALTER COLUMN ledger_id SET NOT NULL;
+
+CREATE INDEX expenses_ledger_id_spent_at_idx ON expenses (ledger_id, spent_at);
+CREATE INDEX fixed_costs_ledger_id_idx ON fixed_costs (ledger_id);
@@ -0,0 +1,7 @@
+-- name: CreateAccountDeletionAudit :exec
+INSERT INTO account_deletion_audits (
+  requested_at,
+  purged_rows
+) VALUES (
+  $1, $2
+);
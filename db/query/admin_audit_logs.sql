@@ -0,0 +1,15 @@
+-- name: CreateAdminAuditLog :exec
+INSERT INTO admin_audit_logs (
+  operator,
+  action,
+  target_user_id,
+  detail
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: AnonymizeAdminAuditLogsByUser :execrows
+-- 操作記録は残し、退会したユーザーとの紐付けだけを外す
+UPDATE admin_audit_logs
+SET target_user_id = NULL
+WHERE target_user_id = $1;
@@ -0,0 +1,42 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+  ledger_id,
+  actor_id,
+  action,
+  entity_type,
+  entity_id,
+  changes
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListAuditEvents :many
+-- user_id の操作と、user_id が参加している家計簿での操作を新しい順に返す。
+-- 絞り込み条件は NULL なら無視する。before_id より前（古いもの）を返す
+SELECT id, ledger_id, actor_id, action, entity_type, entity_id, changes, created_at
+FROM audit_events
+WHERE (
+    actor_id = sqlc.arg(user_id)::text
+    OR ledger_id IN (SELECT m.ledger_id FROM ledger_members m WHERE m.user_id = sqlc.arg(user_id))
+  )
+  AND (sqlc.narg(ledger_id)::int IS NULL OR ledger_id = sqlc.narg(ledger_id))
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type))
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(actor_id)::text IS NULL OR actor_id = sqlc.narg(actor_id))
+  AND (sqlc.narg(since)::timestamp IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamp IS NULL OR created_at < sqlc.narg(until))
+  AND (sqlc.narg(before_id)::int IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: PurgeAuditEventsByUser :execrows
+-- 家計簿に属さない本人の操作（収入など）は削除する
+DELETE FROM audit_events
+WHERE actor_id = $1 AND ledger_id IS NULL;
+
+-- name: AnonymizeAuditEventsByUser :execrows
+-- 共有家計簿の履歴は残し、操作したユーザーとの紐付けだけを外す
+UPDATE audit_events
+SET actor_id = NULL
+WHERE actor_id = $1;
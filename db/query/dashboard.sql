@@ -1,17 +1,37 @@
--- name: GetMonthlySummary :one
+-- name: GetMonthlyBudget :one
+-- 共有家計簿では全メンバーの貯金目標を合算する。収入は incomes.sql の GetMonthlyIncomeSummary で集計する
 SELECT
-  u.income,
-  u.saving_goal,
-  COALESCE(SUM(fc.amount), 0) AS fixed_costs
-FROM users u
-LEFT JOIN fixed_costs fc ON fc.user_id = u.id
-WHERE u.id = $1
-GROUP BY u.id;
+  (SELECT COALESCE(SUM(u.saving_goal), 0)
+   FROM ledger_members m
+   JOIN users u ON u.id = m.user_id
+   WHERE m.ledger_id = sqlc.arg(ledger_id))::bigint AS saving_goal,
+  (SELECT COALESCE(SUM(fc.amount), 0)
+   FROM fixed_costs fc
+   WHERE fc.ledger_id = sqlc.arg(ledger_id))::bigint AS fixed_costs;
 
 -- name: GetMonthlyExpensesSummary :one
+-- month を含む月の支出を集計する。確定済みの支出は返金を差し引いた額を合計する
 SELECT
-  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount ELSE 0 END), 0) AS confirmed_expenses,
-  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0) AS pending_expenses
+  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount - e.refunded_amount ELSE 0 END), 0)::bigint AS confirmed_expenses,
+  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0)::bigint AS pending_expenses
 FROM expenses e
-WHERE e.user_id = $1
-  AND DATE_TRUNC('month', e.spent_at) = DATE_TRUNC('month', CURRENT_DATE);
\ No newline at end of file
+WHERE e.ledger_id = sqlc.arg(ledger_id)
+  AND e.deleted_at IS NULL
+  AND DATE_TRUNC('month', e.spent_at) = DATE_TRUNC('month', sqlc.arg(month)::date);
+
+-- name: ListMonthlyPaymentMethodTotals :many
+-- month を含む月の支出を支払い手段の種類ごとに集計する。支払い手段の無い支出は 'unspecified' にまとめる。
+-- 確定済みの合計は返金を差し引いた額
+SELECT
+  COALESCE(pa.type, 'unspecified') AS payment_method,
+  COUNT(*)::int AS expense_count,
+  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount - e.refunded_amount ELSE 0 END), 0)::bigint AS confirmed_expenses,
+  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0)::bigint AS pending_expenses
+FROM expenses e
+LEFT JOIN expense_payment_accounts epa ON epa.expense_id = e.id
+LEFT JOIN payment_accounts pa ON pa.id = epa.payment_account_id
+WHERE e.ledger_id = sqlc.arg(ledger_id)
+  AND e.deleted_at IS NULL
+  AND DATE_TRUNC('month', e.spent_at) = DATE_TRUNC('month', sqlc.arg(month)::date)
+GROUP BY COALESCE(pa.type, 'unspecified')
+ORDER BY confirmed_expenses DESC, payment_method;
@@ -0,0 +1,70 @@
+-- name: ListExpenseAttachments :many
+-- ゴミ箱の支出の添付ファイルは返さない
+SELECT a.id, a.expense_id, a.file_name, a.content_type, a.size_bytes, a.storage_key, a.thumbnail_key, a.created_at
+FROM expense_attachments a
+JOIN expenses e ON e.id = a.expense_id
+WHERE e.ledger_id = $1
+  AND a.expense_id = $2
+  AND e.deleted_at IS NULL
+ORDER BY a.id;
+
+-- name: GetExpenseAttachment :one
+SELECT a.id, a.expense_id, a.file_name, a.content_type, a.size_bytes, a.storage_key, a.thumbnail_key, a.created_at
+FROM expense_attachments a
+JOIN expenses e ON e.id = a.expense_id
+WHERE e.ledger_id = $1
+  AND a.expense_id = $2
+  AND a.id = $3
+  AND e.deleted_at IS NULL;
+
+-- name: CreateExpenseAttachment :one
+INSERT INTO expense_attachments (
+  expense_id,
+  file_name,
+  content_type,
+  size_bytes,
+  storage_key,
+  thumbnail_key
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, created_at;
+
+-- name: DeleteExpenseAttachment :execrows
+-- 保存先のファイルは attachment_orphans に積まれ、ジョブが削除する
+DELETE FROM expense_attachments a
+USING expenses e
+WHERE e.id = a.expense_id
+  AND e.ledger_id = $1
+  AND a.expense_id = $2
+  AND a.id = $3
+  AND e.deleted_at IS NULL;
+
+-- name: ListAttachmentOrphans :many
+SELECT storage_key
+FROM attachment_orphans
+ORDER BY created_at, storage_key
+LIMIT $1;
+
+-- name: DeleteAttachmentOrphan :exec
+DELETE FROM attachment_orphans
+WHERE storage_key = $1;
+
+-- name: CreateAttachmentUpload :exec
+INSERT INTO attachment_uploads (storage_key, created_at)
+VALUES ($1, $2);
+
+-- name: DeleteAttachmentUpload :exec
+DELETE FROM attachment_uploads
+WHERE storage_key = $1;
+
+-- name: EnqueueAbandonedAttachmentUploads :execrows
+-- 猶予期間を過ぎても確定しなかったアップロード（リクエストがロールバックされたもの）を孤立したファイルとして積む
+WITH abandoned AS (
+  DELETE FROM attachment_uploads
+  WHERE created_at < $1
+  RETURNING storage_key
+)
+INSERT INTO attachment_orphans (storage_key)
+SELECT storage_key FROM abandoned
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,56 @@
+-- name: ListExpenseRefunds :many
+-- ゴミ箱の支出の返金は返さない
+SELECT
+  r.id,
+  r.expense_id,
+  r.amount,
+  r.refunded_at,
+  r.memo,
+  r.created_at
+FROM expense_refunds r
+JOIN expenses e ON e.id = r.expense_id
+WHERE r.expense_id = $1 AND e.ledger_id = $2 AND e.deleted_at IS NULL
+ORDER BY r.refunded_at, r.id;
+
+-- name: AddExpenseRefundedAmount :execrows
+-- 確定済みでゴミ箱に無い支出の返金の合計に amount を加える。合計が支出の金額を超える場合は更新しない
+UPDATE expenses
+SET
+  refunded_amount = refunded_amount + sqlc.arg(amount),
+  version = version + 1,
+  update_at = now()
+WHERE id = sqlc.arg(id)
+  AND ledger_id = sqlc.arg(ledger_id)
+  AND deleted_at IS NULL
+  AND status = 'confirmed'
+  AND refunded_amount + sqlc.arg(amount) <= amount;
+
+-- name: CreateExpenseRefund :one
+INSERT INTO expense_refunds (
+  expense_id,
+  amount,
+  refunded_at,
+  memo
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, expense_id, amount, refunded_at, memo, created_at;
+
+-- name: DeleteExpenseRefund :one
+-- ゴミ箱に無い支出の返金を削除し、削除した金額を返す
+DELETE FROM expense_refunds r
+USING expenses e
+WHERE r.id = $1
+  AND r.expense_id = $2
+  AND e.id = r.expense_id
+  AND e.ledger_id = $3
+  AND e.deleted_at IS NULL
+RETURNING r.amount;
+
+-- name: SubtractExpenseRefundedAmount :exec
+UPDATE expenses
+SET
+  refunded_amount = refunded_amount - sqlc.arg(amount),
+  version = version + 1,
+  update_at = now()
+WHERE id = sqlc.arg(id);
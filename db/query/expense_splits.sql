@@ -0,0 +1,26 @@
+-- name: ListExpenseSplits :many
+SELECT
+  s.expense_id,
+  s.amount,
+  s.memo,
+  c.id AS category_id,
+  c.name AS category_name
+FROM expense_splits s
+JOIN categories c ON s.category_id = c.id
+WHERE s.expense_id = ANY(sqlc.arg(expense_ids)::int[])
+ORDER BY s.expense_id, s.position;
+
+-- name: CreateExpenseSplit :exec
+INSERT INTO expense_splits (
+  expense_id,
+  position,
+  category_id,
+  amount,
+  memo
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: DeleteExpenseSplits :exec
+DELETE FROM expense_splits
+WHERE expense_id = $1;
@@ -5,25 +5,103 @@ INSERT INTO expenses (
   category_id,
   memo,
   spent_at,
-  status
+  status,
+  ledger_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7
 )
 RETURNING id;
 
 -- name: ListExpenses :many
-SELECT 
+-- ゴミ箱の支出は含めない。絞り込み条件は NULL なら無視する。内訳のある支出は内訳のカテゴリで絞り込む。sort_key は -spent_at / spent_at / -amount / amount で、
+-- 同じ値の行は id で順序を決める。cursor_id があれば (並び順のキー, id) がその行より後のものを返す
+SELECT
   e.id,
   e.amount,
   e.memo,
   e.spent_at,
   e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
   c.id AS category_id,
   c.name AS category_name
 FROM expenses e
 JOIN categories c ON e.category_id = c.id
-WHERE e.user_id = $1
-ORDER BY spent_at DESC;
+WHERE e.ledger_id = sqlc.arg(ledger_id)
+  AND e.deleted_at IS NULL
+  AND (sqlc.narg(from_date)::date IS NULL OR e.spent_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR e.spent_at <= sqlc.narg(to_date))
+  AND (
+    sqlc.narg(category_ids)::int[] IS NULL
+    OR EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id AND s.category_id = ANY(sqlc.narg(category_ids)::int[]))
+    OR (e.category_id = ANY(sqlc.narg(category_ids)::int[]) AND NOT EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id))
+  )
+  AND (
+    sqlc.narg(tag_ids)::int[] IS NULL
+    OR EXISTS (SELECT 1 FROM expense_tags et WHERE et.expense_id = e.id AND et.tag_id = ANY(sqlc.narg(tag_ids)::int[]))
+  )
+  AND (sqlc.narg(status)::text IS NULL OR e.status = sqlc.narg(status))
+  AND (sqlc.narg(min_amount)::int IS NULL OR e.amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::int IS NULL OR e.amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(memo)::text IS NULL OR strpos(lower(e.memo), lower(sqlc.narg(memo))) > 0)
+  AND (
+    sqlc.narg(cursor_id)::int IS NULL
+    OR (sqlc.arg(sort_key)::text = '-spent_at' AND (e.spent_at, e.id) < (sqlc.narg(cursor_spent_at)::date, sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort_key) = 'spent_at' AND (e.spent_at, e.id) > (sqlc.narg(cursor_spent_at), sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort_key) = '-amount' AND (e.amount, e.id) < (sqlc.narg(cursor_amount)::int, sqlc.narg(cursor_id)))
+    OR (sqlc.arg(sort_key) = 'amount' AND (e.amount, e.id) > (sqlc.narg(cursor_amount), sqlc.narg(cursor_id)))
+  )
+ORDER BY
+  CASE WHEN sqlc.arg(sort_key) = 'spent_at' THEN e.spent_at END ASC,
+  CASE WHEN sqlc.arg(sort_key) = '-spent_at' THEN e.spent_at END DESC,
+  CASE WHEN sqlc.arg(sort_key) = 'amount' THEN e.amount END ASC,
+  CASE WHEN sqlc.arg(sort_key) = '-amount' THEN e.amount END DESC,
+  CASE WHEN sqlc.arg(sort_key) IN ('spent_at', 'amount') THEN e.id END ASC,
+  CASE WHEN sqlc.arg(sort_key) IN ('-spent_at', '-amount') THEN e.id END DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: SearchExpenses :many
+-- terms をすべて含むメモの支出を、メモに占める一致部分の割合（score）が高い順に返す。
+-- 文字の 1-gram・2-gram の索引で候補を絞り込み、各語が連続して含まれるかを確かめる
+WITH q AS (
+  SELECT COALESCE(array_agg(DISTINCT g), '{}')::text[] AS grams
+  FROM unnest(sqlc.arg(terms)::text[]) AS t, unnest(expense_memo_ngrams(t)) AS g
+)
+SELECT
+  e.id,
+  e.amount,
+  e.memo,
+  e.spent_at,
+  e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
+  c.id AS category_id,
+  c.name AS category_name,
+  (cardinality(q.grams)::float8 / GREATEST(cardinality(expense_memo_ngrams(e.memo)), 1))::float8 AS score
+FROM expenses e
+JOIN categories c ON e.category_id = c.id
+CROSS JOIN q
+WHERE e.ledger_id = sqlc.arg(ledger_id)
+  AND e.deleted_at IS NULL
+  AND expense_memo_ngrams(e.memo) @> q.grams
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.arg(terms)::text[]) AS t
+    WHERE strpos(expense_memo_normalize(e.memo), expense_memo_normalize(t)) = 0
+  )
+  AND (
+    sqlc.narg(category_ids)::int[] IS NULL
+    OR EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id AND s.category_id = ANY(sqlc.narg(category_ids)::int[]))
+    OR (e.category_id = ANY(sqlc.narg(category_ids)::int[]) AND NOT EXISTS (SELECT 1 FROM expense_splits s WHERE s.expense_id = e.id))
+  )
+  AND (sqlc.narg(status)::text IS NULL OR e.status = sqlc.narg(status))
+ORDER BY score DESC, e.spent_at DESC, e.id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: GetExpenseWithCategoryByID :one
 SELECT
@@ -32,11 +110,16 @@ SELECT
   e.memo,
   e.spent_at,
   e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
   c.id AS category_id,
   c.name AS category_name
 FROM expenses e
 JOIN categories c ON e.category_id = c.id
-WHERE e.user_id = $1 AND e.id = $2;
+WHERE e.ledger_id = $1 AND e.id = $2 AND e.deleted_at IS NULL;
 
 -- name: GetExpenseByID :one
 SELECT
@@ -47,26 +130,118 @@ SELECT
   spent_at,
   status
 FROM expenses
-WHERE user_id = $1 AND id = $2;
+WHERE ledger_id = $1 AND id = $2 AND deleted_at IS NULL;
+
+-- name: UpdateExpense :execrows
+-- planned から confirmed に変わる場合は ConfirmExpense と同じく確定日時と予定時の金額・日付を記録する。
+-- version を指定した場合は、現在の version と一致する行だけを更新する
+UPDATE expenses
+SET
+  amount = sqlc.arg(amount),
+  category_id = sqlc.arg(category_id),
+  memo = sqlc.arg(memo),
+  spent_at = sqlc.arg(spent_at),
+  status = sqlc.arg(status),
+  confirmed_at = CASE WHEN status = 'planned' AND sqlc.arg(status) = 'confirmed' THEN now() ELSE confirmed_at END,
+  planned_amount = CASE WHEN status = 'planned' AND sqlc.arg(status) = 'confirmed' THEN amount ELSE planned_amount END,
+  planned_spent_at = CASE WHEN status = 'planned' AND sqlc.arg(status) = 'confirmed' THEN spent_at ELSE planned_spent_at END,
+  version = version + 1,
+  update_at = now()
+WHERE id = sqlc.arg(id) AND ledger_id = sqlc.arg(ledger_id) AND deleted_at IS NULL
+  AND (sqlc.narg(version)::int IS NULL OR version = sqlc.narg(version));
 
--- name: UpdateExpense :exec
+-- name: ConfirmExpense :execrows
+-- planned の支出を confirmed にし、確定日時と予定していた金額・日付を記録する。
+-- 実際の金額・日付（amount・spent_at）を指定した場合はそれで置き換える。planned 以外の行は変更しない
 UPDATE expenses
 SET
-  amount = $2,
-  category_id = $3,
-  memo = $4,
-  spent_at = $5,
-  status = $6,
+  status = 'confirmed',
+  confirmed_at = now(),
+  planned_amount = amount,
+  planned_spent_at = spent_at,
+  amount = COALESCE(sqlc.narg(amount), amount),
+  spent_at = COALESCE(sqlc.narg(spent_at), spent_at),
+  version = version + 1,
   update_at = now()
-WHERE id = $1 AND user_id = $7;
+WHERE id = sqlc.arg(id) AND ledger_id = sqlc.arg(ledger_id) AND status = 'planned' AND deleted_at IS NULL;
 
--- name: UpdateExpenseStatus :exec
+-- name: TrashExpense :execrows
+-- 支出をゴミ箱に移す。行は PurgeTrashedExpenses で保存期間を過ぎてから削除する。
+-- version を指定した場合は、現在の version と一致する行だけを移す
 UPDATE expenses
 SET
-  status = $2,
+  deleted_at = now(),
+  version = version + 1,
   update_at = now()
-WHERE id = $1 AND user_id = $3;
+WHERE id = sqlc.arg(id) AND ledger_id = sqlc.arg(ledger_id) AND deleted_at IS NULL
+  AND (sqlc.narg(version)::int IS NULL OR version = sqlc.narg(version));
 
--- name: DeleteExpense :exec
+-- name: ListTrashedExpenses :many
+-- ゴミ箱の支出を、ゴミ箱に移した日時が新しい順に返す
+SELECT
+  e.id,
+  e.amount,
+  e.memo,
+  e.spent_at,
+  e.status,
+  e.confirmed_at,
+  e.planned_amount,
+  e.planned_spent_at,
+  e.version,
+  e.refunded_amount,
+  e.deleted_at,
+  c.id AS category_id,
+  c.name AS category_name
+FROM expenses e
+JOIN categories c ON e.category_id = c.id
+WHERE e.ledger_id = $1 AND e.deleted_at IS NOT NULL
+ORDER BY e.deleted_at DESC, e.id DESC;
+
+-- name: RestoreExpense :execrows
+UPDATE expenses
+SET
+  deleted_at = NULL,
+  version = version + 1,
+  update_at = now()
+WHERE id = $1 AND ledger_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: PurgeTrashedExpenses :execrows
+-- ゴミ箱に移してから保存期間を過ぎた支出を家計簿を問わず削除する
 DELETE FROM expenses
-WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+WHERE deleted_at < $1;
+-- name: ListExpensesByUser :many
+-- 運用者によるデータの書き出し用。家計簿を問わず user が登録した支出を返す
+SELECT
+  id,
+  user_id,
+  amount,
+  category_id,
+  memo,
+  spent_at,
+  status,
+  created_at,
+  update_at,
+  ledger_id,
+  confirmed_at,
+  planned_amount,
+  planned_spent_at,
+  deleted_at,
+  version,
+  refunded_amount
+FROM expenses
+WHERE user_id = $1
+ORDER BY spent_at ASC, id ASC;
+
+-- name: PurgeExpensesByUser :execrows
+-- 他のメンバーがいない家計簿の支出だけを削除する（共有中の家計簿の支出は AnonymizeExpensesByUser で残す）
+DELETE FROM expenses e
+WHERE e.user_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = e.ledger_id AND m.user_id <> $1
+  );
+
+-- name: AnonymizeExpensesByUser :execrows
+-- 共有中の家計簿の支出は残し、登録したユーザーとの紐付けだけを外す
+UPDATE expenses
+SET user_id = NULL
+WHERE user_id = $1;
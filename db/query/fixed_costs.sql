@@ -2,38 +2,56 @@
 INSERT INTO fixed_costs (
   user_id,
   name,
-  amount
+  amount,
+  ledger_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 )
 RETURNING *;
 
--- name: ListFixedCostsByUser :many
+-- name: ListFixedCostsByLedger :many
 SELECT 
   id,
   user_id,
   name,
   amount,
   created_at,
-  updated_at
+  updated_at,
+  ledger_id
+FROM fixed_costs
+WHERE ledger_id = $1
+ORDER BY id ASC;
+
+-- name: ListFixedCostsByUser :many
+-- 運用者によるデータの書き出し用。家計簿を問わず user が登録した固定費を返す
+SELECT
+  id,
+  user_id,
+  name,
+  amount,
+  created_at,
+  updated_at,
+  ledger_id
 FROM fixed_costs
 WHERE user_id = $1
 ORDER BY id ASC;
 
--- name: DeleteFixedCostsByUser :exec
+-- name: DeleteFixedCostsByLedger :exec
 DELETE FROM fixed_costs
-WHERE user_id = $1;
+WHERE ledger_id = $1;
 
 -- name: BulkCreateFixedCosts :exec
 INSERT INTO fixed_costs (
+  ledger_id,
   user_id,
   name,
   amount
 )
-SELECT u.user_id, n.name, a.amount
-FROM UNNEST($1::text[]) WITH ORDINALITY AS u(user_id, ord)
-JOIN UNNEST($2::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
-JOIN UNNEST($3::int[]) WITH ORDINALITY AS a(amount, ord) USING (ord);
+SELECT l.ledger_id, u.user_id, n.name, a.amount
+FROM UNNEST($1::int[]) WITH ORDINALITY AS l(ledger_id, ord)
+JOIN UNNEST($2::text[]) WITH ORDINALITY AS u(user_id, ord) USING (ord)
+JOIN UNNEST($3::text[]) WITH ORDINALITY AS n(name, ord) USING (ord)
+JOIN UNNEST($4::int[]) WITH ORDINALITY AS a(amount, ord) USING (ord);
 
 -- name: UpdateFixedCost :exec
 UPDATE fixed_costs
@@ -41,8 +59,21 @@ SET
   name = $2,
   amount = $3,
   updated_at = now()
-WHERE id = $1 AND user_id = $4;
+WHERE id = $1 AND ledger_id = $4;
 
 -- name: DeleteFixedCost :exec
 DELETE FROM fixed_costs
-WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+WHERE id = $1 AND ledger_id = $2;
+-- name: PurgeFixedCostsByUser :execrows
+-- 他のメンバーがいない家計簿の固定費だけを削除する（共有中の家計簿の固定費は AnonymizeFixedCostsByUser で残す）
+DELETE FROM fixed_costs fc
+WHERE fc.user_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = fc.ledger_id AND m.user_id <> $1
+  );
+
+-- name: AnonymizeFixedCostsByUser :execrows
+-- 共有中の家計簿の固定費は残し、登録したユーザーとの紐付けだけを外す
+UPDATE fixed_costs
+SET user_id = NULL
+WHERE user_id = $1;
@@ -0,0 +1,38 @@
+-- name: GetIdempotencyKey :one
+SELECT user_id, idempotency_key, request_hash, response_status, response_headers, response_body, created_at, expires_at
+FROM idempotency_keys
+WHERE user_id = $1
+  AND idempotency_key = $2
+  AND expires_at > sqlc.arg(now);
+
+-- name: SaveIdempotencyKey :execrows
+-- 期限切れで削除前のキーは上書きする。有効期限内の同じキーがある場合は何もしない
+-- 期限切れの判定には DB の now() ではなく、アプリが UTC で渡す created_at を使う
+INSERT INTO idempotency_keys (
+  user_id,
+  idempotency_key,
+  request_hash,
+  response_status,
+  response_headers,
+  response_body,
+  created_at,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (user_id, idempotency_key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    response_status = EXCLUDED.response_status,
+    response_headers = EXCLUDED.response_headers,
+    response_body = EXCLUDED.response_body,
+    created_at = EXCLUDED.created_at,
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= EXCLUDED.created_at;
+
+-- name: PurgeExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= $1;
+
+-- name: PurgeIdempotencyKeysByUser :execrows
+DELETE FROM idempotency_keys
+WHERE user_id = $1;
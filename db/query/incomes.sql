@@ -0,0 +1,96 @@
+-- name: CreateIncome :one
+INSERT INTO incomes (
+  ledger_id,
+  user_id,
+  source,
+  amount,
+  received_at,
+  status
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, ledger_id, user_id, source, amount, received_at, status, created_at, updated_at;
+
+-- name: GetIncome :one
+SELECT
+  id,
+  ledger_id,
+  user_id,
+  source,
+  amount,
+  received_at,
+  status,
+  created_at,
+  updated_at
+FROM incomes
+WHERE id = $1 AND ledger_id = $2;
+
+-- name: ListIncomes :many
+-- 受け取った日の新しい順に返す。絞り込み条件は NULL なら無視する
+SELECT
+  id,
+  ledger_id,
+  user_id,
+  source,
+  amount,
+  received_at,
+  status,
+  created_at,
+  updated_at
+FROM incomes
+WHERE ledger_id = sqlc.arg(ledger_id)
+  AND (sqlc.narg(from_date)::date IS NULL OR received_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR received_at < sqlc.narg(to_date))
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+ORDER BY received_at DESC, id DESC;
+
+-- name: UpdateIncome :one
+UPDATE incomes
+SET
+  source = $3,
+  amount = $4,
+  received_at = $5,
+  status = $6,
+  updated_at = now()
+WHERE id = $1 AND ledger_id = $2
+RETURNING id, ledger_id, user_id, source, amount, received_at, status, created_at, updated_at;
+
+-- name: DeleteIncome :execrows
+DELETE FROM incomes
+WHERE id = $1 AND ledger_id = $2;
+
+-- name: GetMonthlyIncomeSummary :one
+-- month を含む月の収入を集計する。その月の収入を登録したメンバーは登録した収入（予定を含む）を、
+-- 登録していないメンバーは users.income を見込みとして income に合算する
+SELECT
+  COALESCE(SUM(CASE WHEN mi.income_count > 0 THEN mi.received + mi.planned ELSE u.income END), 0)::bigint AS income,
+  COALESCE(SUM(mi.received), 0)::bigint AS received_income,
+  COALESCE(SUM(mi.planned), 0)::bigint AS planned_income,
+  COALESCE(SUM(u.income), 0)::bigint AS expected_income
+FROM ledger_members m
+JOIN users u ON u.id = m.user_id
+CROSS JOIN LATERAL (
+  SELECT
+    COUNT(*) AS income_count,
+    COALESCE(SUM(CASE WHEN i.status = 'confirmed' THEN i.amount ELSE 0 END), 0) AS received,
+    COALESCE(SUM(CASE WHEN i.status = 'planned' THEN i.amount ELSE 0 END), 0) AS planned
+  FROM incomes i
+  WHERE i.ledger_id = m.ledger_id
+    AND i.user_id = m.user_id
+    AND DATE_TRUNC('month', i.received_at) = DATE_TRUNC('month', sqlc.arg(month)::date)
+) mi
+WHERE m.ledger_id = sqlc.arg(ledger_id);
+
+-- name: PurgeIncomesByUser :execrows
+-- 他のメンバーがいない家計簿の収入だけを削除する（共有中の家計簿の収入は AnonymizeIncomesByUser で残す）
+DELETE FROM incomes i
+WHERE i.user_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = i.ledger_id AND m.user_id <> $1
+  );
+
+-- name: AnonymizeIncomesByUser :execrows
+-- 共有中の家計簿の収入は残し、登録したユーザーとの紐付けだけを外す
+UPDATE incomes
+SET user_id = NULL
+WHERE user_id = $1;
@@ -0,0 +1,150 @@
+-- name: CreateLedger :one
+INSERT INTO ledgers (
+  name
+) VALUES (
+  $1
+)
+RETURNING *;
+
+-- name: ListLedgersByUser :many
+SELECT
+  l.id,
+  l.name,
+  l.created_at,
+  l.updated_at,
+  (SELECT COUNT(*) FROM ledger_members mm WHERE mm.ledger_id = l.id) AS member_count,
+  COALESCE(u.active_ledger_id = l.id, false)::boolean AS active,
+  m.role
+FROM ledger_members m
+JOIN ledgers l ON l.id = m.ledger_id
+JOIN users u ON u.id = m.user_id
+WHERE m.user_id = $1
+ORDER BY m.joined_at ASC, l.id ASC;
+
+-- name: GetLedgerForMember :one
+SELECT
+  l.id,
+  l.name,
+  l.created_at,
+  l.updated_at,
+  (SELECT COUNT(*) FROM ledger_members mm WHERE mm.ledger_id = l.id) AS member_count,
+  COALESCE(u.active_ledger_id = l.id, false)::boolean AS active,
+  m.role
+FROM ledger_members m
+JOIN ledgers l ON l.id = m.ledger_id
+JOIN users u ON u.id = m.user_id
+WHERE m.user_id = $1 AND m.ledger_id = $2;
+
+-- name: DeleteLedger :exec
+DELETE FROM ledgers
+WHERE id = $1;
+
+-- name: AddLedgerMember :execrows
+INSERT INTO ledger_members (
+  ledger_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveLedgerMember :execrows
+DELETE FROM ledger_members
+WHERE ledger_id = $1 AND user_id = $2;
+
+-- name: GetLedgerMemberRole :one
+SELECT role
+FROM ledger_members
+WHERE ledger_id = $1 AND user_id = $2;
+
+-- name: ListLedgerMembers :many
+SELECT user_id, role, joined_at
+FROM ledger_members
+WHERE ledger_id = $1
+ORDER BY joined_at ASC, user_id ASC;
+
+-- name: UpdateLedgerMemberRole :one
+UPDATE ledger_members
+SET role = $3
+WHERE ledger_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: CountLedgerMembers :one
+SELECT COUNT(*)
+FROM ledger_members
+WHERE ledger_id = $1;
+
+-- name: GetActiveLedgerID :one
+-- 退出済みの家計簿を指していないよう、メンバーシップと突き合わせる
+SELECT m.ledger_id
+FROM users u
+JOIN ledger_members m ON m.ledger_id = u.active_ledger_id AND m.user_id = u.id
+WHERE u.id = $1;
+
+-- name: SetActiveLedger :exec
+UPDATE users
+SET
+  active_ledger_id = $2,
+  updated_at = now()
+WHERE id = $1;
+
+-- name: ReassignActiveLedger :exec
+-- 退出した家計簿がアクティブだった場合、残っている最も古い家計簿に切り替える（無ければ NULL）
+UPDATE users
+SET
+  active_ledger_id = (
+    SELECT m.ledger_id
+    FROM ledger_members m
+    WHERE m.user_id = users.id
+    ORDER BY m.joined_at ASC, m.ledger_id ASC
+    LIMIT 1
+  ),
+  updated_at = now()
+WHERE id = $1 AND active_ledger_id = $2;
+
+-- name: CreateLedgerInvite :one
+INSERT INTO ledger_invites (
+  ledger_id,
+  code_hash,
+  created_by,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ClaimLedgerInvite :one
+-- 未使用かつ有効期限内の招待コードだけを使用済みにする（同時に使われても 1 回しか成功しない）
+-- expires_at はアプリが UTC で設定するため、DB の now() ではなくアプリが UTC で渡す時刻と比べる
+UPDATE ledger_invites
+SET
+  used_by = $2,
+  used_at = sqlc.arg(now)::timestamp
+WHERE code_hash = $1
+  AND used_at IS NULL
+  AND expires_at > sqlc.arg(now)::timestamp
+RETURNING ledger_id;
+
+-- name: PurgeSoleMemberLedgersByUser :execrows
+-- 他のメンバーがいない家計簿だけを削除する（共有中の家計簿は残す）
+DELETE FROM ledgers l
+WHERE EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = l.id AND m.user_id = $1
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = l.id AND m.user_id <> $1
+  );
+
+-- name: PromoteLedgerSuccessorsByUser :execrows
+-- user が owner の共有家計簿で、最も古くから参加しているメンバーを owner にする
+UPDATE ledger_members m
+SET role = 'owner'
+FROM (
+  SELECT DISTINCT ON (s.ledger_id) s.ledger_id, s.user_id
+  FROM ledger_members s
+  JOIN ledger_members o ON o.ledger_id = s.ledger_id AND o.user_id = $1 AND o.role = 'owner'
+  WHERE s.user_id <> $1
+  ORDER BY s.ledger_id, s.joined_at ASC, s.user_id ASC
+) successor
+WHERE m.ledger_id = successor.ledger_id AND m.user_id = successor.user_id;
@@ -0,0 +1,62 @@
+-- name: CreateLocalAccount :execrows
+-- メールアドレスが登録済みの場合は何もしない
+INSERT INTO local_accounts (
+  user_id,
+  email,
+  password_hash
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (email) DO NOTHING;
+
+-- name: GetLocalAccountByEmail :one
+SELECT * FROM local_accounts
+WHERE email = $1;
+
+-- name: GetLocalAccountByUserID :one
+SELECT * FROM local_accounts
+WHERE user_id = $1;
+
+-- name: UpdateLocalAccountPassword :exec
+UPDATE local_accounts
+SET password_hash = $2, password_changed_at = now()
+WHERE user_id = $1;
+
+-- name: PurgeLocalAccountsByUser :execrows
+DELETE FROM local_accounts
+WHERE user_id = $1;
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (
+  user_id,
+  family_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: GetRefreshTokenByHashForUpdate :one
+-- 同じトークンでの同時の交換を直列化するため行をロックする
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: RevokeRefreshToken :exec
+UPDATE refresh_tokens
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked_at = now()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokensByUser :execrows
+UPDATE refresh_tokens
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: PurgeRefreshTokensByUser :execrows
+DELETE FROM refresh_tokens
+WHERE user_id = $1;
@@ -0,0 +1,107 @@
+-- name: ListPayeeSuggestions :many
+-- 名前が prefix で始まる支払先を、よく・最近使ったものから返す。名前は全角・半角や大文字・小文字、空白を揃えて比べる。
+-- 使った支出 1 件ごとに、支出日から 30 日経つごとに半減する重みを足した値の大きい順に並べる（ゴミ箱の支出は数えない）
+SELECT
+  p.id,
+  p.name,
+  COUNT(e.id)::int AS use_count,
+  MAX(e.spent_at) AS last_used_at
+FROM payees p
+LEFT JOIN expense_payees ep ON ep.payee_id = p.id
+LEFT JOIN expenses e ON e.id = ep.expense_id AND e.deleted_at IS NULL
+WHERE p.user_id = sqlc.arg(user_id)
+  AND starts_with(expense_memo_normalize(p.name), expense_memo_normalize(sqlc.arg(prefix)))
+GROUP BY p.id, p.name
+ORDER BY
+  COALESCE(SUM(power(0.5, GREATEST(CURRENT_DATE - e.spent_at, 0) / 30.0)), 0) DESC,
+  p.name,
+  p.id
+LIMIT sqlc.arg(max_rows);
+
+-- name: ListPayeesByIDs :many
+SELECT
+  id,
+  name
+FROM payees
+WHERE user_id = sqlc.arg(user_id) AND id = ANY(sqlc.arg(ids)::int[])
+ORDER BY name, id;
+
+-- name: CreatePayee :one
+-- 同じ名前の支払先が既にある場合は何も返さない
+INSERT INTO payees (
+  user_id,
+  name
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id, name) DO NOTHING
+RETURNING id, name;
+
+-- name: RenamePayee :one
+-- 同じ名前の別の支払先が既にある場合は更新しない
+UPDATE payees
+SET
+  name = sqlc.arg(name),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM payees o WHERE o.user_id = sqlc.arg(user_id) AND o.name = sqlc.arg(name) AND o.id <> sqlc.arg(id)
+  )
+RETURNING id, name;
+
+-- name: DeletePayee :execrows
+-- 支出との関連は外部キーの ON DELETE CASCADE で消える
+DELETE FROM payees
+WHERE id = $1 AND user_id = $2;
+
+-- name: MergePayeeLinks :exec
+-- source_id の支払先が付いた支出の支払先を target_id にする
+UPDATE expense_payees
+SET payee_id = sqlc.arg(target_id)
+WHERE payee_id = sqlc.arg(source_id);
+
+-- name: ListExpensePayees :many
+SELECT
+  ep.expense_id,
+  p.id,
+  p.name
+FROM expense_payees ep
+JOIN payees p ON ep.payee_id = p.id
+WHERE ep.expense_id = ANY(sqlc.arg(expense_ids)::int[])
+ORDER BY ep.expense_id;
+
+-- name: SetExpensePayee :exec
+INSERT INTO expense_payees (
+  expense_id,
+  payee_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (expense_id) DO UPDATE SET payee_id = EXCLUDED.payee_id;
+
+-- name: DeleteExpensePayee :exec
+DELETE FROM expense_payees
+WHERE expense_id = $1;
+
+-- name: ListPayeeTotals :many
+-- ゴミ箱の支出は含めない。from_date 以降・to_date より前の支出を支払先ごとに集計し、確定済みの合計（返金を差し引いた額）の大きい順に返す
+SELECT
+  p.id AS payee_id,
+  p.name AS payee_name,
+  COUNT(*)::int AS expense_count,
+  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount - e.refunded_amount ELSE 0 END), 0)::bigint AS confirmed_total,
+  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0)::bigint AS planned_total
+FROM expense_payees ep
+JOIN payees p ON p.id = ep.payee_id
+JOIN expenses e ON e.id = ep.expense_id
+WHERE e.ledger_id = sqlc.arg(ledger_id)
+  AND e.deleted_at IS NULL
+  AND (sqlc.narg(from_date)::date IS NULL OR e.spent_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR e.spent_at < sqlc.narg(to_date))
+GROUP BY p.id, p.name
+ORDER BY confirmed_total DESC, p.name, p.id;
+
+-- name: PurgePayeesByUser :execrows
+DELETE FROM payees
+WHERE user_id = $1;
@@ -0,0 +1,121 @@
+-- name: ListPaymentAccounts :many
+-- 残高は開始残高から、付けた確定済みの支出（ゴミ箱のものを除く）の返金を除いた額を差し引いたもの
+SELECT
+  pa.id,
+  pa.name,
+  pa.type,
+  pa.opening_balance,
+  (pa.opening_balance - COALESCE((
+    SELECT SUM(e.amount - e.refunded_amount)
+    FROM expense_payment_accounts epa
+    JOIN expenses e ON e.id = epa.expense_id
+    WHERE epa.payment_account_id = pa.id AND e.status = 'confirmed' AND e.deleted_at IS NULL
+  ), 0))::bigint AS balance
+FROM payment_accounts pa
+WHERE pa.user_id = $1
+ORDER BY pa.name, pa.id;
+
+-- name: GetPaymentAccount :one
+SELECT
+  pa.id,
+  pa.name,
+  pa.type,
+  pa.opening_balance,
+  (pa.opening_balance - COALESCE((
+    SELECT SUM(e.amount - e.refunded_amount)
+    FROM expense_payment_accounts epa
+    JOIN expenses e ON e.id = epa.expense_id
+    WHERE epa.payment_account_id = pa.id AND e.status = 'confirmed' AND e.deleted_at IS NULL
+  ), 0))::bigint AS balance
+FROM payment_accounts pa
+WHERE pa.id = $1 AND pa.user_id = $2;
+
+-- name: CreatePaymentAccount :one
+-- 同じ名前の支払い手段が既にある場合は何も返さない
+INSERT INTO payment_accounts (
+  user_id,
+  name,
+  type,
+  opening_balance
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_id, name) DO NOTHING
+RETURNING id;
+
+-- name: UpdatePaymentAccount :execrows
+-- 同じ名前の別の支払い手段が既にある場合は更新しない
+UPDATE payment_accounts
+SET
+  name = sqlc.arg(name),
+  type = sqlc.arg(type),
+  opening_balance = sqlc.arg(opening_balance),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_accounts o WHERE o.user_id = sqlc.arg(user_id) AND o.name = sqlc.arg(name) AND o.id <> sqlc.arg(id)
+  );
+
+-- name: DeletePaymentAccount :execrows
+-- 支出との関連は外部キーの ON DELETE CASCADE で消える
+DELETE FROM payment_accounts
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListExpensePaymentAccounts :many
+SELECT
+  epa.expense_id,
+  pa.id,
+  pa.name,
+  pa.type
+FROM expense_payment_accounts epa
+JOIN payment_accounts pa ON epa.payment_account_id = pa.id
+WHERE epa.expense_id = ANY(sqlc.arg(expense_ids)::int[])
+ORDER BY epa.expense_id;
+
+-- name: SetExpensePaymentAccount :exec
+INSERT INTO expense_payment_accounts (
+  expense_id,
+  payment_account_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (expense_id) DO UPDATE SET payment_account_id = EXCLUDED.payment_account_id;
+
+-- name: DeleteExpensePaymentAccount :exec
+DELETE FROM expense_payment_accounts
+WHERE expense_id = $1;
+
+-- name: ListPaymentAccountEntries :many
+-- 確定済みの支出（ゴミ箱のものを除く）を支出日・ID の順に並べ、それぞれの返金を除いた額を差し引いた後の残高を付けて返す。
+-- 残高は期間より前の支出も含めて計算してから、from_date 以降・to_date より前に絞り込む
+SELECT
+  t.expense_id,
+  t.spent_at,
+  t.amount,
+  t.refunded_amount,
+  t.memo,
+  t.balance
+FROM (
+  SELECT
+    e.id AS expense_id,
+    e.spent_at,
+    e.amount,
+    e.refunded_amount,
+    COALESCE(e.memo, '') AS memo,
+    (pa.opening_balance - SUM(e.amount - e.refunded_amount) OVER (ORDER BY e.spent_at, e.id))::bigint AS balance
+  FROM payment_accounts pa
+  JOIN expense_payment_accounts epa ON epa.payment_account_id = pa.id
+  JOIN expenses e ON e.id = epa.expense_id
+  WHERE pa.id = sqlc.arg(id)
+    AND pa.user_id = sqlc.arg(user_id)
+    AND e.status = 'confirmed'
+    AND e.deleted_at IS NULL
+) t
+WHERE (sqlc.narg(from_date)::date IS NULL OR t.spent_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR t.spent_at < sqlc.narg(to_date))
+ORDER BY t.spent_at, t.expense_id;
+
+-- name: PurgePaymentAccountsByUser :execrows
+DELETE FROM payment_accounts
+WHERE user_id = $1;
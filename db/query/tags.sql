@@ -0,0 +1,104 @@
+-- name: ListTags :many
+SELECT
+  id,
+  name
+FROM tags
+WHERE user_id = $1
+ORDER BY name, id;
+
+-- name: ListTagsByIDs :many
+SELECT
+  id,
+  name
+FROM tags
+WHERE user_id = sqlc.arg(user_id) AND id = ANY(sqlc.arg(ids)::int[])
+ORDER BY name, id;
+
+-- name: CreateTag :one
+-- 同じ名前のタグが既にある場合は何も返さない
+INSERT INTO tags (
+  user_id,
+  name
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id, name) DO NOTHING
+RETURNING id, name;
+
+-- name: RenameTag :one
+-- 同じ名前の別のタグが既にある場合は更新しない
+UPDATE tags
+SET
+  name = sqlc.arg(name),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM tags o WHERE o.user_id = sqlc.arg(user_id) AND o.name = sqlc.arg(name) AND o.id <> sqlc.arg(id)
+  )
+RETURNING id, name;
+
+-- name: DeleteTag :execrows
+-- 支出との関連は外部キーの ON DELETE CASCADE で消える
+DELETE FROM tags
+WHERE id = $1 AND user_id = $2;
+
+-- name: MergeTagLinks :exec
+-- source_id のタグが付いた支出に target_id のタグを付ける。既に両方付いていた支出はそのまま
+INSERT INTO expense_tags (
+  expense_id,
+  tag_id
+)
+SELECT et.expense_id, sqlc.arg(target_id)::int
+FROM expense_tags et
+WHERE et.tag_id = sqlc.arg(source_id)
+ON CONFLICT (expense_id, tag_id) DO NOTHING;
+
+-- name: ListExpenseTags :many
+SELECT
+  et.expense_id,
+  t.id,
+  t.name
+FROM expense_tags et
+JOIN tags t ON et.tag_id = t.id
+WHERE t.user_id = sqlc.arg(user_id) AND et.expense_id = ANY(sqlc.arg(expense_ids)::int[])
+ORDER BY et.expense_id, t.name, t.id;
+
+-- name: DeleteExpenseTags :exec
+-- 他のユーザーが同じ支出に付けたタグは残す
+DELETE FROM expense_tags et
+USING tags t
+WHERE et.tag_id = t.id AND et.expense_id = $1 AND t.user_id = $2;
+
+-- name: CreateExpenseTags :exec
+INSERT INTO expense_tags (
+  expense_id,
+  tag_id
+)
+SELECT sqlc.arg(expense_id)::int, unnest(sqlc.arg(tag_ids)::int[])
+ON CONFLICT (expense_id, tag_id) DO NOTHING;
+
+-- name: ListTagMonthlyTotals :many
+-- ゴミ箱の支出は含めない。from_date 以降・to_date より前の支出を、タグと月（spent_at の月初）ごとに集計する。
+-- 確定済みの合計は返金を差し引いた額
+SELECT
+  t.id AS tag_id,
+  t.name AS tag_name,
+  DATE_TRUNC('month', e.spent_at)::date AS month,
+  COUNT(*)::int AS expense_count,
+  COALESCE(SUM(CASE WHEN e.status = 'confirmed' THEN e.amount - e.refunded_amount ELSE 0 END), 0)::bigint AS confirmed_total,
+  COALESCE(SUM(CASE WHEN e.status = 'planned' THEN e.amount ELSE 0 END), 0)::bigint AS planned_total
+FROM tags t
+JOIN expense_tags et ON et.tag_id = t.id
+JOIN expenses e ON e.id = et.expense_id
+WHERE t.user_id = sqlc.arg(user_id)
+  AND e.ledger_id = sqlc.arg(ledger_id)
+  AND e.deleted_at IS NULL
+  AND (sqlc.narg(from_date)::date IS NULL OR e.spent_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::date IS NULL OR e.spent_at < sqlc.narg(to_date))
+GROUP BY t.id, t.name, DATE_TRUNC('month', e.spent_at)
+ORDER BY month, t.name, t.id;
+
+-- name: PurgeTagsByUser :execrows
+DELETE FROM tags
+WHERE user_id = $1;
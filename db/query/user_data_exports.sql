@@ -0,0 +1,18 @@
+-- name: CreateUserDataExport :one
+INSERT INTO user_data_exports (
+  user_id,
+  requested_by,
+  payload
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetUserDataExport :one
+SELECT id, user_id, requested_by, payload, created_at
+FROM user_data_exports
+WHERE id = $1;
+
+-- name: PurgeUserDataExportsByUser :execrows
+DELETE FROM user_data_exports
+WHERE user_id = $1;
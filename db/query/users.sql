@@ -13,14 +13,137 @@ SELECT
     income,
     saving_goal,
     created_at,
-    updated_at
+    updated_at,
+    deletion_requested_at,
+    deletion_scheduled_at,
+    active_ledger_id,
+    setup_completed_at,
+    disabled_at
 FROM users
 WHERE id = $1;
 
+-- name: ProvisionUser :execrows
+-- 認証済みユーザーの初回アクセス時に行を作成する（既に存在する場合は何もしない）
+INSERT INTO users (
+    id
+) VALUES (
+    $1
+)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: MarkUserSetupCompleted :exec
+UPDATE users
+SET
+    setup_completed_at = COALESCE(setup_completed_at, now()),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: GetUserOnboarding :one
+SELECT
+    u.setup_completed_at,
+    (u.active_ledger_id IS NOT NULL)::boolean AS has_ledger,
+    EXISTS (
+        SELECT 1 FROM fixed_costs f WHERE f.ledger_id = u.active_ledger_id
+    ) AS has_fixed_costs,
+    EXISTS (
+        SELECT 1 FROM expenses e WHERE e.user_id = u.id AND e.deleted_at IS NULL
+    ) AS has_expenses
+FROM users u
+WHERE u.id = $1;
+
 -- name: UpdateUserSettings :exec
 UPDATE users
 SET
     income = $2,
     saving_goal = $3,
     updated_at = now()
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+-- name: RequestUserDeletion :execrows
+UPDATE users
+SET
+    deletion_requested_at = now(),
+    deletion_scheduled_at = $2,
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NULL;
+
+-- name: CancelUserDeletion :execrows
+UPDATE users
+SET
+    deletion_requested_at = NULL,
+    deletion_scheduled_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND deletion_requested_at IS NOT NULL;
+
+-- name: ListUsersDueForDeletion :many
+SELECT id
+FROM users
+WHERE deletion_scheduled_at <= $1
+ORDER BY deletion_scheduled_at ASC
+LIMIT $2;
+
+-- name: LockUserForDeletion :one
+SELECT deletion_requested_at
+FROM users
+WHERE id = $1 AND deletion_scheduled_at <= $2
+FOR UPDATE;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
+
+-- name: GetUserDisabledAt :one
+SELECT disabled_at
+FROM users
+WHERE id = $1;
+
+-- name: DisableUser :execrows
+UPDATE users
+SET disabled_at = COALESCE(disabled_at, now())
+WHERE id = $1;
+
+-- name: EnableUser :execrows
+UPDATE users
+SET disabled_at = NULL
+WHERE id = $1;
+
+-- name: SearchUsers :many
+-- 運用者向け。ID の部分一致で検索し、ユーザーごとの件数と最終操作日時を返す
+SELECT
+    u.id,
+    u.created_at,
+    u.disabled_at,
+    u.deletion_scheduled_at,
+    u.setup_completed_at,
+    (SELECT COUNT(*) FROM expenses e WHERE e.user_id = u.id) AS expense_count,
+    (SELECT COUNT(*) FROM fixed_costs f WHERE f.user_id = u.id) AS fixed_cost_count,
+    GREATEST(
+        u.created_at,
+        u.updated_at,
+        (SELECT MAX(e.update_at) FROM expenses e WHERE e.user_id = u.id),
+        (SELECT MAX(f.updated_at) FROM fixed_costs f WHERE f.user_id = u.id),
+        (SELECT MAX(t.last_used_at) FROM personal_access_tokens t WHERE t.user_id = u.id)
+    )::timestamp AS last_activity_at
+FROM users u
+WHERE strpos(u.id, sqlc.arg(query)::text) > 0
+ORDER BY u.created_at DESC, u.id ASC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: GetUserActivity :one
+-- SearchUsers と同じ項目を 1 ユーザー分返す
+SELECT
+    u.id,
+    u.created_at,
+    u.disabled_at,
+    u.deletion_scheduled_at,
+    u.setup_completed_at,
+    (SELECT COUNT(*) FROM expenses e WHERE e.user_id = u.id) AS expense_count,
+    (SELECT COUNT(*) FROM fixed_costs f WHERE f.user_id = u.id) AS fixed_cost_count,
+    GREATEST(
+        u.created_at,
+        u.updated_at,
+        (SELECT MAX(e.update_at) FROM expenses e WHERE e.user_id = u.id),
+        (SELECT MAX(f.updated_at) FROM fixed_costs f WHERE f.user_id = u.id),
+        (SELECT MAX(t.last_used_at) FROM personal_access_tokens t WHERE t.user_id = u.id)
+    )::timestamp AS last_activity_at
+FROM users u
+WHERE u.id = $1;
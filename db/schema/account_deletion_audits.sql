@@ -0,0 +1,7 @@
+-- 退会処理の記録。ユーザーを特定できる情報は残さない。
+CREATE TABLE account_deletion_audits (
+  id SERIAL PRIMARY KEY,
+  requested_at TIMESTAMP NOT NULL,
+  purged_at TIMESTAMP NOT NULL DEFAULT now(),
+  purged_rows JSONB NOT NULL  -- データ種別ごとの削除件数
+);
@@ -0,0 +1,11 @@
+-- 運用者（/admin）の操作記録
+CREATE TABLE admin_audit_logs (
+  id SERIAL PRIMARY KEY,
+  operator TEXT NOT NULL,              -- ADMIN_API_TOKENS で設定した運用者名
+  action TEXT NOT NULL,                -- users.search / users.disable など
+  target_user_id TEXT,                 -- 操作対象のユーザー。退会でデータを削除した後は NULL
+  detail JSONB NOT NULL DEFAULT '{}',  -- 検索条件などの補足
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX admin_audit_logs_created_at_idx ON admin_audit_logs (created_at);
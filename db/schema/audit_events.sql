@@ -0,0 +1,14 @@
+-- 利用者の更新操作の記録。更新と同じトランザクションで書き込む
+CREATE TABLE audit_events (
+  id SERIAL PRIMARY KEY,
+  ledger_id INTEGER REFERENCES ledgers(id) ON DELETE CASCADE, -- 家計簿に属するデータの操作のみ。初期設定の収入などは NULL
+  actor_id TEXT,                                               -- 操作したユーザー。退会後は NULL
+  action TEXT NOT NULL,                                        -- expense.create / setup.complete など
+  entity_type TEXT NOT NULL,                                   -- expense / user / fixed_costs
+  entity_id TEXT NOT NULL,
+  changes JSONB NOT NULL,                                      -- 項目ごとの {"before": ..., "after": ...}
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_events_ledger_id_idx ON audit_events (ledger_id, id);
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id, id);
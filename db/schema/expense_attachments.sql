@@ -0,0 +1,46 @@
+-- 支出の添付ファイル（レシートの写真など）。ファイルの中身は保存先（ローカルのディレクトリや S3 互換のストレージ）に置き、
+-- ここには保存先のキーとファイルの情報だけを持つ。家計簿のメンバーなら誰でも参照でき、編集者以上が追加・削除できる
+CREATE TABLE expense_attachments (
+  id SERIAL PRIMARY KEY,
+  expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+  file_name TEXT NOT NULL, -- アップロード時のファイル名（ダウンロード時の名前に使う）
+  content_type TEXT NOT NULL, -- 中身から判定した形式（クライアントが送った Content-Type は使わない）
+  size_bytes BIGINT NOT NULL,
+  storage_key TEXT NOT NULL UNIQUE,
+  thumbnail_key TEXT UNIQUE, -- 画像の縮小版の保存先のキー（画像以外や縮小版を作れなかった場合は NULL）
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX expense_attachments_expense_id_idx ON expense_attachments (expense_id, id);
+
+-- 行が消えて使われなくなった保存先のファイル。支出の完全な削除・退会・家計簿の削除など、
+-- どの経路で行が消えても下のトリガーで積まれ、ジョブが保存先から削除してからここの行を消す。
+-- 保存先のキーしか持たないため行レベルセキュリティは掛けない（利用者の権限で行が消えた場合にも積めるように）
+CREATE TABLE attachment_orphans (
+  storage_key TEXT PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- 保存先に置いたが、expense_attachments の行がまだ確定していないファイル。アップロードのリクエストの
+-- トランザクションの外で積み、行の作成と同じトランザクションで消す。リクエストがロールバックされると残るため、
+-- ジョブが猶予期間を過ぎたものを attachment_orphans に移す。created_at はアプリが UTC で設定する
+CREATE TABLE attachment_uploads (
+  storage_key TEXT PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE FUNCTION expense_attachments_enqueue_orphans() RETURNS TRIGGER
+  LANGUAGE plpgsql
+  AS $$
+    BEGIN
+      INSERT INTO attachment_orphans (storage_key) VALUES (OLD.storage_key) ON CONFLICT DO NOTHING;
+      IF OLD.thumbnail_key IS NOT NULL THEN
+        INSERT INTO attachment_orphans (storage_key) VALUES (OLD.thumbnail_key) ON CONFLICT DO NOTHING;
+      END IF;
+      RETURN OLD;
+    END
+  $$;
+
+CREATE TRIGGER expense_attachments_enqueue_orphans
+  AFTER DELETE ON expense_attachments
+  FOR EACH ROW EXECUTE FUNCTION expense_attachments_enqueue_orphans();
@@ -0,0 +1,13 @@
+-- 支出の返金（返品・注文のキャンセルなど）。元の支出に紐付け、一部または全額を記録する。
+-- 返金の合計は expenses.refunded_amount に持ち、支出の金額を超えないことを制約で保証する。
+-- 集計は返金を差し引いた額（amount - refunded_amount）を元の支出の日付で数える
+CREATE TABLE expense_refunds (
+  id SERIAL PRIMARY KEY,
+  expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+  amount INTEGER NOT NULL CHECK (amount > 0),
+  refunded_at DATE NOT NULL,
+  memo TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX expense_refunds_expense_id_idx ON expense_refunds (expense_id, refunded_at);
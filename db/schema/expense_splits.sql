@@ -0,0 +1,13 @@
+-- 支出の内訳。1 枚のレシートの食費・日用品・酒類などを、カテゴリごとの金額に分ける。
+-- 内訳の金額の合計は支出の金額と一致させる（サービスで検証する）。
+-- 内訳のある支出のカテゴリ別の絞り込み・集計には、支出のカテゴリではなく内訳のカテゴリと金額を使う
+CREATE TABLE expense_splits (
+  expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL, -- 内訳の並び順（0 始まり）
+  category_id INTEGER NOT NULL,
+  amount INTEGER NOT NULL CHECK (amount > 0),
+  memo TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (expense_id, position)
+);
+
+CREATE INDEX expense_splits_category_id_idx ON expense_splits (category_id, expense_id);
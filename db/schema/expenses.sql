@@ -1,15 +1,51 @@
 CREATE TABLE expenses (
   id SERIAL PRIMARY KEY,
-  user_id TEXT NOT NULL REFERENCES users(id),
+  user_id TEXT REFERENCES users(id) ON DELETE SET NULL, -- 登録者。退会すると NULL になり、共有家計簿の支出は残る
   amount INTEGER NOT NULL,
   category_id INTEGER NOT NULL,
   memo TEXT,
   spent_at DATE NOT NULL,
   status TEXT NOT NULL DEFAULT 'confirmed',
   created_at TIMESTAMP NOT NULL DEFAULT now(),
-  update_at TIMESTAMP NOT NULL DEFAULT now()
+  update_at TIMESTAMP NOT NULL DEFAULT now(),
+  ledger_id INTEGER NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE, -- 所属する家計簿（user_id は登録者）
+  confirmed_at TIMESTAMP, -- planned から confirmed にした日時（最初から confirmed で登録した支出は NULL）
+  planned_amount INTEGER, -- 確定前に予定していた金額
+  planned_spent_at DATE, -- 確定前に予定していた日付
+  deleted_at TIMESTAMP, -- ゴミ箱に移した日時（NULL なら通常の支出）
+  version INTEGER NOT NULL DEFAULT 1, -- 更新のたびに 1 増やす。ETag / If-Match による楽観的排他制御に使う
+  refunded_amount INTEGER NOT NULL DEFAULT 0 -- 返金（expense_refunds）の金額の合計。返金の登録・削除と同じトランザクションで更新する
 );
 
+CREATE INDEX expenses_ledger_id_spent_at_idx ON expenses (ledger_id, spent_at);
+CREATE INDEX expenses_ledger_id_amount_idx ON expenses (ledger_id, amount, id);
+-- ゴミ箱の一覧と保存期間を過ぎた支出の削除用
+CREATE INDEX expenses_deleted_at_idx ON expenses (ledger_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
 ALTER TABLE expenses
 ADD CONSTRAINT expenses_status_check
 CHECK (status IN ('planned', 'confirmed'));
+
+-- 返金の合計は支出の金額を超えられない（集計では amount - refunded_amount を実際の支出額とする）
+ALTER TABLE expenses
+ADD CONSTRAINT expenses_refunded_amount_check
+CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+-- メモの全文検索（GET /expenses/search）
+-- 日本語は語が空白で区切られないため、形態素解析の代わりに文字の 1-gram・2-gram で索引を作る。
+-- 全角・半角や大文字・小文字を揃え（NFKC・lower）、空白を取り除いた文字列を対象にする。
+CREATE FUNCTION expense_memo_normalize(memo TEXT) RETURNS TEXT
+  LANGUAGE sql IMMUTABLE PARALLEL SAFE
+  AS $$ SELECT regexp_replace(lower(normalize(COALESCE(memo, ''), NFKC)), '\s', '', 'g') $$;
+
+CREATE FUNCTION expense_memo_ngrams(memo TEXT) RETURNS TEXT[]
+  LANGUAGE sql IMMUTABLE PARALLEL SAFE
+  AS $$
+    SELECT COALESCE(array_agg(DISTINCT substr(s.n, i, g.len)), '{}')
+    FROM (SELECT expense_memo_normalize(memo) AS n) s,
+      generate_series(1, char_length(s.n)) AS i,
+      (VALUES (1), (2)) AS g(len)
+    WHERE i + g.len - 1 <= char_length(s.n)
+  $$;
+
+CREATE INDEX expenses_memo_ngrams_idx ON expenses USING gin (expense_memo_ngrams(memo));
@@ -1,8 +1,11 @@
 CREATE TABLE fixed_costs (
   id SERIAL PRIMARY KEY,
-  user_id TEXT NOT NULL REFERENCES users(id),
+  user_id TEXT REFERENCES users(id) ON DELETE SET NULL, -- 登録者。退会すると NULL になり、共有家計簿の固定費は残る
   name TEXT NOT NULL,
   amount INT NOT NULL,
   created_at TIMESTAMP DEFAULT now(),
-  updated_at TIMESTAMP DEFAULT now()
-);
\ No newline at end of file
+  updated_at TIMESTAMP DEFAULT now(),
+  ledger_id INTEGER NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE -- 所属する家計簿（user_id は登録者）
+);
+
+CREATE INDEX fixed_costs_ledger_id_idx ON fixed_costs (ledger_id);
\ No newline at end of file
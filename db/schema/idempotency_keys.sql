@@ -0,0 +1,14 @@
+-- Idempotency-Key 付きのリクエストに返したレスポンス。同じキーの再送には保存したレスポンスを返す
+CREATE TABLE idempotency_keys (
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  idempotency_key TEXT NOT NULL,
+  request_hash TEXT NOT NULL,      -- メソッド・パス・本文の SHA-256（hex）。同じキーで別の内容が送られたかの判定に使う
+  response_status INTEGER NOT NULL,
+  response_headers JSONB NOT NULL, -- 再送にも返すヘッダ（Content-Type・ETag など）
+  response_body BYTEA NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  expires_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,17 @@
+-- 家計簿の収入（給与・賞与・副業など）。支出と同じく予定（planned）と確定（confirmed）がある。
+-- 月次集計では、その月の収入を登録したメンバーは登録した収入を、登録していないメンバーは users.income を見込みとして使う
+CREATE TABLE incomes (
+  id SERIAL PRIMARY KEY,
+  ledger_id INTEGER NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE, -- 所属する家計簿
+  user_id TEXT REFERENCES users(id) ON DELETE SET NULL, -- 受け取ったメンバー（登録者）。退会すると NULL になり、共有家計簿の収入は残る
+  source TEXT NOT NULL, -- 収入源（給与・賞与・副業など）
+  amount INTEGER NOT NULL,
+  received_at DATE NOT NULL,
+  status TEXT NOT NULL DEFAULT 'confirmed',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now(),
+  CONSTRAINT incomes_status_check CHECK (status IN ('planned', 'confirmed'))
+);
+
+CREATE INDEX incomes_ledger_id_received_at_idx ON incomes (ledger_id, received_at);
+CREATE INDEX incomes_user_id_idx ON incomes (user_id);
@@ -0,0 +1,32 @@
+CREATE TABLE ledgers (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE ledger_members (
+  ledger_id INTEGER NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL DEFAULT 'editor' CHECK (role IN ('owner', 'editor', 'viewer')),  -- owner は家計簿ごとに 1 人
+  joined_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (ledger_id, user_id)
+);
+
+CREATE INDEX ledger_members_user_id_idx ON ledger_members (user_id);
+
+CREATE TABLE ledger_invites (
+  id SERIAL PRIMARY KEY,
+  ledger_id INTEGER NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+  code_hash TEXT NOT NULL UNIQUE,  -- SHA-256（平文は保存しない）
+  created_by TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMP NOT NULL,
+  used_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+  used_at TIMESTAMP,               -- 使用済みの招待コードは再利用できない
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- users は ledgers より先に作成するため、users.active_ledger_id の外部キーはここで追加する
+ALTER TABLE users
+ADD CONSTRAINT users_active_ledger_id_fkey
+FOREIGN KEY (active_ledger_id) REFERENCES ledgers(id) ON DELETE SET NULL;
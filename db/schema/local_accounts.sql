@@ -0,0 +1,22 @@
+-- ローカル認証（AUTH_PROVIDER=local）のアカウント
+CREATE TABLE local_accounts (
+  user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  email TEXT NOT NULL UNIQUE,   -- 小文字に正規化して保存する
+  password_hash TEXT NOT NULL,  -- argon2id（PHC 形式）
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  password_changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- ローカル認証のリフレッシュトークン。平文は保存しない
+CREATE TABLE refresh_tokens (
+  id SERIAL PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  family_id TEXT NOT NULL,         -- ログインごとの系列。交換で発行したトークンは同じ系列を引き継ぐ
+  token_hash TEXT NOT NULL UNIQUE, -- SHA-256（hex）
+  expires_at TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP,            -- 交換・ログアウト・パスワード変更で失効した日時
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
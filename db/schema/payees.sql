@@ -0,0 +1,18 @@
+-- ユーザーごとの支払先（店・サービスなど）。支出の登録時に名前を補完し、支払先ごとの支出を集計する
+CREATE TABLE payees (
+  id SERIAL PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now(),
+  CONSTRAINT payees_user_id_name_key UNIQUE (user_id, name)
+);
+
+-- 支出の支払先。支出 1 件に支払先は 1 つで、共有家計簿では他のメンバーにも同じ支払先が見える。
+-- 支出の一覧・集計は支払先の名前をここから引くため、名前を変える・統合するとすべての支出に反映される
+CREATE TABLE expense_payees (
+  expense_id INTEGER PRIMARY KEY REFERENCES expenses(id) ON DELETE CASCADE,
+  payee_id INTEGER NOT NULL REFERENCES payees(id) ON DELETE CASCADE
+);
+
+CREATE INDEX expense_payees_payee_id_idx ON expense_payees (payee_id, expense_id);
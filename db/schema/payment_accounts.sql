@@ -0,0 +1,20 @@
+-- ユーザーごとの支払い手段（現金・クレジットカード・PayPay・銀行口座など）。
+-- 残高は開始残高から、付けた確定済みの支出（ゴミ箱のものを除く）を差し引いて求める
+CREATE TABLE payment_accounts (
+  id SERIAL PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL CHECK (type IN ('cash', 'credit_card', 'e_money', 'bank_account', 'other')),
+  opening_balance INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now(),
+  CONSTRAINT payment_accounts_user_id_name_key UNIQUE (user_id, name)
+);
+
+-- 支出の支払い手段。支出 1 件に 1 つで、共有家計簿では他のメンバーにも同じ支払い手段が見える
+CREATE TABLE expense_payment_accounts (
+  expense_id INTEGER PRIMARY KEY REFERENCES expenses(id) ON DELETE CASCADE,
+  payment_account_id INTEGER NOT NULL REFERENCES payment_accounts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX expense_payment_accounts_payment_account_id_idx ON expense_payment_accounts (payment_account_id, expense_id);
@@ -0,0 +1,161 @@
+-- 行レベルセキュリティ（RLS）
+-- クエリの WHERE 句に頼らず、DB 側でも他のユーザーのデータを読み書きできないようにする。
+-- 呼び出し元はトランザクション開始時に app.user_id へ設定される（infra/transaction、SET LOCAL 相当）。
+-- 未設定のセッションからは対象テーブルの行が一切見えない。
+-- ユーザーを横断するバックグラウンドジョブは app.bypass_rls = 'on' を設定する。
+-- テーブル所有者で接続した場合にもポリシーを適用するため FORCE を指定する（スーパーユーザーには適用されない）。
+
+CREATE FUNCTION app_current_user_id() RETURNS TEXT
+  LANGUAGE sql STABLE
+  AS $$ SELECT NULLIF(current_setting('app.user_id', true), '') $$;
+
+CREATE FUNCTION app_rls_bypassed() RETURNS BOOLEAN
+  LANGUAGE sql STABLE
+  AS $$ SELECT COALESCE(current_setting('app.bypass_rls', true), '') = 'on' $$;
+
+-- 呼び出し元が target_ledger_id の家計簿のメンバーかどうか（ledger_members 自体には RLS を掛けない）
+CREATE FUNCTION app_is_ledger_member(target_ledger_id INTEGER) RETURNS BOOLEAN
+  LANGUAGE sql STABLE
+  AS $$ SELECT EXISTS (
+    SELECT 1 FROM ledger_members m WHERE m.ledger_id = target_ledger_id AND m.user_id = app_current_user_id()
+  ) $$;
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+
+-- 本人の行だけを読み書きできる
+CREATE POLICY users_self ON users
+  USING (id = app_current_user_id() OR app_rls_bypassed());
+
+-- 共有家計簿の集計で他のメンバーの収入・貯金目標を合算するため、同じ家計簿のメンバーは参照だけできる
+CREATE POLICY users_ledger_peers_select ON users
+  FOR SELECT
+  USING (EXISTS (
+    SELECT 1
+    FROM ledger_members me
+    JOIN ledger_members peer ON peer.ledger_id = me.ledger_id
+    WHERE me.user_id = app_current_user_id() AND peer.user_id = users.id
+  ));
+
+ALTER TABLE expenses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expenses FORCE ROW LEVEL SECURITY;
+
+-- 所属する家計簿の支出だけを読み書きできる
+CREATE POLICY expenses_ledger_members ON expenses
+  USING (app_is_ledger_member(ledger_id) OR app_rls_bypassed());
+
+ALTER TABLE fixed_costs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE fixed_costs FORCE ROW LEVEL SECURITY;
+
+-- 所属する家計簿の固定費だけを読み書きできる
+CREATE POLICY fixed_costs_ledger_members ON fixed_costs
+  USING (app_is_ledger_member(ledger_id) OR app_rls_bypassed());
+
+ALTER TABLE audit_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_events FORCE ROW LEVEL SECURITY;
+
+-- 自分の操作と、所属する家計簿での操作を参照できる。記録できるのは自分の操作だけ
+CREATE POLICY audit_events_visible ON audit_events
+  USING (actor_id = app_current_user_id() OR app_is_ledger_member(ledger_id) OR app_rls_bypassed())
+  WITH CHECK (actor_id = app_current_user_id() OR app_rls_bypassed());
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+
+-- 保存したレスポンスには家計簿のデータが含まれるため、自分のキーだけを読み書きできる
+CREATE POLICY idempotency_keys_self ON idempotency_keys
+  USING (user_id = app_current_user_id() OR app_rls_bypassed());
+
+ALTER TABLE expense_splits ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_splits FORCE ROW LEVEL SECURITY;
+
+-- 内訳は支出に従う。読み書きできる支出（expenses のポリシー）の内訳だけを読み書きできる
+CREATE POLICY expense_splits_via_expense ON expense_splits
+  USING (EXISTS (SELECT 1 FROM expenses e WHERE e.id = expense_splits.expense_id));
+
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tags FORCE ROW LEVEL SECURITY;
+
+-- タグはユーザーごとのもので、共有家計簿のメンバーにも見せない
+CREATE POLICY tags_self ON tags
+  USING (user_id = app_current_user_id() OR app_rls_bypassed());
+
+ALTER TABLE expense_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_tags FORCE ROW LEVEL SECURITY;
+
+-- 自分のタグ（tags のポリシー）を、読み書きできる支出（expenses のポリシー）に付けたものだけを読み書きできる
+CREATE POLICY expense_tags_own_tags ON expense_tags
+  USING (
+    EXISTS (SELECT 1 FROM tags t WHERE t.id = expense_tags.tag_id)
+    AND EXISTS (SELECT 1 FROM expenses e WHERE e.id = expense_tags.expense_id)
+  );
+
+ALTER TABLE expense_attachments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_attachments FORCE ROW LEVEL SECURITY;
+
+-- 添付ファイルは支出に従う。読み書きできる支出（expenses のポリシー）の添付ファイルだけを読み書きできる
+CREATE POLICY expense_attachments_via_expense ON expense_attachments
+  USING (EXISTS (SELECT 1 FROM expenses e WHERE e.id = expense_attachments.expense_id));
+
+ALTER TABLE payees ENABLE ROW LEVEL SECURITY;
+ALTER TABLE payees FORCE ROW LEVEL SECURITY;
+
+-- 支払先は作ったユーザーだけが読み書きできる
+CREATE POLICY payees_self ON payees
+  USING (user_id = app_current_user_id() OR app_rls_bypassed());
+
+-- 共有家計簿の支出に付いた支払先は、その支出を読めるメンバーも名前を参照できる
+CREATE POLICY payees_linked_select ON payees
+  FOR SELECT
+  USING (EXISTS (
+    SELECT 1
+    FROM expense_payees ep
+    JOIN expenses e ON e.id = ep.expense_id
+    WHERE ep.payee_id = payees.id
+  ));
+
+ALTER TABLE expense_payees ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_payees FORCE ROW LEVEL SECURITY;
+
+-- 支払先の付与は支出に従う。読み書きできる支出（expenses のポリシー）のものだけを読み書きできる。
+-- payees のポリシーがこのテーブルを参照するため、ここでは payees を参照しない（付けられる支払先はアプリケーションで確認する）
+CREATE POLICY expense_payees_via_expense ON expense_payees
+  USING (EXISTS (SELECT 1 FROM expenses e WHERE e.id = expense_payees.expense_id));
+
+ALTER TABLE payment_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE payment_accounts FORCE ROW LEVEL SECURITY;
+
+-- 支払い手段は作ったユーザーだけが読み書きできる
+CREATE POLICY payment_accounts_self ON payment_accounts
+  USING (user_id = app_current_user_id() OR app_rls_bypassed());
+
+-- 共有家計簿の支出に付いた支払い手段は、その支出を読めるメンバーも参照できる（月次集計の内訳に使う）
+CREATE POLICY payment_accounts_linked_select ON payment_accounts
+  FOR SELECT
+  USING (EXISTS (
+    SELECT 1
+    FROM expense_payment_accounts epa
+    JOIN expenses e ON e.id = epa.expense_id
+    WHERE epa.payment_account_id = payment_accounts.id
+  ));
+
+ALTER TABLE expense_payment_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_payment_accounts FORCE ROW LEVEL SECURITY;
+
+-- expense_payees と同じく支出に従い、payment_accounts は参照しない
+CREATE POLICY expense_payment_accounts_via_expense ON expense_payment_accounts
+  USING (EXISTS (SELECT 1 FROM expenses e WHERE e.id = expense_payment_accounts.expense_id));
+
+ALTER TABLE incomes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE incomes FORCE ROW LEVEL SECURITY;
+
+-- 所属する家計簿の収入だけを読み書きできる
+CREATE POLICY incomes_ledger_members ON incomes
+  USING (app_is_ledger_member(ledger_id) OR app_rls_bypassed());
+
+ALTER TABLE expense_refunds ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_refunds FORCE ROW LEVEL SECURITY;
+
+-- 返金は内訳と同じく支出に従う
+CREATE POLICY expense_refunds_via_expense ON expense_refunds
+  USING (EXISTS (SELECT 1 FROM expenses e WHERE e.id = expense_refunds.expense_id));
@@ -0,0 +1,20 @@
+-- ユーザーごとのタグ。「大阪旅行」「誕生日会」のように、カテゴリをまたぐ支出をまとめる。
+-- タグは作ったユーザーだけのもので、共有家計簿の支出に付けても他のメンバーには見えない
+CREATE TABLE tags (
+  id SERIAL PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now(),
+  CONSTRAINT tags_user_id_name_key UNIQUE (user_id, name)
+);
+
+-- 支出とタグの多対多の関連。支出の一覧・集計はタグの名前をここから引くため、
+-- タグの名前を変える・統合するとそのタグが付いたすべての支出に反映される
+CREATE TABLE expense_tags (
+  expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (expense_id, tag_id)
+);
+
+CREATE INDEX expense_tags_tag_id_idx ON expense_tags (tag_id, expense_id);
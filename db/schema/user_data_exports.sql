@@ -0,0 +1,10 @@
+-- 問い合わせ対応のために運用者が書き出したユーザーデータ。退会で削除される
+CREATE TABLE user_data_exports (
+  id SERIAL PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  requested_by TEXT NOT NULL,  -- 書き出した運用者
+  payload JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_data_exports_user_id_idx ON user_data_exports (user_id);
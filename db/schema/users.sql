@@ -1,7 +1,15 @@
 CREATE TABLE users (
   id TEXT PRIMARY KEY,          -- Firebase UID
-  income INT NOT NULL,           -- 月収（手取り）
-  saving_goal INT NOT NULL,      -- 月の貯金額
+  income INT NOT NULL DEFAULT 0, -- 月収（手取り）の見込み。収入（incomes）を登録していない月の集計に使う。初期設定前は 0
+  saving_goal INT NOT NULL DEFAULT 0, -- 月の貯金額
   created_at TIMESTAMP DEFAULT now(),
-  updated_at TIMESTAMP DEFAULT now()
+  updated_at TIMESTAMP DEFAULT now(),
+  deletion_requested_at TIMESTAMP, -- 退会申請日時
+  deletion_scheduled_at TIMESTAMP, -- この日時を過ぎるとデータを完全に削除する
+  active_ledger_id INTEGER, -- 支出・固定費の読み書きに使う家計簿。ledgers と相互に参照するため、外部キーは ledgers.sql で追加する
+  setup_completed_at TIMESTAMP,     -- 初期設定（POST /setup）の完了日時。NULL なら未完了
+  disabled_at TIMESTAMP             -- 運用者が利用を停止した日時。NULL なら利用可能
 );
+
+CREATE INDEX users_deletion_scheduled_at_idx ON users (deletion_scheduled_at)
+  WHERE deletion_scheduled_at IS NOT NULL;
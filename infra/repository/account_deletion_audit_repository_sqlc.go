@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/repositories"
+)
+
+type accountDeletionAuditRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewAccountDeletionAuditRepositorySQLC(q *db.Queries) repositories.AccountDeletionAuditRepository {
+	return &accountDeletionAuditRepositorySQLC{q: q}
+}
+
+func (r *accountDeletionAuditRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *accountDeletionAuditRepositorySQLC) CreateAudit(ctx context.Context, requestedAt time.Time, purgedRows map[string]int64) error {
+	rows, err := json.Marshal(purgedRows)
+	if err != nil {
+		return err
+	}
+	return r.queries(ctx).CreateAccountDeletionAudit(ctx, db.CreateAccountDeletionAuditParams{
+		RequestedAt: requestedAt,
+		PurgedRows:  rows,
+	})
+}
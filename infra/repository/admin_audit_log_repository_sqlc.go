@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type adminAuditLogRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewAdminAuditLogRepositorySQLC(q *db.Queries) repositories.AdminAuditLogRepository {
+	return &adminAuditLogRepositorySQLC{q: q}
+}
+
+func (r *adminAuditLogRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *adminAuditLogRepositorySQLC) CreateLog(ctx context.Context, log models.AdminAuditLog) error {
+	detail := []byte("{}")
+	if len(log.Detail) > 0 {
+		var err error
+		if detail, err = json.Marshal(log.Detail); err != nil {
+			return err
+		}
+	}
+	return r.queries(ctx).CreateAdminAuditLog(ctx, db.CreateAdminAuditLogParams{
+		Operator:     log.Operator,
+		Action:       log.Action,
+		TargetUserID: sql.NullString{String: log.TargetUserID, Valid: log.TargetUserID != ""},
+		Detail:       detail,
+	})
+}
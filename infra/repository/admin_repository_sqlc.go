@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type adminRepositorySQLC struct {
+	q      *db.Queries
+	ledger *ledgerRepositorySQLC
+	tokens *personalAccessTokenRepositorySQLC
+}
+
+func NewAdminRepositorySQLC(q *db.Queries) repositories.AdminRepository {
+	return &adminRepositorySQLC{
+		q:      q,
+		ledger: &ledgerRepositorySQLC{q: q},
+		tokens: &personalAccessTokenRepositorySQLC{q: q},
+	}
+}
+
+func (r *adminRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *adminRepositorySQLC) SearchUsers(ctx context.Context, query string, limit int, offset int) ([]models.AdminUser, error) {
+	rows, err := r.queries(ctx).SearchUsers(ctx, db.SearchUsersParams{
+		Query:      query,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.AdminUser, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, dbAdminUserToModel(row))
+	}
+	return out, nil
+}
+
+func (r *adminRepositorySQLC) GetUser(ctx context.Context, userID string) (models.AdminUser, error) {
+	row, err := r.queries(ctx).GetUserActivity(ctx, userID)
+	if err != nil {
+		return models.AdminUser{}, err
+	}
+	// GetUserActivity は SearchUsers と同じ列を返す
+	return dbAdminUserToModel(db.SearchUsersRow(row)), nil
+}
+
+func (r *adminRepositorySQLC) SetUserDisabled(ctx context.Context, userID string, disabled bool) (bool, error) {
+	q := r.queries(ctx)
+	var n int64
+	var err error
+	if disabled {
+		n, err = q.DisableUser(ctx, userID)
+	} else {
+		n, err = q.EnableUser(ctx, userID)
+	}
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *adminRepositorySQLC) CollectUserData(ctx context.Context, userID string) (models.UserExportData, error) {
+	q := r.queries(ctx)
+
+	user, err := q.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.UserExportData{}, err
+	}
+	profile := dbUserToModel(user)
+
+	ledgers, err := r.ledger.ListLedgersByUser(ctx, userID)
+	if err != nil {
+		return models.UserExportData{}, err
+	}
+
+	expenses, err := q.ListExpensesByUser(ctx, sql.NullString{String: userID, Valid: true})
+	if err != nil {
+		return models.UserExportData{}, err
+	}
+	ids := make([]int32, 0, len(expenses))
+	for _, e := range expenses {
+		ids = append(ids, e.ID)
+	}
+	splits, err := expenseSplitsByID(ctx, q, ids)
+	if err != nil {
+		return models.UserExportData{}, err
+	}
+	exportedExpenses := make([]models.ExportedExpense, 0, len(expenses))
+	for _, e := range expenses {
+		exportedExpenses = append(exportedExpenses, models.ExportedExpense{
+			ID:             int(e.ID),
+			LedgerID:       int(e.LedgerID),
+			Amount:         int(e.Amount),
+			RefundedAmount: int(e.RefundedAmount),
+			CategoryID:     int(e.CategoryID),
+			Memo:           e.Memo.String,
+			SpentAt:        e.SpentAt.Format(time.RFC3339),
+			Status:         e.Status,
+			CreatedAt:      e.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:      e.UpdateAt.Format(time.RFC3339),
+			Splits:         splits[e.ID],
+		})
+	}
+
+	fixedCosts, err := q.ListFixedCostsByUser(ctx, sql.NullString{String: userID, Valid: true})
+	if err != nil {
+		return models.UserExportData{}, err
+	}
+	exportedFixedCosts := make([]models.FixedCost, 0, len(fixedCosts))
+	for _, fc := range fixedCosts {
+		exportedFixedCosts = append(exportedFixedCosts, dbFixedCostToModel(fc))
+	}
+
+	tokens, err := r.tokens.ListTokensByUser(ctx, userID)
+	if err != nil {
+		return models.UserExportData{}, err
+	}
+
+	return models.UserExportData{
+		UserID:               profile.ID,
+		Income:               profile.Income,
+		SavingGoal:           profile.SavingGoal,
+		CreatedAt:            profile.CreatedAt,
+		SetupCompletedAt:     profile.SetupCompletedAt,
+		Ledgers:              ledgers,
+		Expenses:             exportedExpenses,
+		FixedCosts:           exportedFixedCosts,
+		PersonalAccessTokens: tokens,
+	}, nil
+}
+
+func (r *adminRepositorySQLC) CreateExport(ctx context.Context, userID string, requestedBy string, data models.UserExportData) (models.UserDataExport, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return models.UserDataExport{}, err
+	}
+	row, err := r.queries(ctx).CreateUserDataExport(ctx, db.CreateUserDataExportParams{
+		UserID:      userID,
+		RequestedBy: requestedBy,
+		Payload:     payload,
+	})
+	if err != nil {
+		return models.UserDataExport{}, err
+	}
+	return dbUserDataExportToModel(row), nil
+}
+
+func (r *adminRepositorySQLC) GetExport(ctx context.Context, id int32) (models.UserDataExport, error) {
+	row, err := r.queries(ctx).GetUserDataExport(ctx, id)
+	if err != nil {
+		return models.UserDataExport{}, err
+	}
+	var data models.UserExportData
+	if err := json.Unmarshal(row.Payload, &data); err != nil {
+		return models.UserDataExport{}, err
+	}
+	export := dbUserDataExportToModel(row)
+	export.Data = &data
+	return export, nil
+}
+
+func dbAdminUserToModel(row db.SearchUsersRow) models.AdminUser {
+	createdAt := ""
+	if row.CreatedAt.Valid {
+		createdAt = row.CreatedAt.Time.Format(time.RFC3339)
+	}
+	return models.AdminUser{
+		ID:                  row.ID,
+		CreatedAt:           createdAt,
+		Disabled:            row.DisabledAt.Valid,
+		DisabledAt:          nullTimeToString(row.DisabledAt),
+		DeletionScheduledAt: nullTimeToString(row.DeletionScheduledAt),
+		SetupCompletedAt:    nullTimeToString(row.SetupCompletedAt),
+		ExpenseCount:        int(row.ExpenseCount),
+		FixedCostCount:      int(row.FixedCostCount),
+		LastActivityAt:      row.LastActivityAt.Format(time.RFC3339),
+	}
+}
+
+func dbUserDataExportToModel(row db.UserDataExport) models.UserDataExport {
+	return models.UserDataExport{
+		ID:          int(row.ID),
+		UserID:      row.UserID,
+		RequestedBy: row.RequestedBy,
+		CreatedAt:   row.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type attachmentRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewAttachmentRepositorySQLC(q *db.Queries) repositories.AttachmentRepository {
+	return &attachmentRepositorySQLC{q: q}
+}
+
+func (r *attachmentRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *attachmentRepositorySQLC) ListAttachments(ctx context.Context, ledgerID int32, expenseID int32) ([]models.Attachment, error) {
+	rows, err := r.queries(ctx).ListExpenseAttachments(ctx, db.ListExpenseAttachmentsParams{
+		LedgerID:  ledgerID,
+		ExpenseID: expenseID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Attachment, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toAttachment(row))
+	}
+	return out, nil
+}
+
+func (r *attachmentRepositorySQLC) GetAttachment(ctx context.Context, ledgerID int32, expenseID int32, id int32) (models.Attachment, error) {
+	row, err := r.queries(ctx).GetExpenseAttachment(ctx, db.GetExpenseAttachmentParams{
+		LedgerID:  ledgerID,
+		ExpenseID: expenseID,
+		ID:        id,
+	})
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	return toAttachment(row), nil
+}
+
+func (r *attachmentRepositorySQLC) CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error) {
+	row, err := r.queries(ctx).CreateExpenseAttachment(ctx, db.CreateExpenseAttachmentParams{
+		ExpenseID:    int32(attachment.ExpenseID),
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		SizeBytes:    attachment.Size,
+		StorageKey:   attachment.StorageKey,
+		ThumbnailKey: sql.NullString{String: attachment.ThumbnailKey, Valid: attachment.ThumbnailKey != ""},
+	})
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	attachment.ID = int(row.ID)
+	attachment.CreatedAt = row.CreatedAt.Format(time.RFC3339)
+	attachment.HasThumbnail = attachment.ThumbnailKey != ""
+	return attachment, nil
+}
+
+func (r *attachmentRepositorySQLC) DeleteAttachment(ctx context.Context, ledgerID int32, expenseID int32, id int32) (bool, error) {
+	n, err := r.queries(ctx).DeleteExpenseAttachment(ctx, db.DeleteExpenseAttachmentParams{
+		LedgerID:  ledgerID,
+		ExpenseID: expenseID,
+		ID:        id,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *attachmentRepositorySQLC) RecordUpload(ctx context.Context, key string, at time.Time) error {
+	// リクエストのトランザクションを使わず、ロールバックされても記録が残るようにする
+	return r.q.CreateAttachmentUpload(ctx, db.CreateAttachmentUploadParams{
+		StorageKey: key,
+		CreatedAt:  at,
+	})
+}
+
+func (r *attachmentRepositorySQLC) ConfirmUpload(ctx context.Context, key string) error {
+	return r.queries(ctx).DeleteAttachmentUpload(ctx, key)
+}
+
+func (r *attachmentRepositorySQLC) EnqueueAbandonedUploads(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries(ctx).EnqueueAbandonedAttachmentUploads(ctx, before)
+}
+
+func (r *attachmentRepositorySQLC) ListOrphanedFiles(ctx context.Context, limit int) ([]string, error) {
+	return r.queries(ctx).ListAttachmentOrphans(ctx, int32(limit))
+}
+
+func (r *attachmentRepositorySQLC) DeleteOrphanedFile(ctx context.Context, key string) error {
+	return r.queries(ctx).DeleteAttachmentOrphan(ctx, key)
+}
+
+func toAttachment(row db.ExpenseAttachment) models.Attachment {
+	return models.Attachment{
+		ID:           int(row.ID),
+		ExpenseID:    int(row.ExpenseID),
+		FileName:     row.FileName,
+		ContentType:  row.ContentType,
+		Size:         row.SizeBytes,
+		HasThumbnail: row.ThumbnailKey.Valid,
+		CreatedAt:    row.CreatedAt.Format(time.RFC3339),
+		StorageKey:   row.StorageKey,
+		ThumbnailKey: row.ThumbnailKey.String,
+	}
+}
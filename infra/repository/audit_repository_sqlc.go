@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type auditRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewAuditRepositorySQLC(q *db.Queries) repositories.AuditRepository {
+	return &auditRepositorySQLC{q: q}
+}
+
+func (r *auditRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *auditRepositorySQLC) CreateEvent(ctx context.Context, event models.AuditEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+
+	var ledgerID sql.NullInt32
+	if event.LedgerID != nil {
+		ledgerID = sql.NullInt32{Int32: int32(*event.LedgerID), Valid: true}
+	}
+	var actorID sql.NullString
+	if event.ActorID != nil {
+		actorID = sql.NullString{String: *event.ActorID, Valid: true}
+	}
+
+	return r.queries(ctx).CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		LedgerID:   ledgerID,
+		ActorID:    actorID,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Changes:    changes,
+	})
+}
+
+func (r *auditRepositorySQLC) ListEvents(ctx context.Context, userID string, filter models.AuditEventFilter) ([]models.AuditEvent, error) {
+	rows, err := r.queries(ctx).ListAuditEvents(ctx, db.ListAuditEventsParams{
+		UserID:     userID,
+		LedgerID:   sql.NullInt32{Int32: filter.LedgerID, Valid: filter.LedgerID != 0},
+		EntityType: sql.NullString{String: filter.EntityType, Valid: filter.EntityType != ""},
+		EntityID:   sql.NullString{String: filter.EntityID, Valid: filter.EntityID != ""},
+		Action:     sql.NullString{String: filter.Action, Valid: filter.Action != ""},
+		ActorID:    sql.NullString{String: filter.ActorID, Valid: filter.ActorID != ""},
+		Since:      sql.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()},
+		Until:      sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		BeforeID:   sql.NullInt32{Int32: filter.BeforeID, Valid: filter.BeforeID != 0},
+		PageLimit:  int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		event, err := dbAuditEventToModel(row)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func dbAuditEventToModel(row db.AuditEvent) (models.AuditEvent, error) {
+	event := models.AuditEvent{
+		ID:         int(row.ID),
+		Action:     row.Action,
+		EntityType: row.EntityType,
+		EntityID:   row.EntityID,
+		CreatedAt:  row.CreatedAt.Format(time.RFC3339),
+	}
+	if row.LedgerID.Valid {
+		ledgerID := int(row.LedgerID.Int32)
+		event.LedgerID = &ledgerID
+	}
+	if row.ActorID.Valid {
+		actorID := row.ActorID.String
+		event.ActorID = &actorID
+	}
+	if err := json.Unmarshal(row.Changes, &event.Changes); err != nil {
+		return models.AuditEvent{}, err
+	}
+	return event, nil
+}
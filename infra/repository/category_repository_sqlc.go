@@ -4,6 +4,7 @@ import (
 	"context"
 
 	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
 	"money-buddy-backend/internal/models"
 	"money-buddy-backend/internal/repositories"
 )
@@ -16,8 +17,15 @@ func NewCategoryRepositorySQLC(q *db.Queries) repositories.CategoryRepository {
 	return &categoryRepositorySQLC{q: q}
 }
 
+func (r *categoryRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
 func (r *categoryRepositorySQLC) ListCategories(ctx context.Context) ([]models.Category, error) {
-	items, err := r.q.ListCategories(ctx)
+	items, err := r.queries(ctx).ListCategories(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -38,5 +46,5 @@ func dbCategoryToModel(c db.ListCategoriesRow) models.Category {
 }
 
 func (r *categoryRepositorySQLC) CategoryExists(ctx context.Context, id int32) (bool, error) {
-	return r.q.CategoryExists(ctx, id)
+	return r.queries(ctx).CategoryExists(ctx, id)
 }
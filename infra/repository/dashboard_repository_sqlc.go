@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type dashboardRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewDashboardRepositorySQLC(q *db.Queries) repositories.DashboardRepository {
+	return &dashboardRepositorySQLC{q: q}
+}
+
+func (r *dashboardRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *dashboardRepositorySQLC) MonthlySummary(ctx context.Context, ledgerID int32, month time.Time) (models.MonthlySummary, error) {
+	q := r.queries(ctx)
+	budget, err := q.GetMonthlyBudget(ctx, ledgerID)
+	if err != nil {
+		return models.MonthlySummary{}, err
+	}
+	expenses, err := q.GetMonthlyExpensesSummary(ctx, db.GetMonthlyExpensesSummaryParams{LedgerID: ledgerID, Month: month})
+	if err != nil {
+		return models.MonthlySummary{}, err
+	}
+	methods, err := q.ListMonthlyPaymentMethodTotals(ctx, db.ListMonthlyPaymentMethodTotalsParams{LedgerID: ledgerID, Month: month})
+	if err != nil {
+		return models.MonthlySummary{}, err
+	}
+	summary := models.MonthlySummary{
+		SavingGoal:        int(budget.SavingGoal),
+		FixedCosts:        int(budget.FixedCosts),
+		ConfirmedExpenses: int(expenses.ConfirmedExpenses),
+		PendingExpenses:   int(expenses.PendingExpenses),
+		PaymentMethods:    make([]models.PaymentMethodTotal, 0, len(methods)),
+	}
+	for _, it := range methods {
+		summary.PaymentMethods = append(summary.PaymentMethods, models.PaymentMethodTotal{
+			PaymentMethod:  it.PaymentMethod,
+			ExpenseCount:   int(it.ExpenseCount),
+			ConfirmedTotal: int(it.ConfirmedExpenses),
+			PlannedTotal:   int(it.PendingExpenses),
+		})
+	}
+	return summary, nil
+}
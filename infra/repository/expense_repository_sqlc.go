@@ -6,6 +6,7 @@ import (
 	"time"
 
 	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
 	"money-buddy-backend/internal/models"
 	"money-buddy-backend/internal/repositories"
 )
@@ -19,7 +20,14 @@ func NewExpenseRepositorySQLC(q *db.Queries) repositories.ExpenseRepository {
 	return &expenseRepositorySQLC{q: q}
 }
 
-func (r *expenseRepositorySQLC) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+func (r *expenseRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *expenseRepositorySQLC) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
 	// 複数フォーマットに対応するため、RFC3339 をまず試し、失敗したら日付のみ (2006-01-02) を試す
 	var spentAt time.Time
 	var err error
@@ -36,44 +44,150 @@ func (r *expenseRepositorySQLC) CreateExpense(userID string, input models.Create
 	}
 
 	params := db.CreateExpenseParams{
-		UserID:     userID,
+		UserID:     sql.NullString{String: userID, Valid: true},
 		Amount:     int32(*input.Amount),
 		CategoryID: int32(*input.CategoryID),
 		Memo:       sql.NullString{String: input.Memo, Valid: input.Memo != ""},
 		SpentAt:    spentAt,
 		Status:     defaultStatus(input.Status),
+		LedgerID:   ledgerID,
 	}
 
-	id, err := r.q.CreateExpense(context.Background(), params)
+	id, err := r.queries(ctx).CreateExpense(ctx, params)
 	if err != nil {
 		return models.Expense{}, err
 	}
-
-	row, err := r.q.GetExpenseWithCategoryByID(context.Background(), db.GetExpenseWithCategoryByIDParams{
-		UserID: userID,
-		ID:     id,
-	})
-	if err != nil {
+	if err := replaceExpenseSplits(ctx, r.queries(ctx), id, input.Splits); err != nil {
 		return models.Expense{}, err
 	}
 
-	return dbExpenseToModel(row), nil
+	return r.GetExpenseByID(ctx, ledgerID, id)
 }
 
-func (r *expenseRepositorySQLC) FindAll(userID string) ([]models.Expense, error) {
-	items, err := r.q.ListExpenses(context.Background(), userID)
+func (r *expenseRepositorySQLC) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) {
+	params := db.ListExpensesParams{
+		LedgerID:  ledgerID,
+		FromDate:  sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:    sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+		Status:    sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		MinAmount: sql.NullInt32{Int32: int32(filter.MinAmount), Valid: filter.MinAmount != 0},
+		MaxAmount: sql.NullInt32{Int32: int32(filter.MaxAmount), Valid: filter.MaxAmount != 0},
+		Memo:      sql.NullString{String: filter.Memo, Valid: filter.Memo != ""},
+		SortKey:   filter.Sort,
+		PageLimit: int32(filter.Limit),
+	}
+	if len(filter.CategoryIDs) > 0 {
+		params.CategoryIds = filter.CategoryIDs
+	}
+	if len(filter.TagIDs) > 0 {
+		params.TagIds = filter.TagIDs
+	}
+	if filter.After != nil {
+		params.CursorID = sql.NullInt32{Int32: int32(filter.After.ID), Valid: true}
+		params.CursorSpentAt = sql.NullTime{Time: filter.After.SpentAt, Valid: true}
+		params.CursorAmount = sql.NullInt32{Int32: int32(filter.After.Amount), Valid: true}
+	}
+
+	items, err := r.queries(ctx).ListExpenses(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	var out []models.Expense
+	out := make([]models.Expense, 0, len(items))
+	ids := make([]int32, 0, len(items))
 	for _, it := range items {
 		out = append(out, dbListExpenseRowToModel(it))
+		ids = append(ids, it.ID)
+	}
+
+	splits, err := expenseSplitsByID(ctx, r.queries(ctx), ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Splits = splits[int32(out[i].ID)]
+	}
+	return out, nil
+}
+
+func (r *expenseRepositorySQLC) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	params := db.SearchExpensesParams{
+		Terms:     filter.Terms,
+		LedgerID:  ledgerID,
+		Status:    sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		PageLimit: int32(filter.Limit),
+	}
+	if len(filter.CategoryIDs) > 0 {
+		params.CategoryIds = filter.CategoryIDs
+	}
+
+	items, err := r.queries(ctx).SearchExpenses(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
+	out := make([]models.ExpenseSearchResult, 0, len(items))
+	ids := make([]int32, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.ID)
+		out = append(out, models.ExpenseSearchResult{
+			Expense: models.Expense{
+				ID:       int(it.ID),
+				Amount:   int(it.Amount),
+				Memo:     it.Memo.String,
+				SpentAt:  it.SpentAt.Format(time.RFC3339),
+				Status:   it.Status,
+				Category: models.Category{ID: int(it.CategoryID), Name: it.CategoryName},
+
+				ConfirmedAt:    nullTimeToString(it.ConfirmedAt),
+				PlannedAmount:  nullInt32ToInt(it.PlannedAmount),
+				PlannedSpentAt: nullTimeToString(it.PlannedSpentAt),
+				Version:        int(it.Version),
+				RefundedAmount: int(it.RefundedAmount),
+			},
+			Score: it.Score,
+		})
+	}
+
+	splits, err := expenseSplitsByID(ctx, r.queries(ctx), ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Splits = splits[int32(out[i].ID)]
+	}
 	return out, nil
 }
 
+func (r *expenseRepositorySQLC) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	params := db.ConfirmExpenseParams{ID: id, LedgerID: ledgerID}
+	if input.Amount != nil {
+		params.Amount = sql.NullInt32{Int32: int32(*input.Amount), Valid: true}
+	}
+	if input.SpentAt != "" {
+		spentAt, err := time.Parse(time.RFC3339, input.SpentAt)
+		if err != nil {
+			spentAt, err = time.Parse("2006-01-02", input.SpentAt)
+			if err != nil {
+				return models.Expense{}, err
+			}
+			// 日付のみの場合は UTC の 00:00 として扱う
+			spentAt = time.Date(spentAt.Year(), spentAt.Month(), spentAt.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		params.SpentAt = sql.NullTime{Time: spentAt, Valid: true}
+	}
+
+	n, err := r.queries(ctx).ConfirmExpense(ctx, params)
+	if err != nil {
+		return models.Expense{}, err
+	}
+	if n == 0 {
+		return models.Expense{}, sql.ErrNoRows
+	}
+
+	return r.GetExpenseByID(ctx, ledgerID, id)
+}
+
 func dbExpenseToModel(e db.GetExpenseWithCategoryByIDRow) models.Expense {
 	memo := ""
 	if e.Memo.Valid {
@@ -87,6 +201,12 @@ func dbExpenseToModel(e db.GetExpenseWithCategoryByIDRow) models.Expense {
 		SpentAt:  e.SpentAt.Format(time.RFC3339),
 		Status:   e.Status,
 		Category: models.Category{ID: int(e.CategoryID), Name: e.CategoryName},
+
+		ConfirmedAt:    nullTimeToString(e.ConfirmedAt),
+		PlannedAmount:  nullInt32ToInt(e.PlannedAmount),
+		PlannedSpentAt: nullTimeToString(e.PlannedSpentAt),
+		Version:        int(e.Version),
+		RefundedAmount: int(e.RefundedAmount),
 	}
 }
 
@@ -103,29 +223,54 @@ func dbListExpenseRowToModel(e db.ListExpensesRow) models.Expense {
 		SpentAt:  e.SpentAt.Format(time.RFC3339),
 		Status:   e.Status,
 		Category: models.Category{ID: int(e.CategoryID), Name: e.CategoryName},
+
+		ConfirmedAt:    nullTimeToString(e.ConfirmedAt),
+		PlannedAmount:  nullInt32ToInt(e.PlannedAmount),
+		PlannedSpentAt: nullTimeToString(e.PlannedSpentAt),
+		Version:        int(e.Version),
+		RefundedAmount: int(e.RefundedAmount),
 	}
 }
 
-func (r *expenseRepositorySQLC) GetExpenseByID(userID string, id int32) (models.Expense, error) {
-	row, err := r.q.GetExpenseWithCategoryByID(context.Background(), db.GetExpenseWithCategoryByIDParams{
-		UserID: userID,
-		ID:     id,
+func (r *expenseRepositorySQLC) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
+	return getExpenseWithSplits(ctx, r.queries(ctx), ledgerID, id)
+}
+
+// getExpenseWithSplits はゴミ箱に無い支出を内訳と一緒に返します。
+func getExpenseWithSplits(ctx context.Context, q *db.Queries, ledgerID int32, id int32) (models.Expense, error) {
+	row, err := q.GetExpenseWithCategoryByID(ctx, db.GetExpenseWithCategoryByIDParams{
+		LedgerID: ledgerID,
+		ID:       id,
 	})
 	if err != nil {
 		return models.Expense{}, err
 	}
 
-	return dbExpenseToModel(row), nil
+	expense := dbExpenseToModel(row)
+	splits, err := expenseSplitsByID(ctx, q, []int32{id})
+	if err != nil {
+		return models.Expense{}, err
+	}
+	expense.Splits = splits[id]
+	return expense, nil
 }
 
-func (r *expenseRepositorySQLC) DeleteExpense(userID string, id int32) error {
-	return r.q.DeleteExpense(context.Background(), db.DeleteExpenseParams{
-		ID:     id,
-		UserID: userID,
+func (r *expenseRepositorySQLC) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error {
+	n, err := r.queries(ctx).TrashExpense(ctx, db.TrashExpenseParams{
+		ID:       id,
+		LedgerID: ledgerID,
+		Version:  intToNullInt32(version),
 	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-func (r *expenseRepositorySQLC) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (r *expenseRepositorySQLC) UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error) {
 	// 複数フォーマットに対応するため、RFC3339 をまず試し、失敗したら日付のみ (2006-01-02) を試す
 	var spentAt time.Time
 	var err error
@@ -148,13 +293,75 @@ func (r *expenseRepositorySQLC) UpdateExpense(userID string, input models.Update
 		Memo:       sql.NullString{String: input.Memo, Valid: input.Memo != ""},
 		SpentAt:    spentAt,
 		Status:     defaultStatus(input.Status),
-		UserID:     userID,
+		LedgerID:   ledgerID,
+		Version:    intToNullInt32(input.Version),
 	}
-	err = r.q.UpdateExpense(context.Background(), params)
-
+	n, err := r.queries(ctx).UpdateExpense(ctx, params)
 	if err != nil {
 		return models.Expense{}, err
 	}
+	if n == 0 {
+		return models.Expense{}, sql.ErrNoRows
+	}
+	if err := replaceExpenseSplits(ctx, r.queries(ctx), int32(input.ID), input.Splits); err != nil {
+		return models.Expense{}, err
+	}
+
+	return r.GetExpenseByID(ctx, ledgerID, int32(input.ID))
+}
+
+// expenseSplitsByID は支出ごとの内訳を返します。内訳の無い支出はマップに含めません。
+func expenseSplitsByID(ctx context.Context, q *db.Queries, ids []int32) (map[int32][]models.ExpenseSplit, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := q.ListExpenseSplits(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int32][]models.ExpenseSplit)
+	for _, row := range rows {
+		out[row.ExpenseID] = append(out[row.ExpenseID], models.ExpenseSplit{
+			Category: models.Category{ID: int(row.CategoryID), Name: row.CategoryName},
+			Amount:   int(row.Amount),
+			Memo:     row.Memo,
+		})
+	}
+	return out, nil
+}
+
+// replaceExpenseSplits は支出の内訳を splits で置き換えます。空なら内訳を無くします。
+func replaceExpenseSplits(ctx context.Context, q *db.Queries, expenseID int32, splits []models.ExpenseSplitInput) error {
+	if err := q.DeleteExpenseSplits(ctx, expenseID); err != nil {
+		return err
+	}
+	for i, split := range splits {
+		err := q.CreateExpenseSplit(ctx, db.CreateExpenseSplitParams{
+			ExpenseID:  expenseID,
+			Position:   int32(i),
+			CategoryID: int32(*split.CategoryID),
+			Amount:     int32(*split.Amount),
+			Memo:       split.Memo,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return r.GetExpenseByID(userID, int32(input.ID))
+func intToNullInt32(v *int) sql.NullInt32 {
+	if v == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*v), Valid: true}
+}
+
+func nullInt32ToInt(n sql.NullInt32) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int32)
+	return &v
 }
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type expenseTrashRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewExpenseTrashRepositorySQLC(q *db.Queries) repositories.ExpenseTrashRepository {
+	return &expenseTrashRepositorySQLC{q: q}
+}
+
+func (r *expenseTrashRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *expenseTrashRepositorySQLC) ListTrashed(ctx context.Context, ledgerID int32) ([]models.TrashedExpense, error) {
+	rows, err := r.queries(ctx).ListTrashedExpenses(ctx, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses := make([]models.TrashedExpense, 0, len(rows))
+	ids := make([]int32, 0, len(rows))
+	for _, e := range rows {
+		ids = append(ids, e.ID)
+		memo := ""
+		if e.Memo.Valid {
+			memo = e.Memo.String
+		}
+		expenses = append(expenses, models.TrashedExpense{
+			Expense: models.Expense{
+				ID:       int(e.ID),
+				Amount:   int(e.Amount),
+				Memo:     memo,
+				SpentAt:  e.SpentAt.Format(time.RFC3339),
+				Status:   e.Status,
+				Category: models.Category{ID: int(e.CategoryID), Name: e.CategoryName},
+
+				ConfirmedAt:    nullTimeToString(e.ConfirmedAt),
+				PlannedAmount:  nullInt32ToInt(e.PlannedAmount),
+				PlannedSpentAt: nullTimeToString(e.PlannedSpentAt),
+				Version:        int(e.Version),
+				RefundedAmount: int(e.RefundedAmount),
+			},
+			DeletedAt: e.DeletedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	splits, err := expenseSplitsByID(ctx, r.queries(ctx), ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range expenses {
+		expenses[i].Splits = splits[int32(expenses[i].ID)]
+	}
+	return expenses, nil
+}
+
+func (r *expenseTrashRepositorySQLC) Restore(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
+	n, err := r.queries(ctx).RestoreExpense(ctx, db.RestoreExpenseParams{
+		ID:       id,
+		LedgerID: ledgerID,
+	})
+	if err != nil {
+		return models.Expense{}, err
+	}
+	if n == 0 {
+		return models.Expense{}, sql.ErrNoRows
+	}
+
+	return getExpenseWithSplits(ctx, r.queries(ctx), ledgerID, id)
+}
+
+func (r *expenseTrashRepositorySQLC) PurgeTrashed(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries(ctx).PurgeTrashedExpenses(ctx, sql.NullTime{Time: before, Valid: true})
+}
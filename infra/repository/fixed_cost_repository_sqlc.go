@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	db "money-buddy-backend/db/generated"
@@ -25,11 +26,12 @@ func (r *fixedCostRepositorySQLC) queries(ctx context.Context) *db.Queries {
 	return r.q
 }
 
-func (r *fixedCostRepositorySQLC) CreateFixedCost(ctx context.Context, userID string, name string, amount int) (models.FixedCost, error) {
+func (r *fixedCostRepositorySQLC) CreateFixedCost(ctx context.Context, ledgerID int32, userID string, name string, amount int) (models.FixedCost, error) {
 	params := db.CreateFixedCostParams{
-		UserID: userID,
-		Name:   name,
-		Amount: int32(amount),
+		UserID:   sql.NullString{String: userID, Valid: true},
+		Name:     name,
+		Amount:   int32(amount),
+		LedgerID: ledgerID,
 	}
 	row, err := r.queries(ctx).CreateFixedCost(ctx, params)
 	if err != nil {
@@ -39,8 +41,8 @@ func (r *fixedCostRepositorySQLC) CreateFixedCost(ctx context.Context, userID st
 	return dbFixedCostToModel(row), nil
 }
 
-func (r *fixedCostRepositorySQLC) ListFixedCostsByUser(ctx context.Context, userID string) ([]models.FixedCost, error) {
-	items, err := r.queries(ctx).ListFixedCostsByUser(ctx, userID)
+func (r *fixedCostRepositorySQLC) ListFixedCostsByLedger(ctx context.Context, ledgerID int32) ([]models.FixedCost, error) {
+	items, err := r.queries(ctx).ListFixedCostsByLedger(ctx, ledgerID)
 	if err != nil {
 		return nil, err
 	}
@@ -53,46 +55,49 @@ func (r *fixedCostRepositorySQLC) ListFixedCostsByUser(ctx context.Context, user
 	return out, nil
 }
 
-func (r *fixedCostRepositorySQLC) DeleteFixedCostsByUser(ctx context.Context, userID string) error {
-	return r.queries(ctx).DeleteFixedCostsByUser(ctx, userID)
+func (r *fixedCostRepositorySQLC) DeleteFixedCostsByLedger(ctx context.Context, ledgerID int32) error {
+	return r.queries(ctx).DeleteFixedCostsByLedger(ctx, ledgerID)
 }
 
-func (r *fixedCostRepositorySQLC) BulkCreateFixedCosts(ctx context.Context, userID string, fixedCosts []models.FixedCostInput) error {
+func (r *fixedCostRepositorySQLC) BulkCreateFixedCosts(ctx context.Context, ledgerID int32, userID string, fixedCosts []models.FixedCostInput) error {
 	if len(fixedCosts) == 0 {
 		return nil
 	}
 
+	ledgerIDs := make([]int32, 0, len(fixedCosts))
 	userIDs := make([]string, 0, len(fixedCosts))
 	names := make([]string, 0, len(fixedCosts))
 	amounts := make([]int32, 0, len(fixedCosts))
 	for _, fc := range fixedCosts {
+		ledgerIDs = append(ledgerIDs, ledgerID)
 		userIDs = append(userIDs, userID)
 		names = append(names, fc.Name)
 		amounts = append(amounts, int32(fc.Amount))
 	}
 
 	params := db.BulkCreateFixedCostsParams{
-		Column1: userIDs,
-		Column2: names,
-		Column3: amounts,
+		Column1: ledgerIDs,
+		Column2: userIDs,
+		Column3: names,
+		Column4: amounts,
 	}
 	return r.queries(ctx).BulkCreateFixedCosts(ctx, params)
 }
 
-func (r *fixedCostRepositorySQLC) UpdateFixedCost(ctx context.Context, id int32, userID string, name string, amount int) error {
+func (r *fixedCostRepositorySQLC) UpdateFixedCost(ctx context.Context, id int32, ledgerID int32, name string, amount int) error {
 	params := db.UpdateFixedCostParams{
-		ID:     id,
-		Name:   name,
-		Amount: int32(amount),
-		UserID: userID,
+		ID:       id,
+		Name:     name,
+		Amount:   int32(amount),
+		LedgerID: ledgerID,
 	}
 	return r.queries(ctx).UpdateFixedCost(ctx, params)
 }
 
-func (r *fixedCostRepositorySQLC) DeleteFixedCost(ctx context.Context, id int32, userID string) error {
+func (r *fixedCostRepositorySQLC) DeleteFixedCost(ctx context.Context, id int32, ledgerID int32) error {
 	return r.queries(ctx).DeleteFixedCost(ctx, db.DeleteFixedCostParams{
-		ID:     id,
-		UserID: userID,
+		ID:       id,
+		LedgerID: ledgerID,
 	})
 }
 
@@ -108,7 +113,8 @@ func dbFixedCostToModel(fc db.FixedCost) models.FixedCost {
 
 	return models.FixedCost{
 		ID:        int(fc.ID),
-		UserID:    fc.UserID,
+		LedgerID:  int(fc.LedgerID),
+		UserID:    fc.UserID.String,
 		Name:      fc.Name,
 		Amount:    int(fc.Amount),
 		CreatedAt: createdAt,
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type idempotencyKeyRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewIdempotencyKeyRepositorySQLC(q *db.Queries) repositories.IdempotencyKeyRepository {
+	return &idempotencyKeyRepositorySQLC{q: q}
+}
+
+func (r *idempotencyKeyRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *idempotencyKeyRepositorySQLC) GetKey(ctx context.Context, userID string, key string, now time.Time) (models.IdempotencyKey, error) {
+	row, err := r.queries(ctx).GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		UserID:         userID,
+		IdempotencyKey: key,
+		Now:            now,
+	})
+	if err != nil {
+		return models.IdempotencyKey{}, err
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal(row.ResponseHeaders, &headers); err != nil {
+		return models.IdempotencyKey{}, err
+	}
+	return models.IdempotencyKey{
+		UserID:      row.UserID,
+		Key:         row.IdempotencyKey,
+		RequestHash: row.RequestHash,
+		Response: models.IdempotentResponse{
+			Status:  int(row.ResponseStatus),
+			Headers: headers,
+			Body:    row.ResponseBody,
+		},
+		CreatedAt: row.CreatedAt,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
+
+func (r *idempotencyKeyRepositorySQLC) SaveKey(ctx context.Context, key models.IdempotencyKey) (bool, error) {
+	headers, err := json.Marshal(key.Response.Headers)
+	if err != nil {
+		return false, err
+	}
+	body := key.Response.Body
+	if body == nil {
+		// response_body は NOT NULL のため、本文の無いレスポンスは空のバイト列として保存する
+		body = []byte{}
+	}
+
+	n, err := r.queries(ctx).SaveIdempotencyKey(ctx, db.SaveIdempotencyKeyParams{
+		UserID:          key.UserID,
+		IdempotencyKey:  key.Key,
+		RequestHash:     key.RequestHash,
+		ResponseStatus:  int32(key.Response.Status),
+		ResponseHeaders: headers,
+		ResponseBody:    body,
+		CreatedAt:       key.CreatedAt,
+		ExpiresAt:       key.ExpiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *idempotencyKeyRepositorySQLC) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries(ctx).PurgeExpiredIdempotencyKeys(ctx, before)
+}
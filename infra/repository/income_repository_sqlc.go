@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type incomeRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewIncomeRepositorySQLC(q *db.Queries) repositories.IncomeRepository {
+	return &incomeRepositorySQLC{q: q}
+}
+
+func (r *incomeRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *incomeRepositorySQLC) CreateIncome(ctx context.Context, ledgerID int32, userID string, income models.Income) (models.Income, error) {
+	receivedAt, err := time.Parse("2006-01-02", income.ReceivedAt)
+	if err != nil {
+		return models.Income{}, err
+	}
+	row, err := r.queries(ctx).CreateIncome(ctx, db.CreateIncomeParams{
+		LedgerID:   ledgerID,
+		UserID:     sql.NullString{String: userID, Valid: true},
+		Source:     income.Source,
+		Amount:     int32(income.Amount),
+		ReceivedAt: receivedAt,
+		Status:     income.Status,
+	})
+	if err != nil {
+		return models.Income{}, err
+	}
+	return dbIncomeToModel(row), nil
+}
+
+func (r *incomeRepositorySQLC) GetIncome(ctx context.Context, ledgerID int32, id int32) (models.Income, error) {
+	row, err := r.queries(ctx).GetIncome(ctx, db.GetIncomeParams{ID: id, LedgerID: ledgerID})
+	if err != nil {
+		return models.Income{}, err
+	}
+	return dbIncomeToModel(row), nil
+}
+
+func (r *incomeRepositorySQLC) ListIncomes(ctx context.Context, ledgerID int32, filter models.IncomeFilter) ([]models.Income, error) {
+	items, err := r.queries(ctx).ListIncomes(ctx, db.ListIncomesParams{
+		LedgerID: ledgerID,
+		FromDate: sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:   sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+		Status:   sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Income, 0, len(items))
+	for _, it := range items {
+		out = append(out, dbIncomeToModel(it))
+	}
+	return out, nil
+}
+
+func (r *incomeRepositorySQLC) UpdateIncome(ctx context.Context, ledgerID int32, income models.Income) (models.Income, error) {
+	receivedAt, err := time.Parse("2006-01-02", income.ReceivedAt)
+	if err != nil {
+		return models.Income{}, err
+	}
+	row, err := r.queries(ctx).UpdateIncome(ctx, db.UpdateIncomeParams{
+		ID:         int32(income.ID),
+		LedgerID:   ledgerID,
+		Source:     income.Source,
+		Amount:     int32(income.Amount),
+		ReceivedAt: receivedAt,
+		Status:     income.Status,
+	})
+	if err != nil {
+		return models.Income{}, err
+	}
+	return dbIncomeToModel(row), nil
+}
+
+func (r *incomeRepositorySQLC) DeleteIncome(ctx context.Context, ledgerID int32, id int32) (bool, error) {
+	n, err := r.queries(ctx).DeleteIncome(ctx, db.DeleteIncomeParams{ID: id, LedgerID: ledgerID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *incomeRepositorySQLC) MonthlySummary(ctx context.Context, ledgerID int32, month time.Time) (models.IncomeSummary, error) {
+	row, err := r.queries(ctx).GetMonthlyIncomeSummary(ctx, db.GetMonthlyIncomeSummaryParams{Month: month, LedgerID: ledgerID})
+	if err != nil {
+		return models.IncomeSummary{}, err
+	}
+	return models.IncomeSummary{
+		Income:         int(row.Income),
+		ReceivedIncome: int(row.ReceivedIncome),
+		PlannedIncome:  int(row.PlannedIncome),
+		ExpectedIncome: int(row.ExpectedIncome),
+	}, nil
+}
+
+func dbIncomeToModel(row db.Income) models.Income {
+	return models.Income{
+		ID:         int(row.ID),
+		UserID:     row.UserID.String,
+		Source:     row.Source,
+		Amount:     int(row.Amount),
+		ReceivedAt: row.ReceivedAt.Format("2006-01-02"),
+		Status:     row.Status,
+	}
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type ledgerRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewLedgerRepositorySQLC(q *db.Queries) repositories.LedgerRepository {
+	return &ledgerRepositorySQLC{q: q}
+}
+
+func (r *ledgerRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *ledgerRepositorySQLC) CreateLedger(ctx context.Context, name string) (models.Ledger, error) {
+	row, err := r.queries(ctx).CreateLedger(ctx, name)
+	if err != nil {
+		return models.Ledger{}, err
+	}
+	return models.Ledger{
+		ID:        int(row.ID),
+		Name:      row.Name,
+		CreatedAt: row.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: row.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (r *ledgerRepositorySQLC) ListLedgersByUser(ctx context.Context, userID string) ([]models.Ledger, error) {
+	items, err := r.queries(ctx).ListLedgersByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.Ledger, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.Ledger{
+			ID:          int(it.ID),
+			Name:        it.Name,
+			MemberCount: int(it.MemberCount),
+			Active:      it.Active,
+			Role:        it.Role,
+			CreatedAt:   it.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:   it.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
+
+func (r *ledgerRepositorySQLC) GetLedgerForMember(ctx context.Context, ledgerID int32, userID string) (models.Ledger, error) {
+	row, err := r.queries(ctx).GetLedgerForMember(ctx, db.GetLedgerForMemberParams{
+		UserID:   userID,
+		LedgerID: ledgerID,
+	})
+	if err != nil {
+		return models.Ledger{}, err
+	}
+	return models.Ledger{
+		ID:          int(row.ID),
+		Name:        row.Name,
+		MemberCount: int(row.MemberCount),
+		Active:      row.Active,
+		Role:        row.Role,
+		CreatedAt:   row.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   row.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (r *ledgerRepositorySQLC) DeleteLedger(ctx context.Context, ledgerID int32) error {
+	return r.queries(ctx).DeleteLedger(ctx, ledgerID)
+}
+
+func (r *ledgerRepositorySQLC) AddMember(ctx context.Context, ledgerID int32, userID string, role string) (bool, error) {
+	n, err := r.queries(ctx).AddLedgerMember(ctx, db.AddLedgerMemberParams{
+		LedgerID: ledgerID,
+		UserID:   userID,
+		Role:     role,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *ledgerRepositorySQLC) RemoveMember(ctx context.Context, ledgerID int32, userID string) (bool, error) {
+	n, err := r.queries(ctx).RemoveLedgerMember(ctx, db.RemoveLedgerMemberParams{
+		LedgerID: ledgerID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *ledgerRepositorySQLC) CountMembers(ctx context.Context, ledgerID int32) (int, error) {
+	n, err := r.queries(ctx).CountLedgerMembers(ctx, ledgerID)
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func (r *ledgerRepositorySQLC) ListMembers(ctx context.Context, ledgerID int32) ([]models.LedgerMember, error) {
+	items, err := r.queries(ctx).ListLedgerMembers(ctx, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.LedgerMember, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.LedgerMember{
+			UserID:   it.UserID,
+			Role:     it.Role,
+			JoinedAt: it.JoinedAt.Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
+
+func (r *ledgerRepositorySQLC) GetMemberRole(ctx context.Context, ledgerID int32, userID string) (string, error) {
+	return r.queries(ctx).GetLedgerMemberRole(ctx, db.GetLedgerMemberRoleParams{
+		LedgerID: ledgerID,
+		UserID:   userID,
+	})
+}
+
+func (r *ledgerRepositorySQLC) UpdateMemberRole(ctx context.Context, ledgerID int32, userID string, role string) (models.LedgerMember, error) {
+	row, err := r.queries(ctx).UpdateLedgerMemberRole(ctx, db.UpdateLedgerMemberRoleParams{
+		LedgerID: ledgerID,
+		UserID:   userID,
+		Role:     role,
+	})
+	if err != nil {
+		return models.LedgerMember{}, err
+	}
+	return models.LedgerMember{
+		UserID:   row.UserID,
+		Role:     row.Role,
+		JoinedAt: row.JoinedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (r *ledgerRepositorySQLC) GetActiveLedgerID(ctx context.Context, userID string) (int32, error) {
+	return r.queries(ctx).GetActiveLedgerID(ctx, userID)
+}
+
+func (r *ledgerRepositorySQLC) SetActiveLedger(ctx context.Context, userID string, ledgerID int32) error {
+	return r.queries(ctx).SetActiveLedger(ctx, db.SetActiveLedgerParams{
+		ID:             userID,
+		ActiveLedgerID: sql.NullInt32{Int32: ledgerID, Valid: true},
+	})
+}
+
+func (r *ledgerRepositorySQLC) ReassignActiveLedger(ctx context.Context, userID string, ledgerID int32) error {
+	return r.queries(ctx).ReassignActiveLedger(ctx, db.ReassignActiveLedgerParams{
+		ID:             userID,
+		ActiveLedgerID: sql.NullInt32{Int32: ledgerID, Valid: true},
+	})
+}
+
+func (r *ledgerRepositorySQLC) CreateInvite(ctx context.Context, ledgerID int32, createdBy string, codeHash string, expiresAt time.Time) error {
+	_, err := r.queries(ctx).CreateLedgerInvite(ctx, db.CreateLedgerInviteParams{
+		LedgerID:  ledgerID,
+		CodeHash:  codeHash,
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+	})
+	return err
+}
+
+func (r *ledgerRepositorySQLC) ClaimInvite(ctx context.Context, codeHash string, userID string, now time.Time) (int32, error) {
+	return r.queries(ctx).ClaimLedgerInvite(ctx, db.ClaimLedgerInviteParams{
+		CodeHash: codeHash,
+		UsedBy:   sql.NullString{String: userID, Valid: true},
+		Now:      now,
+	})
+}
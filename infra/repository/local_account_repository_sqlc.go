@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type localAccountRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewLocalAccountRepositorySQLC(q *db.Queries) repositories.LocalAccountRepository {
+	return &localAccountRepositorySQLC{q: q}
+}
+
+func (r *localAccountRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *localAccountRepositorySQLC) CreateAccount(ctx context.Context, userID string, email string, passwordHash string) (bool, error) {
+	n, err := r.queries(ctx).CreateLocalAccount(ctx, db.CreateLocalAccountParams{
+		UserID:       userID,
+		Email:        email,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *localAccountRepositorySQLC) GetAccountByEmail(ctx context.Context, email string) (models.LocalAccount, error) {
+	row, err := r.queries(ctx).GetLocalAccountByEmail(ctx, email)
+	if err != nil {
+		return models.LocalAccount{}, err
+	}
+	return dbLocalAccountToModel(row), nil
+}
+
+func (r *localAccountRepositorySQLC) GetAccountByUserID(ctx context.Context, userID string) (models.LocalAccount, error) {
+	row, err := r.queries(ctx).GetLocalAccountByUserID(ctx, userID)
+	if err != nil {
+		return models.LocalAccount{}, err
+	}
+	return dbLocalAccountToModel(row), nil
+}
+
+func (r *localAccountRepositorySQLC) UpdatePassword(ctx context.Context, userID string, passwordHash string) error {
+	return r.queries(ctx).UpdateLocalAccountPassword(ctx, db.UpdateLocalAccountPasswordParams{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+	})
+}
+
+func dbLocalAccountToModel(a db.LocalAccount) models.LocalAccount {
+	return models.LocalAccount{
+		UserID:       a.UserID,
+		Email:        a.Email,
+		PasswordHash: a.PasswordHash,
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type payeeRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewPayeeRepositorySQLC(q *db.Queries) repositories.PayeeRepository {
+	return &payeeRepositorySQLC{q: q}
+}
+
+func (r *payeeRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *payeeRepositorySQLC) ListPayeeSuggestions(ctx context.Context, userID string, prefix string, limit int32) ([]models.PayeeSuggestion, error) {
+	items, err := r.queries(ctx).ListPayeeSuggestions(ctx, db.ListPayeeSuggestionsParams{UserID: userID, Prefix: prefix, MaxRows: limit})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.PayeeSuggestion, 0, len(items))
+	for _, it := range items {
+		s := models.PayeeSuggestion{
+			Payee:    models.Payee{ID: int(it.ID), Name: it.Name},
+			UseCount: int(it.UseCount),
+		}
+		if it.LastUsedAt.Valid {
+			last := it.LastUsedAt.Time.Format("2006-01-02")
+			s.LastUsedAt = &last
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (r *payeeRepositorySQLC) ListPayeesByIDs(ctx context.Context, userID string, ids []int32) ([]models.Payee, error) {
+	items, err := r.queries(ctx).ListPayeesByIDs(ctx, db.ListPayeesByIDsParams{UserID: userID, Ids: ids})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Payee, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.Payee{ID: int(it.ID), Name: it.Name})
+	}
+	return out, nil
+}
+
+func (r *payeeRepositorySQLC) CreatePayee(ctx context.Context, userID string, name string) (models.Payee, bool, error) {
+	row, err := r.queries(ctx).CreatePayee(ctx, db.CreatePayeeParams{UserID: userID, Name: name})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Payee{}, false, nil
+		}
+		return models.Payee{}, false, err
+	}
+	return models.Payee{ID: int(row.ID), Name: row.Name}, true, nil
+}
+
+func (r *payeeRepositorySQLC) RenamePayee(ctx context.Context, userID string, id int32, name string) (models.Payee, error) {
+	row, err := r.queries(ctx).RenamePayee(ctx, db.RenamePayeeParams{Name: name, ID: id, UserID: userID})
+	if err != nil {
+		return models.Payee{}, err
+	}
+	return models.Payee{ID: int(row.ID), Name: row.Name}, nil
+}
+
+func (r *payeeRepositorySQLC) DeletePayee(ctx context.Context, userID string, id int32) (bool, error) {
+	n, err := r.queries(ctx).DeletePayee(ctx, db.DeletePayeeParams{ID: id, UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *payeeRepositorySQLC) MergePayeeLinks(ctx context.Context, sourceID int32, targetID int32) error {
+	return r.queries(ctx).MergePayeeLinks(ctx, db.MergePayeeLinksParams{TargetID: targetID, SourceID: sourceID})
+}
+
+func (r *payeeRepositorySQLC) ListExpensePayees(ctx context.Context, expenseIDs []int32) (map[int32]models.Payee, error) {
+	out := make(map[int32]models.Payee)
+	if len(expenseIDs) == 0 {
+		return out, nil
+	}
+	items, err := r.queries(ctx).ListExpensePayees(ctx, expenseIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		out[it.ExpenseID] = models.Payee{ID: int(it.ID), Name: it.Name}
+	}
+	return out, nil
+}
+
+func (r *payeeRepositorySQLC) SetExpensePayee(ctx context.Context, expenseID int32, payeeID *int32) error {
+	if payeeID == nil {
+		return r.queries(ctx).DeleteExpensePayee(ctx, expenseID)
+	}
+	return r.queries(ctx).SetExpensePayee(ctx, db.SetExpensePayeeParams{ExpenseID: expenseID, PayeeID: *payeeID})
+}
+
+func (r *payeeRepositorySQLC) Totals(ctx context.Context, ledgerID int32, filter models.PayeeTotalsFilter) ([]models.PayeeTotal, error) {
+	items, err := r.queries(ctx).ListPayeeTotals(ctx, db.ListPayeeTotalsParams{
+		LedgerID: ledgerID,
+		FromDate: sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:   sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.PayeeTotal, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.PayeeTotal{
+			Payee:          models.Payee{ID: int(it.PayeeID), Name: it.PayeeName},
+			ExpenseCount:   int(it.ExpenseCount),
+			ConfirmedTotal: int(it.ConfirmedTotal),
+			PlannedTotal:   int(it.PlannedTotal),
+		})
+	}
+	return out, nil
+}
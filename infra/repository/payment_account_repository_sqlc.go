@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type paymentAccountRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewPaymentAccountRepositorySQLC(q *db.Queries) repositories.PaymentAccountRepository {
+	return &paymentAccountRepositorySQLC{q: q}
+}
+
+func (r *paymentAccountRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *paymentAccountRepositorySQLC) ListPaymentAccounts(ctx context.Context, userID string) ([]models.PaymentAccount, error) {
+	items, err := r.queries(ctx).ListPaymentAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.PaymentAccount, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.PaymentAccount{
+			ID:             int(it.ID),
+			Name:           it.Name,
+			Type:           it.Type,
+			OpeningBalance: int(it.OpeningBalance),
+			Balance:        int(it.Balance),
+		})
+	}
+	return out, nil
+}
+
+func (r *paymentAccountRepositorySQLC) GetPaymentAccount(ctx context.Context, userID string, id int32) (models.PaymentAccount, error) {
+	row, err := r.queries(ctx).GetPaymentAccount(ctx, db.GetPaymentAccountParams{ID: id, UserID: userID})
+	if err != nil {
+		return models.PaymentAccount{}, err
+	}
+	return models.PaymentAccount{
+		ID:             int(row.ID),
+		Name:           row.Name,
+		Type:           row.Type,
+		OpeningBalance: int(row.OpeningBalance),
+		Balance:        int(row.Balance),
+	}, nil
+}
+
+func (r *paymentAccountRepositorySQLC) CreatePaymentAccount(ctx context.Context, userID string, account models.PaymentAccount) (int32, bool, error) {
+	id, err := r.queries(ctx).CreatePaymentAccount(ctx, db.CreatePaymentAccountParams{
+		UserID:         userID,
+		Name:           account.Name,
+		Type:           account.Type,
+		OpeningBalance: int32(account.OpeningBalance),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+func (r *paymentAccountRepositorySQLC) UpdatePaymentAccount(ctx context.Context, userID string, account models.PaymentAccount) (bool, error) {
+	n, err := r.queries(ctx).UpdatePaymentAccount(ctx, db.UpdatePaymentAccountParams{
+		Name:           account.Name,
+		Type:           account.Type,
+		OpeningBalance: int32(account.OpeningBalance),
+		ID:             int32(account.ID),
+		UserID:         userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *paymentAccountRepositorySQLC) DeletePaymentAccount(ctx context.Context, userID string, id int32) (bool, error) {
+	n, err := r.queries(ctx).DeletePaymentAccount(ctx, db.DeletePaymentAccountParams{ID: id, UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *paymentAccountRepositorySQLC) ListExpensePaymentAccounts(ctx context.Context, expenseIDs []int32) (map[int32]models.ExpensePaymentAccount, error) {
+	out := make(map[int32]models.ExpensePaymentAccount)
+	if len(expenseIDs) == 0 {
+		return out, nil
+	}
+	items, err := r.queries(ctx).ListExpensePaymentAccounts(ctx, expenseIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		out[it.ExpenseID] = models.ExpensePaymentAccount{ID: int(it.ID), Name: it.Name, Type: it.Type}
+	}
+	return out, nil
+}
+
+func (r *paymentAccountRepositorySQLC) SetExpensePaymentAccount(ctx context.Context, expenseID int32, accountID *int32) error {
+	if accountID == nil {
+		return r.queries(ctx).DeleteExpensePaymentAccount(ctx, expenseID)
+	}
+	return r.queries(ctx).SetExpensePaymentAccount(ctx, db.SetExpensePaymentAccountParams{ExpenseID: expenseID, PaymentAccountID: *accountID})
+}
+
+func (r *paymentAccountRepositorySQLC) ListEntries(ctx context.Context, userID string, id int32, filter models.PaymentAccountEntriesFilter) ([]models.PaymentAccountEntry, error) {
+	items, err := r.queries(ctx).ListPaymentAccountEntries(ctx, db.ListPaymentAccountEntriesParams{
+		ID:       id,
+		UserID:   userID,
+		FromDate: sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:   sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.PaymentAccountEntry, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.PaymentAccountEntry{
+			ExpenseID:      int(it.ExpenseID),
+			SpentAt:        it.SpentAt.Format("2006-01-02"),
+			Amount:         int(it.Amount),
+			RefundedAmount: int(it.RefundedAmount),
+			Memo:           it.Memo,
+			Balance:        int(it.Balance),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type personalAccessTokenRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewPersonalAccessTokenRepositorySQLC(q *db.Queries) repositories.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepositorySQLC{q: q}
+}
+
+func (r *personalAccessTokenRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *personalAccessTokenRepositorySQLC) CreateToken(ctx context.Context, userID string, name string, tokenHash string, scopes []string, expiresAt *time.Time) (models.PersonalAccessToken, error) {
+	params := db.CreatePersonalAccessTokenParams{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Scopes:    scopes,
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	row, err := r.queries(ctx).CreatePersonalAccessToken(ctx, params)
+	if err != nil {
+		return models.PersonalAccessToken{}, err
+	}
+	return dbPersonalAccessTokenToModel(row), nil
+}
+
+func (r *personalAccessTokenRepositorySQLC) ListTokensByUser(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	items, err := r.queries(ctx).ListPersonalAccessTokensByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.PersonalAccessToken, 0, len(items))
+	for _, it := range items {
+		out = append(out, dbPersonalAccessTokenToModel(it))
+	}
+	return out, nil
+}
+
+func (r *personalAccessTokenRepositorySQLC) GetActiveTokenByHash(ctx context.Context, tokenHash string) (models.PersonalAccessToken, error) {
+	row, err := r.queries(ctx).GetActivePersonalAccessTokenByHash(ctx, tokenHash)
+	if err != nil {
+		return models.PersonalAccessToken{}, err
+	}
+	return dbPersonalAccessTokenToModel(row), nil
+}
+
+func (r *personalAccessTokenRepositorySQLC) RevokeToken(ctx context.Context, id int32, userID string) (bool, error) {
+	n, err := r.queries(ctx).RevokePersonalAccessToken(ctx, db.RevokePersonalAccessTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *personalAccessTokenRepositorySQLC) TouchToken(ctx context.Context, id int32) error {
+	return r.queries(ctx).TouchPersonalAccessToken(ctx, id)
+}
+
+func dbPersonalAccessTokenToModel(t db.PersonalAccessToken) models.PersonalAccessToken {
+	return models.PersonalAccessToken{
+		ID:         int(t.ID),
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Scopes:     t.Scopes,
+		ExpiresAt:  nullTimeToString(t.ExpiresAt),
+		LastUsedAt: nullTimeToString(t.LastUsedAt),
+		RevokedAt:  nullTimeToString(t.RevokedAt),
+		CreatedAt:  t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// nullTimeToString は NULL 許容の時刻を RFC3339 文字列のポインタに変換します（NULL は nil）。
+func nullTimeToString(t sql.NullTime) *string {
+	if !t.Valid {
+		return nil
+	}
+	s := t.Time.Format(time.RFC3339)
+	return &s
+}
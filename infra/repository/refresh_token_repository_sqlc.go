@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type refreshTokenRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewRefreshTokenRepositorySQLC(q *db.Queries) repositories.RefreshTokenRepository {
+	return &refreshTokenRepositorySQLC{q: q}
+}
+
+func (r *refreshTokenRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *refreshTokenRepositorySQLC) CreateToken(ctx context.Context, userID string, familyID string, tokenHash string, expiresAt time.Time) error {
+	return r.queries(ctx).CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (r *refreshTokenRepositorySQLC) GetTokenByHashForUpdate(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	row, err := r.queries(ctx).GetRefreshTokenByHashForUpdate(ctx, tokenHash)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	return models.RefreshToken{
+		ID:        int(row.ID),
+		UserID:    row.UserID,
+		FamilyID:  row.FamilyID,
+		ExpiresAt: row.ExpiresAt,
+		Revoked:   row.RevokedAt.Valid,
+	}, nil
+}
+
+func (r *refreshTokenRepositorySQLC) RevokeToken(ctx context.Context, id int32) error {
+	return r.queries(ctx).RevokeRefreshToken(ctx, id)
+}
+
+func (r *refreshTokenRepositorySQLC) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.queries(ctx).RevokeRefreshTokenFamily(ctx, familyID)
+	return err
+}
+
+func (r *refreshTokenRepositorySQLC) RevokeTokensByUser(ctx context.Context, userID string) error {
+	_, err := r.queries(ctx).RevokeRefreshTokensByUser(ctx, userID)
+	return err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type refundRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewRefundRepositorySQLC(q *db.Queries) repositories.RefundRepository {
+	return &refundRepositorySQLC{q: q}
+}
+
+func (r *refundRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *refundRepositorySQLC) ListRefunds(ctx context.Context, ledgerID int32, expenseID int32) ([]models.ExpenseRefund, error) {
+	items, err := r.queries(ctx).ListExpenseRefunds(ctx, db.ListExpenseRefundsParams{ExpenseID: expenseID, LedgerID: ledgerID})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.ExpenseRefund, 0, len(items))
+	for _, it := range items {
+		out = append(out, dbExpenseRefundToModel(it))
+	}
+	return out, nil
+}
+
+func (r *refundRepositorySQLC) CreateRefund(ctx context.Context, ledgerID int32, refund models.ExpenseRefund) (models.ExpenseRefund, bool, error) {
+	refundedAt, err := time.Parse("2006-01-02", refund.RefundedAt)
+	if err != nil {
+		return models.ExpenseRefund{}, false, err
+	}
+	q := r.queries(ctx)
+	// 先に支出の行を更新してロックし、同時に記録される返金と合わせても金額を超えないようにする
+	n, err := q.AddExpenseRefundedAmount(ctx, db.AddExpenseRefundedAmountParams{
+		Amount:   int32(refund.Amount),
+		ID:       int32(refund.ExpenseID),
+		LedgerID: ledgerID,
+	})
+	if err != nil {
+		return models.ExpenseRefund{}, false, err
+	}
+	if n == 0 {
+		return models.ExpenseRefund{}, false, nil
+	}
+	row, err := q.CreateExpenseRefund(ctx, db.CreateExpenseRefundParams{
+		ExpenseID:  int32(refund.ExpenseID),
+		Amount:     int32(refund.Amount),
+		RefundedAt: refundedAt,
+		Memo:       refund.Memo,
+	})
+	if err != nil {
+		return models.ExpenseRefund{}, false, err
+	}
+	return dbExpenseRefundToModel(row), true, nil
+}
+
+func (r *refundRepositorySQLC) DeleteRefund(ctx context.Context, ledgerID int32, expenseID int32, id int32) (bool, error) {
+	q := r.queries(ctx)
+	amount, err := q.DeleteExpenseRefund(ctx, db.DeleteExpenseRefundParams{ID: id, ExpenseID: expenseID, LedgerID: ledgerID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := q.SubtractExpenseRefundedAmount(ctx, db.SubtractExpenseRefundedAmountParams{Amount: amount, ID: expenseID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func dbExpenseRefundToModel(row db.ExpenseRefund) models.ExpenseRefund {
+	return models.ExpenseRefund{
+		ID:         int(row.ID),
+		ExpenseID:  int(row.ExpenseID),
+		Amount:     int(row.Amount),
+		RefundedAt: row.RefundedAt.Format("2006-01-02"),
+		Memo:       row.Memo,
+		CreatedAt:  row.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type tagRepositorySQLC struct {
+	q *db.Queries
+}
+
+func NewTagRepositorySQLC(q *db.Queries) repositories.TagRepository {
+	return &tagRepositorySQLC{q: q}
+}
+
+func (r *tagRepositorySQLC) queries(ctx context.Context) *db.Queries {
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *tagRepositorySQLC) ListTags(ctx context.Context, userID string) ([]models.Tag, error) {
+	items, err := r.queries(ctx).ListTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Tag, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.Tag{ID: int(it.ID), Name: it.Name})
+	}
+	return out, nil
+}
+
+func (r *tagRepositorySQLC) ListTagsByIDs(ctx context.Context, userID string, ids []int32) ([]models.Tag, error) {
+	items, err := r.queries(ctx).ListTagsByIDs(ctx, db.ListTagsByIDsParams{UserID: userID, Ids: ids})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Tag, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.Tag{ID: int(it.ID), Name: it.Name})
+	}
+	return out, nil
+}
+
+func (r *tagRepositorySQLC) CreateTag(ctx context.Context, userID string, name string) (models.Tag, bool, error) {
+	row, err := r.queries(ctx).CreateTag(ctx, db.CreateTagParams{UserID: userID, Name: name})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Tag{}, false, nil
+		}
+		return models.Tag{}, false, err
+	}
+	return models.Tag{ID: int(row.ID), Name: row.Name}, true, nil
+}
+
+func (r *tagRepositorySQLC) RenameTag(ctx context.Context, userID string, id int32, name string) (models.Tag, error) {
+	row, err := r.queries(ctx).RenameTag(ctx, db.RenameTagParams{Name: name, ID: id, UserID: userID})
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return models.Tag{ID: int(row.ID), Name: row.Name}, nil
+}
+
+func (r *tagRepositorySQLC) DeleteTag(ctx context.Context, userID string, id int32) (bool, error) {
+	n, err := r.queries(ctx).DeleteTag(ctx, db.DeleteTagParams{ID: id, UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *tagRepositorySQLC) MergeTagLinks(ctx context.Context, sourceID int32, targetID int32) error {
+	return r.queries(ctx).MergeTagLinks(ctx, db.MergeTagLinksParams{TargetID: targetID, SourceID: sourceID})
+}
+
+func (r *tagRepositorySQLC) ListExpenseTags(ctx context.Context, userID string, expenseIDs []int32) (map[int32][]models.Tag, error) {
+	out := make(map[int32][]models.Tag)
+	if len(expenseIDs) == 0 {
+		return out, nil
+	}
+	items, err := r.queries(ctx).ListExpenseTags(ctx, db.ListExpenseTagsParams{UserID: userID, ExpenseIds: expenseIDs})
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		out[it.ExpenseID] = append(out[it.ExpenseID], models.Tag{ID: int(it.ID), Name: it.Name})
+	}
+	return out, nil
+}
+
+func (r *tagRepositorySQLC) SetExpenseTags(ctx context.Context, userID string, expenseID int32, tagIDs []int32) error {
+	q := r.queries(ctx)
+	if err := q.DeleteExpenseTags(ctx, db.DeleteExpenseTagsParams{ExpenseID: expenseID, UserID: userID}); err != nil {
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	return q.CreateExpenseTags(ctx, db.CreateExpenseTagsParams{ExpenseID: expenseID, TagIds: tagIDs})
+}
+
+func (r *tagRepositorySQLC) MonthlyTotals(ctx context.Context, userID string, ledgerID int32, filter models.TagReportFilter) ([]models.TagMonthlyTotal, error) {
+	items, err := r.queries(ctx).ListTagMonthlyTotals(ctx, db.ListTagMonthlyTotalsParams{
+		UserID:   userID,
+		LedgerID: ledgerID,
+		FromDate: sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:   sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.TagMonthlyTotal, 0, len(items))
+	for _, it := range items {
+		out = append(out, models.TagMonthlyTotal{
+			Tag:            models.Tag{ID: int(it.TagID), Name: it.TagName},
+			Month:          it.Month.Format("2006-01"),
+			ExpenseCount:   int(it.ExpenseCount),
+			ConfirmedTotal: int(it.ConfirmedTotal),
+			PlannedTotal:   int(it.PlannedTotal),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	db "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/repositories"
+)
+
+type userDataPurgerSQLC struct {
+	q     *db.Queries
+	name  string
+	purge func(q *db.Queries, ctx context.Context, userID string) (int64, error)
+}
+
+func (p *userDataPurgerSQLC) Name() string {
+	return p.name
+}
+
+func (p *userDataPurgerSQLC) PurgeUserData(ctx context.Context, userID string) (int64, error) {
+	q := p.q
+	if tx, ok := transaction.TxFromContext(ctx); ok {
+		q = q.WithTx(tx)
+	}
+	return p.purge(q, ctx, userID)
+}
+
+// NewUserDataPurgersSQLC はアカウント削除で消去するユーザー単位のデータの一覧を返します。
+// users を参照するテーブルを追加したら、ここに削除処理を追加してください。
+// 順序は削除順で、他のテーブルから参照されるものほど後ろに置きます。
+func NewUserDataPurgersSQLC(q *db.Queries) []repositories.UserDataPurger {
+	return []repositories.UserDataPurger{
+		&userDataPurgerSQLC{q: q, name: "personal_access_tokens", purge: (*db.Queries).PurgePersonalAccessTokensByUser},
+		&userDataPurgerSQLC{q: q, name: "refresh_tokens", purge: (*db.Queries).PurgeRefreshTokensByUser},
+		&userDataPurgerSQLC{q: q, name: "local_accounts", purge: (*db.Queries).PurgeLocalAccountsByUser},
+		&userDataPurgerSQLC{q: q, name: "user_data_exports", purge: (*db.Queries).PurgeUserDataExportsByUser},
+		&userDataPurgerSQLC{q: q, name: "idempotency_keys", purge: (*db.Queries).PurgeIdempotencyKeysByUser},
+		// 支出へのタグの付与は外部キーの ON DELETE CASCADE で消える
+		&userDataPurgerSQLC{q: q, name: "tags", purge: (*db.Queries).PurgeTagsByUser},
+		// 支出への支払先の付与も同じく消える（共有家計簿の支出は残り、支払先が外れる）
+		&userDataPurgerSQLC{q: q, name: "payees", purge: (*db.Queries).PurgePayeesByUser},
+		&userDataPurgerSQLC{q: q, name: "payment_accounts", purge: (*db.Queries).PurgePaymentAccountsByUser},
+		// 運用者の操作記録は残し、対象ユーザーとの紐付けだけを外す
+		&userDataPurgerSQLC{q: q, name: "admin_audit_logs", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.AnonymizeAdminAuditLogsByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		// 家計簿に属さない操作記録は削除し、共有家計簿の操作記録は操作者だけを外して残す
+		&userDataPurgerSQLC{q: q, name: "audit_events", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.PurgeAuditEventsByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		&userDataPurgerSQLC{q: q, name: "audit_event_actors", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.AnonymizeAuditEventsByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		// 他のメンバーがいない家計簿の支出・固定費・収入は削除し、共有中の家計簿のものは登録者だけを外して残す。
+		// 支出の添付ファイルは外部キーの ON DELETE CASCADE で消え、保存先のファイルは孤立したファイルの削除ジョブが消す
+		&userDataPurgerSQLC{q: q, name: "expenses", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.PurgeExpensesByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		&userDataPurgerSQLC{q: q, name: "shared_expenses", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.AnonymizeExpensesByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		&userDataPurgerSQLC{q: q, name: "fixed_costs", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.PurgeFixedCostsByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		&userDataPurgerSQLC{q: q, name: "shared_fixed_costs", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.AnonymizeFixedCostsByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		&userDataPurgerSQLC{q: q, name: "incomes", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.PurgeIncomesByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		&userDataPurgerSQLC{q: q, name: "shared_incomes", purge: func(q *db.Queries, ctx context.Context, userID string) (int64, error) {
+			return q.AnonymizeIncomesByUser(ctx, sql.NullString{String: userID, Valid: true})
+		}},
+		// 他のメンバーと共有中の家計簿は残し、メンバーシップだけが users の削除で消える
+		&userDataPurgerSQLC{q: q, name: "ledgers", purge: (*db.Queries).PurgeSoleMemberLedgersByUser},
+		// 共有中の家計簿の owner だった場合は、残ったメンバーに owner を引き継ぐ
+		&userDataPurgerSQLC{q: q, name: "ledger_owners", purge: (*db.Queries).PromoteLedgerSuccessorsByUser},
+	}
+}
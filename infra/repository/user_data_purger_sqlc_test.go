@@ -0,0 +1,123 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/repository"
+	"money-buddy-backend/infra/transaction"
+)
+
+// 退会データの削除の結合テスト。
+// db/schema を適用済みの Postgres に接続する DSN を TEST_DATABASE_URL に指定すると実行されます。
+// 削除ジョブと同じくシステムのコンテキストで実行し、最後にロールバックします。
+
+func TestUserDataPurgers_KeepSharedLedgerRows(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	m := transaction.NewTxManager(conn)
+	ctx := transaction.WithSystemIdentity(context.Background())
+	tx, err := m.Begin(ctx)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	txCtx := tx.Context(ctx)
+	sqlTx, ok := transaction.TxFromContext(txCtx)
+	require.True(t, ok)
+
+	exec := func(query string, args ...any) {
+		t.Helper()
+		_, err := sqlTx.ExecContext(txCtx, query, args...)
+		require.NoError(t, err)
+	}
+	insertID := func(query string, args ...any) int32 {
+		t.Helper()
+		var id int32
+		require.NoError(t, sqlTx.QueryRowContext(txCtx, query, args...).Scan(&id))
+		return id
+	}
+
+	// leaving は共有家計簿 shared と自分だけの家計簿 solo に参加し、staying は shared だけに参加している
+	suffix := time.Now().UnixNano()
+	leaving := fmt.Sprintf("purge-test-leaving-%d", suffix)
+	staying := fmt.Sprintf("purge-test-staying-%d", suffix)
+	exec(`INSERT INTO users (id) VALUES ($1), ($2)`, leaving, staying)
+	shared := insertID(`INSERT INTO ledgers (name) VALUES ('shared') RETURNING id`)
+	solo := insertID(`INSERT INTO ledgers (name) VALUES ('solo') RETURNING id`)
+	exec(`INSERT INTO ledger_members (ledger_id, user_id, role) VALUES ($1, $2, 'owner'), ($1, $3, 'editor'), ($4, $2, 'owner')`, shared, leaving, staying, solo)
+	categoryID := insertID(`INSERT INTO categories (name) VALUES ('purge-test') RETURNING id`)
+
+	insertExpense := func(userID string, ledgerID int32) int32 {
+		return insertID(`INSERT INTO expenses (user_id, amount, category_id, spent_at, ledger_id) VALUES ($1, 1000, $2, CURRENT_DATE, $3) RETURNING id`, userID, categoryID, ledgerID)
+	}
+	insertIncome := func(userID string, ledgerID int32) int32 {
+		return insertID(`INSERT INTO incomes (ledger_id, user_id, source, amount, received_at) VALUES ($1, $2, 'salary', 300000, CURRENT_DATE) RETURNING id`, ledgerID, userID)
+	}
+	insertFixedCost := func(userID string, ledgerID int32) int32 {
+		return insertID(`INSERT INTO fixed_costs (user_id, name, amount, ledger_id) VALUES ($1, 'rent', 80000, $2) RETURNING id`, userID, ledgerID)
+	}
+	sharedExpense := insertExpense(leaving, shared)
+	othersExpense := insertExpense(staying, shared)
+	soloExpense := insertExpense(leaving, solo)
+	sharedIncome := insertIncome(leaving, shared)
+	soloIncome := insertIncome(leaving, solo)
+	sharedFixedCost := insertFixedCost(leaving, shared)
+	soloFixedCost := insertFixedCost(leaving, solo)
+
+	// アカウント削除と同じく、すべての Purger を実行してからユーザーを削除する
+	for _, p := range repository.NewUserDataPurgersSQLC(dbgen.New(conn)) {
+		_, err := p.PurgeUserData(txCtx, leaving)
+		require.NoError(t, err, p.Name())
+	}
+	exec(`DELETE FROM users WHERE id = $1`, leaving)
+
+	owner := func(table string, id int32) (sql.NullString, bool) {
+		t.Helper()
+		var userID sql.NullString
+		err := sqlTx.QueryRowContext(txCtx, `SELECT user_id FROM `+table+` WHERE id = $1`, id).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return sql.NullString{}, false
+		}
+		require.NoError(t, err)
+		return userID, true
+	}
+
+	// 共有家計簿の行は残り、登録者だけが外れる
+	for _, row := range []struct {
+		table string
+		id    int32
+	}{{"expenses", sharedExpense}, {"incomes", sharedIncome}, {"fixed_costs", sharedFixedCost}} {
+		userID, found := owner(row.table, row.id)
+		assert.True(t, found, row.table)
+		assert.False(t, userID.Valid, row.table)
+	}
+	userID, found := owner("expenses", othersExpense)
+	assert.True(t, found)
+	assert.Equal(t, staying, userID.String)
+
+	// 本人だけの家計簿の行は家計簿ごと削除される
+	for _, row := range []struct {
+		table string
+		id    int32
+	}{{"expenses", soloExpense}, {"incomes", soloIncome}, {"fixed_costs", soloFixedCost}} {
+		_, found := owner(row.table, row.id)
+		assert.False(t, found, row.table)
+	}
+	var ledgers int
+	require.NoError(t, sqlTx.QueryRowContext(txCtx, `SELECT COUNT(*) FROM ledgers WHERE id = $1`, solo).Scan(&ledgers))
+	assert.Zero(t, ledgers)
+}
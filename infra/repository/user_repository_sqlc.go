@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	db "money-buddy-backend/db/generated"
@@ -52,6 +53,80 @@ func (r *userRepositorySQLC) UpdateUserSettings(ctx context.Context, id string,
 	return r.queries(ctx).UpdateUserSettings(ctx, params)
 }
 
+func (r *userRepositorySQLC) ProvisionUser(ctx context.Context, id string) (bool, error) {
+	n, err := r.queries(ctx).ProvisionUser(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *userRepositorySQLC) MarkSetupCompleted(ctx context.Context, id string) error {
+	return r.queries(ctx).MarkUserSetupCompleted(ctx, id)
+}
+
+func (r *userRepositorySQLC) GetOnboardingProgress(ctx context.Context, id string) (models.OnboardingProgress, error) {
+	row, err := r.queries(ctx).GetUserOnboarding(ctx, id)
+	if err != nil {
+		return models.OnboardingProgress{}, err
+	}
+	return models.OnboardingProgress{
+		SetupCompleted: row.SetupCompletedAt.Valid,
+		HasLedger:      row.HasLedger,
+		HasFixedCosts:  row.HasFixedCosts,
+		HasExpenses:    row.HasExpenses,
+	}, nil
+}
+
+func (r *userRepositorySQLC) RequestDeletion(ctx context.Context, id string, scheduledAt time.Time) (bool, error) {
+	n, err := r.queries(ctx).RequestUserDeletion(ctx, db.RequestUserDeletionParams{
+		ID:                  id,
+		DeletionScheduledAt: sql.NullTime{Time: scheduledAt, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *userRepositorySQLC) CancelDeletion(ctx context.Context, id string) (bool, error) {
+	n, err := r.queries(ctx).CancelUserDeletion(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *userRepositorySQLC) ListUsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	return r.queries(ctx).ListUsersDueForDeletion(ctx, db.ListUsersDueForDeletionParams{
+		DeletionScheduledAt: sql.NullTime{Time: now, Valid: true},
+		Limit:               int32(limit),
+	})
+}
+
+func (r *userRepositorySQLC) LockUserForDeletion(ctx context.Context, id string, now time.Time) (time.Time, error) {
+	requestedAt, err := r.queries(ctx).LockUserForDeletion(ctx, db.LockUserForDeletionParams{
+		ID:                  id,
+		DeletionScheduledAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return requestedAt.Time, nil
+}
+
+func (r *userRepositorySQLC) DeleteUser(ctx context.Context, id string) error {
+	return r.queries(ctx).DeleteUser(ctx, id)
+}
+
+func (r *userRepositorySQLC) IsDisabled(ctx context.Context, id string) (bool, error) {
+	disabledAt, err := r.queries(ctx).GetUserDisabledAt(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return disabledAt.Valid, nil
+}
+
 func dbUserToModel(u db.User) models.User {
 	createdAt := ""
 	if u.CreatedAt.Valid {
@@ -68,5 +143,9 @@ func dbUserToModel(u db.User) models.User {
 		SavingGoal: int(u.SavingGoal),
 		CreatedAt:  createdAt,
 		UpdatedAt:  updatedAt,
+
+		DeletionRequestedAt: nullTimeToString(u.DeletionRequestedAt),
+		DeletionScheduledAt: nullTimeToString(u.DeletionScheduledAt),
+		SetupCompletedAt:    nullTimeToString(u.SetupCompletedAt),
 	}
 }
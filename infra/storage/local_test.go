@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "attachments/1/2/abc", strings.NewReader("receipt"), 7, "image/jpeg"))
+	body, err := s.Get(ctx, "attachments/1/2/abc")
+	require.NoError(t, err)
+	got, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "receipt", string(got))
+
+	require.NoError(t, s.Delete(ctx, "attachments/1/2/abc"))
+	_, err = s.Get(ctx, "attachments/1/2/abc")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	require.NoError(t, s.Delete(ctx, "attachments/1/2/abc"))
+}
+
+func TestLocalStorage_RejectsKeysOutsideDir(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../outside", "a//b"} {
+		assert.Error(t, s.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"), key)
+	}
+}
@@ -0,0 +1,192 @@
+package transaction_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "money-buddy-backend/db/generated"
+	"money-buddy-backend/infra/transaction"
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/services"
+)
+
+// 行レベルセキュリティの結合テスト。
+// db/schema を適用済みの Postgres に、スーパーユーザーではないロールで接続する DSN を
+// TEST_DATABASE_URL に指定すると実行されます（スーパーユーザーには RLS が適用されません）。
+
+type tenant struct {
+	userID    string
+	ledgerID  int32
+	expenseID int32
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	var bypass bool
+	require.NoError(t, conn.QueryRow(`SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`).Scan(&bypass))
+	require.False(t, bypass, "TEST_DATABASE_URL must connect as a role that is subject to row-level security")
+	return conn
+}
+
+// inTx は ctx の呼び出し元で開始したトランザクション内で fn を実行し、最後にロールバックします。
+func inTx(t *testing.T, m services.TxManager, ctx context.Context, fn func(ctx context.Context, tx *sql.Tx)) {
+	t.Helper()
+	tx, err := m.Begin(ctx)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	txCtx := tx.Context(ctx)
+	sqlTx, ok := transaction.TxFromContext(txCtx)
+	require.True(t, ok)
+	fn(txCtx, sqlTx)
+}
+
+// seedTenants は家計簿を共有していない 2 人のユーザーと、それぞれの支出・固定費を作成します。
+func seedTenants(t *testing.T, m services.TxManager) (tenant, tenant) {
+	t.Helper()
+	ctx := transaction.WithSystemIdentity(context.Background())
+	suffix := time.Now().UnixNano()
+
+	tx, err := m.Begin(ctx)
+	require.NoError(t, err)
+	txCtx := tx.Context(ctx)
+	sqlTx, _ := transaction.TxFromContext(txCtx)
+
+	var categoryID int32
+	require.NoError(t, sqlTx.QueryRowContext(txCtx, `INSERT INTO categories (name) VALUES ('rls-test') RETURNING id`).Scan(&categoryID))
+
+	seed := func(name string) tenant {
+		tn := tenant{userID: fmt.Sprintf("rls-test-%s-%d", name, suffix)}
+		_, err := sqlTx.ExecContext(txCtx, `INSERT INTO users (id) VALUES ($1)`, tn.userID)
+		require.NoError(t, err)
+		require.NoError(t, sqlTx.QueryRowContext(txCtx, `INSERT INTO ledgers (name) VALUES ($1) RETURNING id`, name).Scan(&tn.ledgerID))
+		_, err = sqlTx.ExecContext(txCtx, `INSERT INTO ledger_members (ledger_id, user_id, role) VALUES ($1, $2, 'owner')`, tn.ledgerID, tn.userID)
+		require.NoError(t, err)
+		_, err = sqlTx.ExecContext(txCtx, `UPDATE users SET active_ledger_id = $2 WHERE id = $1`, tn.userID, tn.ledgerID)
+		require.NoError(t, err)
+		require.NoError(t, sqlTx.QueryRowContext(txCtx,
+			`INSERT INTO expenses (user_id, amount, category_id, spent_at, ledger_id) VALUES ($1, 1000, $2, CURRENT_DATE, $3) RETURNING id`,
+			tn.userID, categoryID, tn.ledgerID,
+		).Scan(&tn.expenseID))
+		_, err = sqlTx.ExecContext(txCtx, `INSERT INTO fixed_costs (user_id, name, amount, ledger_id) VALUES ($1, 'rent', 80000, $2)`, tn.userID, tn.ledgerID)
+		require.NoError(t, err)
+		return tn
+	}
+	a := seed("a")
+	b := seed("b")
+	require.NoError(t, tx.Commit())
+
+	t.Cleanup(func() {
+		tx, err := m.Begin(ctx)
+		require.NoError(t, err)
+		txCtx := tx.Context(ctx)
+		sqlTx, _ := transaction.TxFromContext(txCtx)
+		// 支出・固定費・メンバーは外部キーの ON DELETE CASCADE で消える
+		_, err = sqlTx.ExecContext(txCtx, `DELETE FROM ledgers WHERE id IN ($1, $2)`, a.ledgerID, b.ledgerID)
+		require.NoError(t, err)
+		_, err = sqlTx.ExecContext(txCtx, `DELETE FROM users WHERE id IN ($1, $2)`, a.userID, b.userID)
+		require.NoError(t, err)
+		_, err = sqlTx.ExecContext(txCtx, `DELETE FROM categories WHERE id = $1`, categoryID)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+	})
+	return a, b
+}
+
+func TestRowLevelSecurity_CrossTenantReadsReturnNothing(t *testing.T) {
+	conn := openTestDB(t)
+	m := transaction.NewTxManager(conn)
+	q := dbgen.New(conn)
+	a, b := seedTenants(t, m)
+
+	inTx(t, m, auth.WithUserID(context.Background(), a.userID), func(ctx context.Context, tx *sql.Tx) {
+		qtx := q.WithTx(tx)
+
+		// 自分の家計簿は見える
+		own, err := qtx.ListExpenses(ctx, dbgen.ListExpensesParams{LedgerID: a.ledgerID, SortKey: "-spent_at", PageLimit: 100})
+		require.NoError(t, err)
+		assert.Len(t, own, 1)
+
+		// WHERE 句で他人の家計簿を指定しても何も返らない
+		expenses, err := qtx.ListExpenses(ctx, dbgen.ListExpensesParams{LedgerID: b.ledgerID, SortKey: "-spent_at", PageLimit: 100})
+		require.NoError(t, err)
+		assert.Empty(t, expenses)
+
+		_, err = qtx.GetExpenseByID(ctx, dbgen.GetExpenseByIDParams{LedgerID: b.ledgerID, ID: b.expenseID})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		fixedCosts, err := qtx.ListFixedCostsByLedger(ctx, b.ledgerID)
+		require.NoError(t, err)
+		assert.Empty(t, fixedCosts)
+
+		_, err = qtx.GetUserByID(ctx, b.userID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		// 他人の user_id を条件に含めても自分の行だけが返る
+		var userIDs []string
+		rows, err := tx.QueryContext(ctx, `SELECT DISTINCT user_id FROM expenses WHERE user_id IN ($1, $2)`, a.userID, b.userID)
+		require.NoError(t, err)
+		for rows.Next() {
+			var id string
+			require.NoError(t, rows.Scan(&id))
+			userIDs = append(userIDs, id)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []string{a.userID}, userIDs)
+	})
+}
+
+func TestRowLevelSecurity_CrossTenantWritesAffectNothing(t *testing.T) {
+	conn := openTestDB(t)
+	m := transaction.NewTxManager(conn)
+	a, b := seedTenants(t, m)
+
+	inTx(t, m, auth.WithUserID(context.Background(), a.userID), func(ctx context.Context, tx *sql.Tx) {
+		res, err := tx.ExecContext(ctx, `UPDATE expenses SET amount = 1 WHERE id = $1`, b.expenseID)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		assert.Zero(t, n)
+
+		res, err = tx.ExecContext(ctx, `UPDATE users SET income = 1 WHERE id = $1`, b.userID)
+		require.NoError(t, err)
+		n, _ = res.RowsAffected()
+		assert.Zero(t, n)
+
+		// 他人の家計簿への登録はポリシー違反になる
+		_, err = tx.ExecContext(ctx, `INSERT INTO fixed_costs (user_id, name, amount, ledger_id) VALUES ($1, 'x', 1, $2)`, a.userID, b.ledgerID)
+		assert.Error(t, err)
+	})
+}
+
+func TestRowLevelSecurity_NoCallerSeesNothing(t *testing.T) {
+	conn := openTestDB(t)
+	m := transaction.NewTxManager(conn)
+	a, b := seedTenants(t, m)
+
+	inTx(t, m, context.Background(), func(ctx context.Context, tx *sql.Tx) {
+		for _, table := range []string{"expenses", "fixed_costs"} {
+			var n int
+			require.NoError(t, tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table+` WHERE user_id IN ($1, $2)`, a.userID, b.userID).Scan(&n))
+			assert.Zero(t, n, table)
+		}
+		var n int
+		require.NoError(t, tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE id IN ($1, $2)`, a.userID, b.userID).Scan(&n))
+		assert.Zero(t, n)
+	})
+}
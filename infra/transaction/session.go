@@ -0,0 +1,45 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+
+	"money-buddy-backend/internal/auth"
+)
+
+const (
+	// SessionUserIDSetting は RLS ポリシーが参照する、呼び出し元ユーザー ID のセッション変数です。
+	SessionUserIDSetting = "app.user_id"
+	// SessionBypassSetting は RLS ポリシーを迂回するセッション変数です（WithSystemIdentity 参照）。
+	SessionBypassSetting = "app.bypass_rls"
+)
+
+type systemKey struct{}
+
+// WithSystemIdentity は、ユーザーを横断して処理するバックグラウンドジョブ（退会データの削除など）の
+// コンテキストを返します。このコンテキストで開始したトランザクションは RLS ポリシーの対象外になります。
+// リクエストの処理には使わないでください。
+func WithSystemIdentity(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+func isSystemIdentity(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
+
+// setSessionIdentity は tx のセッション変数に呼び出し元を設定します。
+// set_config の第 3 引数 true は SET LOCAL と同じで、値はトランザクションの終了とともに破棄されるため、
+// コネクションプールで再利用される接続に前の呼び出し元が残ることはありません。
+// 呼び出し元が無いコンテキストでは何も設定せず、RLS ポリシーにより対象テーブルの行は一切見えません。
+func setSessionIdentity(ctx context.Context, tx *sql.Tx) error {
+	if userID, ok := auth.UserIDFromContext(ctx); ok {
+		_, err := tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", SessionUserIDSetting, userID)
+		return err
+	}
+	if isSystemIdentity(ctx) {
+		_, err := tx.ExecContext(ctx, "SELECT set_config($1, 'on', true)", SessionBypassSetting)
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package transaction
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sync/atomic"
 
 	"money-buddy-backend/internal/services"
 )
@@ -34,6 +36,40 @@ func (t *sqlTx) Context(ctx context.Context) context.Context {
 	return withTx(ctx, t.tx)
 }
 
+// savepointTx は既存のトランザクション内で開始された入れ子のトランザクションです。
+// Commit はセーブポイントの解放、Rollback はセーブポイントまでの巻き戻しになり、
+// 確定は外側のトランザクションに委ねます。
+type savepointTx struct {
+	tx   *sql.Tx
+	name string
+	done bool
+}
+
+func (t *savepointTx) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.tx.Exec("RELEASE SAVEPOINT " + t.name)
+	return err
+}
+
+func (t *savepointTx) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.tx.Exec("ROLLBACK TO SAVEPOINT " + t.name)
+	return err
+}
+
+func (t *savepointTx) Context(ctx context.Context) context.Context {
+	return withTx(ctx, t.tx)
+}
+
+// savepointSeq はセーブポイント名を一意にするための連番です。
+var savepointSeq atomic.Uint64
+
 type sqlTxManager struct {
 	db *sql.DB
 }
@@ -42,10 +78,25 @@ func NewTxManager(db *sql.DB) services.TxManager {
 	return &sqlTxManager{db: db}
 }
 
+// Begin はトランザクションを開始し、呼び出し元の ID をセッション変数に設定します（setSessionIdentity 参照）。
+// ctx が既にトランザクションを持つ場合（リクエスト単位のトランザクション内でサービスが Begin した場合など）は
+// セーブポイントで入れ子にします。
 func (m *sqlTxManager) Begin(ctx context.Context) (services.Tx, error) {
+	if parent, ok := TxFromContext(ctx); ok {
+		name := fmt.Sprintf("sp_%d", savepointSeq.Add(1))
+		if _, err := parent.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+			return nil, err
+		}
+		return &savepointTx{tx: parent, name: name}, nil
+	}
+
 	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
+	if err := setSessionIdentity(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
 	return &sqlTx{tx: tx}, nil
 }
@@ -0,0 +1,180 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+)
+
+// recordingDriver は実行された SQL を記録するだけのドライバです。
+type recordingDriver struct {
+	mu   sync.Mutex
+	log  []string
+	fail string // この文字列で始まる SQL をエラーにする
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+func (d *recordingDriver) record(s string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, s)
+	if d.fail != "" && strings.HasPrefix(s, d.fail) {
+		return errors.New("exec failed")
+	}
+	return nil
+}
+
+func (d *recordingDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.log...)
+}
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *recordingConn) Close() error { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+func (c *recordingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return c, c.d.record("BEGIN")
+}
+func (c *recordingConn) Commit() error   { return c.d.record("COMMIT") }
+func (c *recordingConn) Rollback() error { return c.d.record("ROLLBACK") }
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	s := query
+	for _, a := range args {
+		s += fmt.Sprintf(" [%v]", a.Value)
+	}
+	return driver.RowsAffected(0), c.d.record(s)
+}
+
+var driverSeq atomic.Int64
+
+func openRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	name := fmt.Sprintf("recording-%d", driverSeq.Add(1))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func TestBegin_SetsCallerForRowLevelSecurity(t *testing.T) {
+	db, d := openRecordingDB(t)
+	ctx := auth.WithUserID(context.Background(), "user-a")
+
+	tx, err := NewTxManager(db).Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SELECT set_config($1, $2, true) [app.user_id] [user-a]",
+		"COMMIT",
+	}, d.statements())
+}
+
+func TestBegin_WithoutCallerSetsNothing(t *testing.T) {
+	db, d := openRecordingDB(t)
+
+	tx, err := NewTxManager(db).Begin(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	// 呼び出し元が無ければ RLS ポリシーにより対象テーブルは空に見える
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, d.statements())
+}
+
+func TestBegin_SystemIdentityBypassesRowLevelSecurity(t *testing.T) {
+	db, d := openRecordingDB(t)
+	ctx := WithSystemIdentity(context.Background())
+
+	tx, err := NewTxManager(db).Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SELECT set_config($1, 'on', true) [app.bypass_rls]",
+		"COMMIT",
+	}, d.statements())
+}
+
+func TestBegin_CallerTakesPrecedenceOverSystemIdentity(t *testing.T) {
+	db, d := openRecordingDB(t)
+	ctx := auth.WithUserID(WithSystemIdentity(context.Background()), "user-a")
+
+	tx, err := NewTxManager(db).Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	assert.Equal(t, "SELECT set_config($1, $2, true) [app.user_id] [user-a]", d.statements()[1])
+}
+
+func TestBegin_RollsBackWhenSettingCallerFails(t *testing.T) {
+	db, d := openRecordingDB(t)
+	d.fail = "SELECT set_config"
+	ctx := auth.WithUserID(context.Background(), "user-a")
+
+	_, err := NewTxManager(db).Begin(ctx)
+	require.Error(t, err)
+
+	stmts := d.statements()
+	assert.Equal(t, "ROLLBACK", stmts[len(stmts)-1])
+}
+
+func TestBegin_NestedUsesSavepoint(t *testing.T) {
+	db, d := openRecordingDB(t)
+	m := NewTxManager(db)
+	ctx := auth.WithUserID(context.Background(), "user-a")
+
+	outer, err := m.Begin(ctx)
+	require.NoError(t, err)
+	outerCtx := outer.Context(ctx)
+
+	committed, err := m.Begin(outerCtx)
+	require.NoError(t, err)
+	require.NoError(t, committed.Commit())
+	assert.ErrorIs(t, committed.Rollback(), sql.ErrTxDone)
+
+	rolledBack, err := m.Begin(outerCtx)
+	require.NoError(t, err)
+	require.NoError(t, rolledBack.Rollback())
+
+	// 入れ子のトランザクションも外側と同じ接続を使う
+	innerTx, ok := TxFromContext(rolledBack.Context(ctx))
+	require.True(t, ok)
+	outerTx, _ := TxFromContext(outerCtx)
+	assert.Same(t, outerTx, innerTx)
+
+	require.NoError(t, outer.Commit())
+
+	stmts := d.statements()
+	require.Len(t, stmts, 7)
+	assert.Equal(t, "BEGIN", stmts[0])
+	assert.Regexp(t, `^SAVEPOINT sp_\d+$`, stmts[2])
+	assert.Equal(t, "RELEASE "+stmts[2], stmts[3])
+	assert.Regexp(t, `^SAVEPOINT sp_\d+$`, stmts[4])
+	assert.NotEqual(t, stmts[2], stmts[4])
+	assert.Equal(t, "ROLLBACK TO "+stmts[4], stmts[5])
+	assert.Equal(t, "COMMIT", stmts[6])
+}
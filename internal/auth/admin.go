@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// AdminTokens は運用者 API（/admin）の資格情報です。
+// 利用者の認証（Firebase・パーソナルアクセストークン）とは別に、運用者ごとにトークンを設定します。
+type AdminTokens struct {
+	operators []adminToken
+}
+
+type adminToken struct {
+	operator string
+	hash     [sha256.Size]byte
+}
+
+// NewAdminTokens は運用者名からトークンへの対応表から資格情報を作ります。
+func NewAdminTokens(tokens map[string]string) *AdminTokens {
+	a := &AdminTokens{}
+	for operator, token := range tokens {
+		a.operators = append(a.operators, adminToken{operator: operator, hash: sha256.Sum256([]byte(token))})
+	}
+	return a
+}
+
+// Enabled は運用者が 1 人以上設定されているかどうかです。
+func (a *AdminTokens) Enabled() bool {
+	return a != nil && len(a.operators) > 0
+}
+
+// Operator は raw に対応する運用者名を返します。
+// 比較は一定時間で行い、一致するトークンが無ければ false を返します。
+func (a *AdminTokens) Operator(raw string) (string, bool) {
+	if a == nil || raw == "" {
+		return "", false
+	}
+	hash := sha256.Sum256([]byte(raw))
+	operator, found := "", false
+	for _, t := range a.operators {
+		if subtle.ConstantTimeCompare(hash[:], t.hash[:]) == 1 {
+			operator, found = t.operator, true
+		}
+	}
+	return operator, found
+}
+
+type operatorKey struct{}
+
+// WithOperator は認証済みの運用者名をコンテキストに格納します。
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// OperatorFromContext はコンテキストから運用者名を取り出します。
+func OperatorFromContext(ctx context.Context) (string, bool) {
+	operator, ok := ctx.Value(operatorKey{}).(string)
+	return operator, ok && operator != ""
+}
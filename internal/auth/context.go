@@ -0,0 +1,57 @@
+package auth
+
+import "context"
+
+type principalKey struct{}
+
+// Principal は認証済みの呼び出し元を表します。
+type Principal struct {
+	UserID string
+	// Scopes はこの資格情報で許可された操作です。nil の場合は全操作を許可します
+	// （Firebase などの対話的ログイン）。
+	Scopes []string
+	// TokenID はパーソナルアクセストークンで認証した場合のトークン ID です。
+	TokenID int
+}
+
+// HasScope は scope の操作が許可されているかを判定します。
+func (p Principal) HasScope(scope string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPrincipal は認証済みの呼び出し元をコンテキストに格納します。
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext はコンテキストから認証済みの呼び出し元を取り出します。
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	if !ok || p.UserID == "" {
+		return Principal{}, false
+	}
+	return p, true
+}
+
+// WithUserID は全操作を許可されたユーザーとしてコンテキストに格納します。
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return WithPrincipal(ctx, Principal{UserID: userID})
+}
+
+// UserIDFromContext はコンテキストから認証済みユーザーの ID を取り出します。
+// 認証を通過していないコンテキストでは false を返します。
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return p.UserID, true
+}
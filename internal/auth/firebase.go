@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken はトークンが検証に失敗したことを表します。
+// 失敗理由は errors.Is で判別できるようにラップして返します。
+var ErrInvalidToken = errors.New("invalid token")
+
+// clockSkew は iat / exp / auth_time の比較で許容する時計のずれです。
+const clockSkew = 30 * time.Second
+
+// maxUIDLength は Firebase UID の最大長です。
+const maxUIDLength = 128
+
+// Token は検証済みの ID トークンを表します。
+type Token struct {
+	UID      string
+	Issuer   string
+	Audience string
+	IssuedAt time.Time
+	Expires  time.Time
+}
+
+// TokenVerifier は Bearer トークンを検証し、呼び出し元を特定します。
+type TokenVerifier interface {
+	VerifyIDToken(ctx context.Context, raw string) (*Token, error)
+}
+
+// FirebaseVerifier は Firebase Authentication が発行した ID トークン（RS256 JWT）を検証します。
+type FirebaseVerifier struct {
+	projectID string
+	keys      KeySource
+	now       func() time.Time
+}
+
+// NewFirebaseVerifier は projectID のトークンを keys の公開鍵で検証する FirebaseVerifier を返します。
+func NewFirebaseVerifier(projectID string, keys KeySource) *FirebaseVerifier {
+	return &FirebaseVerifier{projectID: projectID, keys: keys, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+type firebaseClaims struct {
+	Issuer   string          `json:"iss"`
+	Audience json.RawMessage `json:"aud"`
+	Subject  string          `json:"sub"`
+	IssuedAt int64           `json:"iat"`
+	Expires  int64           `json:"exp"`
+	AuthTime int64           `json:"auth_time"`
+}
+
+func (v *FirebaseVerifier) VerifyIDToken(ctx context.Context, raw string) (*Token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed jwt", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unexpected alg %q", ErrInvalidToken, header.Alg)
+	}
+	if header.Kid == "" {
+		return nil, fmt.Errorf("%w: missing kid", ErrInvalidToken)
+	}
+
+	key, err := v.keys.PublicKey(ctx, header.Kid)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: unknown kid %q", ErrInvalidToken, header.Kid)
+		}
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var claims firebaseClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrInvalidToken, err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		UID:      claims.Subject,
+		Issuer:   claims.Issuer,
+		Audience: v.projectID,
+		IssuedAt: time.Unix(claims.IssuedAt, 0),
+		Expires:  time.Unix(claims.Expires, 0),
+	}, nil
+}
+
+func (v *FirebaseVerifier) validateClaims(c firebaseClaims) error {
+	now := v.now()
+
+	if c.Issuer != "https://securetoken.google.com/"+v.projectID {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, c.Issuer)
+	}
+	if !audienceContains(c.Audience, v.projectID) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	if c.Expires == 0 || !now.Before(time.Unix(c.Expires, 0).Add(clockSkew)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if c.IssuedAt == 0 || time.Unix(c.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return fmt.Errorf("%w: token issued in the future", ErrInvalidToken)
+	}
+	if c.AuthTime != 0 && time.Unix(c.AuthTime, 0).After(now.Add(clockSkew)) {
+		return fmt.Errorf("%w: auth_time in the future", ErrInvalidToken)
+	}
+	if c.Subject == "" || len(c.Subject) > maxUIDLength {
+		return fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+	return nil
+}
+
+// audienceContains は aud クレーム（文字列または文字列配列）に want が含まれるかを判定します。
+func audienceContains(raw json.RawMessage, want string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == want
+	}
+	var multi []string
+	if err := json.Unmarshal(raw, &multi); err == nil {
+		for _, aud := range multi {
+			if aud == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProjectID = "money-buddy-test"
+
+type staticKeySource map[string]*rsa.PublicKey
+
+func (s staticKeySource) PublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, header map[string]any, claims map[string]any) string {
+	t.Helper()
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims(now time.Time) map[string]any {
+	return map[string]any{
+		"iss":       "https://securetoken.google.com/" + testProjectID,
+		"aud":       testProjectID,
+		"sub":       "firebase-uid-1",
+		"iat":       now.Add(-time.Minute).Unix(),
+		"exp":       now.Add(time.Hour).Unix(),
+		"auth_time": now.Add(-time.Minute).Unix(),
+	}
+}
+
+func TestFirebaseVerifier_VerifyIDToken_Valid(t *testing.T) {
+	key := generateKey(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	v := NewFirebaseVerifier(testProjectID, staticKeySource{"kid-1": &key.PublicKey})
+	v.now = func() time.Time { return now }
+
+	raw := signToken(t, key, map[string]any{"alg": "RS256", "kid": "kid-1", "typ": "JWT"}, validClaims(now))
+
+	tok, err := v.VerifyIDToken(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, "firebase-uid-1", tok.UID)
+	assert.Equal(t, testProjectID, tok.Audience)
+}
+
+func TestFirebaseVerifier_VerifyIDToken_Rejects(t *testing.T) {
+	key := generateKey(t)
+	otherKey := generateKey(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	header := map[string]any{"alg": "RS256", "kid": "kid-1"}
+
+	with := func(k string, v any) map[string]any {
+		c := validClaims(now)
+		c[k] = v
+		return c
+	}
+	without := func(k string) map[string]any {
+		c := validClaims(now)
+		delete(c, k)
+		return c
+	}
+
+	cases := []struct {
+		name string
+		raw  string
+	}{
+		{name: "形式不正", raw: "not-a-jwt"},
+		{name: "alg が RS256 以外", raw: signToken(t, key, map[string]any{"alg": "HS256", "kid": "kid-1"}, validClaims(now))},
+		{name: "kid が無い", raw: signToken(t, key, map[string]any{"alg": "RS256"}, validClaims(now))},
+		{name: "未知の kid", raw: signToken(t, key, map[string]any{"alg": "RS256", "kid": "kid-unknown"}, validClaims(now))},
+		{name: "署名が別の鍵", raw: signToken(t, otherKey, header, validClaims(now))},
+		{name: "issuer 不一致", raw: signToken(t, key, header, with("iss", "https://securetoken.google.com/other"))},
+		{name: "audience 不一致", raw: signToken(t, key, header, with("aud", "other"))},
+		{name: "期限切れ", raw: signToken(t, key, header, with("exp", now.Add(-time.Hour).Unix()))},
+		{name: "exp が無い", raw: signToken(t, key, header, without("exp"))},
+		{name: "未来の iat", raw: signToken(t, key, header, with("iat", now.Add(time.Hour).Unix()))},
+		{name: "未来の auth_time", raw: signToken(t, key, header, with("auth_time", now.Add(time.Hour).Unix()))},
+		{name: "sub が空", raw: signToken(t, key, header, with("sub", ""))},
+	}
+
+	v := NewFirebaseVerifier(testProjectID, staticKeySource{"kid-1": &key.PublicKey})
+	v.now = func() time.Time { return now }
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := v.VerifyIDToken(context.Background(), tc.raw)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidToken), "got %v", err)
+		})
+	}
+}
+
+func TestFirebaseVerifier_VerifyIDToken_AudienceArray(t *testing.T) {
+	key := generateKey(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	v := NewFirebaseVerifier(testProjectID, staticKeySource{"kid-1": &key.PublicKey})
+	v.now = func() time.Time { return now }
+
+	claims := validClaims(now)
+	claims["aud"] = []string{"other", testProjectID}
+	raw := signToken(t, key, map[string]any{"alg": "RS256", "kid": "kid-1"}, claims)
+
+	_, err := v.VerifyIDToken(context.Background(), raw)
+	require.NoError(t, err)
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GoogleSecureTokenJWKSURL は Firebase ID トークンの署名鍵を公開している JWKS エンドポイントです。
+const GoogleSecureTokenJWKSURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+const (
+	// defaultJWKSCacheTTL は Cache-Control が無い場合の鍵キャッシュ期間です。
+	defaultJWKSCacheTTL = time.Hour
+	// minJWKSRefreshInterval は未知の kid による強制再取得の最小間隔です。
+	minJWKSRefreshInterval = time.Minute
+)
+
+// ErrKeyNotFound は指定された kid の公開鍵が見つからないことを表します。
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// KeySource は kid に対応する RSA 公開鍵を返します。
+type KeySource interface {
+	PublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// parseJWKS は JWKS ドキュメントを kid → 公開鍵のマップに変換します。
+// RSA 以外の鍵や署名用途でない鍵は無視します。
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwks: key %q: invalid modulus", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwks: key %q: invalid exponent", k.Kid)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() < 3 {
+			return nil, fmt.Errorf("parse jwks: key %q: invalid exponent", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("parse jwks: no usable RSA keys")
+	}
+	return keys, nil
+}
+
+// jwksCache は取得済みの鍵セットと有効期限を保持し、期限切れや未知の kid で再取得します。
+type jwksCache struct {
+	fetch func(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error)
+	now   func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+	fetchedAt time.Time
+}
+
+func (c *jwksCache) PublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.keys == nil || !now.Before(c.expiresAt) {
+		if err := c.refresh(ctx, now); err != nil {
+			return nil, err
+		}
+	}
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+
+	// 鍵のローテーション直後は新しい kid がまだキャッシュに無いため、
+	// 一定間隔を空けて強制的に取り直す。
+	if now.Sub(c.fetchedAt) >= minJWKSRefreshInterval {
+		if err := c.refresh(ctx, now); err != nil {
+			return nil, err
+		}
+		if key, ok := c.keys[kid]; ok {
+			return key, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (c *jwksCache) refresh(ctx context.Context, now time.Time) error {
+	keys, ttl, err := c.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	c.keys = keys
+	c.fetchedAt = now
+	c.expiresAt = now.Add(ttl)
+	return nil
+}
+
+// NewJWKSFileSource はローカルの JWKS ファイルから鍵を読み込む KeySource を返します。
+// ファイルは ttl ごとに読み直されるため、鍵の差し替えに再起動は不要です。
+func NewJWKSFileSource(path string, ttl time.Duration) KeySource {
+	if ttl <= 0 {
+		ttl = defaultJWKSCacheTTL
+	}
+	return &jwksCache{
+		now: time.Now,
+		fetch: func(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, 0, fmt.Errorf("read jwks file: %w", err)
+			}
+			keys, err := parseJWKS(data)
+			if err != nil {
+				return nil, 0, err
+			}
+			return keys, ttl, nil
+		},
+	}
+}
+
+// NewJWKSURLSource は HTTP で JWKS を取得する KeySource を返します。
+// キャッシュ期間はレスポンスの Cache-Control max-age に従います。
+func NewJWKSURLSource(url string, client *http.Client) KeySource {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &jwksCache{
+		now: time.Now,
+		fetch: func(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, 0, fmt.Errorf("fetch jwks: %w", err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, 0, fmt.Errorf("fetch jwks: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return nil, 0, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+			}
+			data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+			if err != nil {
+				return nil, 0, fmt.Errorf("fetch jwks: %w", err)
+			}
+			keys, err := parseJWKS(data)
+			if err != nil {
+				return nil, 0, err
+			}
+			return keys, cacheMaxAge(resp.Header.Get("Cache-Control")), nil
+		},
+	}
+}
+
+// cacheMaxAge は Cache-Control ヘッダから max-age を取り出します。
+func cacheMaxAge(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(directive)
+		value, ok := strings.CutPrefix(directive, "max-age=")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			break
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultJWKSCacheTTL
+}
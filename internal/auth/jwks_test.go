@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jwksJSON(t *testing.T, keys map[string]*rsa.PublicKey) []byte {
+	t.Helper()
+	set := jwkSet{}
+	for kid, k := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kid: kid,
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+func TestJWKSFileSource(t *testing.T) {
+	key := generateKey(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwksJSON(t, map[string]*rsa.PublicKey{"kid-1": &key.PublicKey}), 0o600))
+
+	src := NewJWKSFileSource(path, time.Minute)
+
+	got, err := src.PublicKey(context.Background(), "kid-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.N.Cmp(key.PublicKey.N))
+	assert.Equal(t, key.PublicKey.E, got.E)
+
+	_, err = src.PublicKey(context.Background(), "kid-unknown")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestJWKSURLSource_CachesAndRotates(t *testing.T) {
+	oldKey := generateKey(t)
+	newKey := generateKey(t)
+
+	var requests atomic.Int32
+	var current atomic.Value
+	current.Store(jwksJSON(t, map[string]*rsa.PublicKey{"kid-old": &oldKey.PublicKey}))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		_, _ = w.Write(current.Load().([]byte))
+	}))
+	defer srv.Close()
+
+	src := NewJWKSURLSource(srv.URL, srv.Client()).(*jwksCache)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	src.now = func() time.Time { return now }
+
+	_, err := src.PublicKey(context.Background(), "kid-old")
+	require.NoError(t, err)
+	_, err = src.PublicKey(context.Background(), "kid-old")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "cached keys should be reused within max-age")
+
+	// 鍵がローテーションされた。直後は強制再取得の間隔内なので見つからない。
+	current.Store(jwksJSON(t, map[string]*rsa.PublicKey{"kid-new": &newKey.PublicKey}))
+	_, err = src.PublicKey(context.Background(), "kid-new")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// 最小間隔を過ぎれば未知の kid で再取得する。
+	now = now.Add(2 * time.Minute)
+	_, err = src.PublicKey(context.Background(), "kid-new")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// max-age を過ぎればキャッシュは期限切れになる。
+	now = now.Add(2 * time.Hour)
+	_, err = src.PublicKey(context.Background(), "kid-new")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	assert.Equal(t, 19*time.Second, cacheMaxAge("public, max-age=19, must-revalidate"))
+	assert.Equal(t, defaultJWKSCacheTTL, cacheMaxAge("no-cache"))
+	assert.Equal(t, defaultJWKSCacheTTL, cacheMaxAge(""))
+}
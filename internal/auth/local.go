@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LocalIssuer はローカル認証のアクセストークンの iss クレームです。
+const LocalIssuer = "money-buddy"
+
+// LocalTokens はローカル認証（AUTH_PROVIDER=local）のアクセストークンを発行・検証します。
+// トークンはサーバーの鍵で署名した HS256 JWT で、TokenVerifier として AuthMiddleware に渡せます。
+type LocalTokens struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewLocalTokens は key で署名し、ttl の間有効なアクセストークンを扱う LocalTokens を返します。
+func NewLocalTokens(key []byte, ttl time.Duration) *LocalTokens {
+	return &LocalTokens{key: key, ttl: ttl, now: time.Now}
+}
+
+type localClaims struct {
+	Issuer   string `json:"iss"`
+	Subject  string `json:"sub"`
+	IssuedAt int64  `json:"iat"`
+	Expires  int64  `json:"exp"`
+}
+
+// IssueAccessToken は userID のアクセストークンと有効期限を返します。
+func (l *LocalTokens) IssueAccessToken(userID string) (string, time.Time, error) {
+	now := l.now()
+	expires := now.Add(l.ttl)
+
+	header, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	payload, err := json.Marshal(localClaims{
+		Issuer:   LocalIssuer,
+		Subject:  userID,
+		IssuedAt: now.Unix(),
+		Expires:  expires.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(l.sign(signingInput)), expires, nil
+}
+
+func (l *LocalTokens) VerifyIDToken(ctx context.Context, raw string) (*Token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed jwt", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: unexpected alg %q", ErrInvalidToken, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if !hmac.Equal(signature, l.sign(parts[0]+"."+parts[1])) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var claims localClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrInvalidToken, err)
+	}
+
+	now := l.now()
+	if claims.Issuer != LocalIssuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	if claims.Expires == 0 || !now.Before(time.Unix(claims.Expires, 0).Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if claims.IssuedAt == 0 || time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: token issued in the future", ErrInvalidToken)
+	}
+	if claims.Subject == "" || len(claims.Subject) > maxUIDLength {
+		return nil, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+
+	return &Token{
+		UID:      claims.Subject,
+		Issuer:   claims.Issuer,
+		IssuedAt: time.Unix(claims.IssuedAt, 0),
+		Expires:  time.Unix(claims.Expires, 0),
+	}, nil
+}
+
+func (l *LocalTokens) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// NewLocalUserID はローカル認証で作成するユーザーの ID を返します。
+func NewLocalUserID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "local_" + hex.EncodeToString(buf), nil
+}
+
+// NewRefreshTokenFamilyID はログインごとのリフレッシュトークンの系列 ID を返します。
+func NewRefreshTokenFamilyID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GenerateRefreshToken は新しいリフレッシュトークンとその保存用ハッシュを返します。
+// パーソナルアクセストークンと同様に、DB にはハッシュだけを保存します。
+func GenerateRefreshToken() (raw string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	raw = base64.RawURLEncoding.EncodeToString(buf)
+	return raw, HashRefreshToken(raw), nil
+}
+
+// HashRefreshToken はリフレッシュトークンの保存・照合用ハッシュを返します。
+func HashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLocalKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestLocalTokens(now time.Time) *LocalTokens {
+	l := NewLocalTokens(testLocalKey, 15*time.Minute)
+	l.now = func() time.Time { return now }
+	return l
+}
+
+func TestLocalTokens_RoundTrip(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newTestLocalTokens(now)
+
+	raw, expires, err := l.IssueAccessToken("local_abc")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), expires)
+
+	token, err := l.VerifyIDToken(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, "local_abc", token.UID)
+	assert.Equal(t, LocalIssuer, token.Issuer)
+}
+
+func TestLocalTokens_Rejects(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	issued, _, err := newTestLocalTokens(now).IssueAccessToken("local_abc")
+	require.NoError(t, err)
+
+	otherKey := NewLocalTokens([]byte("ffffffffffffffffffffffffffffffff"), 15*time.Minute)
+	otherKey.now = func() time.Time { return now }
+
+	parts := strings.Split(issued, ".")
+	noneHeader, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+
+	cases := []struct {
+		name     string
+		verifier *LocalTokens
+		raw      string
+	}{
+		{name: "期限切れ", verifier: newTestLocalTokens(now.Add(time.Hour)), raw: issued},
+		{name: "別の鍵で署名", verifier: otherKey, raw: issued},
+		{name: "alg none", verifier: newTestLocalTokens(now), raw: base64.RawURLEncoding.EncodeToString(noneHeader) + "." + parts[1] + "."},
+		{name: "本文の改ざん", verifier: newTestLocalTokens(now), raw: parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"money-buddy","sub":"local_other","iat":1700000000,"exp":1900000000}`)) + "." + parts[2]},
+		{name: "形式不正", verifier: newTestLocalTokens(now), raw: "not-a-jwt"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.verifier.VerifyIDToken(context.Background(), tc.raw)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestGenerateRefreshToken(t *testing.T) {
+	raw, hash, err := GenerateRefreshToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, raw, hash)
+	assert.Equal(t, HashRefreshToken(raw), hash)
+
+	other, _, err := GenerateRefreshToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, raw, other)
+}
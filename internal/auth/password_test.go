@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPassword_Verify(t *testing.T) {
+	hash, err := HashPassword("correct horse battery")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+
+	ok, err := VerifyPassword("correct horse battery", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = VerifyPassword("wrong horse battery", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 同じパスワードでもソルトが異なる
+	again, err := HashPassword("correct horse battery")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again)
+}
+
+func TestVerifyPassword_MalformedHash(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"plaintext",
+		"$argon2i$v=19$m=19456,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!$aGFzaA",
+	} {
+		_, err := VerifyPassword("password", encoded)
+		assert.ErrorIs(t, err, ErrMalformedPasswordHash, encoded)
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix はパーソナルアクセストークンの接頭辞です。
+// Firebase の ID トークン（JWT）と区別するために使います。
+const PersonalAccessTokenPrefix = "mbp_"
+
+// スコープ一覧。ルートごとに必要なスコープを宣言します。
+const (
+	ScopeExpensesRead  = "expenses:read"
+	ScopeExpensesWrite = "expenses:write"
+	ScopeSetupWrite    = "setup:write"
+	ScopeUserRead      = "user:read"
+	ScopeAuditRead     = "audit:read"
+	// ScopeTokensManage はトークン自体の発行・失効です。トークンには付与できず、
+	// 対話的ログインでのみ許可されます。
+	ScopeTokensManage = "tokens:manage"
+	// ScopeAccountDelete は退会の申請・取り消しです。トークンには付与できません。
+	ScopeAccountDelete = "account:delete"
+	// ScopeLedgersManage は家計簿の作成・招待・参加・退出・切り替えです。トークンには付与できません。
+	ScopeLedgersManage = "ledgers:manage"
+	// ScopePasswordChange はローカル認証のパスワード変更です。トークンには付与できません。
+	ScopePasswordChange = "password:change"
+)
+
+// GrantableScopes はパーソナルアクセストークンに付与できるスコープです。
+var GrantableScopes = []string{
+	ScopeExpensesRead,
+	ScopeExpensesWrite,
+	ScopeSetupWrite,
+	ScopeUserRead,
+	ScopeAuditRead,
+}
+
+// IsGrantableScope は scope がトークンに付与可能かを判定します。
+func IsGrantableScope(scope string) bool {
+	for _, s := range GrantableScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPersonalAccessToken は raw がパーソナルアクセストークンの形式かを判定します。
+func IsPersonalAccessToken(raw string) bool {
+	return strings.HasPrefix(raw, PersonalAccessTokenPrefix)
+}
+
+// GeneratePersonalAccessToken は新しいトークン文字列とその保存用ハッシュを返します。
+// 平文は発行時にのみ呼び出し元へ返し、DB にはハッシュだけを保存します。
+func GeneratePersonalAccessToken() (raw string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	raw = PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return raw, HashPersonalAccessToken(raw), nil
+}
+
+// HashPersonalAccessToken はトークンの保存・照合用ハッシュを返します。
+// トークンは十分なエントロピーを持つため、ソルトなしの SHA-256 で照合します。
+func HashPersonalAccessToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
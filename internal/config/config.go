@@ -0,0 +1,244 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 利用者の認証方式（AUTH_PROVIDER）。
+const (
+	// AuthProviderFirebase は Firebase Authentication の ID トークンで認証します（既定）。
+	AuthProviderFirebase = "firebase"
+	// AuthProviderLocal はこのサーバーでメールアドレスとパスワードのアカウントを管理します（セルフホスト向け）。
+	AuthProviderLocal = "local"
+)
+
+// 添付ファイルの保存先（ATTACHMENT_STORAGE）。
+const (
+	// AttachmentStorageLocal はサーバーのディレクトリにファイルを置きます（既定）。
+	AttachmentStorageLocal = "local"
+	// AttachmentStorageS3 は S3 互換のストレージ（Amazon S3・MinIO など）にファイルを置きます。
+	AttachmentStorageS3 = "s3"
+)
+
+// AttachmentS3Config は添付ファイルを置く S3 互換のストレージの接続先です。
+type AttachmentS3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Config は環境変数から読み込むサーバー設定です。
+type Config struct {
+	// AuthProvider は利用者の認証方式です。AuthProviderFirebase または AuthProviderLocal です。
+	AuthProvider string
+
+	// FirebaseProjectID は ID トークンの aud / iss 検証に使う Firebase プロジェクト ID です。
+	FirebaseProjectID string
+	// FirebaseJWKSURL は署名鍵を取得する JWKS の URL です。未設定なら Google の公開エンドポイントを使います。
+	FirebaseJWKSURL string
+	// FirebaseJWKSFile が設定されている場合は URL の代わりにローカルファイルから鍵を読み込みます。
+	FirebaseJWKSFile string
+
+	// LocalAuthSigningKey はローカル認証のアクセストークン（HS256）の署名鍵です。
+	LocalAuthSigningKey []byte
+	// LocalAuthAccessTokenTTL はアクセストークンの有効期間です。
+	LocalAuthAccessTokenTTL time.Duration
+	// LocalAuthRefreshTokenTTL はリフレッシュトークンの有効期間です。使うたびに新しいトークンに交換します。
+	LocalAuthRefreshTokenTTL time.Duration
+
+	// AccountDeletionGracePeriod は退会申請からデータを完全に削除するまでの猶予期間です。
+	AccountDeletionGracePeriod time.Duration
+	// AccountPurgeInterval は猶予期間を過ぎたアカウントを削除するジョブの実行間隔です。
+	AccountPurgeInterval time.Duration
+
+	// LedgerInviteTTL は家計簿の招待コードの有効期間です。
+	LedgerInviteTTL time.Duration
+
+	// ExpenseTrashRetention は削除した支出をゴミ箱に残しておく期間です。過ぎると完全に削除します。
+	ExpenseTrashRetention time.Duration
+	// ExpensePurgeInterval は保存期間を過ぎたゴミ箱の支出を削除するジョブの実行間隔です。
+	ExpensePurgeInterval time.Duration
+
+	// IdempotencyKeyTTL は Idempotency-Key とそのレスポンスを保存しておく期間です。この間の再送には同じレスポンスを返します。
+	IdempotencyKeyTTL time.Duration
+	// IdempotencyPurgeInterval は保存期間を過ぎた Idempotency-Key を削除するジョブの実行間隔です。
+	IdempotencyPurgeInterval time.Duration
+
+	// AttachmentStorage は添付ファイルの保存先です。AttachmentStorageLocal または AttachmentStorageS3 です。
+	AttachmentStorage string
+	// AttachmentLocalDir は AttachmentStorageLocal の場合にファイルを置くディレクトリです。
+	AttachmentLocalDir string
+	// AttachmentS3 は AttachmentStorageS3 の場合の接続先です。
+	AttachmentS3 AttachmentS3Config
+	// AttachmentMaxBytes はアップロードできる添付ファイルの最大サイズ（バイト）です。
+	AttachmentMaxBytes int64
+	// AttachmentPurgeInterval は使われなくなった添付ファイルを保存先から削除するジョブの実行間隔です。
+	AttachmentPurgeInterval time.Duration
+
+	// AdminTokens は運用者 API（/admin）の運用者名とトークンです。空なら /admin を公開しません。
+	AdminTokens map[string]string
+}
+
+// Load は環境変数から設定を読み込みます。
+func Load() (Config, error) {
+	cfg := Config{
+		AuthProvider:      os.Getenv("AUTH_PROVIDER"),
+		FirebaseProjectID: os.Getenv("FIREBASE_PROJECT_ID"),
+		FirebaseJWKSURL:   os.Getenv("FIREBASE_JWKS_URL"),
+		FirebaseJWKSFile:  os.Getenv("FIREBASE_JWKS_FILE"),
+	}
+	if cfg.AuthProvider == "" {
+		cfg.AuthProvider = AuthProviderFirebase
+	}
+
+	var err error
+	switch cfg.AuthProvider {
+	case AuthProviderFirebase:
+		if cfg.FirebaseProjectID == "" {
+			return Config{}, errors.New("FIREBASE_PROJECT_ID must be set")
+		}
+	case AuthProviderLocal:
+		key := os.Getenv("LOCAL_AUTH_SIGNING_KEY")
+		if len(key) < localAuthSigningKeyMinLen {
+			return Config{}, fmt.Errorf("LOCAL_AUTH_SIGNING_KEY must be at least %d characters", localAuthSigningKeyMinLen)
+		}
+		cfg.LocalAuthSigningKey = []byte(key)
+		if cfg.LocalAuthAccessTokenTTL, err = durationEnv("LOCAL_AUTH_ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
+			return Config{}, err
+		}
+		if cfg.LocalAuthRefreshTokenTTL, err = durationEnv("LOCAL_AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour); err != nil {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("AUTH_PROVIDER must be %q or %q: %q", AuthProviderFirebase, AuthProviderLocal, cfg.AuthProvider)
+	}
+
+	if cfg.AccountDeletionGracePeriod, err = durationEnv("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.AccountPurgeInterval, err = durationEnv("ACCOUNT_PURGE_INTERVAL", time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.LedgerInviteTTL, err = durationEnv("LEDGER_INVITE_TTL", 72*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.ExpenseTrashRetention, err = durationEnv("EXPENSE_TRASH_RETENTION", 30*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.ExpensePurgeInterval, err = durationEnv("EXPENSE_PURGE_INTERVAL", time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.IdempotencyKeyTTL, err = durationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.IdempotencyPurgeInterval, err = durationEnv("IDEMPOTENCY_PURGE_INTERVAL", time.Hour); err != nil {
+		return Config{}, err
+	}
+	if err := loadAttachmentConfig(&cfg); err != nil {
+		return Config{}, err
+	}
+	if cfg.AdminTokens, err = adminTokensEnv("ADMIN_API_TOKENS"); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// localAuthSigningKeyMinLen はローカル認証の署名鍵の最小長です（HS256 の鍵長 256 ビット）。
+const localAuthSigningKeyMinLen = 32
+
+// adminTokenMinLen は運用者トークンの最小長です。推測されにくいランダムな値を設定してください。
+const adminTokenMinLen = 32
+
+// adminTokensEnv は "運用者名:トークン" をカンマ区切りで並べた環境変数を読み込みます（例: "alice:xxxx,bob:yyyy"）。
+func adminTokensEnv(key string) (map[string]string, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil, nil
+	}
+	tokens := make(map[string]string)
+	for _, entry := range strings.Split(v, ",") {
+		operator, token, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || operator == "" {
+			return nil, fmt.Errorf("%s must be a comma-separated list of operator:token", key)
+		}
+		if len(token) < adminTokenMinLen {
+			return nil, fmt.Errorf("%s: token for %q must be at least %d characters", key, operator, adminTokenMinLen)
+		}
+		if _, dup := tokens[operator]; dup {
+			return nil, fmt.Errorf("%s: duplicate operator %q", key, operator)
+		}
+		tokens[operator] = token
+	}
+	return tokens, nil
+}
+
+// loadAttachmentConfig は添付ファイルの保存先と制限を読み込みます。
+func loadAttachmentConfig(cfg *Config) error {
+	cfg.AttachmentStorage = os.Getenv("ATTACHMENT_STORAGE")
+	switch cfg.AttachmentStorage {
+	case "", AttachmentStorageLocal:
+		cfg.AttachmentStorage = AttachmentStorageLocal
+		cfg.AttachmentLocalDir = os.Getenv("ATTACHMENT_LOCAL_DIR")
+		if cfg.AttachmentLocalDir == "" {
+			cfg.AttachmentLocalDir = "data/attachments"
+		}
+	case AttachmentStorageS3:
+		cfg.AttachmentS3 = AttachmentS3Config{
+			Endpoint:        os.Getenv("ATTACHMENT_S3_ENDPOINT"),
+			Region:          os.Getenv("ATTACHMENT_S3_REGION"),
+			Bucket:          os.Getenv("ATTACHMENT_S3_BUCKET"),
+			AccessKeyID:     os.Getenv("ATTACHMENT_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("ATTACHMENT_S3_SECRET_ACCESS_KEY"),
+		}
+		if cfg.AttachmentS3.Region == "" {
+			cfg.AttachmentS3.Region = "us-east-1"
+		}
+		if cfg.AttachmentS3.Endpoint == "" || cfg.AttachmentS3.Bucket == "" || cfg.AttachmentS3.AccessKeyID == "" || cfg.AttachmentS3.SecretAccessKey == "" {
+			return errors.New("ATTACHMENT_S3_ENDPOINT, ATTACHMENT_S3_BUCKET, ATTACHMENT_S3_ACCESS_KEY_ID and ATTACHMENT_S3_SECRET_ACCESS_KEY must be set")
+		}
+	default:
+		return fmt.Errorf("ATTACHMENT_STORAGE must be %q or %q: %q", AttachmentStorageLocal, AttachmentStorageS3, cfg.AttachmentStorage)
+	}
+
+	var err error
+	if cfg.AttachmentMaxBytes, err = bytesEnv("ATTACHMENT_MAX_BYTES", 10<<20); err != nil {
+		return err
+	}
+	if cfg.AttachmentPurgeInterval, err = durationEnv("ATTACHMENT_PURGE_INTERVAL", time.Hour); err != nil {
+		return err
+	}
+	return nil
+}
+
+// bytesEnv はバイト数を表す正の整数の環境変数を読み込みます。
+func bytesEnv(key string, fallback int64) (int64, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive number of bytes: %q", key, v)
+	}
+	return n, nil
+}
+
+// durationEnv は time.ParseDuration 形式（例: "720h"）の環境変数を読み込みます。
+func durationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration: %q", key, v)
+	}
+	return d, nil
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/services"
+)
+
+type AccountDeletionHandler struct {
+	service services.AccountDeletionService
+}
+
+func NewAccountDeletionHandler(r gin.IRoutes, service services.AccountDeletionService) {
+	h := &AccountDeletionHandler{service: service}
+	r.DELETE("/user/me", RequireScope(auth.ScopeAccountDelete), h.RequestDeletion)
+	r.DELETE("/user/me/deletion", RequireScope(auth.ScopeAccountDelete), h.CancelDeletion)
+}
+
+// RequestDeletion handles DELETE /user/me. The account is purged after the grace period.
+func (h *AccountDeletionHandler) RequestDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deletion, err := h.service.RequestDeletion(c.Request.Context(), userID)
+	if err != nil {
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"deletion": deletion})
+}
+
+// CancelDeletion handles DELETE /user/me/deletion during the grace period.
+func (h *AccountDeletionHandler) CancelDeletion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelDeletion(c.Request.Context(), userID); err != nil {
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type accountDeletionServiceMock struct {
+	RequestDeletionFunc func(userID string) (models.AccountDeletion, error)
+	CancelDeletionFunc  func(userID string) error
+}
+
+func (m *accountDeletionServiceMock) RequestDeletion(ctx context.Context, userID string) (models.AccountDeletion, error) {
+	if m.RequestDeletionFunc != nil {
+		return m.RequestDeletionFunc(userID)
+	}
+	return models.AccountDeletion{}, nil
+}
+
+func (m *accountDeletionServiceMock) CancelDeletion(ctx context.Context, userID string) error {
+	if m.CancelDeletionFunc != nil {
+		return m.CancelDeletionFunc(userID)
+	}
+	return nil
+}
+
+func (m *accountDeletionServiceMock) PurgeDueAccounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestAccountDeletionHandler_RequestDeletion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	svc := &accountDeletionServiceMock{
+		RequestDeletionFunc: func(userID string) (models.AccountDeletion, error) {
+			require.Equal(t, testUserID, userID)
+			return models.AccountDeletion{
+				RequestedAt: "2025-03-01T09:00:00Z",
+				ScheduledAt: "2025-03-31T09:00:00Z",
+			}, nil
+		},
+	}
+	NewAccountDeletionHandler(router, svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/user/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp map[string]models.AccountDeletion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "2025-03-31T09:00:00Z", resp["deletion"].ScheduledAt)
+}
+
+func TestAccountDeletionHandler_RequestDeletion_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "ユーザーが存在しない", err: &services.NotFoundError{Message: "user not found"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewAccountDeletionHandler(router, &accountDeletionServiceMock{
+				RequestDeletionFunc: func(userID string) (models.AccountDeletion, error) {
+					return models.AccountDeletion{}, tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/user/me", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAccountDeletionHandler_RequestDeletion_RequiresScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		p := auth.Principal{UserID: testUserID, Scopes: []string{auth.ScopeExpensesWrite}}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		c.Next()
+	})
+
+	called := false
+	NewAccountDeletionHandler(router, &accountDeletionServiceMock{
+		RequestDeletionFunc: func(userID string) (models.AccountDeletion, error) {
+			called = true
+			return models.AccountDeletion{}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/user/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.False(t, called)
+}
+
+func TestAccountDeletionHandler_CancelDeletion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "取り消し成功", err: nil, wantStatus: http.StatusNoContent},
+		{name: "申請が無い", err: &services.NotFoundError{Message: "no pending account deletion"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewAccountDeletionHandler(router, &accountDeletionServiceMock{
+				CancelDeletionFunc: func(userID string) error {
+					require.Equal(t, testUserID, userID)
+					return tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/user/me/deletion", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
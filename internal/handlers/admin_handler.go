@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type AdminHandler struct {
+	service services.AdminService
+}
+
+// NewAdminHandler は運用者 API を登録します。r には AdminAuthMiddleware を通したグループを渡します。
+func NewAdminHandler(r gin.IRoutes, service services.AdminService) {
+	h := &AdminHandler{service: service}
+	r.GET("/users", h.SearchUsers)
+	r.GET("/users/:id", h.GetUser)
+	r.POST("/users/:id/disable", h.DisableUser)
+	r.POST("/users/:id/enable", h.EnableUser)
+	r.POST("/users/:id/exports", h.ExportUserData)
+	r.GET("/exports/:id", h.GetExport)
+}
+
+// SearchUsers handles GET /admin/users and lists users whose ID contains q.
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	var input models.AdminUserSearchInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	operator, ok := currentOperator(c)
+	if !ok {
+		return
+	}
+
+	users, err := h.service.SearchUsers(c.Request.Context(), operator, input)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// GetUser handles GET /admin/users/:id and returns the user with activity counts.
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	operator, ok := currentOperator(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), operator, c.Param("id"))
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// DisableUser handles POST /admin/users/:id/disable. Requests from a disabled account are rejected with 403.
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// EnableUser handles POST /admin/users/:id/enable.
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *AdminHandler) setUserDisabled(c *gin.Context, disabled bool) {
+	operator, ok := currentOperator(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.SetUserDisabled(c.Request.Context(), operator, c.Param("id"), disabled)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// ExportUserData handles POST /admin/users/:id/exports and snapshots the user's data for a support case.
+func (h *AdminHandler) ExportUserData(c *gin.Context) {
+	operator, ok := currentOperator(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.service.ExportUserData(c.Request.Context(), operator, c.Param("id"))
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"export": export})
+}
+
+// GetExport handles GET /admin/exports/:id and returns the exported data.
+func (h *AdminHandler) GetExport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	operator, ok := currentOperator(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.service.GetExport(c.Request.Context(), operator, id)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"export": export})
+}
+
+func writeAdminError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
+
+// currentOperator は AdminAuthMiddleware が格納した運用者名を取り出します。
+// 取り出せない場合は 401 を書き込み、false を返します。
+func currentOperator(c *gin.Context) (string, bool) {
+	operator, ok := auth.OperatorFromContext(c.Request.Context())
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return "", false
+	}
+	return operator, true
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+const testAdminToken = "admin-token-0123456789abcdef0123456789"
+
+type adminServiceMock struct {
+	SearchUsersFunc     func(operator string, input models.AdminUserSearchInput) ([]models.AdminUser, error)
+	GetUserFunc         func(operator string, userID string) (models.AdminUser, error)
+	SetUserDisabledFunc func(operator string, userID string, disabled bool) (models.AdminUser, error)
+	ExportUserDataFunc  func(operator string, userID string) (models.UserDataExport, error)
+	GetExportFunc       func(operator string, exportID int) (models.UserDataExport, error)
+}
+
+func (m *adminServiceMock) SearchUsers(ctx context.Context, operator string, input models.AdminUserSearchInput) ([]models.AdminUser, error) {
+	if m.SearchUsersFunc != nil {
+		return m.SearchUsersFunc(operator, input)
+	}
+	return []models.AdminUser{}, nil
+}
+
+func (m *adminServiceMock) GetUser(ctx context.Context, operator string, userID string) (models.AdminUser, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(operator, userID)
+	}
+	return models.AdminUser{}, nil
+}
+
+func (m *adminServiceMock) SetUserDisabled(ctx context.Context, operator string, userID string, disabled bool) (models.AdminUser, error) {
+	if m.SetUserDisabledFunc != nil {
+		return m.SetUserDisabledFunc(operator, userID, disabled)
+	}
+	return models.AdminUser{}, nil
+}
+
+func (m *adminServiceMock) ExportUserData(ctx context.Context, operator string, userID string) (models.UserDataExport, error) {
+	if m.ExportUserDataFunc != nil {
+		return m.ExportUserDataFunc(operator, userID)
+	}
+	return models.UserDataExport{}, nil
+}
+
+func (m *adminServiceMock) GetExport(ctx context.Context, operator string, exportID int) (models.UserDataExport, error) {
+	if m.GetExportFunc != nil {
+		return m.GetExportFunc(operator, exportID)
+	}
+	return models.UserDataExport{}, nil
+}
+
+// newAdminRouter は AdminAuthMiddleware を通した運用者 API のルーターを返します。
+func newAdminRouter(service services.AdminService) *gin.Engine {
+	router := gin.New()
+	admin := router.Group("/admin", AdminAuthMiddleware(auth.NewAdminTokens(map[string]string{"alice": testAdminToken})))
+	NewAdminHandler(admin, service)
+	return router
+}
+
+func adminRequest(method, path string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return req
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{name: "運用者トークン", header: "Bearer " + testAdminToken, wantStatus: http.StatusOK},
+		{name: "ヘッダなし", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "利用者のトークン", header: "Bearer mbp_user-token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAdminRouter(&adminServiceMock{
+				SearchUsersFunc: func(operator string, input models.AdminUserSearchInput) ([]models.AdminUser, error) {
+					require.Equal(t, "alice", operator)
+					return []models.AdminUser{}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAdminHandler_SearchUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAdminRouter(&adminServiceMock{
+		SearchUsersFunc: func(operator string, input models.AdminUserSearchInput) ([]models.AdminUser, error) {
+			require.Equal(t, models.AdminUserSearchInput{Query: "abc", Limit: 20, Offset: 40}, input)
+			return []models.AdminUser{{ID: "abc-1", ExpenseCount: 3}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest(http.MethodGet, "/admin/users?q=abc&limit=20&offset=40"))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string][]models.AdminUser
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp["users"], 1)
+	require.Equal(t, 3, resp["users"][0].ExpenseCount)
+}
+
+func TestAdminHandler_SetUserDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name         string
+		path         string
+		wantDisabled bool
+		err          error
+		wantStatus   int
+	}{
+		{name: "利用停止", path: "/admin/users/user-1/disable", wantDisabled: true, wantStatus: http.StatusOK},
+		{name: "利用再開", path: "/admin/users/user-1/enable", wantDisabled: false, wantStatus: http.StatusOK},
+		{name: "存在しないユーザー", path: "/admin/users/user-1/disable", wantDisabled: true, err: &services.NotFoundError{Message: "user not found"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", path: "/admin/users/user-1/enable", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAdminRouter(&adminServiceMock{
+				SetUserDisabledFunc: func(operator string, userID string, disabled bool) (models.AdminUser, error) {
+					require.Equal(t, "user-1", userID)
+					require.Equal(t, tc.wantDisabled, disabled)
+					return models.AdminUser{ID: userID, Disabled: disabled}, tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, adminRequest(http.MethodPost, tc.path))
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAdminHandler_ExportUserData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAdminRouter(&adminServiceMock{
+		ExportUserDataFunc: func(operator string, userID string) (models.UserDataExport, error) {
+			require.Equal(t, "alice", operator)
+			return models.UserDataExport{ID: 9, UserID: userID, RequestedBy: operator}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest(http.MethodPost, "/admin/users/user-1/exports"))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]models.UserDataExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 9, resp["export"].ID)
+	require.Nil(t, resp["export"].Data)
+}
+
+func TestAdminHandler_GetExport_InvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAdminRouter(&adminServiceMock{})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest(http.MethodGet, "/admin/exports/abc"))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+// multipartOverhead は multipart の本文のうち、ファイル以外（境界やヘッダ）に見込むバイト数です。
+const multipartOverhead = 64 << 10
+
+// AttachmentRequestMaxBytes は maxFileBytes までの添付ファイルをアップロードするリクエストの本文の最大サイズです。
+func AttachmentRequestMaxBytes(maxFileBytes int64) int64 {
+	return maxFileBytes + multipartOverhead
+}
+
+type AttachmentHandler struct {
+	service  services.AttachmentService
+	maxBytes int64
+}
+
+// NewAttachmentHandler は添付ファイルのルートを登録します。maxBytes はアップロードできるファイルの最大サイズで、
+// これを大きく超える本文はサービスに渡す前に読むのをやめます。
+func NewAttachmentHandler(r gin.IRoutes, service services.AttachmentService, maxBytes int64) {
+	h := &AttachmentHandler{service: service, maxBytes: maxBytes}
+	r.GET("/expenses/:id/attachments", RequireScope(auth.ScopeExpensesRead), h.ListAttachments)
+	r.POST("/expenses/:id/attachments", RequireScope(auth.ScopeExpensesWrite), h.UploadAttachment)
+	r.GET("/expenses/:id/attachments/:attachment_id", RequireScope(auth.ScopeExpensesRead), h.DownloadAttachment)
+	r.GET("/expenses/:id/attachments/:attachment_id/thumbnail", RequireScope(auth.ScopeExpensesRead), h.DownloadThumbnail)
+	r.DELETE("/expenses/:id/attachments/:attachment_id", RequireScope(auth.ScopeExpensesWrite), h.DeleteAttachment)
+}
+
+// ListAttachments handles GET /expenses/:id/attachments.
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	expenseID, ok := attachmentExpenseIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.service.ListAttachments(c.Request.Context(), userID, expenseID)
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// UploadAttachment handles POST /expenses/:id/attachments. The file is sent as the "file" field
+// of a multipart/form-data body; its type is detected from the content, not the client's Content-Type.
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	expenseID, ok := attachmentExpenseIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, AttachmentRequestMaxBytes(h.maxBytes))
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds the maximum size"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required as multipart/form-data field \"file\""})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.UploadAttachment(c.Request.Context(), userID, expenseID, models.AttachmentUpload{
+		FileName: fileHeader.Filename,
+		Body:     file,
+	})
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"attachment": attachment})
+}
+
+// DownloadAttachment handles GET /expenses/:id/attachments/:attachment_id. It returns the file
+// as a download with the type detected at upload.
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	expenseID, id, ok := attachmentIDParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.service.DownloadAttachment(c.Request.Context(), userID, expenseID, id)
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+	writeAttachmentFile(c, file, "attachment")
+}
+
+// DownloadThumbnail handles GET /expenses/:id/attachments/:attachment_id/thumbnail. It returns a
+// JPEG preview of an image attachment, or 404 if the attachment has none.
+func (h *AttachmentHandler) DownloadThumbnail(c *gin.Context) {
+	expenseID, id, ok := attachmentIDParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.service.DownloadThumbnail(c.Request.Context(), userID, expenseID, id)
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+	writeAttachmentFile(c, file, "inline")
+}
+
+// DeleteAttachment handles DELETE /expenses/:id/attachments/:attachment_id. The stored file is
+// removed afterwards by a background job.
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	expenseID, id, ok := attachmentIDParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAttachment(c.Request.Context(), userID, expenseID, id); err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func attachmentExpenseIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func attachmentIDParams(c *gin.Context) (int, int, bool) {
+	expenseID, ok := attachmentExpenseIDParam(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment ID"})
+		return 0, 0, false
+	}
+	return expenseID, id, true
+}
+
+// writeAttachmentFile はファイルを本文として返し、Body を閉じます。
+// 保存時に判定した形式で返し、ブラウザに形式を推測させません（nosniff）。
+func writeAttachmentFile(c *gin.Context, file models.AttachmentFile, disposition string) {
+	defer file.Body.Close()
+
+	size := file.Size
+	if size <= 0 {
+		size = -1
+	}
+	c.DataFromReader(http.StatusOK, size, file.ContentType, file.Body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": file.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=0",
+	})
+}
+
+func writeAttachmentError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrNoActiveLedger):
+		c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds the maximum size"})
+	case errors.Is(err, services.ErrUnsupportedAttachmentType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "file type is not supported; use JPEG, PNG, GIF, WebP or PDF"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type attachmentServiceMock struct {
+	ListAttachmentsFunc    func(userID string, expenseID int) ([]models.Attachment, error)
+	UploadAttachmentFunc   func(userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error)
+	DownloadAttachmentFunc func(userID string, expenseID int, id int) (models.AttachmentFile, error)
+	DownloadThumbnailFunc  func(userID string, expenseID int, id int) (models.AttachmentFile, error)
+	DeleteAttachmentFunc   func(userID string, expenseID int, id int) error
+}
+
+func (m *attachmentServiceMock) ListAttachments(ctx context.Context, userID string, expenseID int) ([]models.Attachment, error) {
+	if m.ListAttachmentsFunc != nil {
+		return m.ListAttachmentsFunc(userID, expenseID)
+	}
+	return []models.Attachment{}, nil
+}
+
+func (m *attachmentServiceMock) UploadAttachment(ctx context.Context, userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error) {
+	if m.UploadAttachmentFunc != nil {
+		return m.UploadAttachmentFunc(userID, expenseID, upload)
+	}
+	return models.Attachment{}, nil
+}
+
+func (m *attachmentServiceMock) DownloadAttachment(ctx context.Context, userID string, expenseID int, id int) (models.AttachmentFile, error) {
+	if m.DownloadAttachmentFunc != nil {
+		return m.DownloadAttachmentFunc(userID, expenseID, id)
+	}
+	return models.AttachmentFile{}, nil
+}
+
+func (m *attachmentServiceMock) DownloadThumbnail(ctx context.Context, userID string, expenseID int, id int) (models.AttachmentFile, error) {
+	if m.DownloadThumbnailFunc != nil {
+		return m.DownloadThumbnailFunc(userID, expenseID, id)
+	}
+	return models.AttachmentFile{}, nil
+}
+
+func (m *attachmentServiceMock) DeleteAttachment(ctx context.Context, userID string, expenseID int, id int) error {
+	if m.DeleteAttachmentFunc != nil {
+		return m.DeleteAttachmentFunc(userID, expenseID, id)
+	}
+	return nil
+}
+
+func (m *attachmentServiceMock) PurgeOrphanedFiles(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func multipartFile(t *testing.T, field string, name string, data []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile(field, name)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return &body, w.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("アップロードしたファイルを渡す", func(t *testing.T) {
+		router := newAuthedRouter()
+		// /expenses の他のルートと同じルーターに登録できる
+		NewExpenseHandler(router, &expenseServiceMock{})
+		NewAttachmentHandler(router, &attachmentServiceMock{
+			UploadAttachmentFunc: func(userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error) {
+				require.Equal(t, testUserID, userID)
+				require.Equal(t, 5, expenseID)
+				require.Equal(t, "receipt.jpg", upload.FileName)
+				data, err := io.ReadAll(upload.Body)
+				require.NoError(t, err)
+				require.Equal(t, "jpeg-bytes", string(data))
+				return models.Attachment{ID: 1, ExpenseID: 5, FileName: upload.FileName, ContentType: "image/jpeg", Size: 10, HasThumbnail: true, StorageKey: "secret"}, nil
+			},
+		}, 1<<20)
+
+		body, contentType := multipartFile(t, "file", "receipt.jpg", []byte("jpeg-bytes"))
+		req := httptest.NewRequest(http.MethodPost, "/expenses/5/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "receipt.jpg", resp["attachment"]["file_name"])
+		// 保存先のキーは返さない
+		require.NotContains(t, w.Body.String(), "secret")
+	})
+
+	t.Run("本文が大きすぎれば読むのをやめる", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewAttachmentHandler(router, &attachmentServiceMock{
+			UploadAttachmentFunc: func(userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error) {
+				t.Fatal("service must not be called")
+				return models.Attachment{}, nil
+			},
+		}, 10)
+
+		body, contentType := multipartFile(t, "file", "big.pdf", bytes.Repeat([]byte("x"), multipartOverhead+100))
+		req := httptest.NewRequest(http.MethodPost, "/expenses/5/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("file が無い", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewAttachmentHandler(router, &attachmentServiceMock{}, 1<<20)
+
+		body, contentType := multipartFile(t, "photo", "receipt.jpg", []byte("x"))
+		req := httptest.NewRequest(http.MethodPost, "/expenses/5/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "大きすぎる", err: services.ErrAttachmentTooLarge, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "添付できない形式", err: services.ErrUnsupportedAttachmentType, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "入力エラー", err: &services.ValidationError{Message: "file is not a valid image"}, wantStatus: http.StatusBadRequest},
+		{name: "支出が無い", err: &services.NotFoundError{Message: "expense not found"}, wantStatus: http.StatusNotFound},
+		{name: "閲覧者", err: &services.ForbiddenError{Message: "viewers cannot modify this ledger"}, wantStatus: http.StatusForbidden},
+		{name: "使用中の家計簿が無い", err: services.ErrNoActiveLedger, wantStatus: http.StatusConflict},
+		{name: "その他のエラー", err: errors.New("storage down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewAttachmentHandler(router, &attachmentServiceMock{
+				UploadAttachmentFunc: func(userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error) {
+					return models.Attachment{}, tc.err
+				},
+			}, 1<<20)
+
+			body, contentType := multipartFile(t, "file", "receipt.jpg", []byte("x"))
+			req := httptest.NewRequest(http.MethodPost, "/expenses/5/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAttachmentHandler_ListAttachments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewAttachmentHandler(router, &attachmentServiceMock{
+		ListAttachmentsFunc: func(userID string, expenseID int) ([]models.Attachment, error) {
+			require.Equal(t, 5, expenseID)
+			return []models.Attachment{{ID: 1, ExpenseID: 5, FileName: "a.pdf", ContentType: "application/pdf", Size: 3, CreatedAt: "2025-05-01T09:00:00Z"}}, nil
+		},
+	}, 1<<20)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/5/attachments", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"attachments":[{"id":1,"expense_id":5,"file_name":"a.pdf","content_type":"application/pdf","size":3,"has_thumbnail":false,"created_at":"2025-05-01T09:00:00Z"}]}`, w.Body.String())
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewAttachmentHandler(router, &attachmentServiceMock{
+		DownloadAttachmentFunc: func(userID string, expenseID int, id int) (models.AttachmentFile, error) {
+			require.Equal(t, 5, expenseID)
+			require.Equal(t, 2, id)
+			return models.AttachmentFile{FileName: "レシート.pdf", ContentType: "application/pdf", Size: 8, Body: io.NopCloser(strings.NewReader("%PDF-1.7"))}, nil
+		},
+		DownloadThumbnailFunc: func(userID string, expenseID int, id int) (models.AttachmentFile, error) {
+			return models.AttachmentFile{}, &services.NotFoundError{Message: "thumbnail not found"}
+		},
+	}, 1<<20)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/5/attachments/2", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "%PDF-1.7", w.Body.String())
+	require.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	require.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	require.Equal(t, "attachment; filename*=utf-8''%E3%83%AC%E3%82%B7%E3%83%BC%E3%83%88.pdf", w.Header().Get("Content-Disposition"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/5/attachments/2/thumbnail", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/5/attachments/abc", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAttachmentHandler_DeleteAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewAttachmentHandler(router, &attachmentServiceMock{
+		DeleteAttachmentFunc: func(userID string, expenseID int, id int) error {
+			if id == 9 {
+				return &services.NotFoundError{Message: "attachment not found"}
+			}
+			return nil
+		},
+	}, 1<<20)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/expenses/5/attachments/2", nil))
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/expenses/5/attachments/9", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
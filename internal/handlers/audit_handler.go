@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type AuditHandler struct {
+	service services.AuditService
+}
+
+func NewAuditHandler(r gin.IRoutes, service services.AuditService) {
+	h := &AuditHandler{service: service}
+	r.GET("/audit", RequireScope(auth.ScopeAuditRead), h.ListEvents)
+}
+
+// ListEvents handles GET /audit. It returns the caller's own changes and changes made in
+// ledgers the caller belongs to, newest first. Pass next_cursor as cursor to fetch the next page.
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	var input models.ListAuditEventsInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.service.ListEvents(c.Request.Context(), userID, input)
+	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
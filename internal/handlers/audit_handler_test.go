@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type auditServiceMock struct {
+	ListEventsFunc func(userID string, input models.ListAuditEventsInput) (models.AuditEventPage, error)
+}
+
+func (m *auditServiceMock) ListEvents(ctx context.Context, userID string, input models.ListAuditEventsInput) (models.AuditEventPage, error) {
+	if m.ListEventsFunc != nil {
+		return m.ListEventsFunc(userID, input)
+	}
+	return models.AuditEventPage{Events: []models.AuditEvent{}}, nil
+}
+
+func TestAuditHandler_ListEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("クエリパラメータで絞り込む", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewAuditHandler(router, &auditServiceMock{
+			ListEventsFunc: func(userID string, input models.ListAuditEventsInput) (models.AuditEventPage, error) {
+				require.Equal(t, testUserID, userID)
+				require.Equal(t, models.ListAuditEventsInput{EntityType: "expense", EntityID: "7", Limit: 20, Cursor: 99}, input)
+				next := 80
+				return models.AuditEventPage{Events: []models.AuditEvent{{ID: 98, Action: models.AuditActionExpenseUpdate}}, NextCursor: &next}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit?entity_type=expense&entity_id=7&limit=20&cursor=99", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp models.AuditEventPage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Events, 1)
+		require.Equal(t, 80, *resp.NextCursor)
+	})
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "不正な条件", err: &services.ValidationError{Message: "since is invalid"}, wantStatus: http.StatusBadRequest},
+		{name: "その他のエラー", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewAuditHandler(router, &auditServiceMock{
+				ListEventsFunc: func(userID string, input models.ListAuditEventsInput) (models.AuditEventPage, error) {
+					return models.AuditEventPage{}, tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit?since=x", nil))
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
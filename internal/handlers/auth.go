@@ -1,5 +1,145 @@
 package handlers
 
-// DummyUserID is a placeholder user ID used until authentication is implemented.
-// TODO: Remove this constant and extract user ID from authentication context (e.g., Firebase Auth)
-const DummyUserID = "test-user"
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/services"
+)
+
+// AuthMiddleware は Authorization: Bearer ヘッダの資格情報を検証し、
+// 呼び出し元をリクエストのコンテキストに格納します。
+// パーソナルアクセストークン（auth.PersonalAccessTokenPrefix で始まるもの）は tokens で、
+// それ以外は ID トークンとして verifier で検証します。
+func AuthMiddleware(verifier auth.TokenVerifier, tokens services.PersonalAccessTokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		var principal auth.Principal
+		var err error
+		if auth.IsPersonalAccessToken(raw) {
+			principal, err = tokens.Authenticate(c.Request.Context(), raw)
+		} else {
+			var token *auth.Token
+			token, err = verifier.VerifyIDToken(c.Request.Context(), raw)
+			if err == nil {
+				principal = auth.Principal{UserID: token.UID}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+				return
+			}
+			// 鍵の取得失敗や DB 障害などトークン以外の問題
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "authentication unavailable"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// AdminAuthMiddleware は運用者 API（/admin）の資格情報を検証し、運用者名をリクエストのコンテキストに格納します。
+// 利用者の ID トークンやパーソナルアクセストークンでは通過できません。
+func AdminAuthMiddleware(tokens *auth.AdminTokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+		operator, ok := tokens.Operator(raw)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithOperator(c.Request.Context(), operator))
+		c.Next()
+	}
+}
+
+// RequireScope は呼び出し元の資格情報に scope が許可されていない場合に 403 を返します。
+// ルート登録時にハンドラの前へ置き、各ルートが必要とするスコープを宣言します。
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if !principal.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient scope", "required_scope": scope})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ProvisionUser は初回アクセスのユーザーと個人用の家計簿を作成します。
+// どのエンドポイントが最初に呼ばれても同じ状態になるよう、RequestTransaction の後ろ、
+// RejectDisabledAccounts の前に置きます。
+func ProvisionUser(users services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		if err := users.Provision(c.Request.Context(), userID); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RejectDisabledAccounts は運用者が利用を停止したアカウントからのリクエストに 403 を返します。
+// 利用停止の状態は users から読むため、RequestTransaction の後ろに置きます。
+func RejectDisabledAccounts(users services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		disabled, err := users.IsDisabled(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if disabled {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "account disabled"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// currentUserID は AuthMiddleware が格納したユーザー ID を取り出します。
+// 取り出せない場合は 401 を書き込み、false を返します。
+func currentUserID(c *gin.Context) (string, bool) {
+	userID, ok := auth.UserIDFromContext(c.Request.Context())
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+)
+
+const testUserID = "test-user"
+
+// newAuthedRouter は認証済みの testUserID としてリクエストを処理するルーターを返します。
+func newAuthedRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithUserID(c.Request.Context(), testUserID))
+		c.Next()
+	})
+	return router
+}
+
+type verifierMock struct {
+	VerifyIDTokenFunc func(ctx context.Context, raw string) (*auth.Token, error)
+}
+
+func (m *verifierMock) VerifyIDToken(ctx context.Context, raw string) (*auth.Token, error) {
+	return m.VerifyIDTokenFunc(ctx, raw)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier := &verifierMock{
+		VerifyIDTokenFunc: func(ctx context.Context, raw string) (*auth.Token, error) {
+			switch raw {
+			case "good":
+				return &auth.Token{UID: "firebase-uid"}, nil
+			case "keys-down":
+				return nil, errors.New("fetch jwks: connection refused")
+			default:
+				return nil, auth.ErrInvalidToken
+			}
+		},
+	}
+
+	tokens := &tokenServiceMock{
+		AuthenticateFunc: func(raw string) (auth.Principal, error) {
+			if raw == "mbp_good" {
+				return auth.Principal{UserID: "pat-user", Scopes: []string{auth.ScopeExpensesRead}, TokenID: 1}, nil
+			}
+			return auth.Principal{}, auth.ErrInvalidToken
+		},
+	}
+
+	cases := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantUser   string
+	}{
+		{name: "ヘッダなしは401", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "Bearer 以外のスキームは401", header: "Basic Zm9vOmJhcg==", wantStatus: http.StatusUnauthorized},
+		{name: "検証失敗は401", header: "Bearer bad", wantStatus: http.StatusUnauthorized},
+		{name: "鍵の取得失敗は503", header: "Bearer keys-down", wantStatus: http.StatusServiceUnavailable},
+		{name: "有効なトークンはユーザーIDを格納する", header: "Bearer good", wantStatus: http.StatusOK, wantUser: "firebase-uid"},
+		{name: "有効なアクセストークンはトークンの所有者を格納する", header: "Bearer mbp_good", wantStatus: http.StatusOK, wantUser: "pat-user"},
+		{name: "無効なアクセストークンは401", header: "Bearer mbp_revoked", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			var gotUser string
+			router.GET("/me", AuthMiddleware(verifier, tokens), func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+				gotUser = userID
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			require.Equal(t, tc.wantUser, gotUser)
+		})
+	}
+}
+
+func TestHandlers_RejectUnauthenticatedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewExpenseHandler(router, &expenseServiceMock{})
+
+	req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		principal  auth.Principal
+		wantStatus int
+	}{
+		{name: "対話的ログインは全スコープを持つ", principal: auth.Principal{UserID: "u1"}, wantStatus: http.StatusNoContent},
+		{name: "スコープを持つトークンは許可", principal: auth.Principal{UserID: "u1", Scopes: []string{auth.ScopeExpensesRead}}, wantStatus: http.StatusNoContent},
+		{name: "スコープを持たないトークンは403", principal: auth.Principal{UserID: "u1", Scopes: []string{auth.ScopeExpensesWrite}}, wantStatus: http.StatusForbidden},
+		{name: "スコープが空のトークンは403", principal: auth.Principal{UserID: "u1", Scopes: []string{}}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), tc.principal))
+				c.Next()
+			})
+			router.GET("/expenses", RequireScope(auth.ScopeExpensesRead), func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestProvisionUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("最初のリクエストが支出の登録でも先にユーザーを作成する", func(t *testing.T) {
+		var calls []string
+		users := &userServiceMock{
+			ProvisionFunc: func(ctx context.Context, userID string) error {
+				require.Equal(t, testUserID, userID)
+				calls = append(calls, "provision")
+				return nil
+			},
+			IsDisabledFunc: func(ctx context.Context, userID string) (bool, error) {
+				calls = append(calls, "is_disabled")
+				return false, nil
+			},
+		}
+		router := newAuthedRouter()
+		router.POST("/expenses", ProvisionUser(users), RejectDisabledAccounts(users), func(c *gin.Context) {
+			calls = append(calls, "handler")
+			c.Status(http.StatusCreated)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/expenses", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, []string{"provision", "is_disabled", "handler"}, calls)
+	})
+
+	t.Run("作成に失敗したら500でハンドラを呼ばない", func(t *testing.T) {
+		users := &userServiceMock{
+			ProvisionFunc: func(ctx context.Context, userID string) error {
+				return errors.New("db down")
+			},
+		}
+		router := newAuthedRouter()
+		router.POST("/setup", ProvisionUser(users), func(c *gin.Context) {
+			t.Fatal("handler must not run")
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/setup", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestRejectDisabledAccounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		disabled   bool
+		err        error
+		wantStatus int
+	}{
+		{name: "利用可能なアカウントは通す", wantStatus: http.StatusNoContent},
+		{name: "利用停止中のアカウントは403", disabled: true, wantStatus: http.StatusForbidden},
+		{name: "確認に失敗したら500", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			users := &userServiceMock{
+				IsDisabledFunc: func(ctx context.Context, userID string) (bool, error) {
+					require.Equal(t, testUserID, userID)
+					return tc.disabled, tc.err
+				},
+			}
+			router := newAuthedRouter()
+			router.GET("/expenses", RejectDisabledAccounts(users), func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
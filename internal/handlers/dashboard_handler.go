@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type DashboardHandler struct {
+	service services.DashboardService
+}
+
+func NewDashboardHandler(r gin.IRoutes, service services.DashboardService) {
+	h := &DashboardHandler{service: service}
+	r.GET("/dashboard/summary", RequireScope(auth.ScopeExpensesRead), h.Summary)
+}
+
+// Summary handles GET /dashboard/summary. It returns the month's income, saving goal,
+// fixed costs and expenses for the active ledger.
+func (h *DashboardHandler) Summary(c *gin.Context) {
+	var input models.MonthlySummaryInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.service.MonthlySummary(c.Request.Context(), userID, input)
+	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type dashboardServiceMock struct {
+	MonthlySummaryFunc func(userID string, input models.MonthlySummaryInput) (models.MonthlySummary, error)
+}
+
+func (m *dashboardServiceMock) MonthlySummary(ctx context.Context, userID string, input models.MonthlySummaryInput) (models.MonthlySummary, error) {
+	if m.MonthlySummaryFunc != nil {
+		return m.MonthlySummaryFunc(userID, input)
+	}
+	return models.MonthlySummary{}, nil
+}
+
+func TestDashboardHandler_Summary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("月次集計を返す", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewDashboardHandler(router, &dashboardServiceMock{
+			MonthlySummaryFunc: func(userID string, input models.MonthlySummaryInput) (models.MonthlySummary, error) {
+				require.Equal(t, testUserID, userID)
+				require.Equal(t, "2025-06", input.Month)
+				return models.MonthlySummary{
+					Month: "2025-06", Income: 700000, ReceivedIncome: 400000, ExpectedIncome: 600000,
+					SavingGoal: 50000, FixedCosts: 120000, ConfirmedExpenses: 80000, PendingExpenses: 5000,
+					PaymentMethods: []models.PaymentMethodTotal{
+						{PaymentMethod: "credit_card", ExpenseCount: 4, ConfirmedTotal: 70000},
+						{PaymentMethod: "unspecified", ExpenseCount: 2, ConfirmedTotal: 10000, PlannedTotal: 5000},
+					},
+				}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dashboard/summary?month=2025-06", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, `{"month":"2025-06","income":700000,"received_income":400000,"planned_income":0,"expected_income":600000,
+			"saving_goal":50000,"fixed_costs":120000,"confirmed_expenses":80000,"pending_expenses":5000,
+			"payment_methods":[
+				{"payment_method":"credit_card","expense_count":4,"confirmed_total":70000,"planned_total":0},
+				{"payment_method":"unspecified","expense_count":2,"confirmed_total":10000,"planned_total":5000}
+			]}`, w.Body.String())
+	})
+
+	t.Run("month の形式が不正", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewDashboardHandler(router, &dashboardServiceMock{
+			MonthlySummaryFunc: func(userID string, input models.MonthlySummaryInput) (models.MonthlySummary, error) {
+				return models.MonthlySummary{}, &services.ValidationError{Message: "month must be YYYY-MM"}
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dashboard/summary?month=2025-6", nil))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.JSONEq(t, `{"error":"month must be YYYY-MM"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+// expenseETag は支出のバージョンを表す強い ETag です（例: "3"）。
+func expenseETag(e models.Expense) string {
+	return `"` + strconv.Itoa(e.Version) + `"`
+}
+
+// setExpenseETag は 1 件の支出を返すレスポンスに ETag ヘッダを付けます。
+func setExpenseETag(c *gin.Context, e models.Expense) {
+	c.Header("ETag", expenseETag(e))
+}
+
+// ifMatchVersion は If-Match ヘッダから更新の前提となる支出のバージョンを取り出します。
+// ヘッダが無いか "*" の場合は nil を返します。1 つの強い ETag でない場合は 400 を書き込み、false を返します。
+func ifMatchVersion(c *gin.Context) (*int, bool) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return nil, true
+	}
+	if len(v) >= 2 && strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) {
+		if n, err := strconv.Atoi(v[1 : len(v)-1]); err == nil && n > 0 {
+			return &n, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match must be a single ETag returned by the server"})
+	return nil, false
+}
+
+// writeVersionConflict は err が VersionConflictError の場合に、現在の支出とその ETag を付けて 412 を書き込みます。
+func writeVersionConflict(c *gin.Context, err error) bool {
+	var ce *services.VersionConflictError
+	if !errors.As(err, &ce) {
+		return false
+	}
+	setExpenseETag(c, ce.Current)
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": ce.Message, "expense": ce.Current})
+	return true
+}
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"money-buddy-backend/internal/auth"
 	"money-buddy-backend/internal/models"
 	"money-buddy-backend/internal/services"
 	"strconv"
@@ -15,13 +17,18 @@ type ExpenseHandler struct {
 	service services.ExpenseService
 }
 
-func NewExpenseHandler(r *gin.Engine, service services.ExpenseService) {
+func NewExpenseHandler(r gin.IRoutes, service services.ExpenseService) {
 	handler := &ExpenseHandler{service: service}
 
-	r.POST("/expenses", handler.CreateExpense)
-	r.GET("/expenses", handler.ListExpenses)
-	r.PUT("/expenses/:id", handler.UpdateExpense)
-	r.DELETE("/expenses/:id", handler.DeleteExpense)
+	r.POST("/expenses", RequireScope(auth.ScopeExpensesWrite), handler.CreateExpense)
+	r.GET("/expenses", RequireScope(auth.ScopeExpensesRead), handler.ListExpenses)
+	r.GET("/expenses/search", RequireScope(auth.ScopeExpensesRead), handler.SearchExpenses)
+	r.PUT("/expenses/:id", RequireScope(auth.ScopeExpensesWrite), handler.UpdateExpense)
+	r.PATCH("/expenses/:id", RequireScope(auth.ScopeExpensesWrite), handler.PatchExpense)
+	r.POST("/expenses/:id/confirm", RequireScope(auth.ScopeExpensesWrite), handler.ConfirmExpense)
+	r.POST("/expenses/confirm", RequireScope(auth.ScopeExpensesWrite), handler.ConfirmExpenses)
+	r.POST("/expenses/bulk", RequireScope(auth.ScopeExpensesWrite), handler.BulkExpenses)
+	r.DELETE("/expenses/:id", RequireScope(auth.ScopeExpensesWrite), handler.DeleteExpense)
 }
 
 func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
@@ -32,50 +39,130 @@ func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
 		return
 	}
 
-	// TODO: Extract userID from authentication context when auth is implemented
-	userID := DummyUserID
-	expense, err := h.service.CreateExpense(userID, input)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	expense, err := h.service.CreateExpense(c.Request.Context(), userID, input)
 	if err != nil {
 		var ve *services.ValidationError
 		if errors.As(err, &ve) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
 			return
 		}
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
+		if errors.Is(err, services.ErrNoActiveLedger) {
+			c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
+	setExpenseETag(c, expense)
 	c.JSON(http.StatusCreated, gin.H{"expense": expense})
 }
 
+// ListExpenses handles GET /expenses. Query parameters narrow down the active ledger's expenses;
+// pass next_cursor as cursor with the same sort to fetch the next page.
 func (h *ExpenseHandler) ListExpenses(c *gin.Context) {
-	// TODO: Extract userID from authentication context when auth is implemented
-	userID := DummyUserID
-	expenses, err := h.service.ListExpenses(userID)
+	var input models.ListExpensesInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, err := h.service.ListExpenses(c.Request.Context(), userID, input)
 	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list expenses"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"expenses": expenses})
+	c.JSON(http.StatusOK, page)
+}
+
+// SearchExpenses handles GET /expenses/search. Results are ordered by relevance and
+// carry the memo split into highlighted segments.
+func (h *ExpenseHandler) SearchExpenses(c *gin.Context) {
+	var input models.SearchExpensesInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	results, err := h.service.SearchExpenses(c.Request.Context(), userID, input)
+	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search expenses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
 }
 
+// DeleteExpense handles DELETE /expenses/:id. The expense is moved to the trash. With If-Match,
+// it is deleted only if its ETag still matches; otherwise 412 is returned with the current expense.
 func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
 		return
 	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
 
-	// TODO: Extract userID from authentication context when auth is implemented
-	userID := DummyUserID
-	err = h.service.DeleteExpense(userID, int(id))
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	err = h.service.DeleteExpense(c.Request.Context(), userID, int(id), version)
 	if err != nil {
+		if writeVersionConflict(c, err) {
+			return
+		}
 		var ve *services.ValidationError
 		if errors.As(err, &ve) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
 			return
 		}
+		// Missing or already trashed expense -> 404
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
+		if errors.Is(err, services.ErrNoActiveLedger) {
+			c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
@@ -84,6 +171,7 @@ func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
 }
 
 // UpdateExpense handles PUT /expenses/:id to update an expense.
+// With If-Match, the update is applied only if the expense's ETag still matches.
 func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 	// Path param ID
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -91,6 +179,10 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
 		return
 	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
 
 	// Bind JSON body without ID (ID comes from path)
 	type updateBody struct {
@@ -99,6 +191,12 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 		Memo       string `json:"memo"`
 		SpentAt    string `json:"spent_at" binding:"required"`
 		Status     string `json:"status"`
+		// splits を省略すると内訳を無くす（PUT は支出全体の置き換え）
+		Splits []models.ExpenseSplitInput `json:"splits"`
+		TagIDs []int                      `json:"tag_ids"`
+		// payee_id・payment_account_id を省略すると支払先・支払い手段を外す
+		PayeeID          *int `json:"payee_id"`
+		PaymentAccountID *int `json:"payment_account_id"`
 	}
 	var body updateBody
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -108,33 +206,217 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 
 	// Build service input
 	input := models.UpdateExpenseInput{
-		ID:         int(id),
-		Amount:     body.Amount,
-		CategoryID: body.CategoryID,
-		Memo:       body.Memo,
-		SpentAt:    body.SpentAt,
-		Status:     body.Status,
+		ID:               int(id),
+		Amount:           body.Amount,
+		CategoryID:       body.CategoryID,
+		Memo:             body.Memo,
+		SpentAt:          body.SpentAt,
+		Status:           body.Status,
+		Splits:           body.Splits,
+		TagIDs:           body.TagIDs,
+		PayeeID:          body.PayeeID,
+		PaymentAccountID: body.PaymentAccountID,
+		Version:          version,
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	exp, err := h.service.UpdateExpense(c.Request.Context(), userID, input)
+	if err != nil {
+		writeUpdateExpenseError(c, err)
+		return
+	}
+
+	setExpenseETag(c, exp)
+	c.JSON(http.StatusOK, gin.H{"expense": exp})
+}
+
+// PatchExpense handles PATCH /expenses/:id with a JSON Merge Patch (RFC 7396) body.
+// Omitted fields are left unchanged; "memo": null clears the memo. If-Match is honored as in PUT.
+func (h *ExpenseHandler) PatchExpense(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var patch models.ExpensePatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	exp, err := h.service.PatchExpense(c.Request.Context(), userID, int(id), patch, version)
+	if err != nil {
+		writeUpdateExpenseError(c, err)
+		return
+	}
+
+	setExpenseETag(c, exp)
+	c.JSON(http.StatusOK, gin.H{"expense": exp})
+}
+
+// ConfirmExpense handles POST /expenses/:id/confirm. The body is optional and carries the actual
+// amount and date when they differ from the plan.
+func (h *ExpenseHandler) ConfirmExpense(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		return
+	}
+
+	var input models.ConfirmExpenseInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	exp, err := h.service.ConfirmExpense(c.Request.Context(), userID, int(id), input)
+	if err != nil {
+		writeConfirmExpenseError(c, err)
+		return
+	}
+
+	setExpenseETag(c, exp)
+	c.JSON(http.StatusOK, gin.H{"expense": exp})
+}
+
+// ConfirmExpenses handles POST /expenses/confirm. Either every listed expense is confirmed or none is.
+func (h *ExpenseHandler) ConfirmExpenses(c *gin.Context) {
+	var input models.ConfirmExpensesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	// TODO: Extract userID from authentication context when auth is implemented
-	userID := DummyUserID
-	exp, err := h.service.UpdateExpense(userID, input)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	expenses, err := h.service.ConfirmExpenses(c.Request.Context(), userID, input)
 	if err != nil {
-		// Validation errors -> 400
+		writeConfirmExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"expenses": expenses})
+}
+
+// BulkExpenses handles POST /expenses/bulk. Operations are applied in order within one transaction;
+// if any of them fails, nothing is applied and the response reports the error of each failed item.
+func (h *ExpenseHandler) BulkExpenses(c *gin.Context) {
+	var input models.BulkExpensesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	results, err := h.service.BulkExpenses(c.Request.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, services.ErrBulkExpensesFailed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "results": results})
+			return
+		}
 		var ve *services.ValidationError
 		if errors.As(err, &ve) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
 			return
 		}
-		// Status transition error -> 409
-		if errors.Is(err, services.ErrInvalidStatusTransition) {
-			c.JSON(http.StatusConflict, gin.H{"error": "invalid status transition"})
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
+		if errors.Is(err, services.ErrNoActiveLedger) {
+			c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
 			return
 		}
-		// Others -> 500
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"expense": exp})
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+func writeConfirmExpenseError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidStatusTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": "only planned expenses can be confirmed"})
+		return
+	}
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	if errors.Is(err, services.ErrNoActiveLedger) {
+		c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
+
+// writeUpdateExpenseError maps errors from UpdateExpense and PatchExpense to responses.
+func writeUpdateExpenseError(c *gin.Context, err error) {
+	// Validation errors -> 400
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	// Stale If-Match -> 412 with the current expense
+	if writeVersionConflict(c, err) {
+		return
+	}
+	// Missing expense -> 404
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	// Status transition error -> 409
+	if errors.Is(err, services.ErrInvalidStatusTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": "invalid status transition"})
+		return
+	}
+	// Viewer role -> 403
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	// No active ledger -> 409
+	if errors.Is(err, services.ErrNoActiveLedger) {
+		c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+		return
+	}
+	// Others -> 500
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 }
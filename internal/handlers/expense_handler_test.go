@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -18,40 +20,75 @@ import (
 // expenseServiceMock is a unified mock implementing services.ExpenseService
 // with configurable function fields for each method.
 type expenseServiceMock struct {
-	CreateExpenseFunc func(userID string, input models.CreateExpenseInput) (models.Expense, error)
-	ListExpensesFunc  func(userID string) ([]models.Expense, error)
-	DeleteExpenseFunc func(userID string, id int) error
-	UpdateExpenseFunc func(userID string, input models.UpdateExpenseInput) (models.Expense, error)
-}
-
-func (m *expenseServiceMock) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+	CreateExpenseFunc   func(userID string, input models.CreateExpenseInput) (models.Expense, error)
+	ListExpensesFunc    func(userID string, input models.ListExpensesInput) (models.ExpensePage, error)
+	SearchExpensesFunc  func(userID string, input models.SearchExpensesInput) ([]models.ExpenseSearchResult, error)
+	DeleteExpenseFunc   func(userID string, id int, version *int) error
+	UpdateExpenseFunc   func(userID string, input models.UpdateExpenseInput) (models.Expense, error)
+	PatchExpenseFunc    func(userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error)
+	ConfirmExpenseFunc  func(userID string, id int, input models.ConfirmExpenseInput) (models.Expense, error)
+	ConfirmExpensesFunc func(userID string, input models.ConfirmExpensesInput) ([]models.Expense, error)
+	BulkExpensesFunc    func(userID string, input models.BulkExpensesInput) ([]models.BulkExpenseResult, error)
+}
+
+func (m *expenseServiceMock) CreateExpense(ctx context.Context, userID string, input models.CreateExpenseInput) (models.Expense, error) {
 	if m.CreateExpenseFunc != nil {
 		return m.CreateExpenseFunc(userID, input)
 	}
 	return models.Expense{}, nil
 }
-func (m *expenseServiceMock) ListExpenses(userID string) ([]models.Expense, error) {
+func (m *expenseServiceMock) ListExpenses(ctx context.Context, userID string, input models.ListExpensesInput) (models.ExpensePage, error) {
 	if m.ListExpensesFunc != nil {
-		return m.ListExpensesFunc(userID)
+		return m.ListExpensesFunc(userID, input)
+	}
+	return models.ExpensePage{}, nil
+}
+func (m *expenseServiceMock) SearchExpenses(ctx context.Context, userID string, input models.SearchExpensesInput) ([]models.ExpenseSearchResult, error) {
+	if m.SearchExpensesFunc != nil {
+		return m.SearchExpensesFunc(userID, input)
 	}
 	return nil, nil
 }
-func (m *expenseServiceMock) DeleteExpense(userID string, id int) error {
+func (m *expenseServiceMock) DeleteExpense(ctx context.Context, userID string, id int, version *int) error {
 	if m.DeleteExpenseFunc != nil {
-		return m.DeleteExpenseFunc(userID, id)
+		return m.DeleteExpenseFunc(userID, id, version)
 	}
 	return nil
 }
-func (m *expenseServiceMock) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *expenseServiceMock) UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error) {
 	if m.UpdateExpenseFunc != nil {
 		return m.UpdateExpenseFunc(userID, input)
 	}
 	return models.Expense{}, nil
 }
+func (m *expenseServiceMock) PatchExpense(ctx context.Context, userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error) {
+	if m.PatchExpenseFunc != nil {
+		return m.PatchExpenseFunc(userID, id, patch, version)
+	}
+	return models.Expense{}, nil
+}
+func (m *expenseServiceMock) ConfirmExpense(ctx context.Context, userID string, id int, input models.ConfirmExpenseInput) (models.Expense, error) {
+	if m.ConfirmExpenseFunc != nil {
+		return m.ConfirmExpenseFunc(userID, id, input)
+	}
+	return models.Expense{}, nil
+}
+func (m *expenseServiceMock) ConfirmExpenses(ctx context.Context, userID string, input models.ConfirmExpensesInput) ([]models.Expense, error) {
+	if m.ConfirmExpensesFunc != nil {
+		return m.ConfirmExpensesFunc(userID, input)
+	}
+	return nil, nil
+}
+func (m *expenseServiceMock) BulkExpenses(ctx context.Context, userID string, input models.BulkExpensesInput) ([]models.BulkExpenseResult, error) {
+	if m.BulkExpensesFunc != nil {
+		return m.BulkExpensesFunc(userID, input)
+	}
+	return nil, nil
+}
 
 func TestCreateExpenseHandler_Created(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &expenseServiceMock{
 		CreateExpenseFunc: func(userID string, input models.CreateExpenseInput) (models.Expense, error) {
@@ -94,7 +131,7 @@ func TestCreateExpenseHandler_InvalidJSON_Skip(t *testing.T) {
 
 func TestCreateExpenseHandler_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	// mock service that returns ValidationError
 	svc := &expenseServiceMock{
@@ -133,62 +170,41 @@ func TestCreateExpenseHandler_ValidationError(t *testing.T) {
 // --- PUT /expenses/:id handler tests ---
 
 type mockExpenseServiceUpdateSuccess struct {
+	expenseServiceMock
 	ret models.Expense
 }
 
-func (m *mockExpenseServiceUpdateSuccess) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
-	return models.Expense{}, nil
-}
-func (m *mockExpenseServiceUpdateSuccess) ListExpenses(userID string) ([]models.Expense, error) {
-	return nil, nil
-}
-func (m *mockExpenseServiceUpdateSuccess) DeleteExpense(userID string, id int) error { return nil }
-func (m *mockExpenseServiceUpdateSuccess) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *mockExpenseServiceUpdateSuccess) UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error) {
 	return m.ret, nil
 }
 
-type mockExpenseServiceUpdateValidationErr struct{ msg string }
-
-func (m *mockExpenseServiceUpdateValidationErr) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
-	return models.Expense{}, nil
-}
-func (m *mockExpenseServiceUpdateValidationErr) ListExpenses(userID string) ([]models.Expense, error) {
-	return nil, nil
+type mockExpenseServiceUpdateValidationErr struct {
+	expenseServiceMock
+	msg string
 }
-func (m *mockExpenseServiceUpdateValidationErr) DeleteExpense(userID string, id int) error { return nil }
-func (m *mockExpenseServiceUpdateValidationErr) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+
+func (m *mockExpenseServiceUpdateValidationErr) UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, &services.ValidationError{Message: m.msg}
 }
 
-type mockExpenseServiceUpdateTransitionErr struct{}
+type mockExpenseServiceUpdateTransitionErr struct{ expenseServiceMock }
 
-func (m *mockExpenseServiceUpdateTransitionErr) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
-	return models.Expense{}, nil
-}
-func (m *mockExpenseServiceUpdateTransitionErr) ListExpenses(userID string) ([]models.Expense, error) {
-	return nil, nil
-}
-func (m *mockExpenseServiceUpdateTransitionErr) DeleteExpense(userID string, id int) error { return nil }
-func (m *mockExpenseServiceUpdateTransitionErr) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *mockExpenseServiceUpdateTransitionErr) UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, services.ErrInvalidStatusTransition
 }
 
-type mockExpenseServiceUpdateInternalErr struct{ err error }
-
-func (m *mockExpenseServiceUpdateInternalErr) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
-	return models.Expense{}, nil
-}
-func (m *mockExpenseServiceUpdateInternalErr) ListExpenses(userID string) ([]models.Expense, error) {
-	return nil, nil
+type mockExpenseServiceUpdateInternalErr struct {
+	expenseServiceMock
+	err error
 }
-func (m *mockExpenseServiceUpdateInternalErr) DeleteExpense(userID string, id int) error { return nil }
-func (m *mockExpenseServiceUpdateInternalErr) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+
+func (m *mockExpenseServiceUpdateInternalErr) UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, m.err
 }
 
 func TestUpdateExpenseHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	ret := models.Expense{ID: 42, Amount: 700, Memo: "updated", SpentAt: "2025-07-01", Status: "confirmed", Category: models.Category{ID: 5}}
 	svc := &mockExpenseServiceUpdateSuccess{ret: ret}
@@ -215,7 +231,7 @@ func TestUpdateExpenseHandler_Success(t *testing.T) {
 
 func TestUpdateExpenseHandler_InvalidJSON(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &mockExpenseServiceUpdateSuccess{}
 	NewExpenseHandler(router, svc)
@@ -233,7 +249,7 @@ func TestUpdateExpenseHandler_InvalidJSON(t *testing.T) {
 
 func TestUpdateExpenseHandler_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &mockExpenseServiceUpdateValidationErr{msg: "amount must be greater than 0"}
 	NewExpenseHandler(router, svc)
@@ -255,7 +271,7 @@ func TestUpdateExpenseHandler_ValidationError(t *testing.T) {
 
 func TestUpdateExpenseHandler_StatusTransitionConflict(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &mockExpenseServiceUpdateTransitionErr{}
 	NewExpenseHandler(router, svc)
@@ -275,7 +291,7 @@ func TestUpdateExpenseHandler_StatusTransitionConflict(t *testing.T) {
 
 func TestUpdateExpenseHandler_InternalError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &mockExpenseServiceUpdateInternalErr{err: errors.New("db down")}
 	NewExpenseHandler(router, svc)
@@ -295,7 +311,7 @@ func TestUpdateExpenseHandler_InternalError(t *testing.T) {
 
 func TestUpdateExpenseHandler_InvalidID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &mockExpenseServiceUpdateSuccess{}
 	NewExpenseHandler(router, svc)
@@ -315,9 +331,9 @@ func TestUpdateExpenseHandler_InvalidID(t *testing.T) {
 
 func TestDeleteExpenseHandler_NoContent(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
-	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int) error { return nil }}
+	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int, version *int) error { return nil }}
 	NewExpenseHandler(router, svc)
 
 	req := httptest.NewRequest(http.MethodDelete, "/expenses/123", nil)
@@ -329,9 +345,9 @@ func TestDeleteExpenseHandler_NoContent(t *testing.T) {
 
 func TestDeleteExpenseHandler_InvalidID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
-	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int) error { return nil }}
+	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int, version *int) error { return nil }}
 	NewExpenseHandler(router, svc)
 
 	req := httptest.NewRequest(http.MethodDelete, "/expenses/abc", nil)
@@ -346,9 +362,9 @@ func TestDeleteExpenseHandler_InvalidID(t *testing.T) {
 
 func TestDeleteExpenseHandler_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
-	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int) error { return &services.ValidationError{Message: "cannot delete planned expense"} }}
+	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int, version *int) error { return &services.ValidationError{Message: "cannot delete planned expense"} }}
 	NewExpenseHandler(router, svc)
 
 	req := httptest.NewRequest(http.MethodDelete, "/expenses/10", nil)
@@ -361,29 +377,29 @@ func TestDeleteExpenseHandler_ValidationError(t *testing.T) {
 	require.Equal(t, "cannot delete planned expense", resp["error"])
 }
 
-func TestDeleteExpenseHandler_NotFoundMapsTo500Currently(t *testing.T) {
+func TestDeleteExpenseHandler_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
-	// Current handler maps non-ValidationError to 500
-	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int) error { return &services.NotFoundError{Message: "expense not found"} }}
+	// Missing or already trashed expenses are reported as 404
+	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int, version *int) error { return &services.NotFoundError{Message: "expense not found"} }}
 	NewExpenseHandler(router, svc)
 
 	req := httptest.NewRequest(http.MethodDelete, "/expenses/9999", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, http.StatusNotFound, w.Code)
 	var resp map[string]string
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	require.Equal(t, "internal server error", resp["error"])
+	require.Equal(t, "expense not found", resp["error"])
 }
 
 func TestDeleteExpenseHandler_InternalError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
-	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int) error { return errors.New("db down") }}
+	svc := &expenseServiceMock{DeleteExpenseFunc: func(userID string, id int, version *int) error { return errors.New("db down") }}
 	NewExpenseHandler(router, svc)
 
 	req := httptest.NewRequest(http.MethodDelete, "/expenses/1", nil)
@@ -476,7 +492,7 @@ func TestUpdateExpenseHandler_Cases(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			router := gin.New()
+			router := newAuthedRouter()
 			svc := &expenseServiceMock{UpdateExpenseFunc: tc.mockUpdate}
 			NewExpenseHandler(router, svc)
 
@@ -492,3 +508,480 @@ func TestUpdateExpenseHandler_Cases(t *testing.T) {
 		})
 	}
 }
+
+func TestExpenseHandler_ViewerForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forbidden := &services.ForbiddenError{Message: "viewers cannot modify this ledger"}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "create", method: http.MethodPost, path: "/expenses", body: `{"amount":100,"category_id":1,"spent_at":"2025-01-01"}`},
+		{name: "update", method: http.MethodPut, path: "/expenses/1", body: `{"amount":100,"category_id":1,"spent_at":"2025-01-01"}`},
+		{name: "delete", method: http.MethodDelete, path: "/expenses/1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewExpenseHandler(router, &expenseServiceMock{
+				CreateExpenseFunc: func(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+					return models.Expense{}, forbidden
+				},
+				UpdateExpenseFunc: func(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+					return models.Expense{}, forbidden
+				},
+				DeleteExpenseFunc: func(userID string, id int, version *int) error { return forbidden },
+			})
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusForbidden, w.Code)
+			var resp map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, "viewers cannot modify this ledger", resp["error"])
+		})
+	}
+}
+
+func TestListExpensesHandler_QueryAndCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	next := "opaque"
+	svc := &expenseServiceMock{
+		ListExpensesFunc: func(userID string, input models.ListExpensesInput) (models.ExpensePage, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, models.ListExpensesInput{
+				From:        "2025-01-01",
+				To:          "2025-01-31",
+				CategoryIDs: []string{"1", "2"},
+				Status:      "planned",
+				MinAmount:   100,
+				Memo:        "lunch",
+				Sort:        "-amount",
+				Limit:       10,
+				Cursor:      "abc",
+			}, input)
+			return models.ExpensePage{Expenses: []models.Expense{{ID: 1}}, NextCursor: &next}, nil
+		},
+	}
+	NewExpenseHandler(router, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/expenses?from=2025-01-01&to=2025-01-31&category_id=1&category_id=2&status=planned&min_amount=100&memo=lunch&sort=-amount&limit=10&cursor=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.ExpensePage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Expenses, 1)
+	require.Equal(t, "opaque", *resp.NextCursor)
+}
+
+func TestListExpensesHandler_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewExpenseHandler(router, &expenseServiceMock{
+		ListExpensesFunc: func(userID string, input models.ListExpensesInput) (models.ExpensePage, error) {
+			return models.ExpensePage{}, &services.ValidationError{Message: "sort must be one of '-spent_at', 'spent_at', '-amount', 'amount'"}
+		},
+	})
+
+	for _, target := range []string{"/expenses?min_amount=abc", "/expenses?sort=memo"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+}
+
+func TestSearchExpensesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	svc := &expenseServiceMock{
+		SearchExpensesFunc: func(userID string, input models.SearchExpensesInput) ([]models.ExpenseSearchResult, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, models.SearchExpensesInput{Q: "スタバ ラテ", CategoryIDs: []string{"3"}, Status: "confirmed", Limit: 5}, input)
+			return []models.ExpenseSearchResult{{
+				Expense:   models.Expense{ID: 7, Memo: "スタバでラテ"},
+				Score:     0.5,
+				Highlight: []models.MemoSegment{{Text: "スタバ", Match: true}, {Text: "で"}, {Text: "ラテ", Match: true}},
+			}}, nil
+		},
+	}
+	NewExpenseHandler(router, svc)
+
+	q := url.Values{"q": {"スタバ ラテ"}, "category_id": {"3"}, "status": {"confirmed"}, "limit": {"5"}}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/search?"+q.Encode(), nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Results []models.ExpenseSearchResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 1)
+	require.Equal(t, 7, resp.Results[0].ID)
+	require.Equal(t, []models.MemoSegment{{Text: "スタバ", Match: true}, {Text: "で"}, {Text: "ラテ", Match: true}}, resp.Results[0].Highlight)
+}
+
+func TestSearchExpensesHandler_MissingQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewExpenseHandler(router, &expenseServiceMock{
+		SearchExpensesFunc: func(userID string, input models.SearchExpensesInput) ([]models.ExpenseSearchResult, error) {
+			return nil, &services.ValidationError{Message: "q must be provided"}
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/search", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPatchExpenseHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	var got models.ExpensePatch
+	NewExpenseHandler(router, &expenseServiceMock{
+		PatchExpenseFunc: func(userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, 42, id)
+			got = patch
+			return models.Expense{ID: 42, Amount: 700, Status: "confirmed"}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPatch, "/expenses/42", strings.NewReader(`{"memo":null,"status":"confirmed"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Nil(t, got.Amount)
+	require.Nil(t, got.CategoryID)
+	require.Nil(t, got.SpentAt)
+	require.NotNil(t, got.Memo)
+	require.Equal(t, "", *got.Memo)
+	require.Equal(t, "confirmed", *got.Status)
+}
+
+func TestPatchExpenseHandler_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "invalid id", path: "/expenses/abc", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "not an object", path: "/expenses/1", body: `[1]`, wantStatus: http.StatusBadRequest},
+		{name: "null body", path: "/expenses/1", body: `null`, wantStatus: http.StatusBadRequest},
+		{name: "required field set to null", path: "/expenses/1", body: `{"amount":null}`, wantStatus: http.StatusBadRequest},
+		{name: "wrong type", path: "/expenses/1", body: `{"amount":"100"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown field", path: "/expenses/1", body: `{"amout":100}`, wantStatus: http.StatusBadRequest},
+		{name: "validation error", path: "/expenses/1", body: `{"amount":0}`, err: &services.ValidationError{Message: "amount must be greater than 0"}, wantStatus: http.StatusBadRequest},
+		{name: "status transition", path: "/expenses/1", body: `{"status":"planned"}`, err: services.ErrInvalidStatusTransition, wantStatus: http.StatusConflict},
+		{name: "not found", path: "/expenses/9999", body: `{"memo":"x"}`, err: &services.NotFoundError{Message: "expense not found"}, wantStatus: http.StatusNotFound},
+		{name: "viewer", path: "/expenses/1", body: `{"memo":"x"}`, err: &services.ForbiddenError{Message: "viewers cannot modify this ledger"}, wantStatus: http.StatusForbidden},
+		{name: "internal", path: "/expenses/1", body: `{"memo":"x"}`, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewExpenseHandler(router, &expenseServiceMock{
+				PatchExpenseFunc: func(userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error) {
+					return models.Expense{}, tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPatch, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestConfirmExpenseHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	amount := 5480
+
+	cases := []struct {
+		name       string
+		body       string
+		err        error
+		wantInput  models.ConfirmExpenseInput
+		wantStatus int
+	}{
+		{name: "本文なし", body: "", wantStatus: http.StatusOK},
+		{name: "実際の金額と日付", body: `{"amount":5480,"spent_at":"2025-03-02"}`, wantInput: models.ConfirmExpenseInput{Amount: &amount, SpentAt: "2025-03-02"}, wantStatus: http.StatusOK},
+		{name: "不正な JSON", body: `{"amount":`, wantStatus: http.StatusBadRequest},
+		{name: "存在しない", body: "", err: &services.NotFoundError{Message: "expense 7 not found"}, wantStatus: http.StatusNotFound},
+		{name: "確定済み", body: "", err: services.ErrInvalidStatusTransition, wantStatus: http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewExpenseHandler(router, &expenseServiceMock{
+				ConfirmExpenseFunc: func(userID string, id int, input models.ConfirmExpenseInput) (models.Expense, error) {
+					require.Equal(t, 7, id)
+					require.Equal(t, tc.wantInput, input)
+					return models.Expense{ID: 7, Status: "confirmed"}, tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/expenses/7/confirm", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestConfirmExpensesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	amount := 250
+	NewExpenseHandler(router, &expenseServiceMock{
+		ConfirmExpensesFunc: func(userID string, input models.ConfirmExpensesInput) ([]models.Expense, error) {
+			require.Equal(t, []models.ConfirmExpenseItem{{ID: 1}, {ID: 2, Amount: &amount}}, input.Items)
+			return []models.Expense{{ID: 1, Status: "confirmed"}, {ID: 2, Status: "confirmed"}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/expenses/confirm", strings.NewReader(`{"items":[{"id":1},{"id":2,"amount":250}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Expenses []models.Expense `json:"expenses"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Expenses, 2)
+}
+
+func TestBulkExpensesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	NewExpenseHandler(router, &expenseServiceMock{
+		BulkExpensesFunc: func(userID string, input models.BulkExpensesInput) ([]models.BulkExpenseResult, error) {
+			require.Len(t, input.Operations, 3)
+			require.Equal(t, models.BulkExpenseOpCreate, input.Operations[0].Op)
+			require.Equal(t, 1200, *input.Operations[0].Expense.Amount)
+			require.Equal(t, "", *input.Operations[1].Patch.Memo)
+			require.Equal(t, 9, input.Operations[2].ID)
+			return []models.BulkExpenseResult{
+				{Index: 0, Op: "create", ID: 10, Expense: &models.Expense{ID: 10}},
+				{Index: 1, Op: "update", ID: 8, Expense: &models.Expense{ID: 8}},
+				{Index: 2, Op: "delete", ID: 9},
+			}, nil
+		},
+	})
+
+	body := `{"operations":[
+		{"op":"create","expense":{"amount":1200,"category_id":1,"spent_at":"2025-04-01"}},
+		{"op":"update","id":8,"patch":{"memo":null}},
+		{"op":"delete","id":9}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/expenses/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Results []models.BulkExpenseResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 3)
+	require.Equal(t, 10, resp.Results[0].Expense.ID)
+}
+
+func TestBulkExpensesHandler_Failed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	NewExpenseHandler(router, &expenseServiceMock{
+		BulkExpensesFunc: func(userID string, input models.BulkExpensesInput) ([]models.BulkExpenseResult, error) {
+			return []models.BulkExpenseResult{
+				{Index: 0, Op: "create"},
+				{Index: 1, Op: "delete", ID: 99, Error: "expense not found"},
+			}, services.ErrBulkExpensesFailed
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/expenses/bulk", strings.NewReader(`{"operations":[{"op":"create","expense":{}},{"op":"delete","id":99}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.JSONEq(t, `{
+		"error": "one or more operations failed",
+		"results": [
+			{"index": 0, "op": "create"},
+			{"index": 1, "op": "delete", "id": 99, "error": "expense not found"}
+		]
+	}`, w.Body.String())
+}
+
+func TestExpenseHandler_IfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("PUT は If-Match のバージョンを渡し、更新後の ETag を返す", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewExpenseHandler(router, &expenseServiceMock{
+			UpdateExpenseFunc: func(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+				require.NotNil(t, input.Version)
+				require.Equal(t, 3, *input.Version)
+				return models.Expense{ID: 1, Version: 4}, nil
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodPut, "/expenses/1", strings.NewReader(`{"amount":100,"category_id":1,"spent_at":"2025-01-01"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"4"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("バージョンが古ければ現在の支出とともに 412", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewExpenseHandler(router, &expenseServiceMock{
+			PatchExpenseFunc: func(userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error) {
+				return models.Expense{}, &services.VersionConflictError{Message: "expense has been modified", Current: models.Expense{ID: 1, Amount: 500, Version: 5}}
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodPatch, "/expenses/1", strings.NewReader(`{"memo":"x"}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusPreconditionFailed, w.Code)
+		require.Equal(t, `"5"`, w.Header().Get("ETag"))
+		var resp struct {
+			Error   string         `json:"error"`
+			Expense models.Expense `json:"expense"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "expense has been modified", resp.Error)
+		require.Equal(t, 500, resp.Expense.Amount)
+	})
+
+	t.Run("DELETE の If-Match", func(t *testing.T) {
+		cases := []struct {
+			name        string
+			ifMatch     string
+			wantStatus  int
+			wantVersion *int
+		}{
+			{name: "指定なし", wantStatus: http.StatusNoContent},
+			{name: "*", ifMatch: "*", wantStatus: http.StatusNoContent},
+			{name: "ETag", ifMatch: `"7"`, wantStatus: http.StatusNoContent, wantVersion: func() *int { v := 7; return &v }()},
+			{name: "弱い ETag", ifMatch: `W/"7"`, wantStatus: http.StatusBadRequest},
+			{name: "複数の ETag", ifMatch: `"6", "7"`, wantStatus: http.StatusBadRequest},
+			{name: "引用符なし", ifMatch: `7`, wantStatus: http.StatusBadRequest},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				router := newAuthedRouter()
+				called := false
+				NewExpenseHandler(router, &expenseServiceMock{
+					DeleteExpenseFunc: func(userID string, id int, version *int) error {
+						called = true
+						require.Equal(t, tc.wantVersion, version)
+						return nil
+					},
+				})
+
+				req := httptest.NewRequest(http.MethodDelete, "/expenses/1", nil)
+				if tc.ifMatch != "" {
+					req.Header.Set("If-Match", tc.ifMatch)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				require.Equal(t, tc.wantStatus, w.Code)
+				require.Equal(t, tc.wantStatus == http.StatusNoContent, called)
+			})
+		}
+	})
+
+	t.Run("DELETE の If-Match で支出が既にゴミ箱にあれば 404", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewExpenseHandler(router, &expenseServiceMock{
+			DeleteExpenseFunc: func(userID string, id int, version *int) error {
+				require.Equal(t, 7, *version)
+				return &services.NotFoundError{Message: "expense not found"}
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodDelete, "/expenses/1", nil)
+		req.Header.Set("If-Match", `"7"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.JSONEq(t, `{"error":"expense not found"}`, w.Body.String())
+	})
+}
+
+func TestUpdateExpenseHandler_PayeeAndPaymentAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	payeeID, accountID := 3, 7
+
+	cases := []struct {
+		name        string
+		body        string
+		wantPayee   *int
+		wantAccount *int
+	}{
+		{name: "指定した支払先・支払い手段を渡す", body: `{"amount":100,"category_id":1,"spent_at":"2025-01-01","payee_id":3,"payment_account_id":7}`, wantPayee: &payeeID, wantAccount: &accountID},
+		{name: "省略すると nil", body: `{"amount":100,"category_id":1,"spent_at":"2025-01-01"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			var got models.UpdateExpenseInput
+			NewExpenseHandler(router, &expenseServiceMock{
+				UpdateExpenseFunc: func(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+					got = input
+					return models.Expense{ID: 1}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/expenses/1", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tc.wantPayee, got.PayeeID)
+			require.Equal(t, tc.wantAccount, got.PaymentAccountID)
+		})
+	}
+}
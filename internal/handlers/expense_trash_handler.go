@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/services"
+)
+
+type ExpenseTrashHandler struct {
+	service services.ExpenseTrashService
+}
+
+func NewExpenseTrashHandler(r gin.IRoutes, service services.ExpenseTrashService) {
+	h := &ExpenseTrashHandler{service: service}
+	r.GET("/expenses/trash", RequireScope(auth.ScopeExpensesRead), h.ListTrash)
+	r.POST("/expenses/:id/restore", RequireScope(auth.ScopeExpensesWrite), h.RestoreExpense)
+}
+
+// ListTrash handles GET /expenses/trash. It returns the active ledger's deleted expenses,
+// most recently deleted first, with the time each one will be purged.
+func (h *ExpenseTrashHandler) ListTrash(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	expenses, err := h.service.ListTrash(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"expenses": expenses})
+}
+
+// RestoreExpense handles POST /expenses/:id/restore. It moves an expense out of the trash.
+func (h *ExpenseTrashHandler) RestoreExpense(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	expense, err := h.service.RestoreExpense(c.Request.Context(), userID, int(id))
+	if err != nil {
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
+		if errors.Is(err, services.ErrNoActiveLedger) {
+			c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	setExpenseETag(c, expense)
+	c.JSON(http.StatusOK, gin.H{"expense": expense})
+}
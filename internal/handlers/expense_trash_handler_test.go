@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type expenseTrashServiceMock struct {
+	ListTrashFunc      func(userID string) ([]models.TrashedExpense, error)
+	RestoreExpenseFunc func(userID string, id int) (models.Expense, error)
+}
+
+func (m *expenseTrashServiceMock) ListTrash(ctx context.Context, userID string) ([]models.TrashedExpense, error) {
+	if m.ListTrashFunc != nil {
+		return m.ListTrashFunc(userID)
+	}
+	return []models.TrashedExpense{}, nil
+}
+
+func (m *expenseTrashServiceMock) RestoreExpense(ctx context.Context, userID string, id int) (models.Expense, error) {
+	if m.RestoreExpenseFunc != nil {
+		return m.RestoreExpenseFunc(userID, id)
+	}
+	return models.Expense{}, nil
+}
+
+func (m *expenseTrashServiceMock) PurgeExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestExpenseTrashHandler_ListTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ゴミ箱の支出を返す", func(t *testing.T) {
+		router := newAuthedRouter()
+		// /expenses の他のルートと同じルーターに登録できる
+		NewExpenseHandler(router, &expenseServiceMock{})
+		NewExpenseTrashHandler(router, &expenseTrashServiceMock{
+			ListTrashFunc: func(userID string) ([]models.TrashedExpense, error) {
+				require.Equal(t, testUserID, userID)
+				return []models.TrashedExpense{{
+					Expense:   models.Expense{ID: 3, Amount: 500},
+					DeletedAt: "2025-05-01T09:00:00Z",
+					PurgeAt:   "2025-05-31T09:00:00Z",
+				}}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/trash", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Expenses []models.TrashedExpense `json:"expenses"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Expenses, 1)
+		require.Equal(t, 3, resp.Expenses[0].ID)
+		require.Equal(t, "2025-05-31T09:00:00Z", resp.Expenses[0].PurgeAt)
+	})
+
+	t.Run("エラーは 500", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewExpenseTrashHandler(router, &expenseTrashServiceMock{
+			ListTrashFunc: func(userID string) ([]models.TrashedExpense, error) {
+				return nil, errors.New("db down")
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/trash", nil))
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestExpenseTrashHandler_RestoreExpense(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("元に戻した支出を返す", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewExpenseTrashHandler(router, &expenseTrashServiceMock{
+			RestoreExpenseFunc: func(userID string, id int) (models.Expense, error) {
+				require.Equal(t, testUserID, userID)
+				require.Equal(t, 7, id)
+				return models.Expense{ID: 7, Amount: 900, Version: 4}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/expenses/7/restore", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"4"`, w.Header().Get("ETag"))
+		require.JSONEq(t, `{"expense":{"id":7,"amount":900,"memo":"","spent_at":"","status":"","category":{"id":0,"name":""},"confirmed_at":null,"planned_amount":null,"planned_spent_at":null,"version":4}}`, w.Body.String())
+	})
+
+	cases := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "不正な ID", path: "/expenses/abc/restore", wantStatus: http.StatusBadRequest},
+		{name: "ゴミ箱に無い", path: "/expenses/7/restore", err: &services.NotFoundError{Message: "expense not found in trash"}, wantStatus: http.StatusNotFound},
+		{name: "閲覧者", path: "/expenses/7/restore", err: &services.ForbiddenError{Message: "viewers cannot modify this ledger"}, wantStatus: http.StatusForbidden},
+		{name: "使用中の家計簿が無い", path: "/expenses/7/restore", err: services.ErrNoActiveLedger, wantStatus: http.StatusConflict},
+		{name: "その他のエラー", path: "/expenses/7/restore", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewExpenseTrashHandler(router, &expenseTrashServiceMock{
+				RestoreExpenseFunc: func(userID string, id int) (models.Expense, error) {
+					return models.Expense{}, tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, nil))
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
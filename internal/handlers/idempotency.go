@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+// idempotencyKeyMaxLen は Idempotency-Key の最大長です。UUID などのランダムな値を想定しています。
+const idempotencyKeyMaxLen = 255
+
+// idempotencyMaxResponseBytes は保存するレスポンスの本文の最大サイズです。API のレスポンスは JSON の小さな本文のため、
+// これを超えるレスポンスは保存できないものとして 500 を返します。
+const idempotencyMaxResponseBytes = 1 << 20
+
+// idempotentHeaders は保存したレスポンスの再送にも付けるヘッダです。
+var idempotentHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// Idempotency は Idempotency-Key ヘッダ付きの POST リクエスト（POST /expenses・POST /setup など）を、
+// 再送されても 1 回しか処理しないようにします。RequestTransaction と呼び出し元の認証の後ろに置きます。
+//
+// 処理に成功したレスポンス（ステータスが 400 未満）はリクエストと同じトランザクションでキーと一緒に保存し、
+// 同じキー・同じ内容の再送には処理をせずに保存したレスポンスを返します（Idempotent-Replayed: true）。
+// 失敗したレスポンスは保存しないため、同じキーでやり直せます。
+// 同じキーが別の内容のリクエストに使われた場合は 422、並行して処理された同じキーのリクエストが
+// 先に完了した場合は 409 を返します。ヘッダが無ければ何もしません。
+//
+// 本文はハッシュのためにメモリに読み込むため、maxBodyBytes を超える本文は読まずに 413 を返します。
+// 添付ファイルのアップロードにもキーを付けられるよう、AttachmentRequestMaxBytes の値を渡します。
+func Idempotency(service services.IdempotencyService, maxBodyBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be 1 to 255 printable ASCII characters"})
+			return
+		}
+
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := idempotencyRequestHash(c.Request, body)
+
+		stored, err := service.Lookup(c.Request.Context(), userID, key, requestHash)
+		if err != nil {
+			if errors.Is(err, services.ErrIdempotencyKeyReused) {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key has already been used for a different request"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if stored != nil {
+			replayIdempotentResponse(c, *stored)
+			return
+		}
+
+		w := &idempotencyResponseWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.overflow {
+			// 保存できないレスポンスは返さずに失敗として扱い、リクエストのトランザクションをロールバックさせる
+			for _, h := range idempotentHeaders {
+				c.Writer.Header().Del(h)
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "response is too large to store for Idempotency-Key"})
+			return
+		}
+		if w.status < http.StatusBadRequest {
+			response := models.IdempotentResponse{
+				Status:  w.status,
+				Headers: make(map[string]string),
+				Body:    w.body.Bytes(),
+			}
+			for _, h := range idempotentHeaders {
+				if v := c.Writer.Header().Get(h); v != "" {
+					response.Headers[h] = v
+				}
+			}
+			if err := service.Save(c.Request.Context(), userID, key, requestHash, response); err != nil {
+				// ハンドラが付けたヘッダは破棄したレスポンスのものなので消す
+				for _, h := range idempotentHeaders {
+					c.Writer.Header().Del(h)
+				}
+				if errors.Is(err, services.ErrIdempotencyKeyInProgress) {
+					c.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key has already been processed; retry to get its response"})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+				return
+			}
+		}
+
+		c.Writer.WriteHeader(w.status)
+		if w.body.Len() > 0 {
+			_, _ = c.Writer.Write(w.body.Bytes())
+		} else {
+			c.Writer.WriteHeaderNow()
+		}
+	}
+}
+
+// validIdempotencyKey は key が 1〜255 文字の表示可能な ASCII 文字だけで構成されているかを判定します。
+func validIdempotencyKey(key string) bool {
+	if len(key) > idempotencyKeyMaxLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyRequestHash は同じキーで同じリクエストが送られたかを判定するための、メソッド・パス・本文のハッシュです。
+func idempotencyRequestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replayIdempotentResponse(c *gin.Context, stored models.IdempotentResponse) {
+	for k, v := range stored.Headers {
+		c.Header(k, v)
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Writer.WriteHeader(stored.Status)
+	if len(stored.Body) > 0 {
+		_, _ = c.Writer.Write(stored.Body)
+	} else {
+		c.Writer.WriteHeaderNow()
+	}
+	c.Abort()
+}
+
+// idempotencyResponseWriter はハンドラのレスポンスを書き出さずに保持する gin.ResponseWriter です。
+// キーの保存が済んでから、保持したレスポンスを元の ResponseWriter に書き出します。
+// idempotencyMaxResponseBytes を超えた分は保持せず、overflow を立てます。
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *idempotencyResponseWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *idempotencyResponseWriter) WriteHeaderNow() {}
+
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	if w.overflow || w.body.Len()+len(b) > idempotencyMaxResponseBytes {
+		w.overflow = true
+		w.body.Reset()
+		return len(b), nil
+	}
+	return w.body.Write(b)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *idempotencyResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *idempotencyResponseWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *idempotencyResponseWriter) Written() bool {
+	return false
+}
+
+func (w *idempotencyResponseWriter) Flush() {}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+// idempotencyServiceFake はキーをメモリに保存する IdempotencyService です。
+type idempotencyServiceFake struct {
+	hashes    map[string]string
+	responses map[string]models.IdempotentResponse
+	saveErr   error
+}
+
+func newIdempotencyServiceFake() *idempotencyServiceFake {
+	return &idempotencyServiceFake{hashes: map[string]string{}, responses: map[string]models.IdempotentResponse{}}
+}
+
+func (f *idempotencyServiceFake) Lookup(ctx context.Context, userID string, key string, requestHash string) (*models.IdempotentResponse, error) {
+	hash, ok := f.hashes[userID+"/"+key]
+	if !ok {
+		return nil, nil
+	}
+	if hash != requestHash {
+		return nil, services.ErrIdempotencyKeyReused
+	}
+	r := f.responses[userID+"/"+key]
+	return &r, nil
+}
+
+func (f *idempotencyServiceFake) Save(ctx context.Context, userID string, key string, requestHash string, response models.IdempotentResponse) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.hashes[userID+"/"+key] = requestHash
+	f.responses[userID+"/"+key] = response
+	return nil
+}
+
+func (f *idempotencyServiceFake) PurgeExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// testIdempotencyMaxBodyBytes はテストで受け付ける本文の最大サイズです。
+const testIdempotencyMaxBodyBytes = 1 << 10
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// newRouter は呼ばれるたびに ID を増やして支出を作成したことにするルーターを返します。
+	newRouter := func(service services.IdempotencyService, calls *int) *gin.Engine {
+		router := newAuthedRouter()
+		router.Use(Idempotency(service, testIdempotencyMaxBodyBytes))
+		router.POST("/expenses", func(c *gin.Context) {
+			*calls++
+			var body struct {
+				Amount int `json:"amount"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil || body.Amount <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be greater than 0"})
+				return
+			}
+			c.Header("ETag", `"1"`)
+			c.JSON(http.StatusCreated, gin.H{"expense": gin.H{"id": *calls, "amount": body.Amount}})
+		})
+		return router
+	}
+	post := func(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/expenses", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("再送には保存したレスポンスを返し、処理は 1 回だけ", func(t *testing.T) {
+		calls := 0
+		router := newRouter(newIdempotencyServiceFake(), &calls)
+
+		first := post(router, "k1", `{"amount":500}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.JSONEq(t, `{"expense":{"id":1,"amount":500}}`, first.Body.String())
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+		retry := post(router, "k1", `{"amount":500}`)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, `"1"`, retry.Header().Get("ETag"))
+		assert.Equal(t, "application/json; charset=utf-8", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("別の内容で同じキーを使うと 422", func(t *testing.T) {
+		calls := 0
+		router := newRouter(newIdempotencyServiceFake(), &calls)
+
+		require.Equal(t, http.StatusCreated, post(router, "k1", `{"amount":500}`).Code)
+		w := post(router, "k1", `{"amount":600}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("失敗したレスポンスは保存せず、同じキーでやり直せる", func(t *testing.T) {
+		calls := 0
+		service := newIdempotencyServiceFake()
+		router := newRouter(service, &calls)
+
+		require.Equal(t, http.StatusBadRequest, post(router, "k1", `{"amount":0}`).Code)
+		assert.Empty(t, service.hashes)
+		require.Equal(t, http.StatusBadRequest, post(router, "k1", `{"amount":0}`).Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("大きすぎる本文は読まずに 413", func(t *testing.T) {
+		calls := 0
+		service := newIdempotencyServiceFake()
+		router := newRouter(service, &calls)
+
+		w := post(router, "k1", `{"amount":500,"memo":"`+strings.Repeat("a", testIdempotencyMaxBodyBytes)+`"}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Zero(t, calls)
+		assert.Empty(t, service.hashes)
+	})
+
+	t.Run("キーが無ければ毎回処理する", func(t *testing.T) {
+		calls := 0
+		router := newRouter(newIdempotencyServiceFake(), &calls)
+
+		post(router, "", `{"amount":500}`)
+		post(router, "", `{"amount":500}`)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("不正なキーは 400", func(t *testing.T) {
+		calls := 0
+		router := newRouter(newIdempotencyServiceFake(), &calls)
+
+		w := post(router, "has space", `{"amount":500}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = post(router, strings.Repeat("a", idempotencyKeyMaxLen+1), `{"amount":500}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("並行したリクエストが先に保存していたら 409 でロールバックする", func(t *testing.T) {
+		calls := 0
+		service := newIdempotencyServiceFake()
+		service.saveErr = services.ErrIdempotencyKeyInProgress
+
+		w := httptest.NewRecorder()
+		tx := &requestTxMock{recorder: w}
+		router := newAuthedRouter()
+		router.Use(RequestTransaction(&requestTxManagerMock{tx: tx}), Idempotency(service, testIdempotencyMaxBodyBytes))
+		router.POST("/expenses", func(c *gin.Context) {
+			calls++
+			c.Header("ETag", `"1"`)
+			c.JSON(http.StatusCreated, gin.H{"expense": gin.H{"id": 1}})
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/expenses", strings.NewReader(`{"amount":500}`))
+		req.Header.Set("Idempotency-Key", "k1")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 0, tx.commits)
+		assert.Equal(t, 1, tx.rollbacks)
+	})
+
+	t.Run("保存してからコミットする", func(t *testing.T) {
+		service := newIdempotencyServiceFake()
+		w := httptest.NewRecorder()
+		tx := &requestTxMock{recorder: w}
+		router := newAuthedRouter()
+		router.Use(RequestTransaction(&requestTxManagerMock{tx: tx}), Idempotency(service, testIdempotencyMaxBodyBytes))
+		router.POST("/setup", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/setup", strings.NewReader(`{}`))
+		req.Header.Set("Idempotency-Key", "k1")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+		assert.Equal(t, 1, tx.commits)
+		assert.Len(t, service.responses, 1)
+	})
+	t.Run("保存できない大きさのレスポンスは 500 でロールバックする", func(t *testing.T) {
+		service := newIdempotencyServiceFake()
+		w := httptest.NewRecorder()
+		tx := &requestTxMock{recorder: w}
+		router := newAuthedRouter()
+		router.Use(RequestTransaction(&requestTxManagerMock{tx: tx}), Idempotency(service, testIdempotencyMaxBodyBytes))
+		router.POST("/expenses", func(c *gin.Context) {
+			c.Header("ETag", `"1"`)
+			c.JSON(http.StatusCreated, gin.H{"memo": strings.Repeat("a", idempotencyMaxResponseBytes)})
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/expenses", strings.NewReader(`{"amount":500}`))
+		req.Header.Set("Idempotency-Key", "k1")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.Empty(t, service.responses)
+		assert.Equal(t, 0, tx.commits)
+		assert.Equal(t, 1, tx.rollbacks)
+	})
+}
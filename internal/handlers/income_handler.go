@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type IncomeHandler struct {
+	service services.IncomeService
+}
+
+func NewIncomeHandler(r gin.IRoutes, service services.IncomeService) {
+	h := &IncomeHandler{service: service}
+	r.GET("/incomes", RequireScope(auth.ScopeExpensesRead), h.ListIncomes)
+	r.GET("/incomes/summary", RequireScope(auth.ScopeExpensesRead), h.Summary)
+	r.POST("/incomes", RequireScope(auth.ScopeExpensesWrite), h.CreateIncome)
+	r.PUT("/incomes/:id", RequireScope(auth.ScopeExpensesWrite), h.UpdateIncome)
+	r.DELETE("/incomes/:id", RequireScope(auth.ScopeExpensesWrite), h.DeleteIncome)
+}
+
+// ListIncomes handles GET /incomes. It returns the active ledger's incomes, most recently
+// received first.
+func (h *IncomeHandler) ListIncomes(c *gin.Context) {
+	var input models.ListIncomesInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	incomes, err := h.service.ListIncomes(c.Request.Context(), userID, input)
+	if err != nil {
+		writeIncomeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"incomes": incomes})
+}
+
+// CreateIncome handles POST /incomes. The income is recorded as received by the caller.
+func (h *IncomeHandler) CreateIncome(c *gin.Context) {
+	var input models.IncomeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	income, err := h.service.CreateIncome(c.Request.Context(), userID, input)
+	if err != nil {
+		writeIncomeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"income": income})
+}
+
+// UpdateIncome handles PUT /incomes/:id.
+func (h *IncomeHandler) UpdateIncome(c *gin.Context) {
+	id, ok := incomeIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.IncomeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	income, err := h.service.UpdateIncome(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writeIncomeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"income": income})
+}
+
+// DeleteIncome handles DELETE /incomes/:id.
+func (h *IncomeHandler) DeleteIncome(c *gin.Context) {
+	id, ok := incomeIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteIncome(c.Request.Context(), userID, id); err != nil {
+		writeIncomeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Summary handles GET /incomes/summary. It returns the month's income for the active ledger,
+// falling back to each member's income setting when they have recorded no income that month.
+func (h *IncomeHandler) Summary(c *gin.Context) {
+	var input models.IncomeSummaryInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.service.MonthlySummary(c.Request.Context(), userID, input)
+	if err != nil {
+		writeIncomeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func incomeIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid income ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeIncomeError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrNoActiveLedger):
+		c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+	case errors.Is(err, services.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": "invalid status transition"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type incomeServiceMock struct {
+	ListIncomesFunc    func(userID string, input models.ListIncomesInput) ([]models.Income, error)
+	CreateIncomeFunc   func(userID string, input models.IncomeInput) (models.Income, error)
+	UpdateIncomeFunc   func(userID string, id int, input models.IncomeInput) (models.Income, error)
+	DeleteIncomeFunc   func(userID string, id int) error
+	MonthlySummaryFunc func(userID string, input models.IncomeSummaryInput) (models.IncomeSummary, error)
+}
+
+func (m *incomeServiceMock) ListIncomes(ctx context.Context, userID string, input models.ListIncomesInput) ([]models.Income, error) {
+	if m.ListIncomesFunc != nil {
+		return m.ListIncomesFunc(userID, input)
+	}
+	return []models.Income{}, nil
+}
+
+func (m *incomeServiceMock) CreateIncome(ctx context.Context, userID string, input models.IncomeInput) (models.Income, error) {
+	if m.CreateIncomeFunc != nil {
+		return m.CreateIncomeFunc(userID, input)
+	}
+	return models.Income{}, nil
+}
+
+func (m *incomeServiceMock) UpdateIncome(ctx context.Context, userID string, id int, input models.IncomeInput) (models.Income, error) {
+	if m.UpdateIncomeFunc != nil {
+		return m.UpdateIncomeFunc(userID, id, input)
+	}
+	return models.Income{}, nil
+}
+
+func (m *incomeServiceMock) DeleteIncome(ctx context.Context, userID string, id int) error {
+	if m.DeleteIncomeFunc != nil {
+		return m.DeleteIncomeFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *incomeServiceMock) MonthlySummary(ctx context.Context, userID string, input models.IncomeSummaryInput) (models.IncomeSummary, error) {
+	if m.MonthlySummaryFunc != nil {
+		return m.MonthlySummaryFunc(userID, input)
+	}
+	return models.IncomeSummary{}, nil
+}
+
+func TestIncomeHandler_ListIncomes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewIncomeHandler(router, &incomeServiceMock{
+		ListIncomesFunc: func(userID string, input models.ListIncomesInput) ([]models.Income, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, models.ListIncomesInput{From: "2025-06-01", Status: "confirmed"}, input)
+			return []models.Income{{ID: 1, UserID: testUserID, Source: "給与", Amount: 300000, ReceivedAt: "2025-06-25", Status: "confirmed"}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/incomes?from=2025-06-01&status=confirmed", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"incomes":[{"id":1,"user_id":"`+testUserID+`","source":"給与","amount":300000,"received_at":"2025-06-25","status":"confirmed"}]}`, w.Body.String())
+}
+
+func TestIncomeHandler_CreateUpdateDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `{"source":"賞与","amount":400000,"received_at":"2025-06-30"}`
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "登録する", method: http.MethodPost, path: "/incomes", body: body, wantStatus: http.StatusCreated},
+		{name: "amount が無い", method: http.MethodPost, path: "/incomes", body: `{"source":"賞与","received_at":"2025-06-30"}`, wantStatus: http.StatusBadRequest},
+		{name: "使用中の家計簿が無い", method: http.MethodPost, path: "/incomes", body: body, err: services.ErrNoActiveLedger, wantStatus: http.StatusConflict},
+		{name: "viewer", method: http.MethodPost, path: "/incomes", body: body, err: &services.ForbiddenError{Message: "viewers cannot modify this ledger"}, wantStatus: http.StatusForbidden},
+		{name: "更新する", method: http.MethodPut, path: "/incomes/3", body: body, wantStatus: http.StatusOK},
+		{name: "確定済みを予定に戻す", method: http.MethodPut, path: "/incomes/3", body: body, err: services.ErrInvalidStatusTransition, wantStatus: http.StatusConflict},
+		{name: "不正な ID", method: http.MethodPut, path: "/incomes/0", body: body, wantStatus: http.StatusBadRequest},
+		{name: "削除する", method: http.MethodDelete, path: "/incomes/3", wantStatus: http.StatusNoContent},
+		{name: "削除する収入が無い", method: http.MethodDelete, path: "/incomes/3", err: &services.NotFoundError{Message: "income not found"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", method: http.MethodDelete, path: "/incomes/3", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewIncomeHandler(router, &incomeServiceMock{
+				CreateIncomeFunc: func(userID string, input models.IncomeInput) (models.Income, error) {
+					return models.Income{ID: 1, Source: input.Source}, tc.err
+				},
+				UpdateIncomeFunc: func(userID string, id int, input models.IncomeInput) (models.Income, error) {
+					require.Equal(t, 3, id)
+					return models.Income{ID: id, Source: input.Source}, tc.err
+				},
+				DeleteIncomeFunc: func(userID string, id int) error {
+					require.Equal(t, 3, id)
+					return tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestIncomeHandler_Summary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewIncomeHandler(router, &incomeServiceMock{
+		MonthlySummaryFunc: func(userID string, input models.IncomeSummaryInput) (models.IncomeSummary, error) {
+			require.Equal(t, "2025-06", input.Month)
+			return models.IncomeSummary{Month: "2025-06", Income: 700000, ReceivedIncome: 400000, PlannedIncome: 0, ExpectedIncome: 600000}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/incomes/summary?month=2025-06", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"month":"2025-06","income":700000,"received_income":400000,"planned_income":0,"expected_income":600000}`, w.Body.String())
+}
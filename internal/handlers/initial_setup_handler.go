@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"money-buddy-backend/internal/auth"
 	"money-buddy-backend/internal/models"
 	"money-buddy-backend/internal/services"
 )
@@ -20,9 +21,9 @@ type initialSetupRequest struct {
 	FixedCosts []models.FixedCostInput `json:"fixedCosts"`
 }
 
-func NewInitialSetupHandler(r *gin.Engine, service services.InitialSetupService) {
+func NewInitialSetupHandler(r gin.IRoutes, service services.InitialSetupService) {
 	h := &InitialSetupHandler{service: service}
-	r.POST("/setup", h.CompleteInitialSetup)
+	r.POST("/setup", RequireScope(auth.ScopeSetupWrite), h.CompleteInitialSetup)
 }
 
 func (h *InitialSetupHandler) CompleteInitialSetup(c *gin.Context) {
@@ -32,8 +33,10 @@ func (h *InitialSetupHandler) CompleteInitialSetup(c *gin.Context) {
 		return
 	}
 
-	// TODO: Extract userID from authentication context when auth is implemented
-	userID := DummyUserID
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
 	err := h.service.CompleteInitialSetup(c.Request.Context(), userID, req.Income, req.SavingGoal, req.FixedCosts)
 	if err != nil {
@@ -42,6 +45,11 @@ func (h *InitialSetupHandler) CompleteInitialSetup(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
 			return
 		}
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
 		var be *services.NotFoundError
 		if errors.As(err, &be) {
 			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": be.Message})
@@ -29,13 +29,13 @@ func (m *initialSetupServiceMock) CompleteInitialSetup(ctx context.Context, user
 
 func TestInitialSetupHandler_OK(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	called := false
 	svc := &initialSetupServiceMock{
 		CompleteInitialSetupFunc: func(userID string, income, savingGoal int, fixedCosts []models.FixedCostInput) error {
 			called = true
-			require.Equal(t, DummyUserID, userID)
+			require.Equal(t, testUserID, userID)
 			require.Equal(t, 300000, income)
 			require.Equal(t, 50000, savingGoal)
 			require.Equal(t, []models.FixedCostInput{{Name: "家賃", Amount: 80000}, {Name: "通信費", Amount: 5000}}, fixedCosts)
@@ -61,7 +61,7 @@ func TestInitialSetupHandler_OK(t *testing.T) {
 
 func TestInitialSetupHandler_InvalidJSON(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &initialSetupServiceMock{}
 	NewInitialSetupHandler(router, svc)
@@ -78,7 +78,7 @@ func TestInitialSetupHandler_InvalidJSON(t *testing.T) {
 
 func TestInitialSetupHandler_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &initialSetupServiceMock{
 		CompleteInitialSetupFunc: func(userID string, income, savingGoal int, fixedCosts []models.FixedCostInput) error {
@@ -102,7 +102,7 @@ func TestInitialSetupHandler_ValidationError(t *testing.T) {
 
 func TestInitialSetupHandler_BusinessError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &initialSetupServiceMock{
 		CompleteInitialSetupFunc: func(userID string, income, savingGoal int, fixedCosts []models.FixedCostInput) error {
@@ -126,7 +126,7 @@ func TestInitialSetupHandler_BusinessError(t *testing.T) {
 
 func TestInitialSetupHandler_InternalError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &initialSetupServiceMock{
 		CompleteInitialSetupFunc: func(userID string, income, savingGoal int, fixedCosts []models.FixedCostInput) error {
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type LedgerHandler struct {
+	service services.LedgerService
+}
+
+func NewLedgerHandler(r gin.IRoutes, service services.LedgerService) {
+	h := &LedgerHandler{service: service}
+	r.POST("/ledgers", RequireScope(auth.ScopeLedgersManage), h.CreateLedger)
+	r.GET("/ledgers", RequireScope(auth.ScopeLedgersManage), h.ListLedgers)
+	r.PUT("/ledgers/active", RequireScope(auth.ScopeLedgersManage), h.SwitchLedger)
+	r.POST("/ledgers/join", RequireScope(auth.ScopeLedgersManage), h.JoinLedger)
+	r.POST("/ledgers/:id/invites", RequireScope(auth.ScopeLedgersManage), h.CreateInvite)
+	r.DELETE("/ledgers/:id/membership", RequireScope(auth.ScopeLedgersManage), h.LeaveLedger)
+	r.GET("/ledgers/:id/members", RequireScope(auth.ScopeLedgersManage), h.ListMembers)
+	r.PUT("/ledgers/:id/members/:user_id", RequireScope(auth.ScopeLedgersManage), h.UpdateMemberRole)
+	r.PUT("/ledgers/:id/owner", RequireScope(auth.ScopeLedgersManage), h.TransferOwnership)
+}
+
+func (h *LedgerHandler) CreateLedger(c *gin.Context) {
+	var input models.CreateLedgerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.service.CreateLedger(c.Request.Context(), userID, input)
+	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ne.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"ledger": ledger})
+}
+
+func (h *LedgerHandler) ListLedgers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ledgers, err := h.service.ListLedgers(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list ledgers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ledgers": ledgers})
+}
+
+// SwitchLedger handles PUT /ledgers/active to change the ledger used for expenses and fixed costs.
+func (h *LedgerHandler) SwitchLedger(c *gin.Context) {
+	var input models.SwitchLedgerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.service.SwitchLedger(c.Request.Context(), userID, input)
+	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ledger": ledger})
+}
+
+func (h *LedgerHandler) JoinLedger(c *gin.Context) {
+	var input models.JoinLedgerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.service.JoinLedger(c.Request.Context(), userID, input)
+	if err != nil {
+		var ve *services.ValidationError
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+			return
+		}
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ledger": ledger})
+}
+
+func (h *LedgerHandler) CreateInvite(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ledger ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	invite, err := h.service.CreateInvite(c.Request.Context(), userID, int(id))
+	if err != nil {
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invite": invite})
+}
+
+// LeaveLedger handles DELETE /ledgers/:id/membership. The ledger is deleted when its last member leaves.
+func (h *LedgerHandler) LeaveLedger(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ledger ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.LeaveLedger(c.Request.Context(), userID, int(id)); err != nil {
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		var fe *services.ForbiddenError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LedgerHandler) ListMembers(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ledger ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), userID, int(id))
+	if err != nil {
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// UpdateMemberRole handles PUT /ledgers/:id/members/:user_id. Only the owner may change roles.
+func (h *LedgerHandler) UpdateMemberRole(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ledger ID"})
+		return
+	}
+
+	var input models.UpdateLedgerMemberRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(c.Request.Context(), userID, int(id), c.Param("user_id"), input)
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"member": member})
+}
+
+// TransferOwnership handles PUT /ledgers/:id/owner. The previous owner becomes an editor.
+func (h *LedgerHandler) TransferOwnership(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ledger ID"})
+		return
+	}
+
+	var input models.TransferLedgerOwnershipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.service.TransferOwnership(c.Request.Context(), userID, int(id), input)
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ledger": ledger})
+}
+
+// writeLedgerError maps service errors of member management to HTTP responses.
+func writeLedgerError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
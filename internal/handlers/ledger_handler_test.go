@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type ledgerServiceMock struct {
+	CreateLedgerFunc func(userID string, input models.CreateLedgerInput) (models.Ledger, error)
+	ListLedgersFunc  func(userID string) ([]models.Ledger, error)
+	CreateInviteFunc func(userID string, ledgerID int) (models.LedgerInvite, error)
+	JoinLedgerFunc   func(userID string, input models.JoinLedgerInput) (models.Ledger, error)
+	LeaveLedgerFunc  func(userID string, ledgerID int) error
+	SwitchLedgerFunc func(userID string, input models.SwitchLedgerInput) (models.Ledger, error)
+
+	ListMembersFunc       func(userID string, ledgerID int) ([]models.LedgerMember, error)
+	UpdateMemberRoleFunc  func(userID string, ledgerID int, memberID string, input models.UpdateLedgerMemberRoleInput) (models.LedgerMember, error)
+	TransferOwnershipFunc func(userID string, ledgerID int, input models.TransferLedgerOwnershipInput) (models.Ledger, error)
+}
+
+func (m *ledgerServiceMock) CreateLedger(ctx context.Context, userID string, input models.CreateLedgerInput) (models.Ledger, error) {
+	if m.CreateLedgerFunc != nil {
+		return m.CreateLedgerFunc(userID, input)
+	}
+	return models.Ledger{}, nil
+}
+
+func (m *ledgerServiceMock) ListLedgers(ctx context.Context, userID string) ([]models.Ledger, error) {
+	if m.ListLedgersFunc != nil {
+		return m.ListLedgersFunc(userID)
+	}
+	return []models.Ledger{}, nil
+}
+
+func (m *ledgerServiceMock) CreateInvite(ctx context.Context, userID string, ledgerID int) (models.LedgerInvite, error) {
+	if m.CreateInviteFunc != nil {
+		return m.CreateInviteFunc(userID, ledgerID)
+	}
+	return models.LedgerInvite{}, nil
+}
+
+func (m *ledgerServiceMock) JoinLedger(ctx context.Context, userID string, input models.JoinLedgerInput) (models.Ledger, error) {
+	if m.JoinLedgerFunc != nil {
+		return m.JoinLedgerFunc(userID, input)
+	}
+	return models.Ledger{}, nil
+}
+
+func (m *ledgerServiceMock) LeaveLedger(ctx context.Context, userID string, ledgerID int) error {
+	if m.LeaveLedgerFunc != nil {
+		return m.LeaveLedgerFunc(userID, ledgerID)
+	}
+	return nil
+}
+
+func (m *ledgerServiceMock) SwitchLedger(ctx context.Context, userID string, input models.SwitchLedgerInput) (models.Ledger, error) {
+	if m.SwitchLedgerFunc != nil {
+		return m.SwitchLedgerFunc(userID, input)
+	}
+	return models.Ledger{}, nil
+}
+
+func (m *ledgerServiceMock) ListMembers(ctx context.Context, userID string, ledgerID int) ([]models.LedgerMember, error) {
+	if m.ListMembersFunc != nil {
+		return m.ListMembersFunc(userID, ledgerID)
+	}
+	return []models.LedgerMember{}, nil
+}
+
+func (m *ledgerServiceMock) UpdateMemberRole(ctx context.Context, userID string, ledgerID int, memberID string, input models.UpdateLedgerMemberRoleInput) (models.LedgerMember, error) {
+	if m.UpdateMemberRoleFunc != nil {
+		return m.UpdateMemberRoleFunc(userID, ledgerID, memberID, input)
+	}
+	return models.LedgerMember{}, nil
+}
+
+func (m *ledgerServiceMock) TransferOwnership(ctx context.Context, userID string, ledgerID int, input models.TransferLedgerOwnershipInput) (models.Ledger, error) {
+	if m.TransferOwnershipFunc != nil {
+		return m.TransferOwnershipFunc(userID, ledgerID, input)
+	}
+	return models.Ledger{}, nil
+}
+
+func TestLedgerHandler_CreateLedger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	NewLedgerHandler(router, &ledgerServiceMock{
+		CreateLedgerFunc: func(userID string, input models.CreateLedgerInput) (models.Ledger, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, "家族", input.Name)
+			return models.Ledger{ID: 1, Name: input.Name, MemberCount: 1, Active: true}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ledgers", bytes.NewBufferString(`{"name":"家族"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]models.Ledger
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp["ledger"].ID)
+	require.True(t, resp["ledger"].Active)
+}
+
+func TestLedgerHandler_JoinLedger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "参加成功", err: nil, wantStatus: http.StatusOK},
+		{name: "参加済み", err: &services.ValidationError{Message: "already a member of this ledger"}, wantStatus: http.StatusBadRequest},
+		{name: "無効なコード", err: &services.NotFoundError{Message: "invite code is invalid or expired"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewLedgerHandler(router, &ledgerServiceMock{
+				JoinLedgerFunc: func(userID string, input models.JoinLedgerInput) (models.Ledger, error) {
+					require.Equal(t, "ABCD-EFGH-JKLM-NPQR", input.Code)
+					return models.Ledger{ID: 2}, tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/ledgers/join", bytes.NewBufferString(`{"code":"ABCD-EFGH-JKLM-NPQR"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestLedgerHandler_CreateInvite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("招待コードを発行する", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewLedgerHandler(router, &ledgerServiceMock{
+			CreateInviteFunc: func(userID string, ledgerID int) (models.LedgerInvite, error) {
+				require.Equal(t, 5, ledgerID)
+				return models.LedgerInvite{LedgerID: ledgerID, Code: "ABCD-EFGH-JKLM-NPQR"}, nil
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/ledgers/5/invites", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]models.LedgerInvite
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, "ABCD-EFGH-JKLM-NPQR", resp["invite"].Code)
+	})
+
+	t.Run("不正な ID", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewLedgerHandler(router, &ledgerServiceMock{})
+
+		req := httptest.NewRequest(http.MethodPost, "/ledgers/abc/invites", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("メンバーでない家計簿", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewLedgerHandler(router, &ledgerServiceMock{
+			CreateInviteFunc: func(userID string, ledgerID int) (models.LedgerInvite, error) {
+				return models.LedgerInvite{}, &services.NotFoundError{Message: "ledger not found"}
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/ledgers/9/invites", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestLedgerHandler_LeaveLedger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "退出成功", err: nil, wantStatus: http.StatusNoContent},
+		{name: "メンバーでない", err: &services.NotFoundError{Message: "ledger not found"}, wantStatus: http.StatusNotFound},
+		{name: "他のメンバーがいる owner", err: &services.ForbiddenError{Message: "transfer ownership before leaving the ledger"}, wantStatus: http.StatusForbidden},
+		{name: "その他のエラー", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewLedgerHandler(router, &ledgerServiceMock{
+				LeaveLedgerFunc: func(userID string, ledgerID int) error {
+					require.Equal(t, testUserID, userID)
+					require.Equal(t, 3, ledgerID)
+					return tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/ledgers/3/membership", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestLedgerHandler_SwitchLedger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	NewLedgerHandler(router, &ledgerServiceMock{
+		SwitchLedgerFunc: func(userID string, input models.SwitchLedgerInput) (models.Ledger, error) {
+			require.NotNil(t, input.LedgerID)
+			require.Equal(t, 4, *input.LedgerID)
+			return models.Ledger{ID: 4, Active: true}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/ledgers/active", bytes.NewBufferString(`{"ledger_id":4}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLedgerHandler_ListMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	NewLedgerHandler(router, &ledgerServiceMock{
+		ListMembersFunc: func(userID string, ledgerID int) ([]models.LedgerMember, error) {
+			require.Equal(t, 5, ledgerID)
+			return []models.LedgerMember{{UserID: "owner-1", Role: models.LedgerRoleOwner}, {UserID: testUserID, Role: models.LedgerRoleViewer}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ledgers/5/members", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string][]models.LedgerMember
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp["members"], 2)
+}
+
+func TestLedgerHandler_UpdateMemberRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "変更成功", err: nil, wantStatus: http.StatusOK},
+		{name: "不正なロール", err: &services.ValidationError{Message: "role must be 'editor' or 'viewer'"}, wantStatus: http.StatusBadRequest},
+		{name: "owner 以外", err: &services.ForbiddenError{Message: "only the ledger owner can do this"}, wantStatus: http.StatusForbidden},
+		{name: "メンバーでない", err: &services.NotFoundError{Message: "member not found"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewLedgerHandler(router, &ledgerServiceMock{
+				UpdateMemberRoleFunc: func(userID string, ledgerID int, memberID string, input models.UpdateLedgerMemberRoleInput) (models.LedgerMember, error) {
+					require.Equal(t, testUserID, userID)
+					require.Equal(t, 5, ledgerID)
+					require.Equal(t, "user-2", memberID)
+					require.Equal(t, "viewer", input.Role)
+					return models.LedgerMember{UserID: memberID, Role: input.Role}, tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/ledgers/5/members/user-2", bytes.NewBufferString(`{"role":"viewer"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestLedgerHandler_TransferOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "譲渡成功", err: nil, wantStatus: http.StatusOK},
+		{name: "owner 以外", err: &services.ForbiddenError{Message: "only the ledger owner can do this"}, wantStatus: http.StatusForbidden},
+		{name: "譲渡先がメンバーでない", err: &services.NotFoundError{Message: "member not found"}, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewLedgerHandler(router, &ledgerServiceMock{
+				TransferOwnershipFunc: func(userID string, ledgerID int, input models.TransferLedgerOwnershipInput) (models.Ledger, error) {
+					require.Equal(t, "user-2", input.UserID)
+					return models.Ledger{ID: ledgerID, Role: models.LedgerRoleEditor}, tc.err
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/ledgers/5/owner", bytes.NewBufferString(`{"user_id":"user-2"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type LocalAuthHandler struct {
+	service services.LocalAuthService
+}
+
+// NewLocalAuthHandler はローカル認証のルートを登録します。
+// サインアップ・ログイン・トークン交換・ログアウトは未認証の public に、パスワード変更は authed に登録します。
+func NewLocalAuthHandler(public gin.IRoutes, authed gin.IRoutes, service services.LocalAuthService) {
+	h := &LocalAuthHandler{service: service}
+	public.POST("/auth/signup", h.Signup)
+	public.POST("/auth/login", h.Login)
+	public.POST("/auth/refresh", h.Refresh)
+	public.POST("/auth/logout", h.Logout)
+	authed.PUT("/auth/password", RequireScope(auth.ScopePasswordChange), h.ChangePassword)
+}
+
+// Signup handles POST /auth/signup to create an account and sign in.
+func (h *LocalAuthHandler) Signup(c *gin.Context) {
+	var input models.SignupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.Signup(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": "email already registered"})
+			return
+		}
+		writeLocalAuthError(c, err, "")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tokens)
+}
+
+// Login handles POST /auth/login.
+func (h *LocalAuthHandler) Login(c *gin.Context) {
+	var input models.LoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.Login(c.Request.Context(), input)
+	if err != nil {
+		writeLocalAuthError(c, err, "invalid email or password")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh handles POST /auth/refresh to exchange a refresh token for a new token pair.
+func (h *LocalAuthHandler) Refresh(c *gin.Context) {
+	var input models.RefreshTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		writeLocalAuthError(c, err, "invalid refresh token")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout handles POST /auth/logout to revoke the refresh token and every token rotated from the same login.
+func (h *LocalAuthHandler) Logout(c *gin.Context) {
+	var input models.RefreshTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), input.RefreshToken); err != nil {
+		writeLocalAuthError(c, err, "")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword handles PUT /auth/password. Other sessions are signed out and the caller receives fresh tokens.
+func (h *LocalAuthHandler) ChangePassword(c *gin.Context) {
+	var input models.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.service.ChangePassword(c.Request.Context(), userID, input)
+	if err != nil {
+		writeLocalAuthError(c, err, "")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// writeLocalAuthError はサービスのエラーをステータスコードに変換します。
+// ErrInvalidCredentials には credentialsMessage を返します。
+func writeLocalAuthError(c *gin.Context, err error, credentialsMessage string) {
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": credentialsMessage})
+		return
+	}
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type localAuthServiceMock struct {
+	SignupFunc         func(input models.SignupInput) (models.AuthTokens, error)
+	LoginFunc          func(input models.LoginInput) (models.AuthTokens, error)
+	RefreshFunc        func(refreshToken string) (models.AuthTokens, error)
+	LogoutFunc         func(refreshToken string) error
+	ChangePasswordFunc func(userID string, input models.ChangePasswordInput) (models.AuthTokens, error)
+}
+
+func (m *localAuthServiceMock) Signup(ctx context.Context, input models.SignupInput) (models.AuthTokens, error) {
+	if m.SignupFunc != nil {
+		return m.SignupFunc(input)
+	}
+	return models.AuthTokens{}, nil
+}
+
+func (m *localAuthServiceMock) Login(ctx context.Context, input models.LoginInput) (models.AuthTokens, error) {
+	if m.LoginFunc != nil {
+		return m.LoginFunc(input)
+	}
+	return models.AuthTokens{}, nil
+}
+
+func (m *localAuthServiceMock) Refresh(ctx context.Context, refreshToken string) (models.AuthTokens, error) {
+	if m.RefreshFunc != nil {
+		return m.RefreshFunc(refreshToken)
+	}
+	return models.AuthTokens{}, nil
+}
+
+func (m *localAuthServiceMock) Logout(ctx context.Context, refreshToken string) error {
+	if m.LogoutFunc != nil {
+		return m.LogoutFunc(refreshToken)
+	}
+	return nil
+}
+
+func (m *localAuthServiceMock) ChangePassword(ctx context.Context, userID string, input models.ChangePasswordInput) (models.AuthTokens, error) {
+	if m.ChangePasswordFunc != nil {
+		return m.ChangePasswordFunc(userID, input)
+	}
+	return models.AuthTokens{}, nil
+}
+
+func localAuthRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLocalAuthHandler_Signup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "作成", wantStatus: http.StatusCreated},
+		{name: "登録済み", err: services.ErrEmailTaken, wantStatus: http.StatusConflict},
+		{name: "バリデーション", err: &services.ValidationError{Message: "email is invalid"}, wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			svc := &localAuthServiceMock{
+				SignupFunc: func(input models.SignupInput) (models.AuthTokens, error) {
+					require.Equal(t, "alice@example.com", input.Email)
+					require.Equal(t, "correct horse", input.Password)
+					if tc.err != nil {
+						return models.AuthTokens{}, tc.err
+					}
+					return models.AuthTokens{UserID: "local_1", AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}, nil
+				},
+			}
+			NewLocalAuthHandler(router, router, svc)
+
+			w := localAuthRequest(router, http.MethodPost, "/auth/signup", `{"email":"alice@example.com","password":"correct horse"}`)
+			require.Equal(t, tc.wantStatus, w.Code)
+			if tc.err == nil {
+				var resp models.AuthTokens
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "access", resp.AccessToken)
+				assert.Equal(t, "refresh", resp.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestLocalAuthHandler_InvalidCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	svc := &localAuthServiceMock{
+		LoginFunc: func(input models.LoginInput) (models.AuthTokens, error) {
+			return models.AuthTokens{}, services.ErrInvalidCredentials
+		},
+		RefreshFunc: func(refreshToken string) (models.AuthTokens, error) {
+			require.Equal(t, "stale", refreshToken)
+			return models.AuthTokens{}, services.ErrInvalidCredentials
+		},
+	}
+	NewLocalAuthHandler(router, router, svc)
+
+	w := localAuthRequest(router, http.MethodPost, "/auth/login", `{"email":"alice@example.com","password":"wrong"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"invalid email or password"}`, w.Body.String())
+
+	w = localAuthRequest(router, http.MethodPost, "/auth/refresh", `{"refresh_token":"stale"}`)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"invalid refresh token"}`, w.Body.String())
+}
+
+func TestLocalAuthHandler_Logout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var got string
+	svc := &localAuthServiceMock{
+		LogoutFunc: func(refreshToken string) error {
+			got = refreshToken
+			return nil
+		},
+	}
+	NewLocalAuthHandler(router, router, svc)
+
+	w := localAuthRequest(router, http.MethodPost, "/auth/logout", `{"refresh_token":"refresh"}`)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "refresh", got)
+}
+
+func TestLocalAuthHandler_ChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("変更", func(t *testing.T) {
+		router := newAuthedRouter()
+		svc := &localAuthServiceMock{
+			ChangePasswordFunc: func(userID string, input models.ChangePasswordInput) (models.AuthTokens, error) {
+				require.Equal(t, testUserID, userID)
+				require.Equal(t, "old password", input.CurrentPassword)
+				require.Equal(t, "new password", input.NewPassword)
+				return models.AuthTokens{UserID: userID, AccessToken: "access"}, nil
+			},
+		}
+		NewLocalAuthHandler(gin.New(), router, svc)
+
+		w := localAuthRequest(router, http.MethodPut, "/auth/password", `{"current_password":"old password","new_password":"new password"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("現在のパスワード違い", func(t *testing.T) {
+		router := newAuthedRouter()
+		svc := &localAuthServiceMock{
+			ChangePasswordFunc: func(userID string, input models.ChangePasswordInput) (models.AuthTokens, error) {
+				return models.AuthTokens{}, &services.ForbiddenError{Message: "current password is incorrect"}
+			},
+		}
+		NewLocalAuthHandler(gin.New(), router, svc)
+
+		w := localAuthRequest(router, http.MethodPut, "/auth/password", `{"current_password":"wrong","new_password":"new password"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("パーソナルアクセストークンでは変更できない", func(t *testing.T) {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Principal{
+				UserID: testUserID,
+				Scopes: auth.GrantableScopes,
+			}))
+			c.Next()
+		})
+		NewLocalAuthHandler(gin.New(), router, &localAuthServiceMock{})
+
+		w := localAuthRequest(router, http.MethodPut, "/auth/password", `{"current_password":"old password","new_password":"new password"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
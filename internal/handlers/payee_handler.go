@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type PayeeHandler struct {
+	service services.PayeeService
+}
+
+func NewPayeeHandler(r gin.IRoutes, service services.PayeeService) {
+	h := &PayeeHandler{service: service}
+	r.GET("/payees", RequireScope(auth.ScopeExpensesRead), h.ListPayees)
+	r.GET("/payees/totals", RequireScope(auth.ScopeExpensesRead), h.Totals)
+	r.POST("/payees", RequireScope(auth.ScopeExpensesWrite), h.CreatePayee)
+	r.PATCH("/payees/:id", RequireScope(auth.ScopeExpensesWrite), h.RenamePayee)
+	r.DELETE("/payees/:id", RequireScope(auth.ScopeExpensesWrite), h.DeletePayee)
+	r.POST("/payees/:id/merge", RequireScope(auth.ScopeExpensesWrite), h.MergePayee)
+}
+
+// ListPayees handles GET /payees. It returns the caller's payees whose names start with
+// the prefix query parameter, most frequently and recently used first.
+func (h *PayeeHandler) ListPayees(c *gin.Context) {
+	var input models.ListPayeesInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	payees, err := h.service.ListPayees(c.Request.Context(), userID, input)
+	if err != nil {
+		writePayeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payees": payees})
+}
+
+// CreatePayee handles POST /payees.
+func (h *PayeeHandler) CreatePayee(c *gin.Context) {
+	var input models.PayeeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	payee, err := h.service.CreatePayee(c.Request.Context(), userID, input)
+	if err != nil {
+		writePayeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"payee": payee})
+}
+
+// RenamePayee handles PATCH /payees/:id. Every expense with the payee shows the new name.
+func (h *PayeeHandler) RenamePayee(c *gin.Context) {
+	id, ok := payeeIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.PayeeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	payee, err := h.service.RenamePayee(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writePayeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payee": payee})
+}
+
+// DeletePayee handles DELETE /payees/:id. The payee is removed from every expense carrying it.
+func (h *PayeeHandler) DeletePayee(c *gin.Context) {
+	id, ok := payeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePayee(c.Request.Context(), userID, id); err != nil {
+		writePayeeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergePayee handles POST /payees/:id/merge. Expenses with the payee get the "into" payee
+// instead, and the payee itself is deleted.
+func (h *PayeeHandler) MergePayee(c *gin.Context) {
+	id, ok := payeeIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.MergePayeeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	payee, err := h.service.MergePayee(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writePayeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payee": payee})
+}
+
+// Totals handles GET /payees/totals. It returns per-payee totals for the active ledger.
+func (h *PayeeHandler) Totals(c *gin.Context) {
+	var input models.PayeeTotalsInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	totals, err := h.service.Totals(c.Request.Context(), userID, input)
+	if err != nil {
+		writePayeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"totals": totals})
+}
+
+func payeeIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payee ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writePayeeError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	if errors.Is(err, services.ErrPayeeNameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "a payee with this name already exists"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
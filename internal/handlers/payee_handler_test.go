@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type payeeServiceMock struct {
+	ListPayeesFunc  func(userID string, input models.ListPayeesInput) ([]models.PayeeSuggestion, error)
+	CreatePayeeFunc func(userID string, input models.PayeeInput) (models.Payee, error)
+	RenamePayeeFunc func(userID string, id int, input models.PayeeInput) (models.Payee, error)
+	DeletePayeeFunc func(userID string, id int) error
+	MergePayeeFunc  func(userID string, id int, input models.MergePayeeInput) (models.Payee, error)
+	TotalsFunc      func(userID string, input models.PayeeTotalsInput) ([]models.PayeeTotal, error)
+}
+
+func (m *payeeServiceMock) ListPayees(ctx context.Context, userID string, input models.ListPayeesInput) ([]models.PayeeSuggestion, error) {
+	if m.ListPayeesFunc != nil {
+		return m.ListPayeesFunc(userID, input)
+	}
+	return []models.PayeeSuggestion{}, nil
+}
+
+func (m *payeeServiceMock) CreatePayee(ctx context.Context, userID string, input models.PayeeInput) (models.Payee, error) {
+	if m.CreatePayeeFunc != nil {
+		return m.CreatePayeeFunc(userID, input)
+	}
+	return models.Payee{}, nil
+}
+
+func (m *payeeServiceMock) RenamePayee(ctx context.Context, userID string, id int, input models.PayeeInput) (models.Payee, error) {
+	if m.RenamePayeeFunc != nil {
+		return m.RenamePayeeFunc(userID, id, input)
+	}
+	return models.Payee{}, nil
+}
+
+func (m *payeeServiceMock) DeletePayee(ctx context.Context, userID string, id int) error {
+	if m.DeletePayeeFunc != nil {
+		return m.DeletePayeeFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *payeeServiceMock) MergePayee(ctx context.Context, userID string, id int, input models.MergePayeeInput) (models.Payee, error) {
+	if m.MergePayeeFunc != nil {
+		return m.MergePayeeFunc(userID, id, input)
+	}
+	return models.Payee{}, nil
+}
+
+func (m *payeeServiceMock) Totals(ctx context.Context, userID string, input models.PayeeTotalsInput) ([]models.PayeeTotal, error) {
+	if m.TotalsFunc != nil {
+		return m.TotalsFunc(userID, input)
+	}
+	return []models.PayeeTotal{}, nil
+}
+
+func TestPayeeHandler_ListPayees(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("候補を返す", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewPayeeHandler(router, &payeeServiceMock{
+			ListPayeesFunc: func(userID string, input models.ListPayeesInput) ([]models.PayeeSuggestion, error) {
+				require.Equal(t, testUserID, userID)
+				require.Equal(t, "コン", input.Prefix)
+				require.Equal(t, 5, *input.Limit)
+				last := "2025-05-01"
+				return []models.PayeeSuggestion{{Payee: models.Payee{ID: 1, Name: "コンビニ"}, UseCount: 3, LastUsedAt: &last}}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/payees?prefix=%E3%82%B3%E3%83%B3&limit=5", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, `{"payees":[{"id":1,"name":"コンビニ","use_count":3,"last_used_at":"2025-05-01"}]}`, w.Body.String())
+	})
+
+	t.Run("limit が数値でない", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewPayeeHandler(router, &payeeServiceMock{})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/payees?limit=abc", nil))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPayeeHandler_CreatePayee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "作成する", body: `{"name":"コンビニ"}`, wantStatus: http.StatusCreated},
+		{name: "name が無い", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "同じ名前の支払先がある", body: `{"name":"コンビニ"}`, err: services.ErrPayeeNameTaken, wantStatus: http.StatusConflict},
+		{name: "その他のエラー", body: `{"name":"コンビニ"}`, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewPayeeHandler(router, &payeeServiceMock{
+				CreatePayeeFunc: func(userID string, input models.PayeeInput) (models.Payee, error) {
+					return models.Payee{ID: 1, Name: input.Name}, tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/payees", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestPayeeHandler_RenameDeleteMerge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "名前を変える", method: http.MethodPatch, path: "/payees/3", body: `{"name":"コンビニ"}`, wantStatus: http.StatusOK},
+		{name: "変更で名前が重なる", method: http.MethodPatch, path: "/payees/3", body: `{"name":"コンビニ"}`, err: services.ErrPayeeNameTaken, wantStatus: http.StatusConflict},
+		{name: "不正な ID", method: http.MethodPatch, path: "/payees/0", body: `{"name":"コンビニ"}`, wantStatus: http.StatusBadRequest},
+		{name: "削除する", method: http.MethodDelete, path: "/payees/3", wantStatus: http.StatusNoContent},
+		{name: "削除する支払先が無い", method: http.MethodDelete, path: "/payees/3", err: &services.NotFoundError{Message: "payee not found"}, wantStatus: http.StatusNotFound},
+		{name: "統合する", method: http.MethodPost, path: "/payees/3/merge", body: `{"into":4}`, wantStatus: http.StatusOK},
+		{name: "into が無い", method: http.MethodPost, path: "/payees/3/merge", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewPayeeHandler(router, &payeeServiceMock{
+				RenamePayeeFunc: func(userID string, id int, input models.PayeeInput) (models.Payee, error) {
+					require.Equal(t, 3, id)
+					return models.Payee{ID: id, Name: input.Name}, tc.err
+				},
+				DeletePayeeFunc: func(userID string, id int) error {
+					require.Equal(t, 3, id)
+					return tc.err
+				},
+				MergePayeeFunc: func(userID string, id int, input models.MergePayeeInput) (models.Payee, error) {
+					require.Equal(t, 3, id)
+					require.Equal(t, 4, *input.Into)
+					return models.Payee{ID: 4, Name: "コンビニ"}, tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestPayeeHandler_Totals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewPayeeHandler(router, &payeeServiceMock{
+		TotalsFunc: func(userID string, input models.PayeeTotalsInput) ([]models.PayeeTotal, error) {
+			require.Equal(t, models.PayeeTotalsInput{From: "2025-01-01", To: "2025-12-31"}, input)
+			return []models.PayeeTotal{{Payee: models.Payee{ID: 1, Name: "コンビニ"}, ExpenseCount: 40, ConfirmedTotal: 32000}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/payees/totals?from=2025-01-01&to=2025-12-31", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"totals":[{"payee":{"id":1,"name":"コンビニ"},"expense_count":40,"confirmed_total":32000,"planned_total":0}]}`, w.Body.String())
+}
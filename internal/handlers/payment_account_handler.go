@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type PaymentAccountHandler struct {
+	service services.PaymentAccountService
+}
+
+func NewPaymentAccountHandler(r gin.IRoutes, service services.PaymentAccountService) {
+	h := &PaymentAccountHandler{service: service}
+	r.GET("/payment-accounts", RequireScope(auth.ScopeExpensesRead), h.ListPaymentAccounts)
+	r.GET("/payment-accounts/:id/entries", RequireScope(auth.ScopeExpensesRead), h.ListEntries)
+	r.POST("/payment-accounts", RequireScope(auth.ScopeExpensesWrite), h.CreatePaymentAccount)
+	r.PATCH("/payment-accounts/:id", RequireScope(auth.ScopeExpensesWrite), h.UpdatePaymentAccount)
+	r.DELETE("/payment-accounts/:id", RequireScope(auth.ScopeExpensesWrite), h.DeletePaymentAccount)
+}
+
+// ListPaymentAccounts handles GET /payment-accounts. It returns the caller's payment accounts
+// ordered by name, each with its current balance.
+func (h *PaymentAccountHandler) ListPaymentAccounts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.service.ListPaymentAccounts(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment_accounts": accounts})
+}
+
+// CreatePaymentAccount handles POST /payment-accounts.
+func (h *PaymentAccountHandler) CreatePaymentAccount(c *gin.Context) {
+	var input models.CreatePaymentAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.CreatePaymentAccount(c.Request.Context(), userID, input)
+	if err != nil {
+		writePaymentAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"payment_account": account})
+}
+
+// UpdatePaymentAccount handles PATCH /payment-accounts/:id. Only the fields present in the
+// body are changed.
+func (h *PaymentAccountHandler) UpdatePaymentAccount(c *gin.Context) {
+	id, ok := paymentAccountIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.UpdatePaymentAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.UpdatePaymentAccount(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writePaymentAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment_account": account})
+}
+
+// DeletePaymentAccount handles DELETE /payment-accounts/:id. The account is removed from
+// every expense paid with it.
+func (h *PaymentAccountHandler) DeletePaymentAccount(c *gin.Context) {
+	id, ok := paymentAccountIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePaymentAccount(c.Request.Context(), userID, id); err != nil {
+		writePaymentAccountError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListEntries handles GET /payment-accounts/:id/entries. It returns the confirmed expenses
+// paid with the account in date order, with the running balance after each one.
+func (h *PaymentAccountHandler) ListEntries(c *gin.Context) {
+	id, ok := paymentAccountIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.PaymentAccountEntriesInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.ListEntries(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writePaymentAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+func paymentAccountIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment account ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writePaymentAccountError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	if errors.Is(err, services.ErrPaymentAccountNameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "a payment account with this name already exists"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type paymentAccountServiceMock struct {
+	ListPaymentAccountsFunc  func(userID string) ([]models.PaymentAccount, error)
+	CreatePaymentAccountFunc func(userID string, input models.CreatePaymentAccountInput) (models.PaymentAccount, error)
+	UpdatePaymentAccountFunc func(userID string, id int, input models.UpdatePaymentAccountInput) (models.PaymentAccount, error)
+	DeletePaymentAccountFunc func(userID string, id int) error
+	ListEntriesFunc          func(userID string, id int, input models.PaymentAccountEntriesInput) ([]models.PaymentAccountEntry, error)
+}
+
+func (m *paymentAccountServiceMock) ListPaymentAccounts(ctx context.Context, userID string) ([]models.PaymentAccount, error) {
+	if m.ListPaymentAccountsFunc != nil {
+		return m.ListPaymentAccountsFunc(userID)
+	}
+	return []models.PaymentAccount{}, nil
+}
+
+func (m *paymentAccountServiceMock) CreatePaymentAccount(ctx context.Context, userID string, input models.CreatePaymentAccountInput) (models.PaymentAccount, error) {
+	if m.CreatePaymentAccountFunc != nil {
+		return m.CreatePaymentAccountFunc(userID, input)
+	}
+	return models.PaymentAccount{}, nil
+}
+
+func (m *paymentAccountServiceMock) UpdatePaymentAccount(ctx context.Context, userID string, id int, input models.UpdatePaymentAccountInput) (models.PaymentAccount, error) {
+	if m.UpdatePaymentAccountFunc != nil {
+		return m.UpdatePaymentAccountFunc(userID, id, input)
+	}
+	return models.PaymentAccount{}, nil
+}
+
+func (m *paymentAccountServiceMock) DeletePaymentAccount(ctx context.Context, userID string, id int) error {
+	if m.DeletePaymentAccountFunc != nil {
+		return m.DeletePaymentAccountFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *paymentAccountServiceMock) ListEntries(ctx context.Context, userID string, id int, input models.PaymentAccountEntriesInput) ([]models.PaymentAccountEntry, error) {
+	if m.ListEntriesFunc != nil {
+		return m.ListEntriesFunc(userID, id, input)
+	}
+	return []models.PaymentAccountEntry{}, nil
+}
+
+func TestPaymentAccountHandler_ListPaymentAccounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewPaymentAccountHandler(router, &paymentAccountServiceMock{
+		ListPaymentAccountsFunc: func(userID string) ([]models.PaymentAccount, error) {
+			require.Equal(t, testUserID, userID)
+			return []models.PaymentAccount{{ID: 1, Name: "財布", Type: "cash", OpeningBalance: 5000, Balance: 4200}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/payment-accounts", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"payment_accounts":[{"id":1,"name":"財布","type":"cash","opening_balance":5000,"balance":4200}]}`, w.Body.String())
+}
+
+func TestPaymentAccountHandler_CreateUpdateDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "作成する", method: http.MethodPost, path: "/payment-accounts", body: `{"name":"財布","type":"cash"}`, wantStatus: http.StatusCreated},
+		{name: "type が無い", method: http.MethodPost, path: "/payment-accounts", body: `{"name":"財布"}`, wantStatus: http.StatusBadRequest},
+		{name: "同じ名前の支払い手段がある", method: http.MethodPost, path: "/payment-accounts", body: `{"name":"財布","type":"cash"}`, err: services.ErrPaymentAccountNameTaken, wantStatus: http.StatusConflict},
+		{name: "変更する", method: http.MethodPatch, path: "/payment-accounts/3", body: `{"opening_balance":1000}`, wantStatus: http.StatusOK},
+		{name: "変更で検証に失敗する", method: http.MethodPatch, path: "/payment-accounts/3", body: `{"type":"bitcoin"}`, err: &services.ValidationError{Message: "type is invalid"}, wantStatus: http.StatusBadRequest},
+		{name: "不正な ID", method: http.MethodPatch, path: "/payment-accounts/abc", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "削除する", method: http.MethodDelete, path: "/payment-accounts/3", wantStatus: http.StatusNoContent},
+		{name: "削除する支払い手段が無い", method: http.MethodDelete, path: "/payment-accounts/3", err: &services.NotFoundError{Message: "payment account not found"}, wantStatus: http.StatusNotFound},
+		{name: "その他のエラー", method: http.MethodDelete, path: "/payment-accounts/3", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewPaymentAccountHandler(router, &paymentAccountServiceMock{
+				CreatePaymentAccountFunc: func(userID string, input models.CreatePaymentAccountInput) (models.PaymentAccount, error) {
+					return models.PaymentAccount{ID: 1, Name: input.Name, Type: input.Type}, tc.err
+				},
+				UpdatePaymentAccountFunc: func(userID string, id int, input models.UpdatePaymentAccountInput) (models.PaymentAccount, error) {
+					require.Equal(t, 3, id)
+					return models.PaymentAccount{ID: id}, tc.err
+				},
+				DeletePaymentAccountFunc: func(userID string, id int) error {
+					require.Equal(t, 3, id)
+					return tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestPaymentAccountHandler_ListEntries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewPaymentAccountHandler(router, &paymentAccountServiceMock{
+		ListEntriesFunc: func(userID string, id int, input models.PaymentAccountEntriesInput) ([]models.PaymentAccountEntry, error) {
+			require.Equal(t, 3, id)
+			require.Equal(t, models.PaymentAccountEntriesInput{From: "2025-06-01"}, input)
+			return []models.PaymentAccountEntry{{ExpenseID: 9, SpentAt: "2025-06-02", Amount: 800, Memo: "昼食", Balance: 4200}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/payment-accounts/3/entries?from=2025-06-01", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"entries":[{"expense_id":9,"spent_at":"2025-06-02","amount":800,"memo":"昼食","balance":4200}]}`, w.Body.String())
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type tokenServiceMock struct {
+	CreateTokenFunc  func(userID string, input models.CreatePersonalAccessTokenInput) (models.CreatedPersonalAccessToken, error)
+	ListTokensFunc   func(userID string) ([]models.PersonalAccessToken, error)
+	RevokeTokenFunc  func(userID string, id int) error
+	AuthenticateFunc func(raw string) (auth.Principal, error)
+}
+
+func (m *tokenServiceMock) CreateToken(ctx context.Context, userID string, input models.CreatePersonalAccessTokenInput) (models.CreatedPersonalAccessToken, error) {
+	if m.CreateTokenFunc != nil {
+		return m.CreateTokenFunc(userID, input)
+	}
+	return models.CreatedPersonalAccessToken{}, nil
+}
+
+func (m *tokenServiceMock) ListTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	if m.ListTokensFunc != nil {
+		return m.ListTokensFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *tokenServiceMock) RevokeToken(ctx context.Context, userID string, id int) error {
+	if m.RevokeTokenFunc != nil {
+		return m.RevokeTokenFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *tokenServiceMock) Authenticate(ctx context.Context, raw string) (auth.Principal, error) {
+	if m.AuthenticateFunc != nil {
+		return m.AuthenticateFunc(raw)
+	}
+	return auth.Principal{}, auth.ErrInvalidToken
+}
+
+func TestPersonalAccessTokenHandler_Create(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	svc := &tokenServiceMock{
+		CreateTokenFunc: func(userID string, input models.CreatePersonalAccessTokenInput) (models.CreatedPersonalAccessToken, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, "import script", input.Name)
+			require.Equal(t, []string{"expenses:write"}, input.Scopes)
+			return models.CreatedPersonalAccessToken{
+				PersonalAccessToken: models.PersonalAccessToken{ID: 3, Name: input.Name, Scopes: input.Scopes},
+				Token:               "mbp_secret",
+			}, nil
+		},
+	}
+	NewPersonalAccessTokenHandler(router, svc)
+
+	body := `{"name":"import script","scopes":["expenses:write"]}`
+	req := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]models.CreatedPersonalAccessToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "mbp_secret", resp["token"].Token)
+	require.Equal(t, 3, resp["token"].ID)
+}
+
+func TestPersonalAccessTokenHandler_Create_ValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newAuthedRouter()
+
+	svc := &tokenServiceMock{
+		CreateTokenFunc: func(userID string, input models.CreatePersonalAccessTokenInput) (models.CreatedPersonalAccessToken, error) {
+			return models.CreatedPersonalAccessToken{}, &services.ValidationError{Message: "scope is invalid: admin"}
+		},
+	}
+	NewPersonalAccessTokenHandler(router, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(`{"name":"x","scopes":["admin"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPersonalAccessTokenHandler_TokensCannotManageTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		p := auth.Principal{UserID: testUserID, Scopes: auth.GrantableScopes, TokenID: 1}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		c.Next()
+	})
+	NewPersonalAccessTokenHandler(router, &tokenServiceMock{})
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPersonalAccessTokenHandler_Revoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "失効できた場合は204", path: "/tokens/3", wantStatus: http.StatusNoContent},
+		{name: "存在しない場合は404", path: "/tokens/3", err: &services.NotFoundError{Message: "token not found"}, wantStatus: http.StatusNotFound},
+		{name: "IDが数値でない場合は400", path: "/tokens/abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			svc := &tokenServiceMock{
+				RevokeTokenFunc: func(userID string, id int) error {
+					require.Equal(t, 3, id)
+					return tc.err
+				},
+			}
+			NewPersonalAccessTokenHandler(router, svc)
+
+			req := httptest.NewRequest(http.MethodDelete, tc.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type RefundHandler struct {
+	service services.RefundService
+}
+
+func NewRefundHandler(r gin.IRoutes, service services.RefundService) {
+	h := &RefundHandler{service: service}
+	r.GET("/expenses/:id/refunds", RequireScope(auth.ScopeExpensesRead), h.ListRefunds)
+	r.POST("/expenses/:id/refunds", RequireScope(auth.ScopeExpensesWrite), h.CreateRefund)
+	r.DELETE("/expenses/:id/refunds/:refund_id", RequireScope(auth.ScopeExpensesWrite), h.DeleteRefund)
+}
+
+// ListRefunds handles GET /expenses/:id/refunds. Refunds are returned in order of refund date.
+func (h *RefundHandler) ListRefunds(c *gin.Context) {
+	expenseID, ok := refundExpenseIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	refunds, err := h.service.ListRefunds(c.Request.Context(), userID, expenseID)
+	if err != nil {
+		writeRefundError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"refunds": refunds})
+}
+
+// CreateRefund handles POST /expenses/:id/refunds. Omitting the amount refunds whatever has not
+// been refunded yet; the refunds of an expense never exceed its amount.
+func (h *RefundHandler) CreateRefund(c *gin.Context) {
+	expenseID, ok := refundExpenseIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.ExpenseRefundInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	refund, err := h.service.CreateRefund(c.Request.Context(), userID, expenseID, input)
+	if err != nil {
+		writeRefundError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"refund": refund})
+}
+
+// DeleteRefund handles DELETE /expenses/:id/refunds/:refund_id.
+func (h *RefundHandler) DeleteRefund(c *gin.Context) {
+	expenseID, ok := refundExpenseIDParam(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("refund_id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid refund ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRefund(c.Request.Context(), userID, expenseID, id); err != nil {
+		writeRefundError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func refundExpenseIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeRefundError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	var fe *services.ForbiddenError
+	if errors.As(err, &fe) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fe.Message})
+		return
+	}
+	if errors.Is(err, services.ErrNoActiveLedger) {
+		c.JSON(http.StatusConflict, gin.H{"error": "no active ledger"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type refundServiceMock struct {
+	ListRefundsFunc  func(userID string, expenseID int) ([]models.ExpenseRefund, error)
+	CreateRefundFunc func(userID string, expenseID int, input models.ExpenseRefundInput) (models.ExpenseRefund, error)
+	DeleteRefundFunc func(userID string, expenseID int, id int) error
+}
+
+func (m *refundServiceMock) ListRefunds(ctx context.Context, userID string, expenseID int) ([]models.ExpenseRefund, error) {
+	if m.ListRefundsFunc != nil {
+		return m.ListRefundsFunc(userID, expenseID)
+	}
+	return []models.ExpenseRefund{}, nil
+}
+
+func (m *refundServiceMock) CreateRefund(ctx context.Context, userID string, expenseID int, input models.ExpenseRefundInput) (models.ExpenseRefund, error) {
+	if m.CreateRefundFunc != nil {
+		return m.CreateRefundFunc(userID, expenseID, input)
+	}
+	return models.ExpenseRefund{}, nil
+}
+
+func (m *refundServiceMock) DeleteRefund(ctx context.Context, userID string, expenseID int, id int) error {
+	if m.DeleteRefundFunc != nil {
+		return m.DeleteRefundFunc(userID, expenseID, id)
+	}
+	return nil
+}
+
+func TestRefundHandler_ListRefunds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewRefundHandler(router, &refundServiceMock{
+		ListRefundsFunc: func(userID string, expenseID int) ([]models.ExpenseRefund, error) {
+			require.Equal(t, testUserID, userID)
+			require.Equal(t, 5, expenseID)
+			return []models.ExpenseRefund{{ID: 1, ExpenseID: 5, Amount: 1200, RefundedAt: "2025-06-03", Memo: "返品", CreatedAt: "2025-06-03T10:00:00Z"}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/expenses/5/refunds", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"refunds":[{"id":1,"expense_id":5,"amount":1200,"refunded_at":"2025-06-03","memo":"返品","created_at":"2025-06-03T10:00:00Z"}]}`, w.Body.String())
+}
+
+func TestRefundHandler_CreateAndDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `{"amount":1200,"refunded_at":"2025-06-03"}`
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "返金を記録する", method: http.MethodPost, path: "/expenses/5/refunds", body: body, wantStatus: http.StatusCreated},
+		{name: "金額を省略する", method: http.MethodPost, path: "/expenses/5/refunds", body: `{"refunded_at":"2025-06-03"}`, wantStatus: http.StatusCreated},
+		{name: "refunded_at が無い", method: http.MethodPost, path: "/expenses/5/refunds", body: `{"amount":1200}`, wantStatus: http.StatusBadRequest},
+		{name: "支出の金額を超える", method: http.MethodPost, path: "/expenses/5/refunds", body: body, err: &services.ValidationError{Message: "amount exceeds the refundable amount (500)"}, wantStatus: http.StatusBadRequest},
+		{name: "支出が無い", method: http.MethodPost, path: "/expenses/5/refunds", body: body, err: &services.NotFoundError{Message: "expense not found"}, wantStatus: http.StatusNotFound},
+		{name: "閲覧者", method: http.MethodPost, path: "/expenses/5/refunds", body: body, err: &services.ForbiddenError{Message: "viewers cannot modify this ledger"}, wantStatus: http.StatusForbidden},
+		{name: "使用中の家計簿が無い", method: http.MethodPost, path: "/expenses/5/refunds", body: body, err: services.ErrNoActiveLedger, wantStatus: http.StatusConflict},
+		{name: "不正な支出 ID", method: http.MethodPost, path: "/expenses/x/refunds", body: body, wantStatus: http.StatusBadRequest},
+		{name: "返金を取り消す", method: http.MethodDelete, path: "/expenses/5/refunds/2", wantStatus: http.StatusNoContent},
+		{name: "取り消す返金が無い", method: http.MethodDelete, path: "/expenses/5/refunds/2", err: &services.NotFoundError{Message: "refund not found"}, wantStatus: http.StatusNotFound},
+		{name: "不正な返金 ID", method: http.MethodDelete, path: "/expenses/5/refunds/0", wantStatus: http.StatusBadRequest},
+		{name: "その他のエラー", method: http.MethodDelete, path: "/expenses/5/refunds/2", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewRefundHandler(router, &refundServiceMock{
+				CreateRefundFunc: func(userID string, expenseID int, input models.ExpenseRefundInput) (models.ExpenseRefund, error) {
+					require.Equal(t, 5, expenseID)
+					return models.ExpenseRefund{ID: 1, ExpenseID: expenseID}, tc.err
+				},
+				DeleteRefundFunc: func(userID string, expenseID int, id int) error {
+					require.Equal(t, 5, expenseID)
+					require.Equal(t, 2, id)
+					return tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type TagHandler struct {
+	service services.TagService
+}
+
+func NewTagHandler(r gin.IRoutes, service services.TagService) {
+	h := &TagHandler{service: service}
+	r.GET("/tags", RequireScope(auth.ScopeExpensesRead), h.ListTags)
+	r.GET("/tags/report", RequireScope(auth.ScopeExpensesRead), h.Report)
+	r.POST("/tags", RequireScope(auth.ScopeExpensesWrite), h.CreateTag)
+	r.PATCH("/tags/:id", RequireScope(auth.ScopeExpensesWrite), h.RenameTag)
+	r.DELETE("/tags/:id", RequireScope(auth.ScopeExpensesWrite), h.DeleteTag)
+	r.POST("/tags/:id/merge", RequireScope(auth.ScopeExpensesWrite), h.MergeTag)
+}
+
+// ListTags handles GET /tags. It returns the caller's tags ordered by name.
+func (h *TagHandler) ListTags(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.service.ListTags(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// CreateTag handles POST /tags.
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var input models.TagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.service.CreateTag(c.Request.Context(), userID, input)
+	if err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"tag": tag})
+}
+
+// RenameTag handles PATCH /tags/:id. Every expense carrying the tag shows the new name.
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.TagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.service.RenameTag(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tag": tag})
+}
+
+// DeleteTag handles DELETE /tags/:id. The tag is removed from every expense carrying it.
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTag(c.Request.Context(), userID, id); err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergeTag handles POST /tags/:id/merge. Expenses carrying the tag get the "into" tag
+// instead, and the tag itself is deleted.
+func (h *TagHandler) MergeTag(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+	var input models.MergeTagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.service.MergeTag(c.Request.Context(), userID, id, input)
+	if err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tag": tag})
+}
+
+// Report handles GET /tags/report. It returns per-tag totals by month for the active ledger.
+func (h *TagHandler) Report(c *gin.Context) {
+	var input models.TagReportInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	totals, err := h.service.Report(c.Request.Context(), userID, input)
+	if err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"totals": totals})
+}
+
+func tagIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeTagError(c *gin.Context, err error) {
+	var ve *services.ValidationError
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ve.Message})
+		return
+	}
+	var ne *services.NotFoundError
+	if errors.As(err, &ne) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ne.Message})
+		return
+	}
+	if errors.Is(err, services.ErrTagNameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "a tag with this name already exists"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
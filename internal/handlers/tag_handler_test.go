@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
+)
+
+type tagServiceMock struct {
+	ListTagsFunc  func(userID string) ([]models.Tag, error)
+	CreateTagFunc func(userID string, input models.TagInput) (models.Tag, error)
+	RenameTagFunc func(userID string, id int, input models.TagInput) (models.Tag, error)
+	DeleteTagFunc func(userID string, id int) error
+	MergeTagFunc  func(userID string, id int, input models.MergeTagInput) (models.Tag, error)
+	ReportFunc    func(userID string, input models.TagReportInput) ([]models.TagMonthlyTotal, error)
+}
+
+func (m *tagServiceMock) ListTags(ctx context.Context, userID string) ([]models.Tag, error) {
+	if m.ListTagsFunc != nil {
+		return m.ListTagsFunc(userID)
+	}
+	return []models.Tag{}, nil
+}
+
+func (m *tagServiceMock) CreateTag(ctx context.Context, userID string, input models.TagInput) (models.Tag, error) {
+	if m.CreateTagFunc != nil {
+		return m.CreateTagFunc(userID, input)
+	}
+	return models.Tag{}, nil
+}
+
+func (m *tagServiceMock) RenameTag(ctx context.Context, userID string, id int, input models.TagInput) (models.Tag, error) {
+	if m.RenameTagFunc != nil {
+		return m.RenameTagFunc(userID, id, input)
+	}
+	return models.Tag{}, nil
+}
+
+func (m *tagServiceMock) DeleteTag(ctx context.Context, userID string, id int) error {
+	if m.DeleteTagFunc != nil {
+		return m.DeleteTagFunc(userID, id)
+	}
+	return nil
+}
+
+func (m *tagServiceMock) MergeTag(ctx context.Context, userID string, id int, input models.MergeTagInput) (models.Tag, error) {
+	if m.MergeTagFunc != nil {
+		return m.MergeTagFunc(userID, id, input)
+	}
+	return models.Tag{}, nil
+}
+
+func (m *tagServiceMock) Report(ctx context.Context, userID string, input models.TagReportInput) ([]models.TagMonthlyTotal, error) {
+	if m.ReportFunc != nil {
+		return m.ReportFunc(userID, input)
+	}
+	return []models.TagMonthlyTotal{}, nil
+}
+
+func TestTagHandler_ListTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewTagHandler(router, &tagServiceMock{
+		ListTagsFunc: func(userID string) ([]models.Tag, error) {
+			require.Equal(t, testUserID, userID)
+			return []models.Tag{{ID: 1, Name: "旅行"}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tags", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"tags":[{"id":1,"name":"旅行"}]}`, w.Body.String())
+}
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("作成したタグを返す", func(t *testing.T) {
+		router := newAuthedRouter()
+		NewTagHandler(router, &tagServiceMock{
+			CreateTagFunc: func(userID string, input models.TagInput) (models.Tag, error) {
+				require.Equal(t, "旅行", input.Name)
+				return models.Tag{ID: 2, Name: input.Name}, nil
+			},
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/tags", strings.NewReader(`{"name":"旅行"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.JSONEq(t, `{"tag":{"id":2,"name":"旅行"}}`, w.Body.String())
+	})
+
+	cases := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "name が無い", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "入力エラー", body: `{"name":" "}`, err: &services.ValidationError{Message: "name must be provided"}, wantStatus: http.StatusBadRequest},
+		{name: "同じ名前のタグがある", body: `{"name":"旅行"}`, err: services.ErrTagNameTaken, wantStatus: http.StatusConflict},
+		{name: "その他のエラー", body: `{"name":"旅行"}`, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewTagHandler(router, &tagServiceMock{
+				CreateTagFunc: func(userID string, input models.TagInput) (models.Tag, error) {
+					return models.Tag{}, tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/tags", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestTagHandler_RenameAndDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "名前を変える", method: http.MethodPatch, path: "/tags/3", wantStatus: http.StatusOK},
+		{name: "変更で名前が重なる", method: http.MethodPatch, path: "/tags/3", err: services.ErrTagNameTaken, wantStatus: http.StatusConflict},
+		{name: "不正な ID", method: http.MethodPatch, path: "/tags/abc", wantStatus: http.StatusBadRequest},
+		{name: "削除する", method: http.MethodDelete, path: "/tags/3", wantStatus: http.StatusNoContent},
+		{name: "削除するタグが無い", method: http.MethodDelete, path: "/tags/3", err: &services.NotFoundError{Message: "tag not found"}, wantStatus: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAuthedRouter()
+			NewTagHandler(router, &tagServiceMock{
+				RenameTagFunc: func(userID string, id int, input models.TagInput) (models.Tag, error) {
+					require.Equal(t, 3, id)
+					return models.Tag{ID: id, Name: input.Name}, tc.err
+				},
+				DeleteTagFunc: func(userID string, id int) error {
+					require.Equal(t, 3, id)
+					return tc.err
+				},
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"name":"旅行"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestTagHandler_MergeTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewTagHandler(router, &tagServiceMock{
+		MergeTagFunc: func(userID string, id int, input models.MergeTagInput) (models.Tag, error) {
+			require.Equal(t, 3, id)
+			require.Equal(t, 4, *input.Into)
+			return models.Tag{ID: 4, Name: "旅行"}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/tags/3/merge", strings.NewReader(`{"into":4}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"tag":{"id":4,"name":"旅行"}}`, w.Body.String())
+}
+
+func TestTagHandler_Report(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthedRouter()
+	NewTagHandler(router, &tagServiceMock{
+		ReportFunc: func(userID string, input models.TagReportInput) ([]models.TagMonthlyTotal, error) {
+			require.Equal(t, models.TagReportInput{From: "2025-01", To: "2025-03"}, input)
+			return []models.TagMonthlyTotal{{Tag: models.Tag{ID: 1, Name: "旅行"}, Month: "2025-02", ExpenseCount: 2, ConfirmedTotal: 3000, PlannedTotal: 500}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tags/report?from=2025-01&to=2025-03", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Totals []models.TagMonthlyTotal `json:"totals"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Totals, 1)
+	require.Equal(t, 3000, resp.Totals[0].ConfirmedTotal)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"money-buddy-backend/internal/services"
+)
+
+// RequestTransaction は認証済みリクエストを 1 つのトランザクションで処理します。
+// AuthMiddleware の後ろに置きます。トランザクションの開始時に呼び出し元がセッション変数に設定されるため
+// （infra/transaction 参照）、リポジトリは行レベルセキュリティのもとで呼び出し元の権限で動作します。
+// サービスが開始するトランザクションはこのトランザクション内のセーブポイントになります。
+//
+// コミットはレスポンスの送信直前に行い、ステータスが 400 以上ならロールバックします。
+// コミットに失敗した場合はハンドラの応答の代わりに 500 を返します。
+func RequestTransaction(txManager services.TxManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tx, err := txManager.Begin(ctx)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+
+		w := &txResponseWriter{ResponseWriter: c.Writer, tx: tx}
+		c.Writer = w
+		c.Request = c.Request.WithContext(tx.Context(ctx))
+
+		defer func() {
+			if r := recover(); r != nil {
+				w.rollback()
+				panic(r)
+			}
+		}()
+		c.Next()
+		w.finish()
+	}
+}
+
+// txResponseWriter は最初の書き込みの直前にトランザクションを確定させる gin.ResponseWriter です。
+type txResponseWriter struct {
+	gin.ResponseWriter
+	tx     services.Tx
+	done   bool
+	failed bool
+}
+
+func (w *txResponseWriter) finish() {
+	if w.done {
+		return
+	}
+	if w.ResponseWriter.Status() >= http.StatusBadRequest {
+		w.rollback()
+		return
+	}
+	w.done = true
+	if err := w.tx.Commit(); err != nil {
+		w.failed = true
+		w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.ResponseWriter.Write([]byte(`{"error":"internal server error"}`))
+	}
+}
+
+func (w *txResponseWriter) rollback() {
+	if w.done {
+		return
+	}
+	w.done = true
+	_ = w.tx.Rollback()
+}
+
+func (w *txResponseWriter) WriteHeaderNow() {
+	w.finish()
+	if !w.failed {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *txResponseWriter) Write(b []byte) (int, error) {
+	w.finish()
+	if w.failed {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *txResponseWriter) WriteString(s string) (int, error) {
+	w.finish()
+	if w.failed {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *txResponseWriter) Flush() {
+	w.finish()
+	if !w.failed {
+		w.ResponseWriter.Flush()
+	}
+}
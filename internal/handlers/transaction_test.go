@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"money-buddy-backend/internal/services"
+)
+
+type requestTxKey struct{}
+
+type requestTxMock struct {
+	commitErr  error
+	commits    int
+	rollbacks  int
+	writesSeen bool // コミット時点でレスポンスが書き込まれていたか
+	recorder   *httptest.ResponseRecorder
+}
+
+func (m *requestTxMock) Commit() error {
+	m.commits++
+	m.writesSeen = m.recorder.Body.Len() > 0
+	return m.commitErr
+}
+
+func (m *requestTxMock) Rollback() error {
+	m.rollbacks++
+	return nil
+}
+
+func (m *requestTxMock) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestTxKey{}, m)
+}
+
+type requestTxManagerMock struct {
+	tx       services.Tx
+	beginErr error
+}
+
+func (m *requestTxManagerMock) Begin(ctx context.Context) (services.Tx, error) {
+	return m.tx, m.beginErr
+}
+
+func TestRequestTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name          string
+		handler       gin.HandlerFunc
+		commitErr     error
+		wantStatus    int
+		wantBody      string
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name:        "成功したらレスポンスの前にコミットする",
+			handler:     func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"ok": true}) },
+			wantStatus:  http.StatusCreated,
+			wantBody:    `{"ok":true}`,
+			wantCommits: 1,
+		},
+		{
+			name:        "本文の無いレスポンスでもコミットする",
+			handler:     func(c *gin.Context) { c.Status(http.StatusNoContent) },
+			wantStatus:  http.StatusNoContent,
+			wantCommits: 1,
+		},
+		{
+			name:          "4xx はロールバックする",
+			handler:       func(c *gin.Context) { c.JSON(http.StatusBadRequest, gin.H{"error": "bad"}) },
+			wantStatus:    http.StatusBadRequest,
+			wantBody:      `{"error":"bad"}`,
+			wantRollbacks: 1,
+		},
+		{
+			name: "中断されたリクエストもロールバックする",
+			handler: func(c *gin.Context) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient scope"})
+			},
+			wantStatus:    http.StatusForbidden,
+			wantBody:      `{"error":"insufficient scope"}`,
+			wantRollbacks: 1,
+		},
+		{
+			name:          "5xx はロールバックする",
+			handler:       func(c *gin.Context) { c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"}) },
+			wantStatus:    http.StatusInternalServerError,
+			wantBody:      `{"error":"internal server error"}`,
+			wantRollbacks: 1,
+		},
+		{
+			name:        "コミットに失敗したら 500 に差し替える",
+			handler:     func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"ok": true}) },
+			commitErr:   errors.New("serialization failure"),
+			wantStatus:  http.StatusInternalServerError,
+			wantBody:    `{"error":"internal server error"}`,
+			wantCommits: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tx := &requestTxMock{commitErr: tc.commitErr, recorder: w}
+
+			router := gin.New()
+			var gotTx any
+			router.POST("/x", RequestTransaction(&requestTxManagerMock{tx: tx}), func(c *gin.Context) {
+				gotTx = c.Request.Context().Value(requestTxKey{})
+				tc.handler(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/x", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			if tc.wantBody != "" {
+				assert.JSONEq(t, tc.wantBody, w.Body.String())
+			}
+			assert.Equal(t, tc.wantCommits, tx.commits)
+			assert.Equal(t, tc.wantRollbacks, tx.rollbacks)
+			assert.False(t, tx.writesSeen, "commit must happen before the response is written")
+			assert.Same(t, tx, gotTx, "handlers must run with the request transaction in context")
+		})
+	}
+}
+
+func TestRequestTransaction_BeginFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	called := false
+	router.GET("/x", RequestTransaction(&requestTxManagerMock{beginErr: errors.New("db down")}), func(c *gin.Context) {
+		called = true
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.False(t, called)
+}
+
+func TestRequestTransaction_RollsBackOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	tx := &requestTxMock{recorder: w}
+
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.GET("/x", RequestTransaction(&requestTxManagerMock{tx: tx}), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 0, tx.commits)
+	assert.Equal(t, 1, tx.rollbacks)
+}
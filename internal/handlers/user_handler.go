@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"money-buddy-backend/internal/auth"
 	"money-buddy-backend/internal/services"
 )
 
@@ -12,18 +14,27 @@ type UserHandler struct {
 	service services.UserService
 }
 
-func NewUserHandler(r *gin.Engine, service services.UserService) {
+func NewUserHandler(r gin.IRoutes, service services.UserService) {
 	h := &UserHandler{service: service}
-	r.GET("/user/me", h.GetCurrentUser)
+	r.GET("/user/me", RequireScope(auth.ScopeUserRead), h.GetCurrentUser)
 }
 
+// GetCurrentUser handles GET /user/me. The user is provisioned on first access,
+// and the response includes the onboarding state so the client knows whether to show /setup.
 func (h *UserHandler) GetCurrentUser(c *gin.Context) {
-	// TODO: Extract userID from authentication context when auth is implemented
-	userID := DummyUserID
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
-	user, err := h.service.GetUserByID(c.Request.Context(), userID)
+	user, err := h.service.GetCurrentUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		var ne *services.NotFoundError
+		if errors.As(err, &ne) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
@@ -12,10 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/services"
 )
 
 type userServiceMock struct {
-	GetUserByIDFunc func(ctx context.Context, userID string) (*models.User, error)
+	GetUserByIDFunc    func(ctx context.Context, userID string) (*models.User, error)
+	GetCurrentUserFunc func(ctx context.Context, userID string) (*models.User, error)
+	IsDisabledFunc     func(ctx context.Context, userID string) (bool, error)
+	ProvisionFunc      func(ctx context.Context, userID string) error
 }
 
 func (m *userServiceMock) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
@@ -25,19 +29,45 @@ func (m *userServiceMock) GetUserByID(ctx context.Context, userID string) (*mode
 	return nil, nil
 }
 
+func (m *userServiceMock) GetCurrentUser(ctx context.Context, userID string) (*models.User, error) {
+	if m.GetCurrentUserFunc != nil {
+		return m.GetCurrentUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *userServiceMock) IsDisabled(ctx context.Context, userID string) (bool, error) {
+	if m.IsDisabledFunc != nil {
+		return m.IsDisabledFunc(ctx, userID)
+	}
+	return false, nil
+}
+
+func (m *userServiceMock) Provision(ctx context.Context, userID string) error {
+	if m.ProvisionFunc != nil {
+		return m.ProvisionFunc(ctx, userID)
+	}
+	return nil
+}
+
 func TestUserHandler_GetCurrentUser_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &userServiceMock{
-		GetUserByIDFunc: func(ctx context.Context, userID string) (*models.User, error) {
-			require.Equal(t, DummyUserID, userID)
+		GetCurrentUserFunc: func(ctx context.Context, userID string) (*models.User, error) {
+			require.Equal(t, testUserID, userID)
 			return &models.User{
 				ID:         "test-user",
 				Income:     300000,
 				SavingGoal: 50000,
 				CreatedAt:  "2024-01-01T00:00:00Z",
 				UpdatedAt:  "2024-01-01T00:00:00Z",
+				Onboarding: models.Onboarding{
+					Status:         models.OnboardingStatusNeedsSetup,
+					StepsCompleted: []string{},
+					StepsRemaining: models.OnboardingSteps,
+				},
 			}, nil
 		},
 	}
@@ -55,15 +85,17 @@ func TestUserHandler_GetCurrentUser_Success(t *testing.T) {
 	require.Equal(t, "test-user", user.ID)
 	require.Equal(t, 300000, user.Income)
 	require.Equal(t, 50000, user.SavingGoal)
+	require.Nil(t, user.SetupCompletedAt)
+	require.Equal(t, models.OnboardingStatusNeedsSetup, user.Onboarding.Status)
 }
 
 func TestUserHandler_GetCurrentUser_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &userServiceMock{
-		GetUserByIDFunc: func(ctx context.Context, userID string) (*models.User, error) {
-			return nil, errors.New("user not found")
+		GetCurrentUserFunc: func(ctx context.Context, userID string) (*models.User, error) {
+			return nil, &services.NotFoundError{Message: "user not found"}
 		},
 	}
 	NewUserHandler(router, svc)
@@ -82,10 +114,10 @@ func TestUserHandler_GetCurrentUser_NotFound(t *testing.T) {
 
 func TestUserHandler_GetCurrentUser_RepositoryError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	router := gin.New()
+	router := newAuthedRouter()
 
 	svc := &userServiceMock{
-		GetUserByIDFunc: func(ctx context.Context, userID string) (*models.User, error) {
+		GetCurrentUserFunc: func(ctx context.Context, userID string) (*models.User, error) {
 			return nil, errors.New("database connection error")
 		},
 	}
@@ -95,10 +127,11 @@ func TestUserHandler_GetCurrentUser_RepositoryError(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	require.Equal(t, http.StatusNotFound, w.Code)
+	// DB 障害は未登録ユーザーと区別して 500 を返す
+	require.Equal(t, http.StatusInternalServerError, w.Code)
 
 	var resp map[string]string
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	require.NoError(t, err)
-	require.Equal(t, "user not found", resp["error"])
+	require.Equal(t, "internal server error", resp["error"])
 }
@@ -0,0 +1,78 @@
+package models
+
+// 運用者の操作。admin_audit_logs.action に記録します。
+const (
+	AdminActionSearchUsers = "users.search"
+	AdminActionViewUser    = "users.view"
+	AdminActionDisableUser = "users.disable"
+	AdminActionEnableUser  = "users.enable"
+	AdminActionExportUser  = "users.export"
+	AdminActionViewExport  = "exports.view"
+)
+
+// AdminUser は運用者向けのユーザー情報です。件数と最終操作日時は登録者として記録されたデータから集計します。
+type AdminUser struct {
+	ID                  string  `json:"id"`
+	CreatedAt           string  `json:"created_at"`
+	Disabled            bool    `json:"disabled"`
+	DisabledAt          *string `json:"disabled_at"`
+	DeletionScheduledAt *string `json:"deletion_scheduled_at"`
+	SetupCompletedAt    *string `json:"setup_completed_at"`
+	ExpenseCount        int     `json:"expense_count"`
+	FixedCostCount      int     `json:"fixed_cost_count"`
+	LastActivityAt      string  `json:"last_activity_at"`
+}
+
+// AdminUserSearchInput は GET /admin/users の検索条件です。
+type AdminUserSearchInput struct {
+	// Query はユーザー ID の部分一致です。空なら全件を返します。
+	Query  string `form:"q"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// AdminAuditLog は運用者の操作記録です。
+type AdminAuditLog struct {
+	Operator     string
+	Action       string
+	TargetUserID string
+	Detail       map[string]any
+}
+
+// UserDataExport は問い合わせ対応のために書き出したユーザーデータです。
+// Data は 1 件取得した場合のみ設定します。
+type UserDataExport struct {
+	ID          int             `json:"id"`
+	UserID      string          `json:"user_id"`
+	RequestedBy string          `json:"requested_by"`
+	CreatedAt   string          `json:"created_at"`
+	Data        *UserExportData `json:"data,omitempty"`
+}
+
+// UserExportData は書き出すデータの中身です。支出・固定費は家計簿を問わずユーザーが登録したものです。
+type UserExportData struct {
+	UserID               string                `json:"user_id"`
+	Income               int                   `json:"income"`
+	SavingGoal           int                   `json:"saving_goal"`
+	CreatedAt            string                `json:"created_at"`
+	SetupCompletedAt     *string               `json:"setup_completed_at"`
+	Ledgers              []Ledger              `json:"ledgers"`
+	Expenses             []ExportedExpense     `json:"expenses"`
+	FixedCosts           []FixedCost           `json:"fixed_costs"`
+	PersonalAccessTokens []PersonalAccessToken `json:"personal_access_tokens"`
+}
+
+type ExportedExpense struct {
+	ID             int    `json:"id"`
+	LedgerID       int    `json:"ledger_id"`
+	Amount         int    `json:"amount"`
+	RefundedAmount int    `json:"refunded_amount,omitempty"`
+	CategoryID     int    `json:"category_id"`
+	Memo           string `json:"memo"`
+	SpentAt        string `json:"spent_at"`
+	Status         string `json:"status"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+	// Splits は内訳です。内訳の無い支出では省略します。
+	Splits []ExpenseSplit `json:"splits,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "io"
+
+// Attachment は支出の添付ファイル（レシートの写真など）です。ファイルの中身は保存先に置き、
+// GET /expenses/:id/attachments/:attachment_id で取得します。
+type Attachment struct {
+	ID        int    `json:"id"`
+	ExpenseID int    `json:"expense_id"`
+	FileName  string `json:"file_name"`
+	// ContentType はファイルの中身から判定した形式です。
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// HasThumbnail は縮小版の画像（GET /expenses/:id/attachments/:attachment_id/thumbnail）があるかどうかです。
+	HasThumbnail bool   `json:"has_thumbnail"`
+	CreatedAt    string `json:"created_at"`
+
+	// StorageKey・ThumbnailKey は保存先のキーです。ThumbnailKey は縮小版が無ければ空です。
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
+
+// AttachmentUpload は POST /expenses/:id/attachments でアップロードされたファイルです。
+type AttachmentUpload struct {
+	FileName string
+	Body     io.Reader
+}
+
+// AttachmentFile はダウンロードするファイルです。呼び出し元が Body を閉じます。
+type AttachmentFile struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
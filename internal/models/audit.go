@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// 更新操作の種類。audit_events.action に記録します。
+const (
+	AuditActionExpenseCreate       = "expense.create"
+	AuditActionExpenseUpdate       = "expense.update"
+	AuditActionExpenseDelete       = "expense.delete"
+	AuditActionExpenseConfirm      = "expense.confirm"
+	AuditActionExpenseRestore      = "expense.restore"
+	AuditActionExpenseRefund       = "expense.refund"
+	AuditActionExpenseRefundDelete = "expense.refund_delete"
+	AuditActionIncomeCreate        = "income.create"
+	AuditActionIncomeUpdate        = "income.update"
+	AuditActionIncomeDelete        = "income.delete"
+	AuditActionSetupComplete       = "setup.complete"
+	AuditActionFixedCostsReplace   = "fixed_costs.replace"
+)
+
+// 操作対象の種類。audit_events.entity_type に記録します。
+// fixed_costs は家計簿の固定費一覧全体で、entity_id は家計簿 ID です。
+const (
+	AuditEntityExpense    = "expense"
+	AuditEntityIncome     = "income"
+	AuditEntityUser       = "user"
+	AuditEntityFixedCosts = "fixed_costs"
+)
+
+// AuditChange は 1 項目の変更前後の値です。作成時の Before と削除時の After は null です。
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEvent は利用者の更新操作の記録です。
+type AuditEvent struct {
+	ID int `json:"id"`
+	// LedgerID は家計簿に属さない操作（初期設定の収入など）では nil です。
+	LedgerID *int `json:"ledger_id"`
+	// ActorID は操作したユーザーです。退会したユーザーの場合は nil です。
+	ActorID    *string                `json:"actor_id"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Changes    map[string]AuditChange `json:"changes"`
+	CreatedAt  string                 `json:"created_at"`
+}
+
+// ListAuditEventsInput は GET /audit のクエリパラメータです。
+type ListAuditEventsInput struct {
+	LedgerID   int    `form:"ledger_id"`
+	EntityType string `form:"entity_type"`
+	EntityID   string `form:"entity_id"`
+	Action     string `form:"action"`
+	ActorID    string `form:"actor_id"`
+	// Since・Until は RFC3339 または YYYY-MM-DD です。日付のみの Until はその日の終わりまでを含みます。
+	Since string `form:"since"`
+	Until string `form:"until"`
+	Limit int    `form:"limit"`
+	// Cursor は前のページの next_cursor です。
+	Cursor int `form:"cursor"`
+}
+
+// AuditEventFilter はリポジトリに渡す検索条件です。ゼロ値の項目は絞り込みません。
+type AuditEventFilter struct {
+	LedgerID   int32
+	EntityType string
+	EntityID   string
+	Action     string
+	ActorID    string
+	Since      time.Time
+	Until      time.Time
+	// BeforeID より小さい ID のイベントを返します。
+	BeforeID int32
+	Limit    int
+}
+
+// AuditEventPage は GET /audit のレスポンスです。NextCursor は続きが無ければ nil です。
+type AuditEventPage struct {
+	Events     []AuditEvent `json:"events"`
+	NextCursor *int         `json:"next_cursor"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// MonthlySummaryInput は GET /dashboard/summary のクエリパラメータです。
+type MonthlySummaryInput struct {
+	// Month は YYYY-MM です。省略すると今月です。
+	Month string `form:"month"`
+}
+
+// MonthlySummary は家計簿の 1 か月分の収支です。共有家計簿では全メンバーの分を合算します。
+type MonthlySummary struct {
+	Month string `json:"month"`
+	// Income・ReceivedIncome・PlannedIncome・ExpectedIncome は IncomeSummary と同じ集計です。
+	Income         int `json:"income"`
+	ReceivedIncome int `json:"received_income"`
+	PlannedIncome  int `json:"planned_income"`
+	ExpectedIncome int `json:"expected_income"`
+	// SavingGoal は全メンバーの貯金目標の合計、FixedCosts は家計簿の固定費の合計です。
+	SavingGoal int `json:"saving_goal"`
+	FixedCosts int `json:"fixed_costs"`
+	// ConfirmedExpenses は確定済みの支出から返金を差し引いた合計、PendingExpenses は予定の支出の合計です。
+	ConfirmedExpenses int `json:"confirmed_expenses"`
+	PendingExpenses   int `json:"pending_expenses"`
+	// PaymentMethods は支出の支払い手段の種類ごとの内訳で、確定済みの合計の多い順です。
+	PaymentMethods []PaymentMethodTotal `json:"payment_methods"`
+}
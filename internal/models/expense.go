@@ -1,11 +1,25 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 type CreateExpenseInput struct {
 	Amount     *int   `json:"amount" binding:"required"`
 	CategoryID *int   `json:"category_id" binding:"required"`
 	Memo       string `json:"memo"`
 	SpentAt    string `json:"spent_at" binding:"required"`
 	Status     string `json:"status"`
+	// Splits は内訳です。指定する場合は金額の合計を Amount と一致させます。
+	Splits []ExpenseSplitInput `json:"splits"`
+	// TagIDs は付けるタグです。呼び出し元のタグだけを指定できます。
+	TagIDs []int `json:"tag_ids"`
+	// PayeeID は支払先です。呼び出し元の支払先だけを指定できます。
+	PayeeID *int `json:"payee_id"`
+	// PaymentAccountID は支払い手段です。呼び出し元の支払い手段だけを指定できます。
+	PaymentAccountID *int `json:"payment_account_id"`
 }
 
 type UpdateExpenseInput struct {
@@ -15,6 +29,117 @@ type UpdateExpenseInput struct {
 	Memo       string `json:"memo"`
 	SpentAt    string `json:"spent_at" binding:"required"`
 	Status     string `json:"status"`
+	// Splits は更新後の内訳です。空なら内訳を無くします。
+	Splits []ExpenseSplitInput `json:"splits"`
+	// TagIDs は更新後に呼び出し元が付けているタグです。空なら呼び出し元のタグを外します。
+	TagIDs []int `json:"tag_ids"`
+	// PayeeID は更新後の支払先です。nil なら支払先を外します。呼び出し元の支払先か、今付いている支払先を指定できます。
+	PayeeID *int `json:"payee_id"`
+	// PaymentAccountID は更新後の支払い手段です。nil なら支払い手段を外します。呼び出し元の支払い手段か、今付いている支払い手段を指定できます。
+	PaymentAccountID *int `json:"payment_account_id"`
+	// Version は If-Match で指定された更新前のバージョンです。nil なら現在のバージョンを問わず更新します。
+	Version *int `json:"-"`
+}
+
+// ExpenseSplitInput は支出の内訳 1 行の入力です。
+type ExpenseSplitInput struct {
+	CategoryID *int   `json:"category_id"`
+	Amount     *int   `json:"amount"`
+	Memo       string `json:"memo"`
+}
+
+// ExpenseSplit は支出の内訳 1 行です。内訳のある支出は、カテゴリ別の絞り込みで支出の
+// カテゴリの代わりに内訳のカテゴリを使います。
+type ExpenseSplit struct {
+	Category Category `json:"category"`
+	Amount   int      `json:"amount"`
+	Memo     string   `json:"memo"`
+}
+
+// ExpensePatch は PATCH /expenses/:id の JSON Merge Patch（RFC 7396）です。
+// nil の項目は変更しません。memo に null を指定すると Memo は空文字列を指し、メモを消します。
+// splits・tag_ids は配列全体で置き換え、null を指定すると空の配列を指し、内訳・タグを無くします。
+type ExpensePatch struct {
+	Amount     *int
+	CategoryID *int
+	Memo       *string
+	SpentAt    *string
+	Status     *string
+	Splits     *[]ExpenseSplitInput
+	TagIDs     *[]int
+	// PayeeID・PaymentAccountID は 0 なら支払先・支払い手段を外します（JSON では null）。
+	PayeeID          *int
+	PaymentAccountID *int
+}
+
+func (p *ExpensePatch) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if fields == nil {
+		return fmt.Errorf("merge patch must be a JSON object")
+	}
+
+	*p = ExpensePatch{}
+	for name, raw := range fields {
+		var target any
+		switch name {
+		case "amount":
+			target = &p.Amount
+		case "category_id":
+			target = &p.CategoryID
+		case "memo":
+			target = &p.Memo
+		case "spent_at":
+			target = &p.SpentAt
+		case "status":
+			target = &p.Status
+		case "splits":
+			target = &p.Splits
+		case "tag_ids":
+			target = &p.TagIDs
+		case "payee_id":
+			target = &p.PayeeID
+		case "payment_account_id":
+			target = &p.PaymentAccountID
+		default:
+			return fmt.Errorf("unknown field %q", name)
+		}
+
+		if string(raw) == "null" {
+			// 削除できるのはメモ・内訳・タグ・支払先・支払い手段だけ
+			switch name {
+			case "memo":
+				empty := ""
+				p.Memo = &empty
+			case "splits":
+				p.Splits = &[]ExpenseSplitInput{}
+			case "tag_ids":
+				p.TagIDs = &[]int{}
+			case "payee_id":
+				none := 0
+				p.PayeeID = &none
+			case "payment_account_id":
+				none := 0
+				p.PaymentAccountID = &none
+			default:
+				return fmt.Errorf("%s cannot be null", name)
+			}
+			continue
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return fmt.Errorf("%s is invalid", name)
+		}
+		// 0 は支払先・支払い手段を外す指定と区別できないため受け付けない
+		if name == "payee_id" && *p.PayeeID <= 0 {
+			return fmt.Errorf("payee_id must be greater than 0")
+		}
+		if name == "payment_account_id" && *p.PaymentAccountID <= 0 {
+			return fmt.Errorf("payment_account_id must be greater than 0")
+		}
+	}
+	return nil
 }
 
 type Expense struct {
@@ -24,4 +149,168 @@ type Expense struct {
 	SpentAt  string   `json:"spent_at"`
 	Status   string   `json:"status"`
 	Category Category `json:"category"`
+	// ConfirmedAt は planned から confirmed にした日時です。最初から confirmed で登録した支出は nil です。
+	ConfirmedAt *string `json:"confirmed_at"`
+	// PlannedAmount・PlannedSpentAt は確定前に予定していた金額・日付で、実績との比較に使います。
+	PlannedAmount  *int    `json:"planned_amount"`
+	PlannedSpentAt *string `json:"planned_spent_at"`
+	// Version は更新のたびに増える番号です。ETag として返し、If-Match による楽観的排他制御に使います。
+	Version int `json:"version"`
+	// RefundedAmount は返金の合計です。集計では Amount から差し引いた額を実際の支出額として数えます。返金の無い支出では省略します。
+	RefundedAmount int `json:"refunded_amount,omitempty"`
+	// Splits は内訳です。金額の合計は Amount と一致します。内訳の無い支出では省略します。
+	Splits []ExpenseSplit `json:"splits,omitempty"`
+	// Tags は呼び出し元がこの支出に付けたタグです。他のユーザーのタグは含みません。タグの無い支出では省略します。
+	Tags []Tag `json:"tags,omitempty"`
+	// Payee は支払先です。共有家計簿では他のメンバーが付けた支払先も返します。支払先の無い支出では省略します。
+	Payee *Payee `json:"payee,omitempty"`
+	// PaymentAccount は支払い手段です。共有家計簿では他のメンバーが付けた支払い手段も返します。支払い手段の無い支出では省略します。
+	PaymentAccount *ExpensePaymentAccount `json:"payment_account,omitempty"`
+}
+
+// TrashedExpense はゴミ箱（GET /expenses/trash）の支出です。
+// PurgeAt を過ぎると完全に削除され、復元できなくなります。
+type TrashedExpense struct {
+	Expense
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"`
+}
+
+// 一括操作（POST /expenses/bulk）の操作の種類です。
+const (
+	BulkExpenseOpCreate = "create"
+	BulkExpenseOpUpdate = "update"
+	BulkExpenseOpDelete = "delete"
+)
+
+// BulkExpensesInput は POST /expenses/bulk の本文です。操作は先頭から順に適用します。
+type BulkExpensesInput struct {
+	Operations []BulkExpenseOperation `json:"operations" binding:"required"`
+}
+
+// BulkExpenseOperation は一括操作の 1 件です。
+// create は Expense、update は ID と Patch（PATCH /expenses/:id と同じ JSON Merge Patch）、delete は ID を指定します。
+type BulkExpenseOperation struct {
+	Op      string              `json:"op"`
+	ID      int                 `json:"id"`
+	Expense *CreateExpenseInput `json:"expense"`
+	Patch   *ExpensePatch       `json:"patch"`
+}
+
+// BulkExpenseResult は一括操作 1 件の結果です。Index は本文の operations での位置（0 始まり）です。
+// 成功した create・update は Expense を持ちます。失敗した操作は Error を持ち、その場合は
+// すべての操作を取り消すため、どの結果も Expense を持ちません。
+type BulkExpenseResult struct {
+	Index   int      `json:"index"`
+	Op      string   `json:"op"`
+	ID      int      `json:"id,omitempty"`
+	Expense *Expense `json:"expense,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ConfirmExpenseInput は POST /expenses/:id/confirm の本文です。
+// 実際の金額・日付が予定と異なる場合に指定し、省略した項目は予定のままにします。
+type ConfirmExpenseInput struct {
+	Amount  *int   `json:"amount"`
+	SpentAt string `json:"spent_at"`
+}
+
+// ConfirmExpensesInput は POST /expenses/confirm の本文です。
+type ConfirmExpensesInput struct {
+	Items []ConfirmExpenseItem `json:"items" binding:"required"`
+}
+
+type ConfirmExpenseItem struct {
+	ID      int    `json:"id" binding:"required"`
+	Amount  *int   `json:"amount"`
+	SpentAt string `json:"spent_at"`
+}
+
+// 一覧の並び順（GET /expenses の sort）。先頭の - は降順です。
+const (
+	ExpenseSortSpentAtDesc = "-spent_at"
+	ExpenseSortSpentAtAsc  = "spent_at"
+	ExpenseSortAmountDesc  = "-amount"
+	ExpenseSortAmountAsc   = "amount"
+)
+
+// ListExpensesInput は GET /expenses のクエリパラメータです。
+type ListExpensesInput struct {
+	// From・To は YYYY-MM-DD で、どちらもその日を含みます。
+	From string `form:"from"`
+	To   string `form:"to"`
+	// CategoryIDs は category_id の繰り返し、またはカンマ区切りです。いずれかに一致するものを返します。
+	CategoryIDs []string `form:"category_id"`
+	// TagIDs は tag_id の繰り返し、またはカンマ区切りです。いずれかのタグが付いたものを返します。
+	TagIDs    []string `form:"tag_id"`
+	Status    string   `form:"status"`
+	MinAmount int      `form:"min_amount"`
+	MaxAmount int      `form:"max_amount"`
+	// Memo はメモに含まれる文字列です（大文字・小文字を区別しません）。
+	Memo  string `form:"memo"`
+	Sort  string `form:"sort"`
+	Limit int    `form:"limit"`
+	// Cursor は前のページの next_cursor です。
+	Cursor string `form:"cursor"`
+}
+
+// ExpenseFilter はリポジトリに渡す検索条件です。ゼロ値の項目は絞り込みません。
+type ExpenseFilter struct {
+	From        time.Time
+	To          time.Time
+	CategoryIDs []int32
+	TagIDs      []int32
+	Status      string
+	MinAmount   int
+	MaxAmount   int
+	Memo        string
+	// Sort は ExpenseSort* のいずれかです。
+	Sort string
+	// After が指定されている場合は、Sort の順でその支出より後のものを返します。
+	After *ExpenseCursor
+	Limit int
+}
+
+// ExpenseCursor はページの最後の支出の並び順のキーです。
+type ExpenseCursor struct {
+	SpentAt time.Time
+	Amount  int
+	ID      int
+}
+
+// ExpensePage は支出一覧の 1 ページです。NextCursor は最後のページでは nil です。
+type ExpensePage struct {
+	Expenses   []Expense `json:"expenses"`
+	NextCursor *string   `json:"next_cursor"`
+}
+
+// SearchExpensesInput は GET /expenses/search のクエリパラメータです。
+type SearchExpensesInput struct {
+	// Q は検索語です。空白で区切った語はすべて含むものを返します。
+	Q           string   `form:"q"`
+	CategoryIDs []string `form:"category_id"`
+	Status      string   `form:"status"`
+	Limit       int      `form:"limit"`
+}
+
+// ExpenseSearchFilter はリポジトリに渡す検索条件です。Terms 以外はゼロ値なら絞り込みません。
+type ExpenseSearchFilter struct {
+	Terms       []string
+	CategoryIDs []int32
+	Status      string
+	Limit       int
+}
+
+// ExpenseSearchResult は検索に一致した支出です。
+type ExpenseSearchResult struct {
+	Expense
+	// Score はメモに占める一致部分の割合（0〜1）で、大きいほど上位です。
+	Score float64 `json:"score"`
+	// Highlight はメモを一致した部分とそれ以外に分けたものです。Text をつなげると Memo になります。
+	Highlight []MemoSegment `json:"highlight"`
+}
+
+type MemoSegment struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match"`
 }
@@ -2,6 +2,7 @@ package models
 
 type FixedCost struct {
 	ID        int    `json:"id"`
+	LedgerID  int    `json:"ledger_id"`
 	UserID    string `json:"user_id"`
 	Name      string `json:"name"`
 	Amount    int    `json:"amount"`
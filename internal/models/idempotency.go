@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// IdempotencyKey は Idempotency-Key 付きのリクエストと、それに返したレスポンスの記録です。
+type IdempotencyKey struct {
+	UserID string
+	Key    string
+	// RequestHash はリクエストのメソッド・パス・本文の SHA-256（hex）です。
+	RequestHash string
+	Response    IdempotentResponse
+	// CreatedAt・ExpiresAt は UTC です。列は TIMESTAMP のため、DB の now() とは比べずアプリの時刻で比べます。
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IdempotentResponse は同じ Idempotency-Key の再送に返すレスポンスです。
+type IdempotentResponse struct {
+	Status int
+	// Headers は再送にも返すヘッダ（Content-Type・ETag など）です。
+	Headers map[string]string
+	Body    []byte
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Income は家計簿の収入 1 件です（給与・賞与・副業など）。支出と同じく予定（planned）と確定（confirmed）があります。
+type Income struct {
+	ID int `json:"id"`
+	// UserID は収入を受け取ったメンバー（登録者）です。月次集計の見込み（users.income）との置き換えに使います。退会したメンバーの収入では空です。
+	UserID     string `json:"user_id"`
+	Source     string `json:"source"`
+	Amount     int    `json:"amount"`
+	ReceivedAt string `json:"received_at"`
+	Status     string `json:"status"`
+}
+
+// IncomeInput は POST /incomes・PUT /incomes/:id の本文です。
+type IncomeInput struct {
+	Source string `json:"source" binding:"required"`
+	Amount *int   `json:"amount" binding:"required"`
+	// ReceivedAt は YYYY-MM-DD です。
+	ReceivedAt string `json:"received_at" binding:"required"`
+	// Status は planned か confirmed です。省略すると登録時は confirmed、更新時は現在のステータスです。
+	Status string `json:"status"`
+}
+
+// ListIncomesInput は GET /incomes のクエリパラメータです。
+type ListIncomesInput struct {
+	// From・To は YYYY-MM-DD で、どちらもその日を含みます。省略した側は期間を区切りません。
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Status string `form:"status"`
+}
+
+// IncomeFilter はリポジトリに渡す絞り込み条件です。From 以降・To より前の収入を返し、ゼロ値の項目は絞り込みません。
+type IncomeFilter struct {
+	From   time.Time
+	To     time.Time
+	Status string
+}
+
+// IncomeSummaryInput は GET /incomes/summary のクエリパラメータです。
+type IncomeSummaryInput struct {
+	// Month は YYYY-MM です。省略すると今月です。
+	Month string `form:"month"`
+}
+
+// IncomeSummary は家計簿の 1 か月分の収入です。共有家計簿では全メンバーの分を合算します。
+type IncomeSummary struct {
+	Month string `json:"month"`
+	// Income は月次集計に使う収入です。その月の収入を登録したメンバーは登録した収入（予定を含む）を、
+	// 登録していないメンバーは設定の月収（ExpectedIncome）を合算します。
+	Income int `json:"income"`
+	// ReceivedIncome・PlannedIncome は登録した収入のうち確定済み・予定の合計です。
+	ReceivedIncome int `json:"received_income"`
+	PlannedIncome  int `json:"planned_income"`
+	// ExpectedIncome は全メンバーの設定の月収（users.income）の合計です。
+	ExpectedIncome int `json:"expected_income"`
+}
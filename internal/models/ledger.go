@@ -0,0 +1,55 @@
+package models
+
+// 家計簿メンバーのロール。owner は家計簿ごとに 1 人で、メンバーの管理と招待ができます。
+// editor は支出・固定費を編集でき、viewer は閲覧のみです。
+const (
+	LedgerRoleOwner  = "owner"
+	LedgerRoleEditor = "editor"
+	LedgerRoleViewer = "viewer"
+)
+
+// Ledger は支出・固定費を所有する家計簿です。夫婦などで共有できます。
+type Ledger struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	MemberCount int    `json:"member_count"`
+	// Active は呼び出し元のユーザーが現在この家計簿を使っているかどうかです。
+	Active bool `json:"active"`
+	// Role は呼び出し元のユーザーのこの家計簿でのロールです。
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type LedgerMember struct {
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joined_at"`
+}
+
+type CreateLedgerInput struct {
+	Name string `json:"name"`
+}
+
+// LedgerInvite は家計簿への招待コードです。Code は発行時にのみ返します。
+type LedgerInvite struct {
+	LedgerID  int    `json:"ledger_id"`
+	Code      string `json:"code"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type JoinLedgerInput struct {
+	Code string `json:"code"`
+}
+
+type SwitchLedgerInput struct {
+	LedgerID *int `json:"ledger_id"`
+}
+
+type UpdateLedgerMemberRoleInput struct {
+	Role string `json:"role"`
+}
+
+type TransferLedgerOwnershipInput struct {
+	UserID string `json:"user_id"`
+}
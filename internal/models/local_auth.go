@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// SignupInput はローカル認証（AUTH_PROVIDER=local）のアカウント作成の入力です。
+type SignupInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshTokenInput はリフレッシュトークンの交換・ログアウトの入力です。
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// AuthTokens はサインアップ・ログイン・トークン交換で返す資格情報です。
+// AccessToken は Authorization: Bearer ヘッダで送り、期限が切れたら RefreshToken で交換します。
+// RefreshToken は一度しか使えません。
+type AuthTokens struct {
+	UserID      string `json:"user_id"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresIn はアクセストークンの残り有効秒数です。
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshToken          string `json:"refresh_token"`
+	RefreshTokenExpiresAt string `json:"refresh_token_expires_at"`
+}
+
+// LocalAccount はローカル認証のアカウントです。
+type LocalAccount struct {
+	UserID       string
+	Email        string
+	PasswordHash string
+}
+
+// RefreshToken は発行済みのリフレッシュトークンです。
+// 同じログインから交換で発行されたトークンは FamilyID を共有します。
+type RefreshToken struct {
+	ID        int
+	UserID    string
+	FamilyID  string
+	ExpiresAt time.Time
+	Revoked   bool
+}
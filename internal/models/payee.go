@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Payee はユーザーごとの支払先（店・サービスなど）です。支出に付けた支払先は、共有家計簿の
+// 他のメンバーにも支出の一部として見えます。
+type Payee struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// PayeeInput は POST /payees・PATCH /payees/:id の本文です。
+type PayeeInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MergePayeeInput は POST /payees/:id/merge の本文です。:id の支払先を Into の支払先に統合します。
+type MergePayeeInput struct {
+	Into *int `json:"into" binding:"required"`
+}
+
+// ListPayeesInput は GET /payees のクエリパラメータです。
+type ListPayeesInput struct {
+	// Prefix は名前の先頭です。全角・半角や大文字・小文字、空白の違いは区別しません。省略するとすべての支払先が対象です。
+	Prefix string `form:"prefix"`
+	Limit  *int   `form:"limit"`
+}
+
+// PayeeSuggestion は補完の候補です。よく・最近使った支払先ほど先に並びます。
+type PayeeSuggestion struct {
+	Payee
+	// UseCount・LastUsedAt はゴミ箱にない支出で使った回数と最後の支出日（YYYY-MM-DD）です。未使用なら 0 と nil です。
+	UseCount   int     `json:"use_count"`
+	LastUsedAt *string `json:"last_used_at"`
+}
+
+// PayeeTotalsInput は GET /payees/totals のクエリパラメータです。
+type PayeeTotalsInput struct {
+	// From・To は YYYY-MM-DD で、どちらもその日を含みます。省略した側は期間を区切りません。
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// PayeeTotalsFilter はリポジトリに渡す集計の期間です。From 以降・To より前の支出を集計し、ゼロ値は区切りません。
+type PayeeTotalsFilter struct {
+	From time.Time
+	To   time.Time
+}
+
+// PayeeTotal は支払先 1 つの集計です。
+type PayeeTotal struct {
+	Payee          Payee `json:"payee"`
+	ExpenseCount   int   `json:"expense_count"`
+	ConfirmedTotal int   `json:"confirmed_total"`
+	PlannedTotal   int   `json:"planned_total"`
+}
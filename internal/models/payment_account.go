@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// 支払い手段の種類です。
+const (
+	PaymentAccountTypeCash        = "cash"
+	PaymentAccountTypeCreditCard  = "credit_card"
+	PaymentAccountTypeEMoney      = "e_money" // PayPay・交通系 IC など
+	PaymentAccountTypeBankAccount = "bank_account"
+	PaymentAccountTypeOther       = "other"
+)
+
+// PaymentMethodUnspecified は月次集計の内訳で、支払い手段の無い支出をまとめる種類です。
+const PaymentMethodUnspecified = "unspecified"
+
+// IsPaymentAccountType は t が支払い手段の種類かどうかを返します。
+func IsPaymentAccountType(t string) bool {
+	switch t {
+	case PaymentAccountTypeCash, PaymentAccountTypeCreditCard, PaymentAccountTypeEMoney, PaymentAccountTypeBankAccount, PaymentAccountTypeOther:
+		return true
+	}
+	return false
+}
+
+// PaymentAccount はユーザーごとの支払い手段（財布・カード・口座など）です。
+type PaymentAccount struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	OpeningBalance int    `json:"opening_balance"`
+	// Balance は OpeningBalance から、この支払い手段で払った確定済みの支出を差し引いた残高です。
+	// クレジットカードでは利用額の分だけマイナスになります。
+	Balance int `json:"balance"`
+}
+
+// ExpensePaymentAccount は支出に付いた支払い手段です。共有家計簿の他のメンバーにも見えるため、残高は含めません。
+type ExpensePaymentAccount struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CreatePaymentAccountInput は POST /payment-accounts の本文です。
+type CreatePaymentAccountInput struct {
+	Name string `json:"name" binding:"required"`
+	Type string `json:"type" binding:"required"`
+	// OpeningBalance は開始残高です。省略すると 0 です。
+	OpeningBalance *int `json:"opening_balance"`
+}
+
+// UpdatePaymentAccountInput は PATCH /payment-accounts/:id の本文です。指定した項目だけを変えます。
+type UpdatePaymentAccountInput struct {
+	Name           *string `json:"name"`
+	Type           *string `json:"type"`
+	OpeningBalance *int    `json:"opening_balance"`
+}
+
+// PaymentAccountEntriesInput は GET /payment-accounts/:id/entries のクエリパラメータです。
+type PaymentAccountEntriesInput struct {
+	// From・To は YYYY-MM-DD で、どちらもその日を含みます。省略した側は期間を区切りません。
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// PaymentAccountEntriesFilter はリポジトリに渡す期間です。From 以降・To より前の支出を返し、ゼロ値は区切りません。
+type PaymentAccountEntriesFilter struct {
+	From time.Time
+	To   time.Time
+}
+
+// PaymentAccountEntry は支払い手段の明細 1 行です。
+type PaymentAccountEntry struct {
+	ExpenseID int    `json:"expense_id"`
+	SpentAt   string `json:"spent_at"`
+	Amount    int    `json:"amount"`
+	// RefundedAmount は支出の返金の合計です。残高からは Amount から返金を除いた額を差し引きます。返金の無い支出では省略します。
+	RefundedAmount int    `json:"refunded_amount,omitempty"`
+	Memo           string `json:"memo"`
+	// Balance はこの支出（返金を除いた額）を差し引いた後の残高です。
+	Balance int `json:"balance"`
+}
+
+// PaymentMethodTotal は月次集計の、支払い手段の種類 1 つ分の内訳です。
+type PaymentMethodTotal struct {
+	// PaymentMethod は支払い手段の種類です。支払い手段の無い支出は PaymentMethodUnspecified です。
+	PaymentMethod  string `json:"payment_method"`
+	ExpenseCount   int    `json:"expense_count"`
+	ConfirmedTotal int    `json:"confirmed_total"`
+	PlannedTotal   int    `json:"planned_total"`
+}
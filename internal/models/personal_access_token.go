@@ -0,0 +1,27 @@
+package models
+
+// PersonalAccessToken はスクリプトや自動化から API を呼ぶための資格情報です。
+// トークンの平文は発行時の CreatedPersonalAccessToken でのみ返します。
+type PersonalAccessToken struct {
+	ID         int      `json:"id"`
+	UserID     string   `json:"user_id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	RevokedAt  *string  `json:"revoked_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type CreatePersonalAccessTokenInput struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays は有効期限（日数）です。省略時は無期限です。
+	ExpiresInDays *int `json:"expires_in_days"`
+}
+
+// CreatedPersonalAccessToken は発行直後のトークンです。Token は二度と取得できません。
+type CreatedPersonalAccessToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
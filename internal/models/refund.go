@@ -0,0 +1,21 @@
+package models
+
+// ExpenseRefund は支出の返金（返品・注文のキャンセルなど）です。1 件の支出に一部ずつ何度でも記録でき、
+// 合計は支出の金額を超えません。集計では返金を差し引いた額を元の支出の日付で数えます。
+type ExpenseRefund struct {
+	ID         int    `json:"id"`
+	ExpenseID  int    `json:"expense_id"`
+	Amount     int    `json:"amount"`
+	RefundedAt string `json:"refunded_at"`
+	Memo       string `json:"memo"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ExpenseRefundInput は POST /expenses/:id/refunds の本文です。
+type ExpenseRefundInput struct {
+	// Amount は返金額です。省略すると、まだ返金されていない残りの全額です。
+	Amount *int `json:"amount"`
+	// RefundedAt は返金を受けた日（YYYY-MM-DD）です。支出日より前にはできません。
+	RefundedAt string `json:"refunded_at" binding:"required"`
+	Memo       string `json:"memo"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Tag はユーザーごとのタグです。カテゴリをまたぐ支出（旅行・イベントなど）をまとめるために使い、
+// 共有家計簿の支出に付けても他のメンバーには見えません。
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TagInput は POST /tags・PATCH /tags/:id の本文です。
+type TagInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MergeTagInput は POST /tags/:id/merge の本文です。:id のタグを Into のタグに統合します。
+type MergeTagInput struct {
+	Into *int `json:"into" binding:"required"`
+}
+
+// TagReportInput は GET /tags/report のクエリパラメータです。
+type TagReportInput struct {
+	// From・To は YYYY-MM で、どちらもその月を含みます。省略した側は期間を区切りません。
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// TagReportFilter はリポジトリに渡す集計の期間です。From 以降・To より前の支出を集計し、ゼロ値は区切りません。
+type TagReportFilter struct {
+	From time.Time
+	To   time.Time
+}
+
+// TagMonthlyTotal はタグ 1 つの 1 か月分の集計です。複数のタグが付いた支出は、それぞれのタグに数えます。
+type TagMonthlyTotal struct {
+	Tag Tag `json:"tag"`
+	// Month は YYYY-MM です。
+	Month          string `json:"month"`
+	ExpenseCount   int    `json:"expense_count"`
+	ConfirmedTotal int    `json:"confirmed_total"`
+	PlannedTotal   int    `json:"planned_total"`
+}
@@ -6,4 +6,52 @@ type User struct {
 	SavingGoal int    `json:"saving_goal"`
 	CreatedAt  string `json:"created_at"`
 	UpdatedAt  string `json:"updated_at"`
+	// 退会申請中のみ設定される。DeletionScheduledAt を過ぎるとデータが完全に削除される。
+	DeletionRequestedAt *string `json:"deletion_requested_at"`
+	DeletionScheduledAt *string `json:"deletion_scheduled_at"`
+	// SetupCompletedAt は初期設定の完了日時です。未完了なら nil で、クライアントは /setup へ誘導します。
+	SetupCompletedAt *string    `json:"setup_completed_at"`
+	Onboarding       Onboarding `json:"onboarding"`
+}
+
+// オンボーディングの状態
+const (
+	OnboardingStatusNeedsSetup = "needs_setup"
+	OnboardingStatusCompleted  = "completed"
+)
+
+// オンボーディングのステップ。OnboardingSteps の順に案内します。
+const (
+	OnboardingStepSettings     = "settings"      // 収入・貯金目標の設定（初期設定の完了）
+	OnboardingStepLedger       = "ledger"        // 家計簿の作成または参加
+	OnboardingStepFixedCosts   = "fixed_costs"   // 固定費の登録
+	OnboardingStepFirstExpense = "first_expense" // 最初の支出の登録
+)
+
+var OnboardingSteps = []string{
+	OnboardingStepSettings,
+	OnboardingStepLedger,
+	OnboardingStepFixedCosts,
+	OnboardingStepFirstExpense,
+}
+
+// Onboarding は GET /user/me で返すオンボーディングの進み具合です。
+type Onboarding struct {
+	Status         string   `json:"status"`
+	StepsCompleted []string `json:"steps_completed"`
+	StepsRemaining []string `json:"steps_remaining"`
+}
+
+// OnboardingProgress はオンボーディングの各ステップを満たしているかどうかです。
+type OnboardingProgress struct {
+	SetupCompleted bool
+	HasLedger      bool
+	HasFixedCosts  bool
+	HasExpenses    bool
+}
+
+// AccountDeletion は退会申請の状態です。
+type AccountDeletion struct {
+	RequestedAt string `json:"requested_at"`
+	ScheduledAt string `json:"scheduled_at"`
 }
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// AccountDeletionAuditRepository は匿名化した退会記録を保存します。
+type AccountDeletionAuditRepository interface {
+	CreateAudit(ctx context.Context, requestedAt time.Time, purgedRows map[string]int64) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// AdminRepository は運用者向けにユーザーを横断して読み書きします。
+type AdminRepository interface {
+	SearchUsers(ctx context.Context, query string, limit int, offset int) ([]models.AdminUser, error)
+	// GetUser はユーザーが存在しない場合 sql.ErrNoRows を返します。
+	GetUser(ctx context.Context, userID string) (models.AdminUser, error)
+	// SetUserDisabled は利用停止・再開します。ユーザーが存在しない場合は false を返します。
+	SetUserDisabled(ctx context.Context, userID string, disabled bool) (bool, error)
+	// CollectUserData はユーザーが存在しない場合 sql.ErrNoRows を返します。
+	CollectUserData(ctx context.Context, userID string) (models.UserExportData, error)
+	CreateExport(ctx context.Context, userID string, requestedBy string, data models.UserExportData) (models.UserDataExport, error)
+	// GetExport は書き出したデータを中身付きで返します。見つからない場合は sql.ErrNoRows を返します。
+	GetExport(ctx context.Context, id int32) (models.UserDataExport, error)
+}
+
+// AdminAuditLogRepository は運用者の操作記録を保存します。
+type AdminAuditLogRepository interface {
+	CreateLog(ctx context.Context, log models.AdminAuditLog) error
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+// AttachmentRepository は支出の添付ファイルの情報を扱います。ファイルの中身は FileStorage に置きます。
+// ゴミ箱の支出の添付ファイルは扱いません（支出を元に戻すと再び扱えます）。
+type AttachmentRepository interface {
+	// ListAttachments は支出の添付ファイルを追加した順に返します。
+	ListAttachments(ctx context.Context, ledgerID int32, expenseID int32) ([]models.Attachment, error)
+	// GetAttachment は添付ファイルを返します。見つからない場合は sql.ErrNoRows を返します。
+	GetAttachment(ctx context.Context, ledgerID int32, expenseID int32, id int32) (models.Attachment, error)
+	// CreateAttachment は attachment を保存し、ID と作成日時を設定して返します。
+	CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error)
+	// DeleteAttachment は添付ファイルを削除したかどうかを返します。保存先のファイルは孤立したファイルとして記録されます。
+	DeleteAttachment(ctx context.Context, ledgerID int32, expenseID int32, id int32) (bool, error)
+	// RecordUpload は保存先に置く前のファイルのキーを記録します。リクエストがロールバックされても残るよう、
+	// 呼び出し元のトランザクションの外で記録します。
+	RecordUpload(ctx context.Context, key string, at time.Time) error
+	// ConfirmUpload は添付ファイルの行を作成したファイルを RecordUpload の記録から外します。
+	// 行の作成と同じトランザクションで呼んでください。
+	ConfirmUpload(ctx context.Context, key string) error
+	// EnqueueAbandonedUploads は before より前に記録されて確定しなかったファイルを孤立したファイルとして記録し、件数を返します。
+	EnqueueAbandonedUploads(ctx context.Context, before time.Time) (int64, error)
+	// ListOrphanedFiles は添付ファイルが削除されて使われなくなった保存先のキーを、古い順に最大 limit 件返します。
+	ListOrphanedFiles(ctx context.Context, limit int) ([]string, error)
+	// DeleteOrphanedFile は保存先から削除したファイルを孤立したファイルの記録から外します。
+	DeleteOrphanedFile(ctx context.Context, key string) error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// AuditRepository は利用者の更新操作の記録（audit_events）を扱います。
+// CreateEvent は更新と同じトランザクションのコンテキストで呼び出します。
+type AuditRepository interface {
+	CreateEvent(ctx context.Context, event models.AuditEvent) error
+	// ListEvents は userID の操作と、userID が参加している家計簿での操作を新しい順に返します。
+	ListEvents(ctx context.Context, userID string, filter models.AuditEventFilter) ([]models.AuditEvent, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+// DashboardRepository は家計簿（ledgerID）単位の月次集計を扱います。
+type DashboardRepository interface {
+	// MonthlySummary は month を含む月の貯金目標・固定費・支出と、支出の支払い手段の種類ごとの内訳を集計します。
+	// 収入は IncomeRepository.MonthlySummary で集計するため、収入の項目と Month は設定しません。
+	MonthlySummary(ctx context.Context, ledgerID int32, month time.Time) (models.MonthlySummary, error)
+}
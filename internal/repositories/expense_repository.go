@@ -1,12 +1,27 @@
 package repositories
 
-import "money-buddy-backend/internal/models"
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
 
 // ExpenseRepository は経費リポジトリの振る舞いを表します。
+// 経費は家計簿（ledgerID）単位で読み書きし、userID は登録者として記録します。
 type ExpenseRepository interface {
-	CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error)
-	FindAll(userID string) ([]models.Expense, error)
-	GetExpenseByID(userID string, id int32) (models.Expense, error)
-	DeleteExpense(userID string, id int32) error
-	UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error)
+	CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error)
+	// FindAll は filter に一致する支出を filter.Sort の順に最大 filter.Limit 件返します。
+	FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error)
+	// SearchExpenses はメモが filter.Terms をすべて含む支出を一致度の高い順に返します。Highlight は設定しません。
+	SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error)
+	GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error)
+	// DeleteExpense は支出をゴミ箱に移します。ゴミ箱の支出は ExpenseTrashRepository で扱います。
+	// version が nil でなければ、現在のバージョンと一致する場合だけ移します。移さなかった場合は sql.ErrNoRows を返します。
+	DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error
+	// UpdateExpense は支出を更新します。input.Version が nil でなければ、現在のバージョンと一致する場合だけ更新します。
+	// 更新しなかった場合は sql.ErrNoRows を返します。
+	UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error)
+	// ConfirmExpense は planned の支出を confirmed にし、予定していた金額・日付と確定日時を記録します。
+	// 支出が無いか planned でない場合は sql.ErrNoRows を返します。
+	ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error)
 }
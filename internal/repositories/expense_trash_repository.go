@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+// ExpenseTrashRepository はゴミ箱に移した支出を扱います。
+type ExpenseTrashRepository interface {
+	// ListTrashed は家計簿のゴミ箱の支出をゴミ箱に移した日時が新しい順に返します。PurgeAt は設定しません。
+	ListTrashed(ctx context.Context, ledgerID int32) ([]models.TrashedExpense, error)
+	// Restore はゴミ箱の支出を元に戻し、戻した支出を返します。ゴミ箱に無い場合は sql.ErrNoRows を返します。
+	Restore(ctx context.Context, ledgerID int32, id int32) (models.Expense, error)
+	// PurgeTrashed は before より前にゴミ箱に移した支出を家計簿を問わず削除し、削除した件数を返します。
+	PurgeTrashed(ctx context.Context, before time.Time) (int64, error)
+}
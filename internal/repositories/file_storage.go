@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"io"
+)
+
+// FileStorage は添付ファイルの中身の保存先です。ローカルのディレクトリと S3 互換のストレージの実装があります。
+// キーは "/" 区切りの相対パスで、呼び出し元が重ならないように決めます。
+type FileStorage interface {
+	// Put は body を key に保存します。同じキーがあれば上書きします。
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get は key のファイルを返します。呼び出し元が閉じます。無ければ fs.ErrNotExist をラップしたエラーを返します。
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete は key のファイルを削除します。無いファイルの削除はエラーにしません。
+	Delete(ctx context.Context, key string) error
+}
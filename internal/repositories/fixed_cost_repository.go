@@ -6,11 +6,12 @@ import (
 	"money-buddy-backend/internal/models"
 )
 
+// FixedCostRepository は家計簿（ledgerID）単位で固定費を扱います。userID は登録者です。
 type FixedCostRepository interface {
-	CreateFixedCost(ctx context.Context, userID string, name string, amount int) (models.FixedCost, error)
-	ListFixedCostsByUser(ctx context.Context, userID string) ([]models.FixedCost, error)
-	DeleteFixedCostsByUser(ctx context.Context, userID string) error
-	BulkCreateFixedCosts(ctx context.Context, userID string, fixedCosts []models.FixedCostInput) error
-	UpdateFixedCost(ctx context.Context, id int32, userID string, name string, amount int) error
-	DeleteFixedCost(ctx context.Context, id int32, userID string) error
+	CreateFixedCost(ctx context.Context, ledgerID int32, userID string, name string, amount int) (models.FixedCost, error)
+	ListFixedCostsByLedger(ctx context.Context, ledgerID int32) ([]models.FixedCost, error)
+	DeleteFixedCostsByLedger(ctx context.Context, ledgerID int32) error
+	BulkCreateFixedCosts(ctx context.Context, ledgerID int32, userID string, fixedCosts []models.FixedCostInput) error
+	UpdateFixedCost(ctx context.Context, id int32, ledgerID int32, name string, amount int) error
+	DeleteFixedCost(ctx context.Context, id int32, ledgerID int32) error
 }
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+type IdempotencyKeyRepository interface {
+	// GetKey は now の時点で有効期限内のキーを返します。見つからない場合は sql.ErrNoRows を返します。
+	GetKey(ctx context.Context, userID string, key string, now time.Time) (models.IdempotencyKey, error)
+	// SaveKey は保存したかどうかを返します（key.CreatedAt の時点で有効期限内の同じキーが既にある場合は false）。
+	SaveKey(ctx context.Context, key models.IdempotencyKey) (bool, error)
+	// PurgeExpired は before までに期限切れになったキーをユーザーを問わず削除し、削除した件数を返します。
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+// IncomeRepository は家計簿（ledgerID）単位で収入を扱います。userID は受け取ったメンバー（登録者）です。
+type IncomeRepository interface {
+	CreateIncome(ctx context.Context, ledgerID int32, userID string, income models.Income) (models.Income, error)
+	// GetIncome は見つからない場合に sql.ErrNoRows を返します。
+	GetIncome(ctx context.Context, ledgerID int32, id int32) (models.Income, error)
+	// ListIncomes は受け取った日の新しい順に返します。
+	ListIncomes(ctx context.Context, ledgerID int32, filter models.IncomeFilter) ([]models.Income, error)
+	// UpdateIncome は見つからない場合に sql.ErrNoRows を返します。
+	UpdateIncome(ctx context.Context, ledgerID int32, income models.Income) (models.Income, error)
+	// DeleteIncome は削除したかどうかを返します。
+	DeleteIncome(ctx context.Context, ledgerID int32, id int32) (bool, error)
+	// MonthlySummary は month を含む月の収入を集計します。IncomeSummary.Month は設定しません。
+	MonthlySummary(ctx context.Context, ledgerID int32, month time.Time) (models.IncomeSummary, error)
+}
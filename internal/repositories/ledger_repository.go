@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+type LedgerRepository interface {
+	CreateLedger(ctx context.Context, name string) (models.Ledger, error)
+	ListLedgersByUser(ctx context.Context, userID string) ([]models.Ledger, error)
+	// GetLedgerForMember はユーザーがメンバーである家計簿を返します。メンバーでない場合は sql.ErrNoRows を返します。
+	GetLedgerForMember(ctx context.Context, ledgerID int32, userID string) (models.Ledger, error)
+	DeleteLedger(ctx context.Context, ledgerID int32) error
+
+	// AddMember は追加したかどうかを返します（既にメンバーの場合は false）。
+	AddMember(ctx context.Context, ledgerID int32, userID string, role string) (bool, error)
+	// RemoveMember は削除したかどうかを返します（メンバーでない場合は false）。
+	RemoveMember(ctx context.Context, ledgerID int32, userID string) (bool, error)
+	CountMembers(ctx context.Context, ledgerID int32) (int, error)
+	ListMembers(ctx context.Context, ledgerID int32) ([]models.LedgerMember, error)
+	// GetMemberRole はメンバーのロールを返します。メンバーでない場合は sql.ErrNoRows を返します。
+	GetMemberRole(ctx context.Context, ledgerID int32, userID string) (string, error)
+	// UpdateMemberRole はメンバーのロールを変更します。メンバーでない場合は sql.ErrNoRows を返します。
+	UpdateMemberRole(ctx context.Context, ledgerID int32, userID string, role string) (models.LedgerMember, error)
+
+	// GetActiveLedgerID はユーザーが現在使っている家計簿の ID を返します。未選択の場合は sql.ErrNoRows を返します。
+	GetActiveLedgerID(ctx context.Context, userID string) (int32, error)
+	SetActiveLedger(ctx context.Context, userID string, ledgerID int32) error
+	// ReassignActiveLedger は ledgerID がアクティブな場合に、残っている家計簿へ切り替えます。
+	ReassignActiveLedger(ctx context.Context, userID string, ledgerID int32) error
+
+	CreateInvite(ctx context.Context, ledgerID int32, createdBy string, codeHash string, expiresAt time.Time) error
+	// ClaimInvite は招待コードを now（UTC）の時点で使用済みにし、家計簿の ID を返します。
+	// 存在しない・期限切れ・使用済みの場合は sql.ErrNoRows を返します。
+	ClaimInvite(ctx context.Context, codeHash string, userID string, now time.Time) (int32, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+type LocalAccountRepository interface {
+	// CreateAccount はメールアドレスが登録済みの場合 false を返します。
+	CreateAccount(ctx context.Context, userID string, email string, passwordHash string) (bool, error)
+	// GetAccountByEmail は見つからない場合 sql.ErrNoRows を返します。
+	GetAccountByEmail(ctx context.Context, email string) (models.LocalAccount, error)
+	// GetAccountByUserID は見つからない場合 sql.ErrNoRows を返します。
+	GetAccountByUserID(ctx context.Context, userID string) (models.LocalAccount, error)
+	UpdatePassword(ctx context.Context, userID string, passwordHash string) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// PayeeRepository はユーザーごとの支払先と、支出への支払先の付け外しを扱います。
+// 支払先は userID のユーザーのものだけを読み書きします。
+type PayeeRepository interface {
+	// ListPayeeSuggestions は名前が prefix で始まる userID の支払先を、よく・最近使ったものから最大 limit 件返します。
+	ListPayeeSuggestions(ctx context.Context, userID string, prefix string, limit int32) ([]models.PayeeSuggestion, error)
+	// ListPayeesByIDs は ids のうち userID の支払先を返します。
+	ListPayeesByIDs(ctx context.Context, userID string, ids []int32) ([]models.Payee, error)
+	// CreatePayee は作成したかどうかを返します（同じ名前の支払先が既にある場合は false）。
+	CreatePayee(ctx context.Context, userID string, name string) (models.Payee, bool, error)
+	// RenamePayee は支払先の名前を変えます。支払先が無いか、同じ名前の別の支払先がある場合は sql.ErrNoRows を返します。
+	RenamePayee(ctx context.Context, userID string, id int32, name string) (models.Payee, error)
+	// DeletePayee は削除したかどうかを返します。支払先は付いていたすべての支出から外れます。
+	DeletePayee(ctx context.Context, userID string, id int32) (bool, error)
+	// MergePayeeLinks は sourceID の支払先が付いた支出の支払先を targetID にします。sourceID の支払先は残します。
+	MergePayeeLinks(ctx context.Context, sourceID int32, targetID int32) error
+	// ListExpensePayees は expenseIDs の支出の支払先を、支出の ID ごとに返します。他のユーザーの支払先も含みます。
+	ListExpensePayees(ctx context.Context, expenseIDs []int32) (map[int32]models.Payee, error)
+	// SetExpensePayee は支出の支払先を payeeID にします。nil なら支払先を外します。
+	SetExpensePayee(ctx context.Context, expenseID int32, payeeID *int32) error
+	// Totals は ledgerID の家計簿の支出を支払先ごとに集計します。
+	Totals(ctx context.Context, ledgerID int32, filter models.PayeeTotalsFilter) ([]models.PayeeTotal, error)
+}
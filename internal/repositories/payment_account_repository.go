@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// PaymentAccountRepository はユーザーごとの支払い手段と、支出への支払い手段の付け外しを扱います。
+// 支払い手段は userID のユーザーのものだけを読み書きします。
+type PaymentAccountRepository interface {
+	// ListPaymentAccounts は userID の支払い手段を残高とともに名前順で返します。
+	ListPaymentAccounts(ctx context.Context, userID string) ([]models.PaymentAccount, error)
+	// GetPaymentAccount は見つからない場合に sql.ErrNoRows を返します。
+	GetPaymentAccount(ctx context.Context, userID string, id int32) (models.PaymentAccount, error)
+	// CreatePaymentAccount は作成した支払い手段の ID と、作成したかどうかを返します（同じ名前の支払い手段が既にある場合は false）。
+	CreatePaymentAccount(ctx context.Context, userID string, account models.PaymentAccount) (int32, bool, error)
+	// UpdatePaymentAccount は名前・種類・開始残高を変え、変えたかどうかを返します。
+	// 支払い手段が無いか、同じ名前の別の支払い手段がある場合は false です。
+	UpdatePaymentAccount(ctx context.Context, userID string, account models.PaymentAccount) (bool, error)
+	// DeletePaymentAccount は削除したかどうかを返します。支払い手段は付いていたすべての支出から外れます。
+	DeletePaymentAccount(ctx context.Context, userID string, id int32) (bool, error)
+	// ListExpensePaymentAccounts は expenseIDs の支出の支払い手段を、支出の ID ごとに返します。他のユーザーの支払い手段も含みます。
+	ListExpensePaymentAccounts(ctx context.Context, expenseIDs []int32) (map[int32]models.ExpensePaymentAccount, error)
+	// SetExpensePaymentAccount は支出の支払い手段を accountID にします。nil なら支払い手段を外します。
+	SetExpensePaymentAccount(ctx context.Context, expenseID int32, accountID *int32) error
+	// ListEntries は userID の支払い手段 id で払った確定済みの支出を、支出日の順に残高とともに返します。
+	ListEntries(ctx context.Context, userID string, id int32, filter models.PaymentAccountEntriesFilter) ([]models.PaymentAccountEntry, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+type PersonalAccessTokenRepository interface {
+	CreateToken(ctx context.Context, userID string, name string, tokenHash string, scopes []string, expiresAt *time.Time) (models.PersonalAccessToken, error)
+	ListTokensByUser(ctx context.Context, userID string) ([]models.PersonalAccessToken, error)
+	// GetActiveTokenByHash は失効・期限切れでないトークンを返します。見つからない場合は sql.ErrNoRows を返します。
+	GetActiveTokenByHash(ctx context.Context, tokenHash string) (models.PersonalAccessToken, error)
+	// RevokeToken は失効させたかどうかを返します（存在しない・失効済みの場合は false）。
+	RevokeToken(ctx context.Context, id int32, userID string) (bool, error)
+	TouchToken(ctx context.Context, id int32) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+type RefreshTokenRepository interface {
+	CreateToken(ctx context.Context, userID string, familyID string, tokenHash string, expiresAt time.Time) error
+	// GetTokenByHashForUpdate は失効済み・期限切れのトークンも返し、トランザクションの終わりまで行をロックします。
+	// 見つからない場合は sql.ErrNoRows を返します。
+	GetTokenByHashForUpdate(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	RevokeToken(ctx context.Context, id int32) error
+	// RevokeFamily は同じログインから発行されたトークンをすべて失効させます。
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeTokensByUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// RefundRepository は支出の返金を扱います。返金の合計は支出の refunded_amount にも記録し、
+// 支出の金額を超えないようにします。ゴミ箱の支出の返金は扱いません。
+// 返金の記録・削除は支出も更新するため、トランザクションの中で呼び出してください。
+type RefundRepository interface {
+	// ListRefunds は支出の返金を返金日の順に返します。
+	ListRefunds(ctx context.Context, ledgerID int32, expenseID int32) ([]models.ExpenseRefund, error)
+	// CreateRefund は返金を記録し、支出の返金の合計に加えて支出のバージョンを上げます。
+	// 支出が無いか確定済みでない場合、返金の合計が支出の金額を超える場合は記録せずに false を返します。
+	CreateRefund(ctx context.Context, ledgerID int32, refund models.ExpenseRefund) (models.ExpenseRefund, bool, error)
+	// DeleteRefund は返金を削除し、支出の返金の合計から差し引いて支出のバージョンを上げます。削除したかどうかを返します。
+	DeleteRefund(ctx context.Context, ledgerID int32, expenseID int32, id int32) (bool, error)
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// TagRepository はユーザーごとのタグと、支出へのタグの付け外しを扱います。
+// タグは userID のユーザーのものだけを読み書きします。
+type TagRepository interface {
+	ListTags(ctx context.Context, userID string) ([]models.Tag, error)
+	// ListTagsByIDs は ids のうち userID のタグを返します。
+	ListTagsByIDs(ctx context.Context, userID string, ids []int32) ([]models.Tag, error)
+	// CreateTag は作成したかどうかを返します（同じ名前のタグが既にある場合は false）。
+	CreateTag(ctx context.Context, userID string, name string) (models.Tag, bool, error)
+	// RenameTag はタグの名前を変えます。タグが無いか、同じ名前の別のタグがある場合は sql.ErrNoRows を返します。
+	RenameTag(ctx context.Context, userID string, id int32, name string) (models.Tag, error)
+	// DeleteTag は削除したかどうかを返します。タグは付いていたすべての支出から外れます。
+	DeleteTag(ctx context.Context, userID string, id int32) (bool, error)
+	// MergeTagLinks は sourceID のタグが付いた支出に targetID のタグを付けます。sourceID のタグは残します。
+	MergeTagLinks(ctx context.Context, sourceID int32, targetID int32) error
+	// ListExpenseTags は expenseIDs の支出に userID が付けたタグを、支出の ID ごとに返します。
+	ListExpenseTags(ctx context.Context, userID string, expenseIDs []int32) (map[int32][]models.Tag, error)
+	// SetExpenseTags は userID がその支出に付けたタグを tagIDs に置き換えます。他のユーザーのタグは変えません。
+	SetExpenseTags(ctx context.Context, userID string, expenseID int32, tagIDs []int32) error
+	// MonthlyTotals は ledgerID の家計簿の支出を、userID のタグと月ごとに集計します。
+	MonthlyTotals(ctx context.Context, userID string, ledgerID int32, filter models.TagReportFilter) ([]models.TagMonthlyTotal, error)
+}
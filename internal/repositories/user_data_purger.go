@@ -0,0 +1,12 @@
+package repositories
+
+import "context"
+
+// UserDataPurger はアカウント削除時にユーザーに紐づくデータを消去します。
+// ユーザー単位のテーブルを追加したら、対応する Purger を登録してください。
+type UserDataPurger interface {
+	// Name は退会記録に残すデータ種別名です。
+	Name() string
+	// PurgeUserData は userID のデータをすべて削除し、削除した件数を返します。
+	PurgeUserData(ctx context.Context, userID string) (int64, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"money-buddy-backend/internal/models"
 )
@@ -10,4 +11,20 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, id string, income int, savingGoal int) error
 	GetUserByID(ctx context.Context, id string) (models.User, error)
 	UpdateUserSettings(ctx context.Context, id string, income int, savingGoal int) error
+	// ProvisionUser はユーザーが存在しなければ作成します。作成した場合は true を返します。
+	ProvisionUser(ctx context.Context, id string) (bool, error)
+	MarkSetupCompleted(ctx context.Context, id string) error
+	// GetOnboardingProgress はユーザーが存在しない場合 sql.ErrNoRows を返します。
+	GetOnboardingProgress(ctx context.Context, id string) (models.OnboardingProgress, error)
+	// RequestDeletion は退会を申請します。既に申請済みの場合は false を返します。
+	RequestDeletion(ctx context.Context, id string, scheduledAt time.Time) (bool, error)
+	// CancelDeletion は退会申請を取り消します。申請が無い場合は false を返します。
+	CancelDeletion(ctx context.Context, id string) (bool, error)
+	ListUsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error)
+	// LockUserForDeletion は削除期限を過ぎたユーザーの行をロックし、申請日時を返します。
+	// 取り消し済み・期限前の場合は sql.ErrNoRows を返します。
+	LockUserForDeletion(ctx context.Context, id string, now time.Time) (time.Time, error)
+	DeleteUser(ctx context.Context, id string) error
+	// IsDisabled は運用者が利用を停止しているかどうかを返します。ユーザーが存在しない場合は sql.ErrNoRows を返します。
+	IsDisabled(ctx context.Context, id string) (bool, error)
 }
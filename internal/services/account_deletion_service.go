@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// purgeBatchSize は 1 回の削除ジョブで処理するユーザー数の上限です。
+const purgeBatchSize = 100
+
+type AccountDeletionService interface {
+	// RequestDeletion は猶予期間後の退会を予約します。既に申請済みの場合は現在の予約を返します。
+	RequestDeletion(ctx context.Context, userID string) (models.AccountDeletion, error)
+	// CancelDeletion は猶予期間中の退会申請を取り消します。
+	CancelDeletion(ctx context.Context, userID string) error
+	// PurgeDueAccounts は猶予期間を過ぎたユーザーのデータを削除し、削除したユーザー数を返します。
+	PurgeDueAccounts(ctx context.Context) (int, error)
+}
+
+type accountDeletionService struct {
+	userRepo    repositories.UserRepository
+	auditRepo   repositories.AccountDeletionAuditRepository
+	purgers     []repositories.UserDataPurger
+	txManager   TxManager
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+func NewAccountDeletionService(userRepo repositories.UserRepository, auditRepo repositories.AccountDeletionAuditRepository, purgers []repositories.UserDataPurger, txManager TxManager, gracePeriod time.Duration) AccountDeletionService {
+	return &accountDeletionService{
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+		purgers:     purgers,
+		txManager:   txManager,
+		gracePeriod: gracePeriod,
+		now:         time.Now,
+	}
+}
+
+func (s *accountDeletionService) RequestDeletion(ctx context.Context, userID string) (models.AccountDeletion, error) {
+	scheduledAt := s.now().UTC().Add(s.gracePeriod)
+	if _, err := s.userRepo.RequestDeletion(ctx, userID, scheduledAt); err != nil {
+		return models.AccountDeletion{}, &InternalError{Message: "internal error"}
+	}
+
+	// 申請済みだった場合も含め、保存されている予約内容を返す
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AccountDeletion{}, &NotFoundError{Message: "user not found"}
+		}
+		return models.AccountDeletion{}, &InternalError{Message: "internal error"}
+	}
+	if user.DeletionRequestedAt == nil || user.DeletionScheduledAt == nil {
+		return models.AccountDeletion{}, &InternalError{Message: "internal error"}
+	}
+
+	return models.AccountDeletion{
+		RequestedAt: *user.DeletionRequestedAt,
+		ScheduledAt: *user.DeletionScheduledAt,
+	}, nil
+}
+
+func (s *accountDeletionService) CancelDeletion(ctx context.Context, userID string) error {
+	cancelled, err := s.userRepo.CancelDeletion(ctx, userID)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !cancelled {
+		return &NotFoundError{Message: "no pending account deletion"}
+	}
+	return nil
+}
+
+func (s *accountDeletionService) PurgeDueAccounts(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	userIDs, err := s.listDueUsers(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		ok, err := s.purgeAccount(ctx, userID, now)
+		if err != nil {
+			return purged, err
+		}
+		if ok {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// listDueUsers は削除予定日時を過ぎたユーザーを取得します。
+// users には行レベルセキュリティが掛かっているため、一覧の取得もトランザクション内で行います。
+func (s *accountDeletionService) listDueUsers(ctx context.Context, now time.Time) ([]string, error) {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs, err := s.userRepo.ListUsersDueForDeletion(tx.Context(ctx), now, purgeBatchSize)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// purgeAccount は 1 ユーザー分のデータを 1 つのトランザクションで削除し、匿名の退会記録を残します。
+// 取り消し済みなど削除対象でなくなっていた場合は false を返します。
+func (s *accountDeletionService) purgeAccount(ctx context.Context, userID string, now time.Time) (bool, error) {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	txCtx := tx.Context(ctx)
+
+	// 一覧取得後に取り消された場合に備え、行ロックを取って再確認する
+	requestedAt, err := s.userRepo.LockUserForDeletion(txCtx, userID, now)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	purgedRows := make(map[string]int64, len(s.purgers))
+	for _, p := range s.purgers {
+		n, err := p.PurgeUserData(txCtx, userID)
+		if err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
+		purgedRows[p.Name()] = n
+	}
+
+	if err := s.userRepo.DeleteUser(txCtx, userID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+	if err := s.auditRepo.CreateAudit(txCtx, requestedAt, purgedRows); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
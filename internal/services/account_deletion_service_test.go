@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+type auditRepoMock struct{ mock.Mock }
+
+func (m *auditRepoMock) CreateAudit(ctx context.Context, requestedAt time.Time, purgedRows map[string]int64) error {
+	args := m.Called(ctx, requestedAt, purgedRows)
+	return args.Error(0)
+}
+
+type purgerMock struct {
+	mock.Mock
+	name string
+}
+
+func (m *purgerMock) Name() string { return m.name }
+
+func (m *purgerMock) PurgeUserData(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestAccountDeletionService_RequestDeletion(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	ur := new(userRepoMock)
+	ur.On("RequestDeletion", ctx, "user-1", now.Add(30*24*time.Hour)).Return(true, nil)
+	ur.On("GetUserByID", ctx, "user-1").Return(models.User{
+		ID:                  "user-1",
+		DeletionRequestedAt: strPtr("2025-03-01T09:00:00Z"),
+		DeletionScheduledAt: strPtr("2025-03-31T09:00:00Z"),
+	}, nil)
+
+	s := NewAccountDeletionService(ur, new(auditRepoMock), nil, new(txManagerMock), 30*24*time.Hour).(*accountDeletionService)
+	s.now = func() time.Time { return now }
+
+	got, err := s.RequestDeletion(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-31T09:00:00Z", got.ScheduledAt)
+	ur.AssertExpectations(t)
+}
+
+func TestAccountDeletionService_RequestDeletion_UserNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	ur := new(userRepoMock)
+	ur.On("RequestDeletion", ctx, "ghost", mock.Anything).Return(false, nil)
+	ur.On("GetUserByID", ctx, "ghost").Return(nil, sql.ErrNoRows)
+
+	s := NewAccountDeletionService(ur, new(auditRepoMock), nil, new(txManagerMock), time.Hour)
+
+	_, err := s.RequestDeletion(ctx, "ghost")
+	var ne *NotFoundError
+	assert.ErrorAs(t, err, &ne)
+}
+
+func TestAccountDeletionService_CancelDeletion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("申請中なら取り消せる", func(t *testing.T) {
+		ur := new(userRepoMock)
+		ur.On("CancelDeletion", ctx, "user-1").Return(true, nil)
+		s := NewAccountDeletionService(ur, new(auditRepoMock), nil, new(txManagerMock), time.Hour)
+
+		assert.NoError(t, s.CancelDeletion(ctx, "user-1"))
+	})
+
+	t.Run("申請が無ければ NotFoundError", func(t *testing.T) {
+		ur := new(userRepoMock)
+		ur.On("CancelDeletion", ctx, "user-1").Return(false, nil)
+		s := NewAccountDeletionService(ur, new(auditRepoMock), nil, new(txManagerMock), time.Hour)
+
+		var ne *NotFoundError
+		assert.ErrorAs(t, s.CancelDeletion(ctx, "user-1"), &ne)
+	})
+}
+
+func TestAccountDeletionService_PurgeDueAccounts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	requestedAt := now.Add(-31 * 24 * time.Hour)
+
+	ur := new(userRepoMock)
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	ar := new(auditRepoMock)
+	expenses := &purgerMock{name: "expenses"}
+	fixedCosts := &purgerMock{name: "fixed_costs"}
+
+	listTx := new(txMock)
+	tm.On("Begin", ctx).Return(listTx, nil).Once()
+	ur.On("ListUsersDueForDeletion", ctx, now, purgeBatchSize).Return([]string{"user-1", "user-2"}, nil)
+	listTx.On("Commit").Return(nil).Once()
+
+	// user-1 は削除される
+	tm.On("Begin", ctx).Return(tx, nil)
+	ur.On("LockUserForDeletion", ctx, "user-1", now).Return(requestedAt, nil)
+	expenses.On("PurgeUserData", ctx, "user-1").Return(int64(12), nil)
+	fixedCosts.On("PurgeUserData", ctx, "user-1").Return(int64(3), nil)
+	ur.On("DeleteUser", ctx, "user-1").Return(nil)
+	ar.On("CreateAudit", ctx, requestedAt, map[string]int64{"expenses": 12, "fixed_costs": 3}).Return(nil)
+	tx.On("Commit").Return(nil).Once()
+
+	// user-2 は一覧取得後に取り消された
+	ur.On("LockUserForDeletion", ctx, "user-2", now).Return(nil, sql.ErrNoRows)
+	tx.On("Rollback").Return(nil).Once()
+
+	s := NewAccountDeletionService(ur, ar, []repositories.UserDataPurger{expenses, fixedCosts}, tm, 30*24*time.Hour).(*accountDeletionService)
+	s.now = func() time.Time { return now }
+
+	n, err := s.PurgeDueAccounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	ur.AssertExpectations(t)
+	ar.AssertExpectations(t)
+	listTx.AssertExpectations(t)
+	tx.AssertExpectations(t)
+	expenses.AssertNotCalled(t, "PurgeUserData", ctx, "user-2")
+}
+
+func TestAccountDeletionService_PurgeDueAccounts_RollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	ur := new(userRepoMock)
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	expenses := &purgerMock{name: "expenses"}
+	fixedCosts := &purgerMock{name: "fixed_costs"}
+
+	listTx := new(txMock)
+	tm.On("Begin", ctx).Return(listTx, nil).Once()
+	ur.On("ListUsersDueForDeletion", ctx, now, purgeBatchSize).Return([]string{"user-1"}, nil)
+	listTx.On("Commit").Return(nil).Once()
+	tm.On("Begin", ctx).Return(tx, nil)
+	ur.On("LockUserForDeletion", ctx, "user-1", now).Return(now.Add(-time.Hour), nil)
+	expenses.On("PurgeUserData", ctx, "user-1").Return(int64(12), nil)
+	fixedCosts.On("PurgeUserData", ctx, "user-1").Return(int64(0), errors.New("db down"))
+	tx.On("Rollback").Return(nil).Once()
+
+	s := NewAccountDeletionService(ur, new(auditRepoMock), []repositories.UserDataPurger{expenses, fixedCosts}, tm, time.Hour).(*accountDeletionService)
+	s.now = func() time.Time { return now }
+
+	_, err := s.PurgeDueAccounts(ctx)
+	require.Error(t, err)
+
+	tx.AssertExpectations(t)
+	tx.AssertNotCalled(t, "Commit")
+	ur.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+const (
+	// AdminSearchDefaultLimit はユーザー検索で limit を省略したときの件数です。
+	AdminSearchDefaultLimit = 50
+	// AdminSearchMaxLimit はユーザー検索で一度に返す最大件数です。
+	AdminSearchMaxLimit = 100
+)
+
+// AdminService は運用者向けの操作です。すべての操作は運用者名とともに admin_audit_logs に記録します。
+type AdminService interface {
+	SearchUsers(ctx context.Context, operator string, input models.AdminUserSearchInput) ([]models.AdminUser, error)
+	GetUser(ctx context.Context, operator string, userID string) (models.AdminUser, error)
+	// SetUserDisabled はアカウントの利用を停止・再開し、更新後の状態を返します。
+	SetUserDisabled(ctx context.Context, operator string, userID string, disabled bool) (models.AdminUser, error)
+	// ExportUserData は問い合わせ対応のためにユーザーのデータを書き出します。
+	ExportUserData(ctx context.Context, operator string, userID string) (models.UserDataExport, error)
+	GetExport(ctx context.Context, operator string, exportID int) (models.UserDataExport, error)
+}
+
+type adminService struct {
+	repo      repositories.AdminRepository
+	auditRepo repositories.AdminAuditLogRepository
+	txManager TxManager
+}
+
+func NewAdminService(repo repositories.AdminRepository, auditRepo repositories.AdminAuditLogRepository, txManager TxManager) AdminService {
+	return &adminService{repo: repo, auditRepo: auditRepo, txManager: txManager}
+}
+
+func (s *adminService) SearchUsers(ctx context.Context, operator string, input models.AdminUserSearchInput) ([]models.AdminUser, error) {
+	if input.Limit < 0 || input.Limit > AdminSearchMaxLimit {
+		return nil, &ValidationError{Message: "limit must be between 1 and 100"}
+	}
+	if input.Limit == 0 {
+		input.Limit = AdminSearchDefaultLimit
+	}
+	if input.Offset < 0 {
+		return nil, &ValidationError{Message: "offset must not be negative"}
+	}
+
+	var users []models.AdminUser
+	err := s.audited(ctx, func(txCtx context.Context) (models.AdminAuditLog, error) {
+		var err error
+		users, err = s.repo.SearchUsers(txCtx, input.Query, input.Limit, input.Offset)
+		if err != nil {
+			return models.AdminAuditLog{}, &InternalError{Message: "internal error"}
+		}
+		return models.AdminAuditLog{
+			Operator: operator,
+			Action:   models.AdminActionSearchUsers,
+			Detail:   map[string]any{"q": input.Query, "limit": input.Limit, "offset": input.Offset},
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (s *adminService) GetUser(ctx context.Context, operator string, userID string) (models.AdminUser, error) {
+	var user models.AdminUser
+	err := s.audited(ctx, func(txCtx context.Context) (models.AdminAuditLog, error) {
+		var err error
+		user, err = s.getUser(txCtx, userID)
+		if err != nil {
+			return models.AdminAuditLog{}, err
+		}
+		return models.AdminAuditLog{Operator: operator, Action: models.AdminActionViewUser, TargetUserID: userID}, nil
+	})
+	if err != nil {
+		return models.AdminUser{}, err
+	}
+	return user, nil
+}
+
+func (s *adminService) SetUserDisabled(ctx context.Context, operator string, userID string, disabled bool) (models.AdminUser, error) {
+	action := models.AdminActionEnableUser
+	if disabled {
+		action = models.AdminActionDisableUser
+	}
+
+	var user models.AdminUser
+	err := s.audited(ctx, func(txCtx context.Context) (models.AdminAuditLog, error) {
+		found, err := s.repo.SetUserDisabled(txCtx, userID, disabled)
+		if err != nil {
+			return models.AdminAuditLog{}, &InternalError{Message: "internal error"}
+		}
+		if !found {
+			return models.AdminAuditLog{}, &NotFoundError{Message: "user not found"}
+		}
+		user, err = s.getUser(txCtx, userID)
+		if err != nil {
+			return models.AdminAuditLog{}, err
+		}
+		return models.AdminAuditLog{Operator: operator, Action: action, TargetUserID: userID}, nil
+	})
+	if err != nil {
+		return models.AdminUser{}, err
+	}
+	return user, nil
+}
+
+func (s *adminService) ExportUserData(ctx context.Context, operator string, userID string) (models.UserDataExport, error) {
+	var export models.UserDataExport
+	err := s.audited(ctx, func(txCtx context.Context) (models.AdminAuditLog, error) {
+		data, err := s.repo.CollectUserData(txCtx, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.AdminAuditLog{}, &NotFoundError{Message: "user not found"}
+			}
+			return models.AdminAuditLog{}, &InternalError{Message: "internal error"}
+		}
+		export, err = s.repo.CreateExport(txCtx, userID, operator, data)
+		if err != nil {
+			return models.AdminAuditLog{}, &InternalError{Message: "internal error"}
+		}
+		return models.AdminAuditLog{
+			Operator:     operator,
+			Action:       models.AdminActionExportUser,
+			TargetUserID: userID,
+			Detail:       map[string]any{"export_id": export.ID},
+		}, nil
+	})
+	if err != nil {
+		return models.UserDataExport{}, err
+	}
+	return export, nil
+}
+
+func (s *adminService) GetExport(ctx context.Context, operator string, exportID int) (models.UserDataExport, error) {
+	var export models.UserDataExport
+	err := s.audited(ctx, func(txCtx context.Context) (models.AdminAuditLog, error) {
+		var err error
+		export, err = s.repo.GetExport(txCtx, int32(exportID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.AdminAuditLog{}, &NotFoundError{Message: "export not found"}
+			}
+			return models.AdminAuditLog{}, &InternalError{Message: "internal error"}
+		}
+		return models.AdminAuditLog{
+			Operator:     operator,
+			Action:       models.AdminActionViewExport,
+			TargetUserID: export.UserID,
+			Detail:       map[string]any{"export_id": export.ID},
+		}, nil
+	})
+	if err != nil {
+		return models.UserDataExport{}, err
+	}
+	return export, nil
+}
+
+func (s *adminService) getUser(ctx context.Context, userID string) (models.AdminUser, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AdminUser{}, &NotFoundError{Message: "user not found"}
+		}
+		return models.AdminUser{}, &InternalError{Message: "internal error"}
+	}
+	return user, nil
+}
+
+// audited は fn とその操作記録を 1 つのトランザクションで実行します。
+// fn は成功した場合に記録する内容を返します。失敗した操作は記録しません。
+func (s *adminService) audited(ctx context.Context, fn func(txCtx context.Context) (models.AdminAuditLog, error)) error {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+
+	txCtx := tx.Context(ctx)
+
+	log, err := fn(txCtx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := s.auditRepo.CreateLog(txCtx, log); err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type adminRepoMock struct{ mock.Mock }
+
+type adminAuditLogRepoMock struct{ mock.Mock }
+
+func (m *adminRepoMock) SearchUsers(ctx context.Context, query string, limit int, offset int) ([]models.AdminUser, error) {
+	args := m.Called(ctx, query, limit, offset)
+	if list, ok := args.Get(0).([]models.AdminUser); ok {
+		return list, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *adminRepoMock) GetUser(ctx context.Context, userID string) (models.AdminUser, error) {
+	args := m.Called(ctx, userID)
+	if u, ok := args.Get(0).(models.AdminUser); ok {
+		return u, args.Error(1)
+	}
+	return models.AdminUser{}, args.Error(1)
+}
+
+func (m *adminRepoMock) SetUserDisabled(ctx context.Context, userID string, disabled bool) (bool, error) {
+	args := m.Called(ctx, userID, disabled)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *adminRepoMock) CollectUserData(ctx context.Context, userID string) (models.UserExportData, error) {
+	args := m.Called(ctx, userID)
+	if d, ok := args.Get(0).(models.UserExportData); ok {
+		return d, args.Error(1)
+	}
+	return models.UserExportData{}, args.Error(1)
+}
+
+func (m *adminRepoMock) CreateExport(ctx context.Context, userID string, requestedBy string, data models.UserExportData) (models.UserDataExport, error) {
+	args := m.Called(ctx, userID, requestedBy, data)
+	if e, ok := args.Get(0).(models.UserDataExport); ok {
+		return e, args.Error(1)
+	}
+	return models.UserDataExport{}, args.Error(1)
+}
+
+func (m *adminRepoMock) GetExport(ctx context.Context, id int32) (models.UserDataExport, error) {
+	args := m.Called(ctx, id)
+	if e, ok := args.Get(0).(models.UserDataExport); ok {
+		return e, args.Error(1)
+	}
+	return models.UserDataExport{}, args.Error(1)
+}
+
+func (m *adminAuditLogRepoMock) CreateLog(ctx context.Context, log models.AdminAuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func TestAdminService_SearchUsers(t *testing.T) {
+	ctx := context.Background()
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	ar := new(adminRepoMock)
+	lr := new(adminAuditLogRepoMock)
+
+	tm.On("Begin", ctx).Return(tx, nil)
+	ar.On("SearchUsers", ctx, "abc", AdminSearchDefaultLimit, 10).Return([]models.AdminUser{{ID: "abc-1"}}, nil)
+	lr.On("CreateLog", ctx, models.AdminAuditLog{
+		Operator: "alice",
+		Action:   models.AdminActionSearchUsers,
+		Detail:   map[string]any{"q": "abc", "limit": AdminSearchDefaultLimit, "offset": 10},
+	}).Return(nil)
+	tx.On("Commit").Return(nil)
+
+	s := NewAdminService(ar, lr, tm)
+	got, err := s.SearchUsers(ctx, "alice", models.AdminUserSearchInput{Query: "abc", Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []models.AdminUser{{ID: "abc-1"}}, got)
+	ar.AssertExpectations(t)
+	lr.AssertExpectations(t)
+	tx.AssertExpectations(t)
+}
+
+func TestAdminService_SearchUsers_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		input models.AdminUserSearchInput
+	}{
+		{name: "limit が負", input: models.AdminUserSearchInput{Limit: -1}},
+		{name: "limit が上限超過", input: models.AdminUserSearchInput{Limit: AdminSearchMaxLimit + 1}},
+		{name: "offset が負", input: models.AdminUserSearchInput{Offset: -1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := new(txManagerMock)
+			s := NewAdminService(new(adminRepoMock), new(adminAuditLogRepoMock), tm)
+
+			_, err := s.SearchUsers(context.Background(), "alice", tc.input)
+
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			tm.AssertNotCalled(t, "Begin", mock.Anything)
+		})
+	}
+}
+
+func TestAdminService_SetUserDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("利用を停止して記録する", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		ar := new(adminRepoMock)
+		lr := new(adminAuditLogRepoMock)
+
+		tm.On("Begin", ctx).Return(tx, nil)
+		ar.On("SetUserDisabled", ctx, "user-1", true).Return(true, nil)
+		ar.On("GetUser", ctx, "user-1").Return(models.AdminUser{ID: "user-1", Disabled: true}, nil)
+		lr.On("CreateLog", ctx, models.AdminAuditLog{Operator: "alice", Action: models.AdminActionDisableUser, TargetUserID: "user-1"}).Return(nil)
+		tx.On("Commit").Return(nil)
+
+		s := NewAdminService(ar, lr, tm)
+		got, err := s.SetUserDisabled(ctx, "alice", "user-1", true)
+		require.NoError(t, err)
+		assert.True(t, got.Disabled)
+		lr.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("存在しないユーザーは記録しない", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		ar := new(adminRepoMock)
+		lr := new(adminAuditLogRepoMock)
+
+		tm.On("Begin", ctx).Return(tx, nil)
+		ar.On("SetUserDisabled", ctx, "missing", false).Return(false, nil)
+		tx.On("Rollback").Return(nil)
+
+		s := NewAdminService(ar, lr, tm)
+		_, err := s.SetUserDisabled(ctx, "alice", "missing", false)
+
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		lr.AssertNotCalled(t, "CreateLog", mock.Anything, mock.Anything)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("記録に失敗したら操作も取り消す", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		ar := new(adminRepoMock)
+		lr := new(adminAuditLogRepoMock)
+
+		tm.On("Begin", ctx).Return(tx, nil)
+		ar.On("SetUserDisabled", ctx, "user-1", true).Return(true, nil)
+		ar.On("GetUser", ctx, "user-1").Return(models.AdminUser{ID: "user-1", Disabled: true}, nil)
+		lr.On("CreateLog", ctx, mock.Anything).Return(errors.New("db down"))
+		tx.On("Rollback").Return(nil)
+
+		s := NewAdminService(ar, lr, tm)
+		_, err := s.SetUserDisabled(ctx, "alice", "user-1", true)
+
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+	})
+}
+
+func TestAdminService_ExportUserData(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("書き出して記録する", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		ar := new(adminRepoMock)
+		lr := new(adminAuditLogRepoMock)
+		data := models.UserExportData{UserID: "user-1", Income: 300000}
+
+		tm.On("Begin", ctx).Return(tx, nil)
+		ar.On("CollectUserData", ctx, "user-1").Return(data, nil)
+		ar.On("CreateExport", ctx, "user-1", "alice", data).Return(models.UserDataExport{ID: 9, UserID: "user-1", RequestedBy: "alice"}, nil)
+		lr.On("CreateLog", ctx, models.AdminAuditLog{
+			Operator:     "alice",
+			Action:       models.AdminActionExportUser,
+			TargetUserID: "user-1",
+			Detail:       map[string]any{"export_id": 9},
+		}).Return(nil)
+		tx.On("Commit").Return(nil)
+
+		s := NewAdminService(ar, lr, tm)
+		got, err := s.ExportUserData(ctx, "alice", "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, 9, got.ID)
+		lr.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("存在しないユーザー", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		ar := new(adminRepoMock)
+
+		tm.On("Begin", ctx).Return(tx, nil)
+		ar.On("CollectUserData", ctx, "missing").Return(nil, sql.ErrNoRows)
+		tx.On("Rollback").Return(nil)
+
+		s := NewAdminService(ar, new(adminAuditLogRepoMock), tm)
+		_, err := s.ExportUserData(ctx, "alice", "missing")
+
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		ar.AssertNotCalled(t, "CreateExport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminService_GetExport_NotFound(t *testing.T) {
+	ctx := context.Background()
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	ar := new(adminRepoMock)
+
+	tm.On("Begin", ctx).Return(tx, nil)
+	ar.On("GetExport", ctx, int32(3)).Return(nil, sql.ErrNoRows)
+	tx.On("Rollback").Return(nil)
+
+	s := NewAdminService(ar, new(adminAuditLogRepoMock), tm)
+	_, err := s.GetExport(ctx, "alice", 3)
+
+	var ne *NotFoundError
+	assert.ErrorAs(t, err, &ne)
+}
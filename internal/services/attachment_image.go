@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif" // image.Decode で GIF を読めるようにする
+	"image/jpeg"
+	_ "image/png" // image.Decode で PNG を読めるようにする
+)
+
+// AttachmentThumbnailMaxSide は添付画像の縮小版の長辺の最大ピクセル数です。
+const AttachmentThumbnailMaxSide = 320
+
+// attachmentThumbnailMaxPixels は縮小版を作る画像の最大画素数です。
+// 展開するとメモリを使い切るような画像（圧縮率の極端に高いものなど）は縮小版を作らずに保存します。
+const attachmentThumbnailMaxPixels = 50_000_000
+
+// thumbnailContentTypes は縮小版を作る形式です。WebP は標準ライブラリで読めないため縮小版を作りません。
+var thumbnailContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// attachmentThumbnail は画像の縮小版を JPEG で返します。縮小版を作らない形式・大きさなら nil を返します。
+// 形式が画像なのに読めない場合はエラーを返します。
+func attachmentThumbnail(contentType string, data []byte) ([]byte, error) {
+	if !thumbnailContentTypes[contentType] {
+		return nil, nil
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > attachmentThumbnailMaxPixels {
+		return nil, nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleDown(img, AttachmentThumbnailMaxSide), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown は縦横比を保ったまま長辺が maxSide 以下になるよう画像を縮小します。
+// 縮小先の 1 ピクセルに対応する元の画素の平均を取り、透明な部分は白で塗ります（JPEG は透明を表せないため）。
+func scaleDown(src image.Image, maxSide int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w >= h && w > maxSide {
+		tw, th = maxSide, max(1, h*maxSide/w)
+	} else if h > w && h > maxSide {
+		tw, th = max(1, w*maxSide/h), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		sy0, sy1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			sx0, sx1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// RGBA() の値はアルファ乗算済みなので、白の背景に重ねるには透明な分の白を足す
+			white := 0xffff - a/n
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r/n + white),
+				G: uint16(g/n + white),
+				B: uint16(bl/n + white),
+				A: 0xffff,
+			})
+		}
+	}
+	return dst
+}
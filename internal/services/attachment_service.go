@@ -0,0 +1,393 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// AttachmentMaxPerExpense は 1 件の支出に添付できるファイルの最大数です。
+const AttachmentMaxPerExpense = 10
+
+// attachmentFileNameMaxLen はファイル名の最大文字数です。超える部分は切り詰めます。
+const attachmentFileNameMaxLen = 255
+
+// attachmentOrphanBatchSize は孤立したファイルの削除で 1 回のトランザクションで扱う件数です。
+const attachmentOrphanBatchSize = 100
+
+// attachmentUploadGracePeriod は保存先に置いたファイルの行が確定するのを待つ期間です。
+// 過ぎても確定しないファイルはリクエストがロールバックされたものとして削除します。
+const attachmentUploadGracePeriod = time.Hour
+
+// attachmentContentTypes は添付できるファイルの形式です。形式はファイルの中身から判定します。
+var attachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// AttachmentService は支出の添付ファイルを扱います。
+// 家計簿のメンバーなら誰でも参照でき、追加・削除は編集者以上ができます。ゴミ箱の支出の添付ファイルは扱いません。
+type AttachmentService interface {
+	// ListAttachments は支出の添付ファイルを追加した順に返します。
+	ListAttachments(ctx context.Context, userID string, expenseID int) ([]models.Attachment, error)
+	// UploadAttachment はファイルを保存先に置いて支出に添付します。画像なら縮小版も作ります。
+	// ファイルが上限サイズを超えれば ErrAttachmentTooLarge、添付できない形式なら ErrUnsupportedAttachmentType を返します。
+	UploadAttachment(ctx context.Context, userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error)
+	// DownloadAttachment は添付ファイルの中身を返します。呼び出し元が Body を閉じます。
+	DownloadAttachment(ctx context.Context, userID string, expenseID int, id int) (models.AttachmentFile, error)
+	// DownloadThumbnail は画像の添付ファイルの縮小版（JPEG）を返します。呼び出し元が Body を閉じます。
+	DownloadThumbnail(ctx context.Context, userID string, expenseID int, id int) (models.AttachmentFile, error)
+	// DeleteAttachment は添付ファイルを削除します。保存先のファイルは PurgeOrphanedFiles で削除されます。
+	DeleteAttachment(ctx context.Context, userID string, expenseID int, id int) error
+	// PurgeOrphanedFiles は添付ファイルの削除（支出の完全な削除・退会によるものを含む）で使われなくなった
+	// ファイルと、アップロードのリクエストがロールバックされて行が作られなかったファイルを保存先から削除し、
+	// 削除した件数を返します。
+	PurgeOrphanedFiles(ctx context.Context) (int64, error)
+}
+
+type attachmentService struct {
+	repo        repositories.AttachmentRepository
+	expenseRepo repositories.ExpenseRepository
+	ledgerRepo  repositories.LedgerRepository
+	storage     repositories.FileStorage
+	txManager   TxManager
+	maxBytes    int64
+	newKey      func() (string, error)
+	now         func() time.Time
+}
+
+func NewAttachmentService(repo repositories.AttachmentRepository, expenseRepo repositories.ExpenseRepository, ledgerRepo repositories.LedgerRepository, storage repositories.FileStorage, txManager TxManager, maxBytes int64) AttachmentService {
+	return &attachmentService{
+		repo:        repo,
+		expenseRepo: expenseRepo,
+		ledgerRepo:  ledgerRepo,
+		storage:     storage,
+		txManager:   txManager,
+		maxBytes:    maxBytes,
+		newKey:      randomStorageKey,
+		now:         time.Now,
+	}
+}
+
+func (s *attachmentService) ListAttachments(ctx context.Context, userID string, expenseID int) ([]models.Attachment, error) {
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkExpense(ctx, ledgerID, expenseID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListAttachments(ctx, ledgerID, int32(expenseID))
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	return attachments, nil
+}
+
+func (s *attachmentService) UploadAttachment(ctx context.Context, userID string, expenseID int, upload models.AttachmentUpload) (models.Attachment, error) {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	if err := s.checkExpense(ctx, ledgerID, expenseID); err != nil {
+		return models.Attachment{}, err
+	}
+	existing, err := s.repo.ListAttachments(ctx, ledgerID, int32(expenseID))
+	if err != nil {
+		return models.Attachment{}, &InternalError{Message: "internal error"}
+	}
+	if len(existing) >= AttachmentMaxPerExpense {
+		return models.Attachment{}, &ValidationError{Message: fmt.Sprintf("an expense can have at most %d attachments", AttachmentMaxPerExpense)}
+	}
+
+	// 上限を 1 バイト超えて読めたら大きすぎる
+	data, err := io.ReadAll(io.LimitReader(upload.Body, s.maxBytes+1))
+	if err != nil {
+		return models.Attachment{}, &ValidationError{Message: "failed to read file"}
+	}
+	if int64(len(data)) > s.maxBytes {
+		return models.Attachment{}, ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return models.Attachment{}, &ValidationError{Message: "file must not be empty"}
+	}
+	contentType := sniffContentType(data)
+	if !attachmentContentTypes[contentType] {
+		return models.Attachment{}, ErrUnsupportedAttachmentType
+	}
+	thumbnail, err := attachmentThumbnail(contentType, data)
+	if err != nil {
+		return models.Attachment{}, &ValidationError{Message: "file is not a valid image"}
+	}
+
+	key, err := s.newKey()
+	if err != nil {
+		return models.Attachment{}, &InternalError{Message: "internal error"}
+	}
+	attachment := models.Attachment{
+		ExpenseID:   expenseID,
+		FileName:    sanitizeFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  fmt.Sprintf("attachments/%d/%d/%s", ledgerID, expenseID, key),
+	}
+	if thumbnail != nil {
+		attachment.ThumbnailKey = attachment.StorageKey + "-thumbnail.jpg"
+	}
+	// 行の作成より前に保存先に置くため、リクエストがロールバックされてもファイルを回収できるよう先に記録する
+	keys := attachmentFileKeys(attachment)
+	for _, key := range keys {
+		if err := s.repo.RecordUpload(ctx, key, s.now().UTC()); err != nil {
+			return models.Attachment{}, &InternalError{Message: "internal error"}
+		}
+	}
+
+	if err := s.storage.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return models.Attachment{}, &InternalError{Message: "internal error"}
+	}
+	if thumbnail != nil {
+		if err := s.storage.Put(ctx, attachment.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+			s.discardFiles(ctx, attachment)
+			return models.Attachment{}, &InternalError{Message: "internal error"}
+		}
+	}
+
+	created, err := s.repo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		s.discardFiles(ctx, attachment)
+		return models.Attachment{}, &InternalError{Message: "internal error"}
+	}
+	for _, key := range keys {
+		if err := s.repo.ConfirmUpload(ctx, key); err != nil {
+			return models.Attachment{}, &InternalError{Message: "internal error"}
+		}
+	}
+	return created, nil
+}
+
+func (s *attachmentService) DownloadAttachment(ctx context.Context, userID string, expenseID int, id int) (models.AttachmentFile, error) {
+	attachment, err := s.getAttachment(ctx, userID, expenseID, id)
+	if err != nil {
+		return models.AttachmentFile{}, err
+	}
+	body, err := s.openFile(ctx, attachment.StorageKey)
+	if err != nil {
+		return models.AttachmentFile{}, err
+	}
+	return models.AttachmentFile{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Body:        body,
+	}, nil
+}
+
+func (s *attachmentService) DownloadThumbnail(ctx context.Context, userID string, expenseID int, id int) (models.AttachmentFile, error) {
+	attachment, err := s.getAttachment(ctx, userID, expenseID, id)
+	if err != nil {
+		return models.AttachmentFile{}, err
+	}
+	if attachment.ThumbnailKey == "" {
+		return models.AttachmentFile{}, &NotFoundError{Message: "thumbnail not found"}
+	}
+	body, err := s.openFile(ctx, attachment.ThumbnailKey)
+	if err != nil {
+		return models.AttachmentFile{}, err
+	}
+	// 縮小版のサイズは記録していないため、Size は 0（不明）にする
+	return models.AttachmentFile{
+		FileName:    strings.TrimSuffix(attachment.FileName, path.Ext(attachment.FileName)) + "-thumbnail.jpg",
+		ContentType: "image/jpeg",
+		Body:        body,
+	}, nil
+}
+
+func (s *attachmentService) DeleteAttachment(ctx context.Context, userID string, expenseID int, id int) error {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteAttachment(ctx, ledgerID, int32(expenseID), int32(id))
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !deleted {
+		return &NotFoundError{Message: "attachment not found"}
+	}
+	return nil
+}
+
+func (s *attachmentService) PurgeOrphanedFiles(ctx context.Context) (int64, error) {
+	if err := s.enqueueAbandonedUploads(ctx); err != nil {
+		return 0, err
+	}
+	var total int64
+	for {
+		n, full, err := s.purgeOrphanBatch(ctx)
+		total += n
+		if err != nil || !full {
+			return total, err
+		}
+	}
+}
+
+// enqueueAbandonedUploads は猶予期間を過ぎても行が確定しなかったアップロードを孤立したファイルとして積みます。
+func (s *attachmentService) enqueueAbandonedUploads(ctx context.Context) error {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := s.repo.EnqueueAbandonedUploads(tx.Context(ctx), s.now().UTC().Add(-attachmentUploadGracePeriod)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// purgeOrphanBatch は孤立したファイルを最大 attachmentOrphanBatchSize 件削除します。
+// full は続きがあるかもしれない（件数が上限ちょうどで、すべて削除できた）かどうかです。
+// 保存先から削除できなかったファイルは記録を残し、次回の実行で再試行します。
+func (s *attachmentService) purgeOrphanBatch(ctx context.Context) (int64, bool, error) {
+	// 行レベルセキュリティの設定はトランザクションの開始時に行われるため、トランザクションで実行する
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	txCtx := tx.Context(ctx)
+
+	keys, err := s.repo.ListOrphanedFiles(txCtx, attachmentOrphanBatchSize)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, false, err
+	}
+	var n int64
+	var errs []error
+	for _, key := range keys {
+		if err := s.storage.Delete(txCtx, key); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s: %w", key, err))
+			continue
+		}
+		if err := s.repo.DeleteOrphanedFile(txCtx, key); err != nil {
+			_ = tx.Rollback()
+			return 0, false, err
+		}
+		n++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return n, len(keys) == attachmentOrphanBatchSize && len(errs) == 0, errors.Join(errs...)
+}
+
+// checkExpense は支出が家計簿にあり、ゴミ箱に無いことを確認します。
+func (s *attachmentService) checkExpense(ctx context.Context, ledgerID int32, expenseID int) error {
+	if _, err := s.expenseRepo.GetExpenseByID(ctx, ledgerID, int32(expenseID)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &NotFoundError{Message: "expense not found"}
+		}
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+func (s *attachmentService) getAttachment(ctx context.Context, userID string, expenseID int, id int) (models.Attachment, error) {
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	attachment, err := s.repo.GetAttachment(ctx, ledgerID, int32(expenseID), int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Attachment{}, &NotFoundError{Message: "attachment not found"}
+		}
+		return models.Attachment{}, &InternalError{Message: "internal error"}
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) openFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &NotFoundError{Message: "attachment not found"}
+		}
+		return nil, &InternalError{Message: "internal error"}
+	}
+	return body, nil
+}
+
+// attachmentFileKeys は添付ファイルが保存先に置くファイルのキーを返します。
+func attachmentFileKeys(attachment models.Attachment) []string {
+	keys := []string{attachment.StorageKey}
+	if attachment.ThumbnailKey != "" {
+		keys = append(keys, attachment.ThumbnailKey)
+	}
+	return keys
+}
+
+// discardFiles は記録できなかった添付ファイルの保存先のファイルを削除します。失敗しても無視します。
+func (s *attachmentService) discardFiles(ctx context.Context, attachment models.Attachment) {
+	for _, key := range attachmentFileKeys(attachment) {
+		_ = s.storage.Delete(ctx, key)
+	}
+}
+
+// sniffContentType はファイルの先頭から形式を判定し、パラメータ（charset など）を除いて返します。
+func sniffContentType(data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// sanitizeFileName はアップロードされたファイル名からディレクトリと制御文字を取り除きます。
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > attachmentFileNameMaxLen {
+		name = string(runes[:attachmentFileNameMaxLen])
+	}
+	return name
+}
+
+// randomStorageKey は推測されにくい保存先のキー（の末尾）を作ります。
+func randomStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
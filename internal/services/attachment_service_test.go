@@ -0,0 +1,396 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// memFileStorage はファイルをメモリに持つ FileStorage です。failDelete のキーは削除に失敗します。
+type memFileStorage struct {
+	files      map[string][]byte
+	failDelete map[string]bool
+}
+
+func newMemFileStorage() *memFileStorage {
+	return &memFileStorage{files: make(map[string][]byte), failDelete: make(map[string]bool)}
+}
+
+func (m *memFileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.files[key] = data
+	return nil
+}
+
+func (m *memFileStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.files[key]
+	if !ok {
+		return nil, fmt.Errorf("get %s: %w", key, fs.ErrNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memFileStorage) Delete(ctx context.Context, key string) error {
+	if m.failDelete[key] {
+		return errors.New("storage unavailable")
+	}
+	delete(m.files, key)
+	return nil
+}
+
+// memAttachmentRepo は添付ファイルの情報と孤立したファイルの記録をメモリに持つ AttachmentRepository です。
+// 家計簿は区別しません。
+type memAttachmentRepo struct {
+	attachments []models.Attachment
+	orphans     []string
+	uploads     map[string]time.Time
+	createErr   error
+	// recorded・confirmed は rollbackRequest で使う、トランザクションの外で記録したアップロードと確定したキーです
+	recorded  map[string]time.Time
+	confirmed []string
+}
+
+// rollbackRequest はリクエストのトランザクションのロールバックを再現します。
+// 行の作成と確定は巻き戻り、トランザクションの外で記録したアップロードだけが残ります。
+func (m *memAttachmentRepo) rollbackRequest() {
+	m.attachments = nil
+	for _, key := range m.confirmed {
+		m.uploads[key] = m.recorded[key]
+	}
+	m.confirmed = nil
+}
+
+func (m *memAttachmentRepo) ListAttachments(ctx context.Context, ledgerID int32, expenseID int32) ([]models.Attachment, error) {
+	var out []models.Attachment
+	for _, a := range m.attachments {
+		if a.ExpenseID == int(expenseID) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (m *memAttachmentRepo) GetAttachment(ctx context.Context, ledgerID int32, expenseID int32, id int32) (models.Attachment, error) {
+	for _, a := range m.attachments {
+		if a.ExpenseID == int(expenseID) && a.ID == int(id) {
+			return a, nil
+		}
+	}
+	return models.Attachment{}, sql.ErrNoRows
+}
+
+func (m *memAttachmentRepo) CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error) {
+	if m.createErr != nil {
+		return models.Attachment{}, m.createErr
+	}
+	attachment.ID = len(m.attachments) + 1
+	attachment.HasThumbnail = attachment.ThumbnailKey != ""
+	m.attachments = append(m.attachments, attachment)
+	return attachment, nil
+}
+
+func (m *memAttachmentRepo) DeleteAttachment(ctx context.Context, ledgerID int32, expenseID int32, id int32) (bool, error) {
+	for i, a := range m.attachments {
+		if a.ExpenseID == int(expenseID) && a.ID == int(id) {
+			m.attachments = append(m.attachments[:i], m.attachments[i+1:]...)
+			// DB ではトリガーが保存先のキーを積む
+			m.orphans = append(m.orphans, a.StorageKey)
+			if a.ThumbnailKey != "" {
+				m.orphans = append(m.orphans, a.ThumbnailKey)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memAttachmentRepo) RecordUpload(ctx context.Context, key string, at time.Time) error {
+	if m.uploads == nil {
+		m.uploads, m.recorded = map[string]time.Time{}, map[string]time.Time{}
+	}
+	m.uploads[key], m.recorded[key] = at, at
+	return nil
+}
+
+func (m *memAttachmentRepo) ConfirmUpload(ctx context.Context, key string) error {
+	delete(m.uploads, key)
+	m.confirmed = append(m.confirmed, key)
+	return nil
+}
+
+func (m *memAttachmentRepo) EnqueueAbandonedUploads(ctx context.Context, before time.Time) (int64, error) {
+	keys := make([]string, 0, len(m.uploads))
+	for key, at := range m.uploads {
+		if at.Before(before) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		delete(m.uploads, key)
+		m.orphans = append(m.orphans, key)
+	}
+	return int64(len(keys)), nil
+}
+
+func (m *memAttachmentRepo) ListOrphanedFiles(ctx context.Context, limit int) ([]string, error) {
+	if len(m.orphans) > limit {
+		return append([]string(nil), m.orphans[:limit]...), nil
+	}
+	return append([]string(nil), m.orphans...), nil
+}
+
+func (m *memAttachmentRepo) DeleteOrphanedFile(ctx context.Context, key string) error {
+	for i, k := range m.orphans {
+		if k == key {
+			m.orphans = append(m.orphans[:i], m.orphans[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func pngImage(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func newTestAttachmentService(ledger *ledgerRepoMock) (*attachmentService, *memAttachmentRepo, *memFileStorage) {
+	repo := &memAttachmentRepo{}
+	storage := newMemFileStorage()
+	s := NewAttachmentService(repo, &mockUpdateRepo{current: models.Expense{ID: 5}}, ledger, storage, nopTxManager{}, 1<<20).(*attachmentService)
+	n := 0
+	s.newKey = func() (string, error) {
+		n++
+		return fmt.Sprintf("key%d", n), nil
+	}
+	return s, repo, storage
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("画像は縮小版も保存する", func(t *testing.T) {
+		s, _, storage := newTestAttachmentService(activeLedger(1))
+
+		a, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: `C:\photos\レシート.png`, Body: bytes.NewReader(pngImage(t, 800, 400))})
+		require.NoError(t, err)
+		assert.Equal(t, "レシート.png", a.FileName)
+		assert.Equal(t, "image/png", a.ContentType)
+		assert.True(t, a.HasThumbnail)
+		assert.Equal(t, "attachments/1/5/key1", a.StorageKey)
+
+		thumb, err := jpeg.Decode(bytes.NewReader(storage.files[a.ThumbnailKey]))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, AttachmentThumbnailMaxSide, AttachmentThumbnailMaxSide/2), thumb.Bounds())
+		assert.Len(t, storage.files, 2)
+	})
+
+	t.Run("PDF は縮小版を作らない", func(t *testing.T) {
+		s, _, storage := newTestAttachmentService(activeLedger(1))
+
+		a, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "receipt.pdf", Body: strings.NewReader("%PDF-1.7\n...")})
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", a.ContentType)
+		assert.False(t, a.HasThumbnail)
+		assert.Len(t, storage.files, 1)
+	})
+
+	t.Run("形式はクライアントの申告ではなく中身で判定する", func(t *testing.T) {
+		s, _, storage := newTestAttachmentService(activeLedger(1))
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "receipt.png", Body: strings.NewReader("<html><script>alert(1)</script>")})
+		assert.ErrorIs(t, err, ErrUnsupportedAttachmentType)
+		assert.Empty(t, storage.files)
+	})
+
+	t.Run("壊れた画像", func(t *testing.T) {
+		s, _, _ := newTestAttachmentService(activeLedger(1))
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.png", Body: bytes.NewReader(pngImage(t, 10, 10)[:40])})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "file is not a valid image", ve.Message)
+	})
+
+	t.Run("上限サイズを超える", func(t *testing.T) {
+		s, _, storage := newTestAttachmentService(activeLedger(1))
+		s.maxBytes = 10
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.pdf", Body: strings.NewReader("%PDF-1.7 too large")})
+		assert.ErrorIs(t, err, ErrAttachmentTooLarge)
+		assert.Empty(t, storage.files)
+	})
+
+	t.Run("添付できる数の上限", func(t *testing.T) {
+		s, repo, _ := newTestAttachmentService(activeLedger(1))
+		for i := 0; i < AttachmentMaxPerExpense; i++ {
+			repo.attachments = append(repo.attachments, models.Attachment{ID: i + 1, ExpenseID: 5})
+		}
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.pdf", Body: strings.NewReader("%PDF-1.7")})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("閲覧者は添付できない", func(t *testing.T) {
+		s, _, _ := newTestAttachmentService(activeLedgerAs(1, models.LedgerRoleViewer))
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.pdf", Body: strings.NewReader("%PDF-1.7")})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+	})
+
+	t.Run("支出が無い", func(t *testing.T) {
+		s, _, _ := newTestAttachmentService(activeLedger(1))
+		s.expenseRepo = &mockUpdateRepo{getErr: sql.ErrNoRows}
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.pdf", Body: strings.NewReader("%PDF-1.7")})
+		var ne *NotFoundError
+		require.ErrorAs(t, err, &ne)
+		assert.Equal(t, "expense not found", ne.Message)
+	})
+
+	t.Run("記録に失敗したら保存したファイルを消す", func(t *testing.T) {
+		s, repo, storage := newTestAttachmentService(activeLedger(1))
+		repo.createErr = errors.New("db down")
+
+		_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.png", Body: bytes.NewReader(pngImage(t, 20, 20))})
+		var ie *InternalError
+		require.ErrorAs(t, err, &ie)
+		assert.Empty(t, storage.files)
+	})
+}
+
+func TestAttachmentService_Download(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestAttachmentService(activeLedgerAs(1, models.LedgerRoleViewer))
+	uploader, _, _ := newTestAttachmentService(activeLedger(1))
+	// 閲覧者も参照はできる
+	s.repo, s.storage = uploader.repo, uploader.storage
+
+	pdf, err := uploader.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "receipt.pdf", Body: strings.NewReader("%PDF-1.7")})
+	require.NoError(t, err)
+
+	file, err := s.DownloadAttachment(ctx, "viewer", 5, pdf.ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7", string(data))
+	assert.Equal(t, "application/pdf", file.ContentType)
+
+	_, err = s.DownloadThumbnail(ctx, "viewer", 5, pdf.ID)
+	var ne *NotFoundError
+	require.ErrorAs(t, err, &ne)
+	assert.Equal(t, "thumbnail not found", ne.Message)
+
+	_, err = s.DownloadAttachment(ctx, "viewer", 6, pdf.ID)
+	assert.ErrorAs(t, err, &ne)
+}
+
+func TestAttachmentService_DeleteAndPurge(t *testing.T) {
+	ctx := context.Background()
+	s, repo, storage := newTestAttachmentService(activeLedger(1))
+
+	img, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.png", Body: bytes.NewReader(pngImage(t, 20, 20))})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteAttachment(ctx, "user-1", 5, img.ID))
+
+	var ne *NotFoundError
+	assert.ErrorAs(t, s.DeleteAttachment(ctx, "user-1", 5, img.ID), &ne)
+
+	// 削除しただけではファイルは残り、ジョブが消す
+	assert.Len(t, storage.files, 2)
+	storage.failDelete[img.ThumbnailKey] = true
+
+	n, err := s.PurgeOrphanedFiles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, []string{img.ThumbnailKey}, repo.orphans)
+
+	// 削除できなかったファイルは次回に再試行する
+	delete(storage.failDelete, img.ThumbnailKey)
+	n, err = s.PurgeOrphanedFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Empty(t, repo.orphans)
+	assert.Empty(t, storage.files)
+}
+
+func TestAttachmentService_PurgeOrphanedFiles_AbandonedUploads(t *testing.T) {
+	ctx := context.Background()
+	s, repo, storage := newTestAttachmentService(activeLedger(1))
+	uploadedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return uploadedAt }
+
+	_, err := s.UploadAttachment(ctx, "user-1", 5, models.AttachmentUpload{FileName: "a.png", Body: bytes.NewReader(pngImage(t, 20, 20))})
+	require.NoError(t, err)
+	// 行を作成したファイルは確定し、回収の対象にならない
+	assert.Empty(t, repo.uploads)
+
+	// 行を作成した後でリクエストのトランザクションがロールバックされた
+	repo.rollbackRequest()
+	require.Len(t, storage.files, 2)
+
+	// 猶予期間中はアップロードの途中かもしれないので残す
+	s.now = func() time.Time { return uploadedAt.Add(attachmentUploadGracePeriod) }
+	n, err := s.PurgeOrphanedFiles(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Len(t, storage.files, 2)
+
+	s.now = func() time.Time { return uploadedAt.Add(attachmentUploadGracePeriod + time.Second) }
+	n, err = s.PurgeOrphanedFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Empty(t, storage.files)
+	assert.Empty(t, repo.uploads)
+	assert.Empty(t, repo.orphans)
+}
+
+func TestAttachmentService_PurgeOrphanedFiles_Batches(t *testing.T) {
+	s, repo, _ := newTestAttachmentService(activeLedger(1))
+	for i := 0; i < attachmentOrphanBatchSize*2+1; i++ {
+		repo.orphans = append(repo.orphans, fmt.Sprintf("attachments/1/5/%d", i))
+	}
+
+	n, err := s.PurgeOrphanedFiles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(attachmentOrphanBatchSize*2+1), n)
+	assert.Empty(t, repo.orphans)
+}
+
+func TestScaleDown_TransparentBecomesWhite(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	dst := scaleDown(src, 2)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), dst.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, dst.RGBAAt(0, 0))
+}
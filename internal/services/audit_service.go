@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+const (
+	// AuditDefaultLimit は GET /audit で limit を省略したときの件数です。
+	AuditDefaultLimit = 50
+	// AuditMaxLimit は GET /audit で一度に返す最大件数です。
+	AuditMaxLimit = 100
+)
+
+// AuditService は利用者自身の操作履歴と、参加している家計簿での操作履歴を返します。
+type AuditService interface {
+	ListEvents(ctx context.Context, userID string, input models.ListAuditEventsInput) (models.AuditEventPage, error)
+}
+
+type auditService struct {
+	repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) ListEvents(ctx context.Context, userID string, input models.ListAuditEventsInput) (models.AuditEventPage, error) {
+	if input.Limit < 0 || input.Limit > AuditMaxLimit {
+		return models.AuditEventPage{}, &ValidationError{Message: "limit must be between 1 and 100"}
+	}
+	if input.Limit == 0 {
+		input.Limit = AuditDefaultLimit
+	}
+	if input.Cursor < 0 {
+		return models.AuditEventPage{}, &ValidationError{Message: "cursor is invalid"}
+	}
+	if input.LedgerID < 0 {
+		return models.AuditEventPage{}, &ValidationError{Message: "ledger_id is invalid"}
+	}
+
+	filter := models.AuditEventFilter{
+		LedgerID:   int32(input.LedgerID),
+		EntityType: input.EntityType,
+		EntityID:   input.EntityID,
+		Action:     input.Action,
+		ActorID:    input.ActorID,
+		BeforeID:   int32(input.Cursor),
+		// 次のページの有無を判定するため 1 件多く取得する
+		Limit: input.Limit + 1,
+	}
+	var err error
+	if input.Since != "" {
+		if filter.Since, err = parseAuditTime(input.Since, false); err != nil {
+			return models.AuditEventPage{}, &ValidationError{Message: "since is invalid"}
+		}
+	}
+	if input.Until != "" {
+		if filter.Until, err = parseAuditTime(input.Until, true); err != nil {
+			return models.AuditEventPage{}, &ValidationError{Message: "until is invalid"}
+		}
+	}
+
+	events, err := s.repo.ListEvents(ctx, userID, filter)
+	if err != nil {
+		return models.AuditEventPage{}, &InternalError{Message: "internal error"}
+	}
+
+	page := models.AuditEventPage{Events: events}
+	if len(events) > input.Limit {
+		page.Events = events[:input.Limit]
+		next := page.Events[input.Limit-1].ID
+		page.NextCursor = &next
+	}
+	if page.Events == nil {
+		page.Events = []models.AuditEvent{}
+	}
+	return page, nil
+}
+
+// parseAuditTime は RFC3339 または YYYY-MM-DD を解釈します。
+// 日付のみで endOfDay が true の場合は翌日の 00:00（UTC）を返し、その日全体を含めます。
+func parseAuditTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// recordAudit は before と after の差分を event として記録します。作成時は before、削除時は after に nil を渡します。
+// 差分が無い場合は何も記録しません。更新と同じトランザクションのコンテキストで呼び出します。
+func recordAudit(ctx context.Context, repo repositories.AuditRepository, event models.AuditEvent, before, after map[string]any) error {
+	event.Changes = auditChanges(before, after)
+	if len(event.Changes) == 0 {
+		return nil
+	}
+	return repo.CreateEvent(ctx, event)
+}
+
+func auditChanges(before, after map[string]any) map[string]models.AuditChange {
+	changes := map[string]models.AuditChange{}
+	for key, b := range before {
+		if a := after[key]; !reflect.DeepEqual(b, a) {
+			changes[key] = models.AuditChange{Before: b, After: a}
+		}
+	}
+	for key, a := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = models.AuditChange{Before: nil, After: a}
+		}
+	}
+	return changes
+}
+
+// ledgerAuditEvent は家計簿 ledgerID のデータに対する userID の操作の記録を作ります。
+func ledgerAuditEvent(userID string, ledgerID int32, action, entityType, entityID string) models.AuditEvent {
+	ledger := int(ledgerID)
+	return models.AuditEvent{
+		LedgerID:   &ledger,
+		ActorID:    &userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+}
+
+// expenseAuditFields は支出の記録対象の項目です。内訳・返金額は内訳・返金のある支出だけ記録します。
+// タグは付けたユーザーにしか見えないため、家計簿のメンバーが参照する操作記録には含めません。
+func expenseAuditFields(e models.Expense) map[string]any {
+	fields := map[string]any{
+		"amount":      e.Amount,
+		"category_id": e.Category.ID,
+		"memo":        e.Memo,
+		"spent_at":    e.SpentAt,
+		"status":      e.Status,
+	}
+	if len(e.Splits) > 0 {
+		splits := make([]map[string]any, 0, len(e.Splits))
+		for _, sp := range e.Splits {
+			splits = append(splits, map[string]any{"category_id": sp.Category.ID, "amount": sp.Amount, "memo": sp.Memo})
+		}
+		fields["splits"] = splits
+	}
+	if e.RefundedAmount > 0 {
+		fields["refunded_amount"] = e.RefundedAmount
+	}
+	if e.Payee != nil {
+		fields["payee_id"] = e.Payee.ID
+	}
+	if e.PaymentAccount != nil {
+		fields["payment_account_id"] = e.PaymentAccount.ID
+	}
+	return fields
+}
+
+func expenseAuditEvent(userID string, ledgerID int32, action string, expenseID int) models.AuditEvent {
+	return ledgerAuditEvent(userID, ledgerID, action, models.AuditEntityExpense, strconv.Itoa(expenseID))
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// nopTxManager は何もしないトランザクションを返します。トランザクションの境界を検証しないテストで使います。
+type nopTxManager struct{}
+
+type nopTx struct{}
+
+func (nopTxManager) Begin(ctx context.Context) (Tx, error) { return nopTx{}, nil }
+
+func (nopTx) Commit() error                               { return nil }
+func (nopTx) Rollback() error                             { return nil }
+func (nopTx) Context(ctx context.Context) context.Context { return ctx }
+
+// auditRecorder は記録された操作を保持する AuditRepository です。
+type auditRecorder struct {
+	events    []models.AuditEvent
+	createErr error
+
+	listUserID string
+	listFilter models.AuditEventFilter
+	listResult []models.AuditEvent
+}
+
+func (r *auditRecorder) CreateEvent(ctx context.Context, event models.AuditEvent) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *auditRecorder) ListEvents(ctx context.Context, userID string, filter models.AuditEventFilter) ([]models.AuditEvent, error) {
+	r.listUserID = userID
+	r.listFilter = filter
+	return r.listResult, nil
+}
+
+func TestAuditChanges(t *testing.T) {
+	t.Run("変更された項目だけを返す", func(t *testing.T) {
+		got := auditChanges(
+			map[string]any{"amount": 100, "memo": "lunch"},
+			map[string]any{"amount": 200, "memo": "lunch"},
+		)
+		assert.Equal(t, map[string]models.AuditChange{"amount": {Before: 100, After: 200}}, got)
+	})
+
+	t.Run("作成は変更前が null", func(t *testing.T) {
+		got := auditChanges(nil, map[string]any{"amount": 100})
+		assert.Equal(t, map[string]models.AuditChange{"amount": {Before: nil, After: 100}}, got)
+	})
+
+	t.Run("削除は変更後が null", func(t *testing.T) {
+		got := auditChanges(map[string]any{"amount": 100}, nil)
+		assert.Equal(t, map[string]models.AuditChange{"amount": {Before: 100, After: nil}}, got)
+	})
+}
+
+func TestRecordAudit_SkipsWhenNothingChanged(t *testing.T) {
+	rec := &auditRecorder{}
+	fields := map[string]any{"amount": 100}
+
+	err := recordAudit(context.Background(), rec, models.AuditEvent{Action: models.AuditActionExpenseUpdate}, fields, fields)
+	require.NoError(t, err)
+	assert.Empty(t, rec.events)
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("絞り込み条件を渡し、続きがあれば next_cursor を返す", func(t *testing.T) {
+		rec := &auditRecorder{listResult: []models.AuditEvent{{ID: 30}, {ID: 20}, {ID: 10}}}
+		s := NewAuditService(rec)
+
+		page, err := s.ListEvents(ctx, "user-1", models.ListAuditEventsInput{
+			LedgerID:   3,
+			EntityType: models.AuditEntityExpense,
+			EntityID:   "7",
+			Since:      "2025-01-01",
+			Until:      "2025-01-31",
+			Limit:      2,
+			Cursor:     40,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", rec.listUserID)
+		assert.Equal(t, models.AuditEventFilter{
+			LedgerID:   3,
+			EntityType: models.AuditEntityExpense,
+			EntityID:   "7",
+			Since:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Until:      time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			BeforeID:   40,
+			Limit:      3,
+		}, rec.listFilter)
+		assert.Len(t, page.Events, 2)
+		require.NotNil(t, page.NextCursor)
+		assert.Equal(t, 20, *page.NextCursor)
+	})
+
+	t.Run("最後のページは next_cursor が null", func(t *testing.T) {
+		rec := &auditRecorder{}
+		s := NewAuditService(rec)
+
+		page, err := s.ListEvents(ctx, "user-1", models.ListAuditEventsInput{})
+		require.NoError(t, err)
+		assert.Equal(t, AuditDefaultLimit+1, rec.listFilter.Limit)
+		assert.NotNil(t, page.Events)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("不正な条件は ValidationError", func(t *testing.T) {
+		for _, input := range []models.ListAuditEventsInput{
+			{Limit: AuditMaxLimit + 1},
+			{Cursor: -1},
+			{Since: "yesterday"},
+			{Until: "2025-13-01"},
+		} {
+			_, err := NewAuditService(&auditRecorder{}).ListEvents(ctx, "user-1", input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve, "%+v", input)
+		}
+	})
+}
+
+func TestExpenseService_RecordsAudit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("作成", func(t *testing.T) {
+		rec := &auditRecorder{}
+		s := NewExpenseService(&mockRepo{}, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(4), rec, nopTxManager{})
+
+		_, err := s.CreateExpense(ctx, "user-1", models.CreateExpenseInput{Amount: intPtr(100), CategoryID: intPtr(1), Memo: "lunch", SpentAt: "2025-01-02"})
+		require.NoError(t, err)
+		require.Len(t, rec.events, 1)
+		ev := rec.events[0]
+		assert.Equal(t, models.AuditActionExpenseCreate, ev.Action)
+		assert.Equal(t, models.AuditEntityExpense, ev.EntityType)
+		assert.Equal(t, "1", ev.EntityID)
+		assert.Equal(t, "user-1", *ev.ActorID)
+		assert.Equal(t, 4, *ev.LedgerID)
+		assert.Equal(t, models.AuditChange{Before: nil, After: 100}, ev.Changes["amount"])
+		assert.Equal(t, models.AuditChange{Before: nil, After: "lunch"}, ev.Changes["memo"])
+	})
+
+	t.Run("更新は変更された項目だけを記録する", func(t *testing.T) {
+		rec := &auditRecorder{}
+		repo := &mockUpdateRepo{current: models.Expense{ID: 5, Amount: 100, SpentAt: "2025-01-01", Status: "planned", Category: models.Category{ID: 1}}}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(4), rec, nopTxManager{})
+
+		_, err := s.UpdateExpense(ctx, "user-1", models.UpdateExpenseInput{ID: 5, Amount: intPtr(250), CategoryID: intPtr(1), SpentAt: "2025-01-01", Status: "planned"})
+		require.NoError(t, err)
+		require.Len(t, rec.events, 1)
+		assert.Equal(t, models.AuditActionExpenseUpdate, rec.events[0].Action)
+		assert.Equal(t, map[string]models.AuditChange{"amount": {Before: 100, After: 250}}, rec.events[0].Changes)
+	})
+
+	t.Run("削除は変更前の値を記録する", func(t *testing.T) {
+		rec := &auditRecorder{}
+		s := NewExpenseService(&mockDeleteRepo{}, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(4), rec, nopTxManager{})
+
+		require.NoError(t, s.DeleteExpense(ctx, "user-1", 10, nil))
+		require.Len(t, rec.events, 1)
+		assert.Equal(t, models.AuditActionExpenseDelete, rec.events[0].Action)
+		assert.Equal(t, models.AuditChange{Before: "planned", After: nil}, rec.events[0].Changes["status"])
+	})
+
+	t.Run("記録に失敗したら更新も取り消す", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil)
+		s := NewExpenseService(&mockDeleteRepo{}, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(4), &auditRecorder{createErr: errors.New("db down")}, tm)
+
+		err := s.DeleteExpense(ctx, "user-1", 10, nil)
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+	})
+}
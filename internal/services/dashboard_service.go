@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// DashboardService は使用中の家計簿の月次集計を扱います。
+type DashboardService interface {
+	// MonthlySummary は 1 か月分の収入・貯金目標・固定費・支出と、支出の支払い手段の種類ごとの内訳を集計します。
+	// 使用中の家計簿が無ければ 0 の集計と空の内訳を返します。
+	MonthlySummary(ctx context.Context, userID string, input models.MonthlySummaryInput) (models.MonthlySummary, error)
+}
+
+type dashboardService struct {
+	repo       repositories.DashboardRepository
+	incomeRepo repositories.IncomeRepository
+	ledgerRepo repositories.LedgerRepository
+	now        func() time.Time
+}
+
+func NewDashboardService(repo repositories.DashboardRepository, incomeRepo repositories.IncomeRepository, ledgerRepo repositories.LedgerRepository) DashboardService {
+	return &dashboardService{repo: repo, incomeRepo: incomeRepo, ledgerRepo: ledgerRepo, now: time.Now}
+}
+
+func (s *dashboardService) MonthlySummary(ctx context.Context, userID string, input models.MonthlySummaryInput) (models.MonthlySummary, error) {
+	now := s.now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if input.Month != "" {
+		var err error
+		if month, err = time.Parse("2006-01", input.Month); err != nil {
+			return models.MonthlySummary{}, &ValidationError{Message: "month must be YYYY-MM"}
+		}
+	}
+	label := month.Format("2006-01")
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return models.MonthlySummary{Month: label, PaymentMethods: []models.PaymentMethodTotal{}}, nil
+		}
+		return models.MonthlySummary{}, err
+	}
+
+	summary, err := s.repo.MonthlySummary(ctx, ledgerID, month)
+	if err != nil {
+		return models.MonthlySummary{}, &InternalError{Message: "internal error"}
+	}
+	// 収入は GET /incomes/summary と同じ集計を使う
+	income, err := s.incomeRepo.MonthlySummary(ctx, ledgerID, month)
+	if err != nil {
+		return models.MonthlySummary{}, &InternalError{Message: "internal error"}
+	}
+	if summary.PaymentMethods == nil {
+		summary.PaymentMethods = []models.PaymentMethodTotal{}
+	}
+	summary.Month = label
+	summary.Income = income.Income
+	summary.ReceivedIncome = income.ReceivedIncome
+	summary.PlannedIncome = income.PlannedIncome
+	summary.ExpectedIncome = income.ExpectedIncome
+	return summary, nil
+}
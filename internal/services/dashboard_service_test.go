@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// memDashboardRepo は決まった集計を返す DashboardRepository です。
+type memDashboardRepo struct {
+	summary  models.MonthlySummary
+	ledgerID int32
+	month    time.Time
+	err      error
+}
+
+func (m *memDashboardRepo) MonthlySummary(ctx context.Context, ledgerID int32, month time.Time) (models.MonthlySummary, error) {
+	m.ledgerID = ledgerID
+	m.month = month
+	return m.summary, m.err
+}
+
+func TestDashboardService_MonthlySummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("month を省略すると今月。収入は収入の集計を使う", func(t *testing.T) {
+		methods := []models.PaymentMethodTotal{{PaymentMethod: "credit_card", ExpenseCount: 4, ConfirmedTotal: 70000}, {PaymentMethod: "unspecified", ExpenseCount: 2, ConfirmedTotal: 10000, PlannedTotal: 5000}}
+		repo := &memDashboardRepo{summary: models.MonthlySummary{SavingGoal: 50000, FixedCosts: 120000, ConfirmedExpenses: 80000, PendingExpenses: 5000, PaymentMethods: methods}}
+		incomes := &memIncomeRepo{summary: models.IncomeSummary{Income: 700000, ReceivedIncome: 400000, PlannedIncome: 0, ExpectedIncome: 600000}}
+		s := NewDashboardService(repo, incomes, activeLedger(3)).(*dashboardService)
+		s.now = func() time.Time { return time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC) }
+
+		got, err := s.MonthlySummary(ctx, "user-1", models.MonthlySummaryInput{})
+		require.NoError(t, err)
+		assert.Equal(t, models.MonthlySummary{
+			Month:             "2025-06",
+			Income:            700000,
+			ReceivedIncome:    400000,
+			ExpectedIncome:    600000,
+			SavingGoal:        50000,
+			FixedCosts:        120000,
+			ConfirmedExpenses: 80000,
+			PendingExpenses:   5000,
+			PaymentMethods:    methods,
+		}, got)
+		assert.Equal(t, int32(3), repo.ledgerID)
+		assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), repo.month)
+		assert.Equal(t, repo.month, incomes.month)
+	})
+
+	t.Run("家計簿が無ければ 0 の集計と空の内訳", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetActiveLedgerID", mock.Anything, "user-1").Return(nil, sql.ErrNoRows)
+		s := NewDashboardService(&memDashboardRepo{}, &memIncomeRepo{}, lr)
+
+		got, err := s.MonthlySummary(ctx, "user-1", models.MonthlySummaryInput{Month: "2025-01"})
+		require.NoError(t, err)
+		assert.Equal(t, models.MonthlySummary{Month: "2025-01", PaymentMethods: []models.PaymentMethodTotal{}}, got)
+	})
+
+	t.Run("month の形式が不正", func(t *testing.T) {
+		s := NewDashboardService(&memDashboardRepo{}, &memIncomeRepo{}, activeLedger(1))
+
+		_, err := s.MonthlySummary(ctx, "user-1", models.MonthlySummaryInput{Month: "2025-6"})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("リポジトリのエラーは InternalError", func(t *testing.T) {
+		s := NewDashboardService(&memDashboardRepo{}, &memIncomeRepo{err: errors.New("db down")}, activeLedger(1))
+
+		_, err := s.MonthlySummary(ctx, "user-1", models.MonthlySummaryInput{Month: "2025-01"})
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+}
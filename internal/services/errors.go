@@ -1,6 +1,10 @@
 package services
 
-import "errors"
+import (
+	"errors"
+
+	"money-buddy-backend/internal/models"
+)
 
 // ValidationError はサービス層が返す入力バリデーションエラーを表します。
 // 具体的な型にすることで、呼び出し側は errors.As などでエラーの種類を判別できます。
@@ -16,8 +20,10 @@ func (e *ValidationError) Error() string {
 }
 
 // NotFoundError はリソースが見つからないことを表します。
+// Err を指定すると、以前の仕様で返していたエラーとしても errors.Is で判定できます。
 type NotFoundError struct {
 	Message string
+	Err     error
 }
 
 func (e *NotFoundError) Error() string {
@@ -27,6 +33,39 @@ func (e *NotFoundError) Error() string {
 	return e.Message
 }
 
+func (e *NotFoundError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// ForbiddenError は認証済みのユーザーに操作の権限が無いことを表します（例: viewer による編集）。
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	if e == nil {
+		return "forbidden"
+	}
+	return e.Message
+}
+
+// VersionConflictError は If-Match で指定されたバージョンが現在の支出と一致しないことを表します。
+// Current は現在の状態で、呼び出し元はこれを元に変更をやり直せます。
+type VersionConflictError struct {
+	Message string
+	Current models.Expense
+}
+
+func (e *VersionConflictError) Error() string {
+	if e == nil {
+		return "version conflict"
+	}
+	return e.Message
+}
+
 // InternalError は内部エラーを表します（外部に詳細を漏らさないためのラップ）。
 type InternalError struct {
 	Message string
@@ -41,3 +80,38 @@ func (e *InternalError) Error() string {
 
 // ErrInvalidStatusTransition は不正なステータス遷移を表すエラーです。
 var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// ErrNoActiveLedger はユーザーが使用中の家計簿を持たない（初期設定前・全て退出済み）ことを表します。
+var ErrNoActiveLedger = errors.New("no active ledger")
+
+// ErrInvalidCredentials はメールアドレス・パスワード、またはリフレッシュトークンが正しくないことを表します。
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrEmailTaken はメールアドレスが既に別のアカウントで登録されていることを表します。
+var ErrEmailTaken = errors.New("email already registered")
+
+// ErrBulkExpensesFailed は一括操作のいずれかが失敗し、すべての操作を取り消したことを表します。
+// 失敗した操作とその理由は一括操作の結果に含まれます。
+var ErrBulkExpensesFailed = errors.New("one or more operations failed")
+
+// ErrIdempotencyKeyReused は Idempotency-Key が別の内容のリクエストで使われたことを表します。
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+// ErrIdempotencyKeyInProgress は同じ Idempotency-Key のリクエストが並行して処理され、先に完了したことを表します。
+// 再送すると先に完了したリクエストのレスポンスが返ります。
+var ErrIdempotencyKeyInProgress = errors.New("idempotency key in progress")
+
+// ErrTagNameTaken は呼び出し元が同じ名前のタグを既に持っていることを表します。
+var ErrTagNameTaken = errors.New("tag name already exists")
+
+// ErrAttachmentTooLarge はアップロードされたファイルが添付ファイルの上限サイズを超えていることを表します。
+var ErrAttachmentTooLarge = errors.New("attachment is too large")
+
+// ErrUnsupportedAttachmentType はアップロードされたファイルが添付できない形式であることを表します。
+var ErrUnsupportedAttachmentType = errors.New("unsupported attachment type")
+
+// ErrPayeeNameTaken は呼び出し元が同じ名前の支払先を既に持っていることを表します。
+var ErrPayeeNameTaken = errors.New("payee name already exists")
+
+// ErrPaymentAccountNameTaken は呼び出し元が同じ名前の支払い手段を既に持っていることを表します。
+var ErrPaymentAccountNameTaken = errors.New("payment account name already exists")
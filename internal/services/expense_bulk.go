@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"money-buddy-backend/internal/models"
+)
+
+// ExpenseBulkMaxOperations は一括操作で一度に送れる操作の最大件数です。
+const ExpenseBulkMaxOperations = 200
+
+func (s *expenseService) BulkExpenses(ctx context.Context, userID string, input models.BulkExpensesInput) ([]models.BulkExpenseResult, error) {
+	if len(input.Operations) == 0 {
+		return nil, &ValidationError{Message: "operations must not be empty"}
+	}
+	if len(input.Operations) > ExpenseBulkMaxOperations {
+		return nil, &ValidationError{Message: fmt.Sprintf("operations must not exceed %d", ExpenseBulkMaxOperations)}
+	}
+
+	// viewer や家計簿が無い場合は個々の操作ではなく一括操作全体の失敗にする
+	if _, err := writableLedgerID(ctx, s.ledgerRepo, userID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	// 各操作は CreateExpense などがセーブポイントで入れ子にするため、
+	// 失敗した操作だけを巻き戻して残りの操作も検証できる
+	results := make([]models.BulkExpenseResult, len(input.Operations))
+	failed := false
+	for i, op := range input.Operations {
+		results[i] = models.BulkExpenseResult{Index: i, Op: op.Op, ID: op.ID}
+		exp, err := s.applyBulkOperation(txCtx, userID, op)
+		if err != nil {
+			msg, ok := bulkOperationError(err)
+			if !ok {
+				_ = tx.Rollback()
+				return nil, &InternalError{Message: "internal error"}
+			}
+			results[i].Error = msg
+			failed = true
+			continue
+		}
+		if exp != nil {
+			results[i].ID = exp.ID
+			results[i].Expense = exp
+		}
+	}
+
+	if failed {
+		_ = tx.Rollback()
+		for i := range results {
+			results[i].Expense = nil
+			if results[i].Op == models.BulkExpenseOpCreate {
+				results[i].ID = 0
+			}
+		}
+		return results, ErrBulkExpensesFailed
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	return results, nil
+}
+
+// applyBulkOperation は 1 件の操作を適用します。delete は nil を返します。
+func (s *expenseService) applyBulkOperation(ctx context.Context, userID string, op models.BulkExpenseOperation) (*models.Expense, error) {
+	switch op.Op {
+	case models.BulkExpenseOpCreate:
+		if op.Expense == nil {
+			return nil, &ValidationError{Message: "expense must be provided"}
+		}
+		if op.ID != 0 {
+			return nil, &ValidationError{Message: "id must not be set for create"}
+		}
+		exp, err := s.CreateExpense(ctx, userID, *op.Expense)
+		if err != nil {
+			return nil, err
+		}
+		return &exp, nil
+	case models.BulkExpenseOpUpdate:
+		if op.ID <= 0 {
+			return nil, &ValidationError{Message: "id must be greater than 0"}
+		}
+		if op.Patch == nil {
+			return nil, &ValidationError{Message: "patch must be provided"}
+		}
+		exp, err := s.PatchExpense(ctx, userID, op.ID, *op.Patch, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &exp, nil
+	case models.BulkExpenseOpDelete:
+		if op.ID <= 0 {
+			return nil, &ValidationError{Message: "id must be greater than 0"}
+		}
+		return nil, s.DeleteExpense(ctx, userID, op.ID, nil)
+	default:
+		return nil, &ValidationError{Message: "op must be one of 'create', 'update', 'delete'"}
+	}
+}
+
+// bulkOperationError は操作の失敗を結果に載せるメッセージにします。
+// 内部エラーなど操作ごとに報告できないものは false を返します。
+func bulkOperationError(err error) (string, bool) {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve.Message, true
+	}
+	var ne *NotFoundError
+	if errors.As(err, &ne) {
+		return ne.Message, true
+	}
+	if errors.Is(err, ErrInvalidStatusTransition) {
+		return err.Error(), true
+	}
+	return "", false
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// bulkRepo は支出をメモリ上に持つ ExpenseRepository です。
+type bulkRepo struct {
+	mockRepo
+	expenses map[int32]models.Expense
+	nextID   int32
+}
+
+func (m *bulkRepo) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
+	m.nextID++
+	e := models.Expense{ID: int(m.nextID), Amount: *input.Amount, Memo: input.Memo, SpentAt: input.SpentAt, Status: "confirmed", Category: models.Category{ID: *input.CategoryID}}
+	m.expenses[m.nextID] = e
+	return e, nil
+}
+
+func (m *bulkRepo) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
+	e, ok := m.expenses[id]
+	if !ok {
+		return models.Expense{}, sql.ErrNoRows
+	}
+	return e, nil
+}
+
+func (m *bulkRepo) UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error) {
+	e := m.expenses[int32(input.ID)]
+	e.Amount, e.Memo, e.SpentAt, e.Status = *input.Amount, input.Memo, input.SpentAt, input.Status
+	m.expenses[int32(input.ID)] = e
+	return e, nil
+}
+
+func (m *bulkRepo) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error {
+	delete(m.expenses, id)
+	return nil
+}
+
+func newBulkRepo() *bulkRepo {
+	return &bulkRepo{
+		expenses: map[int32]models.Expense{
+			1: {ID: 1, Amount: 100, Memo: "lunch", SpentAt: "2025-04-01", Status: "confirmed", Category: models.Category{ID: 1}},
+			2: {ID: 2, Amount: 200, SpentAt: "2025-04-02", Status: "planned", Category: models.Category{ID: 1}},
+		},
+		nextID: 2,
+	}
+}
+
+func TestExpenseService_BulkExpenses(t *testing.T) {
+	ctx := context.Background()
+	repo := newBulkRepo()
+	tx := &txMock{}
+	tm := &txManagerMock{}
+	tm.On("Begin", mock.Anything).Return(tx, nil)
+	tx.On("Commit").Return(nil)
+	s := NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, tm)
+
+	memo := ""
+	results, err := s.BulkExpenses(ctx, "user-1", models.BulkExpensesInput{Operations: []models.BulkExpenseOperation{
+		{Op: "create", Expense: &models.CreateExpenseInput{Amount: intPtr(1200), CategoryID: intPtr(1), SpentAt: "2025-04-03"}},
+		{Op: "update", ID: 1, Patch: &models.ExpensePatch{Memo: &memo}},
+		{Op: "delete", ID: 2},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.BulkExpenseResult{
+		{Index: 0, Op: "create", ID: 3, Expense: &models.Expense{ID: 3, Amount: 1200, SpentAt: "2025-04-03", Status: "confirmed", Category: models.Category{ID: 1}}},
+		{Index: 1, Op: "update", ID: 1, Expense: &models.Expense{ID: 1, Amount: 100, SpentAt: "2025-04-01", Status: "confirmed", Category: models.Category{ID: 1}}},
+		{Index: 2, Op: "delete", ID: 2},
+	}, results)
+	assert.NotContains(t, repo.expenses, int32(2))
+	// 一括操作全体と各操作（セーブポイント）で Begin する
+	tm.AssertNumberOfCalls(t, "Begin", 4)
+	tx.AssertNumberOfCalls(t, "Commit", 4)
+}
+
+func TestExpenseService_BulkExpenses_FailureRollsBackEverything(t *testing.T) {
+	ctx := context.Background()
+	repo := newBulkRepo()
+	tx := &txMock{}
+	tm := &txManagerMock{}
+	tm.On("Begin", mock.Anything).Return(tx, nil)
+	tx.On("Commit").Return(nil)
+	tx.On("Rollback").Return(nil)
+	s := NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, tm)
+
+	results, err := s.BulkExpenses(ctx, "user-1", models.BulkExpensesInput{Operations: []models.BulkExpenseOperation{
+		{Op: "create", Expense: &models.CreateExpenseInput{Amount: intPtr(1200), CategoryID: intPtr(1), SpentAt: "2025-04-03"}},
+		{Op: "create", Expense: &models.CreateExpenseInput{Amount: intPtr(0), CategoryID: intPtr(1), SpentAt: "2025-04-03"}},
+		{Op: "delete", ID: 99},
+		{Op: "move", ID: 1},
+		{Op: "update", ID: 1},
+	}})
+	assert.ErrorIs(t, err, ErrBulkExpensesFailed)
+
+	// 失敗した操作の位置と理由を返し、成功した操作の結果は取り消す
+	assert.Equal(t, []models.BulkExpenseResult{
+		{Index: 0, Op: "create"},
+		{Index: 1, Op: "create", Error: "amount must be greater than 0"},
+		{Index: 2, Op: "delete", ID: 99, Error: "expense not found"},
+		{Index: 3, Op: "move", ID: 1, Error: "op must be one of 'create', 'update', 'delete'"},
+		{Index: 4, Op: "update", ID: 1, Error: "patch must be provided"},
+	}, results)
+
+	// 最後に一括操作全体をロールバックする
+	calls := tx.Calls
+	require.NotEmpty(t, calls)
+	assert.Equal(t, "Rollback", calls[len(calls)-1].Method)
+}
+
+func TestExpenseService_BulkExpenses_Validation(t *testing.T) {
+	s := NewExpenseService(newBulkRepo(), &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	for _, input := range []models.BulkExpensesInput{
+		{},
+		{Operations: make([]models.BulkExpenseOperation, ExpenseBulkMaxOperations+1)},
+	} {
+		_, err := s.BulkExpenses(context.Background(), "user-1", input)
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	}
+
+	// viewer は一括操作全体が 403 になる
+	s = NewExpenseService(newBulkRepo(), &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedgerAs(1, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{})
+	_, err := s.BulkExpenses(context.Background(), "user-1", models.BulkExpensesInput{Operations: []models.BulkExpenseOperation{{Op: "delete", ID: 1}}})
+	var fe *ForbiddenError
+	assert.ErrorAs(t, err, &fe)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+// ExpenseConfirmMaxItems はまとめて確定できる支出の最大件数です。
+const ExpenseConfirmMaxItems = 100
+
+func (s *expenseService) ConfirmExpense(ctx context.Context, userID string, id int, input models.ConfirmExpenseInput) (models.Expense, error) {
+	if err := validateConfirmInput(input); err != nil {
+		return models.Expense{}, err
+	}
+
+	var confirmed models.Expense
+	err := s.inConfirmTx(ctx, userID, func(txCtx context.Context, ledgerID int32) error {
+		var err error
+		confirmed, err = s.confirmExpense(txCtx, userID, ledgerID, id, input)
+		return err
+	})
+	if err != nil {
+		return models.Expense{}, err
+	}
+	return confirmed, nil
+}
+
+func (s *expenseService) ConfirmExpenses(ctx context.Context, userID string, input models.ConfirmExpensesInput) ([]models.Expense, error) {
+	if len(input.Items) == 0 {
+		return nil, &ValidationError{Message: "items must not be empty"}
+	}
+	if len(input.Items) > ExpenseConfirmMaxItems {
+		return nil, &ValidationError{Message: fmt.Sprintf("items must not exceed %d", ExpenseConfirmMaxItems)}
+	}
+	seen := make(map[int]bool, len(input.Items))
+	for i, item := range input.Items {
+		if item.ID <= 0 {
+			return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: id must be greater than 0", i)}
+		}
+		if seen[item.ID] {
+			return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: expense %d is listed more than once", i, item.ID)}
+		}
+		seen[item.ID] = true
+		if err := validateConfirmInput(models.ConfirmExpenseInput{Amount: item.Amount, SpentAt: item.SpentAt}); err != nil {
+			var ve *ValidationError
+			if errors.As(err, &ve) {
+				return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: %s", i, ve.Message)}
+			}
+			return nil, err
+		}
+	}
+
+	confirmed := make([]models.Expense, 0, len(input.Items))
+	err := s.inConfirmTx(ctx, userID, func(txCtx context.Context, ledgerID int32) error {
+		for _, item := range input.Items {
+			exp, err := s.confirmExpense(txCtx, userID, ledgerID, item.ID, models.ConfirmExpenseInput{Amount: item.Amount, SpentAt: item.SpentAt})
+			if err != nil {
+				return err
+			}
+			confirmed = append(confirmed, exp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return confirmed, nil
+}
+
+// inConfirmTx は使用中の家計簿（viewer は不可）で、確定と操作の記録を 1 つのトランザクションで行います。
+func (s *expenseService) inConfirmTx(ctx context.Context, userID string, fn func(txCtx context.Context, ledgerID int32) error) error {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	if err := fn(txCtx, ledgerID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+func (s *expenseService) confirmExpense(txCtx context.Context, userID string, ledgerID int32, id int, input models.ConfirmExpenseInput) (models.Expense, error) {
+	current, err := s.repo.GetExpenseByID(txCtx, ledgerID, int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Expense{}, &NotFoundError{Message: fmt.Sprintf("expense %d not found", id)}
+		}
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	// 確定できるのは planned の支出だけ
+	if strings.ToLower(current.Status) != "planned" {
+		return models.Expense{}, ErrInvalidStatusTransition
+	}
+	// 内訳のある支出は内訳と合計が合わなくなるため、確定時に金額を変えられない
+	if len(current.Splits) > 0 && input.Amount != nil && *input.Amount != current.Amount {
+		return models.Expense{}, &ValidationError{Message: "amount of a split expense cannot be changed on confirm; update its splits instead"}
+	}
+
+	confirmed, err := s.repo.ConfirmExpense(txCtx, ledgerID, int32(id), input)
+	if err != nil {
+		// 読み取り後に別のリクエストが確定した場合
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Expense{}, ErrInvalidStatusTransition
+		}
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	if err := s.attachExpenseTags(txCtx, userID, []*models.Expense{&confirmed}); err != nil {
+		return models.Expense{}, err
+	}
+	if err := s.attachExpensePayees(txCtx, []*models.Expense{&confirmed}); err != nil {
+		return models.Expense{}, err
+	}
+	if err := s.attachExpensePaymentAccounts(txCtx, []*models.Expense{&confirmed}); err != nil {
+		return models.Expense{}, err
+	}
+
+	event := expenseAuditEvent(userID, ledgerID, models.AuditActionExpenseConfirm, id)
+	if err := recordAudit(txCtx, s.auditRepo, event, expenseAuditFields(current), expenseAuditFields(confirmed)); err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	return confirmed, nil
+}
+
+// validateConfirmInput は確定時に指定する実際の金額・日付を検証します。どちらも省略できます。
+func validateConfirmInput(input models.ConfirmExpenseInput) error {
+	if input.Amount != nil {
+		if *input.Amount <= 0 {
+			return &ValidationError{Message: "amount must be greater than 0"}
+		}
+		if *input.Amount > BusinessMaxAmount {
+			return &ValidationError{Message: "amount exceeds maximum allowed"}
+		}
+	}
+	if input.SpentAt != "" {
+		if _, err := time.Parse(time.RFC3339, input.SpentAt); err != nil {
+			if _, err := time.Parse("2006-01-02", input.SpentAt); err != nil {
+				return &ValidationError{Message: "spent_at is invalid"}
+			}
+		}
+	}
+	return nil
+}
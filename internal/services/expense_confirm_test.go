@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// confirmRepo は ID ごとの支出を持ち、ConfirmExpense で状態を更新する ExpenseRepository です。
+type confirmRepo struct {
+	mockRepo
+	expenses  map[int32]models.Expense
+	confirmed []int32
+}
+
+func (m *confirmRepo) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
+	e, ok := m.expenses[id]
+	if !ok {
+		return models.Expense{}, sql.ErrNoRows
+	}
+	return e, nil
+}
+
+func (m *confirmRepo) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	e := m.expenses[id]
+	e.Status = "confirmed"
+	if input.Amount != nil {
+		e.Amount = *input.Amount
+	}
+	m.expenses[id] = e
+	m.confirmed = append(m.confirmed, id)
+	return e, nil
+}
+
+func TestExpenseService_ConfirmExpense(t *testing.T) {
+	repo := &mockUpdateRepo{current: models.Expense{ID: 3, Amount: 5000, SpentAt: "2025-03-01T00:00:00Z", Status: "planned", Category: models.Category{ID: 1}}}
+	audit := &auditRecorder{}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), audit, nopTxManager{})
+
+	out, err := s.ConfirmExpense(context.Background(), "user-1", 3, models.ConfirmExpenseInput{Amount: intPtr(5480), SpentAt: "2025-03-02"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "confirmed", out.Status)
+	assert.Equal(t, 5480, out.Amount)
+	assert.Equal(t, "2025-03-02", out.SpentAt)
+	require.NotNil(t, out.PlannedAmount)
+	assert.Equal(t, 5000, *out.PlannedAmount)
+	require.NotNil(t, out.ConfirmedAt)
+
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, models.AuditActionExpenseConfirm, audit.events[0].Action)
+	assert.Equal(t, models.AuditChange{Before: "planned", After: "confirmed"}, audit.events[0].Changes["status"])
+}
+
+func TestExpenseService_ConfirmExpense_Errors(t *testing.T) {
+	planned := models.Expense{ID: 3, Amount: 5000, SpentAt: "2025-03-01", Status: "planned"}
+
+	cases := []struct {
+		name    string
+		current models.Expense
+		getErr  error
+		input   models.ConfirmExpenseInput
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name:    "確定済み",
+			current: models.Expense{ID: 3, Status: "confirmed"},
+			check:   func(t *testing.T, err error) { assert.ErrorIs(t, err, ErrInvalidStatusTransition) },
+		},
+		{
+			name:   "存在しない",
+			getErr: sql.ErrNoRows,
+			check: func(t *testing.T, err error) {
+				var ne *NotFoundError
+				assert.ErrorAs(t, err, &ne)
+			},
+		},
+		{
+			name:    "金額が 0",
+			current: planned,
+			input:   models.ConfirmExpenseInput{Amount: intPtr(0)},
+			check: func(t *testing.T, err error) {
+				var ve *ValidationError
+				assert.ErrorAs(t, err, &ve)
+			},
+		},
+		{
+			name:    "日付の形式不正",
+			current: planned,
+			input:   models.ConfirmExpenseInput{SpentAt: "3/2"},
+			check: func(t *testing.T, err error) {
+				var ve *ValidationError
+				assert.ErrorAs(t, err, &ve)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockUpdateRepo{current: tc.current, getErr: tc.getErr}
+			s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+			_, err := s.ConfirmExpense(context.Background(), "user-1", 3, tc.input)
+			tc.check(t, err)
+			assert.False(t, repo.called)
+		})
+	}
+}
+
+func TestExpenseService_ConfirmExpenses(t *testing.T) {
+	newRepo := func() *confirmRepo {
+		return &confirmRepo{expenses: map[int32]models.Expense{
+			1: {ID: 1, Amount: 100, Status: "planned"},
+			2: {ID: 2, Amount: 200, Status: "planned"},
+			3: {ID: 3, Amount: 300, Status: "confirmed"},
+		}}
+	}
+
+	t.Run("すべて確定する", func(t *testing.T) {
+		repo := newRepo()
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		out, err := s.ConfirmExpenses(context.Background(), "user-1", models.ConfirmExpensesInput{Items: []models.ConfirmExpenseItem{
+			{ID: 1},
+			{ID: 2, Amount: intPtr(250)},
+		}})
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, 250, out[1].Amount)
+		assert.Equal(t, []int32{1, 2}, repo.confirmed)
+	})
+
+	t.Run("確定できない支出があれば失敗する", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo()
+		tx := &txMock{}
+		tm := &txManagerMock{}
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, tm)
+
+		_, err := s.ConfirmExpenses(ctx, "user-1", models.ConfirmExpensesInput{Items: []models.ConfirmExpenseItem{{ID: 1}, {ID: 3}}})
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("入力の検証", func(t *testing.T) {
+		inputs := []models.ConfirmExpensesInput{
+			{},
+			{Items: make([]models.ConfirmExpenseItem, ExpenseConfirmMaxItems+1)},
+			{Items: []models.ConfirmExpenseItem{{ID: 1}, {ID: 1}}},
+			{Items: []models.ConfirmExpenseItem{{ID: 1}, {ID: 2, Amount: intPtr(-5)}}},
+		}
+		for _, input := range inputs {
+			repo := newRepo()
+			s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+			_, err := s.ConfirmExpenses(context.Background(), "user-1", input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			assert.Empty(t, repo.confirmed)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"money-buddy-backend/internal/models"
+)
+
+// checkExpensePayee は支出に付ける支払先の入力チェックです。付けられるのは呼び出し元の支払先か、
+// 支出に今付いている支払先（共有家計簿で他のメンバーが付けたものを含む）です。付ける支払先を返します。
+func (s *expenseService) checkExpensePayee(ctx context.Context, userID string, payeeID *int, current *models.Payee) (*models.Payee, error) {
+	if payeeID == nil {
+		return nil, nil
+	}
+	if *payeeID <= 0 {
+		return nil, &ValidationError{Message: "payee_id must be greater than 0"}
+	}
+	if current != nil && current.ID == *payeeID {
+		return current, nil
+	}
+
+	payees, err := s.payeeRepo.ListPayeesByIDs(ctx, userID, []int32{int32(*payeeID)})
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	// 他のユーザーの支払先は存在しないものとして扱う
+	if len(payees) == 0 {
+		return nil, &ValidationError{Message: "payee_id is invalid"}
+	}
+	return &payees[0], nil
+}
+
+// attachExpensePayees は支出に付いた支払先を expenses に設定します。
+func (s *expenseService) attachExpensePayees(ctx context.Context, expenses []*models.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	ids := make([]int32, 0, len(expenses))
+	for _, e := range expenses {
+		ids = append(ids, int32(e.ID))
+	}
+	payees, err := s.payeeRepo.ListExpensePayees(ctx, ids)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	for _, e := range expenses {
+		e.Payee = nil
+		if p, ok := payees[int32(e.ID)]; ok {
+			e.Payee = &p
+		}
+	}
+	return nil
+}
+
+// setExpensePayee は支出の支払先を payee に置き換え、expense に設定します。nil なら支払先を外します。
+func (s *expenseService) setExpensePayee(ctx context.Context, expense *models.Expense, payee *models.Payee) error {
+	var payeeID *int32
+	if payee != nil {
+		id := int32(payee.ID)
+		payeeID = &id
+	}
+	if err := s.payeeRepo.SetExpensePayee(ctx, int32(expense.ID), payeeID); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	expense.Payee = payee
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+func TestCreateExpense_Payee(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("支払先を付けて登録する", func(t *testing.T) {
+		payees := &memPayeeRepo{}
+		store := payees.add("test-user", "コンビニ")
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, payees, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		out, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(500), CategoryID: intPtr(1), SpentAt: "2025-05-01", PayeeID: intPtr(store.ID),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &store, out.Payee)
+		assert.Equal(t, int32(store.ID), payees.links[1])
+	})
+
+	t.Run("他のユーザーの支払先は付けられない", func(t *testing.T) {
+		payees := &memPayeeRepo{}
+		other := payees.add("other-user", "コンビニ")
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, payees, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(500), CategoryID: intPtr(1), SpentAt: "2025-05-01", PayeeID: intPtr(other.ID),
+		})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "payee_id is invalid", ve.Message)
+		assert.False(t, m.called)
+	})
+}
+
+func TestUpdateExpense_Payee(t *testing.T) {
+	ctx := context.Background()
+	// 支出 5 には共有家計簿の他のメンバーが自分の支払先を付けている
+	setup := func() (*memPayeeRepo, ExpenseService) {
+		payees := &memPayeeRepo{}
+		payees.add("other-user", "スーパー")
+		payees.add("test-user", "コンビニ")
+		payees.links = map[int32]int32{5: 1}
+		repo := &mockUpdateRepo{current: models.Expense{ID: 5, Amount: 1000, Category: models.Category{ID: 1}, SpentAt: "2025-05-01T00:00:00Z", Status: "confirmed"}}
+		return payees, NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, payees, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+	}
+	put := func(payeeID *int) models.UpdateExpenseInput {
+		return models.UpdateExpenseInput{ID: 5, Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01", PayeeID: payeeID}
+	}
+
+	t.Run("他のメンバーが付けた支払先はそのまま指定できる", func(t *testing.T) {
+		payees, s := setup()
+
+		out, err := s.UpdateExpense(ctx, "test-user", put(intPtr(1)))
+		require.NoError(t, err)
+		assert.Equal(t, &models.Payee{ID: 1, Name: "スーパー"}, out.Payee)
+		assert.Equal(t, int32(1), payees.links[5])
+	})
+
+	t.Run("PUT で自分の支払先に付け替える", func(t *testing.T) {
+		payees, s := setup()
+
+		out, err := s.UpdateExpense(ctx, "test-user", put(intPtr(2)))
+		require.NoError(t, err)
+		assert.Equal(t, &models.Payee{ID: 2, Name: "コンビニ"}, out.Payee)
+		assert.Equal(t, int32(2), payees.links[5])
+	})
+
+	t.Run("PUT で省略すると支払先を外す", func(t *testing.T) {
+		payees, s := setup()
+
+		out, err := s.UpdateExpense(ctx, "test-user", put(nil))
+		require.NoError(t, err)
+		assert.Nil(t, out.Payee)
+		assert.NotContains(t, payees.links, int32(5))
+	})
+
+	t.Run("PATCH で省略すると現在の支払先を保つ", func(t *testing.T) {
+		payees, s := setup()
+
+		memo := "updated"
+		out, err := s.PatchExpense(ctx, "test-user", 5, models.ExpensePatch{Memo: &memo}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, &models.Payee{ID: 1, Name: "スーパー"}, out.Payee)
+		assert.Equal(t, int32(1), payees.links[5])
+	})
+
+	t.Run("PATCH で自分の支払先に付け替える", func(t *testing.T) {
+		payees, s := setup()
+
+		out, err := s.PatchExpense(ctx, "test-user", 5, models.ExpensePatch{PayeeID: intPtr(2)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, &models.Payee{ID: 2, Name: "コンビニ"}, out.Payee)
+		assert.Equal(t, int32(2), payees.links[5])
+	})
+
+	t.Run("PATCH の null で支払先を外す", func(t *testing.T) {
+		var patch models.ExpensePatch
+		require.NoError(t, json.Unmarshal([]byte(`{"payee_id":null}`), &patch))
+		payees, s := setup()
+
+		out, err := s.PatchExpense(ctx, "test-user", 5, patch, nil)
+		require.NoError(t, err)
+		assert.Nil(t, out.Payee)
+		assert.NotContains(t, payees.links, int32(5))
+	})
+}
+
+func TestExpensePatch_PayeeIDZero(t *testing.T) {
+	var patch models.ExpensePatch
+	err := json.Unmarshal([]byte(`{"payee_id":0}`), &patch)
+	assert.EqualError(t, err, "payee_id must be greater than 0")
+}
+
+func TestListExpenses_Payee(t *testing.T) {
+	payees := &memPayeeRepo{}
+	store := payees.add("other-user", "コンビニ")
+	payees.links = map[int32]int32{2: int32(store.ID)}
+	repo := &listExpensesRepo{result: []models.Expense{{ID: 1}, {ID: 2}}}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, payees, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	page, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{})
+	require.NoError(t, err)
+	require.Len(t, page.Expenses, 2)
+	assert.Nil(t, page.Expenses[0].Payee)
+	assert.Equal(t, &store, page.Expenses[1].Payee)
+}
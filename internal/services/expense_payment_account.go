@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"money-buddy-backend/internal/models"
+)
+
+// checkExpensePaymentAccount は支出に付ける支払い手段の入力チェックです。付けられるのは呼び出し元の支払い手段か、
+// 支出に今付いている支払い手段（共有家計簿で他のメンバーが付けたものを含む）です。付ける支払い手段を返します。
+func (s *expenseService) checkExpensePaymentAccount(ctx context.Context, userID string, accountID *int, current *models.ExpensePaymentAccount) (*models.ExpensePaymentAccount, error) {
+	if accountID == nil {
+		return nil, nil
+	}
+	if *accountID <= 0 {
+		return nil, &ValidationError{Message: "payment_account_id must be greater than 0"}
+	}
+	if current != nil && current.ID == *accountID {
+		return current, nil
+	}
+
+	account, err := s.paymentAccountRepo.GetPaymentAccount(ctx, userID, int32(*accountID))
+	if err != nil {
+		// 他のユーザーの支払い手段は存在しないものとして扱う
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &ValidationError{Message: "payment_account_id is invalid"}
+		}
+		return nil, &InternalError{Message: "internal error"}
+	}
+	return &models.ExpensePaymentAccount{ID: account.ID, Name: account.Name, Type: account.Type}, nil
+}
+
+// attachExpensePaymentAccounts は支出に付いた支払い手段を expenses に設定します。
+func (s *expenseService) attachExpensePaymentAccounts(ctx context.Context, expenses []*models.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	ids := make([]int32, 0, len(expenses))
+	for _, e := range expenses {
+		ids = append(ids, int32(e.ID))
+	}
+	accounts, err := s.paymentAccountRepo.ListExpensePaymentAccounts(ctx, ids)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	for _, e := range expenses {
+		e.PaymentAccount = nil
+		if a, ok := accounts[int32(e.ID)]; ok {
+			e.PaymentAccount = &a
+		}
+	}
+	return nil
+}
+
+// setExpensePaymentAccount は支出の支払い手段を account に置き換え、expense に設定します。nil なら支払い手段を外します。
+func (s *expenseService) setExpensePaymentAccount(ctx context.Context, expense *models.Expense, account *models.ExpensePaymentAccount) error {
+	var accountID *int32
+	if account != nil {
+		id := int32(account.ID)
+		accountID = &id
+	}
+	if err := s.paymentAccountRepo.SetExpensePaymentAccount(ctx, int32(expense.ID), accountID); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	expense.PaymentAccount = account
+	return nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+func TestCreateExpense_PaymentAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("支払い手段を付けて登録する", func(t *testing.T) {
+		accounts := &memPaymentAccountRepo{}
+		card := accounts.add("test-user", "楽天カード", models.PaymentAccountTypeCreditCard)
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, accounts, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		out, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(500), CategoryID: intPtr(1), SpentAt: "2025-05-01", PaymentAccountID: intPtr(card.ID),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &models.ExpensePaymentAccount{ID: card.ID, Name: "楽天カード", Type: "credit_card"}, out.PaymentAccount)
+		assert.Equal(t, int32(card.ID), accounts.links[1])
+	})
+
+	t.Run("他のユーザーの支払い手段は付けられない", func(t *testing.T) {
+		accounts := &memPaymentAccountRepo{}
+		other := accounts.add("other-user", "財布", models.PaymentAccountTypeCash)
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, accounts, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(500), CategoryID: intPtr(1), SpentAt: "2025-05-01", PaymentAccountID: intPtr(other.ID),
+		})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "payment_account_id is invalid", ve.Message)
+		assert.False(t, m.called)
+	})
+}
+
+func TestUpdateExpense_PaymentAccount(t *testing.T) {
+	ctx := context.Background()
+	// 支出 5 には共有家計簿の他のメンバーが自分の支払い手段を付けている
+	setup := func() (*memPaymentAccountRepo, ExpenseService) {
+		accounts := &memPaymentAccountRepo{}
+		accounts.add("other-user", "財布", models.PaymentAccountTypeCash)
+		accounts.add("test-user", "PayPay", models.PaymentAccountTypeEMoney)
+		accounts.links = map[int32]int32{5: 1}
+		repo := &mockUpdateRepo{current: models.Expense{ID: 5, Amount: 1000, Category: models.Category{ID: 1}, SpentAt: "2025-05-01T00:00:00Z", Status: "confirmed"}}
+		return accounts, NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, accounts, activeLedger(1), &auditRecorder{}, nopTxManager{})
+	}
+	wallet := &models.ExpensePaymentAccount{ID: 1, Name: "財布", Type: "cash"}
+
+	t.Run("PUT で今の支払い手段を送ると残高は変わらない", func(t *testing.T) {
+		accounts, s := setup()
+		accounts.accounts[0].account.Balance = 5000
+		accounts.spent = map[int32]int{5: 1000}
+		before, err := accounts.GetPaymentAccount(ctx, "other-user", 1)
+		require.NoError(t, err)
+		require.Equal(t, 4000, before.Balance)
+
+		out, err := s.UpdateExpense(ctx, "test-user", models.UpdateExpenseInput{ID: 5, Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01", PaymentAccountID: intPtr(1)})
+		require.NoError(t, err)
+		assert.Equal(t, wallet, out.PaymentAccount)
+		after, err := accounts.GetPaymentAccount(ctx, "other-user", 1)
+		require.NoError(t, err)
+		assert.Equal(t, before.Balance, after.Balance)
+	})
+
+	t.Run("PUT で省略すると支払い手段を外す", func(t *testing.T) {
+		accounts, s := setup()
+
+		out, err := s.UpdateExpense(ctx, "test-user", models.UpdateExpenseInput{ID: 5, Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01"})
+		require.NoError(t, err)
+		assert.Nil(t, out.PaymentAccount)
+		assert.NotContains(t, accounts.links, int32(5))
+	})
+
+	t.Run("PATCH で省略すると他のメンバーが付けた支払い手段を保つ", func(t *testing.T) {
+		accounts, s := setup()
+
+		memo := "updated"
+		out, err := s.PatchExpense(ctx, "test-user", 5, models.ExpensePatch{Memo: &memo}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, wallet, out.PaymentAccount)
+		assert.Equal(t, int32(1), accounts.links[5])
+	})
+
+	t.Run("PATCH で自分の支払い手段に付け替える", func(t *testing.T) {
+		accounts, s := setup()
+
+		out, err := s.PatchExpense(ctx, "test-user", 5, models.ExpensePatch{PaymentAccountID: intPtr(2)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, &models.ExpensePaymentAccount{ID: 2, Name: "PayPay", Type: "e_money"}, out.PaymentAccount)
+		assert.Equal(t, int32(2), accounts.links[5])
+	})
+
+	t.Run("PATCH の null で支払い手段を外す", func(t *testing.T) {
+		var patch models.ExpensePatch
+		require.NoError(t, json.Unmarshal([]byte(`{"payment_account_id":null}`), &patch))
+		accounts, s := setup()
+
+		out, err := s.PatchExpense(ctx, "test-user", 5, patch, nil)
+		require.NoError(t, err)
+		assert.Nil(t, out.PaymentAccount)
+		assert.NotContains(t, accounts.links, int32(5))
+	})
+}
+
+func TestExpensePatch_PaymentAccountIDZero(t *testing.T) {
+	var patch models.ExpensePatch
+	err := json.Unmarshal([]byte(`{"payment_account_id":0}`), &patch)
+	assert.EqualError(t, err, "payment_account_id must be greater than 0")
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"money-buddy-backend/internal/models"
+)
+
+// expenseCursor は GET /expenses の next_cursor の中身です。
+// クライアントには base64url で符号化した不透明な文字列として渡し、並び順や絞り込み条件が変わった場合は受け付けません。
+type expenseCursor struct {
+	Sort    string `json:"s"`
+	Filter  string `json:"f"`
+	SpentAt string `json:"d"`
+	Amount  int    `json:"a"`
+	ID      int    `json:"i"`
+}
+
+func encodeExpenseCursor(filter models.ExpenseFilter, last models.Expense) (string, error) {
+	spentAt, err := time.Parse(time.RFC3339, last.SpentAt)
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(expenseCursor{
+		Sort:    filter.Sort,
+		Filter:  expenseFilterHash(filter),
+		SpentAt: spentAt.Format("2006-01-02"),
+		Amount:  last.Amount,
+		ID:      last.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeExpenseCursor(filter models.ExpenseFilter, value string) (*models.ExpenseCursor, error) {
+	invalid := &ValidationError{Message: "cursor is invalid"}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalid
+	}
+	var c expenseCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, invalid
+	}
+	if c.Sort != filter.Sort || c.ID <= 0 {
+		return nil, invalid
+	}
+	// 別の条件の結果の途中から続けると、ページの境目がずれて支出が抜けたり重複したりする
+	if c.Filter != expenseFilterHash(filter) {
+		return nil, &ValidationError{Message: "cursor does not match the filters"}
+	}
+	spentAt, err := time.Parse("2006-01-02", c.SpentAt)
+	if err != nil {
+		return nil, invalid
+	}
+	return &models.ExpenseCursor{SpentAt: spentAt, Amount: c.Amount, ID: c.ID}, nil
+}
+
+// expenseFilterHash は絞り込み条件（並び順・件数・続きの位置を除く）の要約を返します。
+// ID の一覧は順序と重複を無視します。
+func expenseFilterHash(filter models.ExpenseFilter) string {
+	raw, _ := json.Marshal(struct {
+		From        string  `json:"from"`
+		To          string  `json:"to"`
+		CategoryIDs []int32 `json:"category_ids"`
+		TagIDs      []int32 `json:"tag_ids"`
+		Status      string  `json:"status"`
+		MinAmount   int     `json:"min_amount"`
+		MaxAmount   int     `json:"max_amount"`
+		Memo        string  `json:"memo"`
+	}{
+		From:        filter.From.Format("2006-01-02"),
+		To:          filter.To.Format("2006-01-02"),
+		CategoryIDs: canonicalIDs(filter.CategoryIDs),
+		TagIDs:      canonicalIDs(filter.TagIDs),
+		Status:      filter.Status,
+		MinAmount:   filter.MinAmount,
+		MaxAmount:   filter.MaxAmount,
+		Memo:        filter.Memo,
+	})
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// canonicalIDs は ID の一覧を昇順に並べ、重複を除いた複製を返します。
+func canonicalIDs(ids []int32) []int32 {
+	out := append([]int32{}, ids...)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// expenseFilterFromInput は GET /expenses のクエリパラメータを検証し、リポジトリの検索条件に変換します。
+func expenseFilterFromInput(input models.ListExpensesInput) (models.ExpenseFilter, error) {
+	if input.Limit < 0 || input.Limit > ExpenseListMaxLimit {
+		return models.ExpenseFilter{}, &ValidationError{Message: "limit must be between 1 and 100"}
+	}
+	filter := models.ExpenseFilter{Limit: input.Limit}
+	if filter.Limit == 0 {
+		filter.Limit = ExpenseListDefaultLimit
+	}
+
+	switch input.Sort {
+	case "":
+		filter.Sort = models.ExpenseSortSpentAtDesc
+	case models.ExpenseSortSpentAtDesc, models.ExpenseSortSpentAtAsc, models.ExpenseSortAmountDesc, models.ExpenseSortAmountAsc:
+		filter.Sort = input.Sort
+	default:
+		return models.ExpenseFilter{}, &ValidationError{Message: "sort must be one of '-spent_at', 'spent_at', '-amount', 'amount'"}
+	}
+
+	var err error
+	if input.From != "" {
+		if filter.From, err = time.Parse("2006-01-02", input.From); err != nil {
+			return models.ExpenseFilter{}, &ValidationError{Message: "from is invalid"}
+		}
+	}
+	if input.To != "" {
+		if filter.To, err = time.Parse("2006-01-02", input.To); err != nil {
+			return models.ExpenseFilter{}, &ValidationError{Message: "to is invalid"}
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return models.ExpenseFilter{}, &ValidationError{Message: "from must not be after to"}
+	}
+
+	if filter.CategoryIDs, err = parseCategoryIDs(input.CategoryIDs); err != nil {
+		return models.ExpenseFilter{}, err
+	}
+	if filter.TagIDs, err = parseIDList(input.TagIDs, "tag_id"); err != nil {
+		return models.ExpenseFilter{}, err
+	}
+	if filter.Status, err = parseStatusFilter(input.Status); err != nil {
+		return models.ExpenseFilter{}, err
+	}
+
+	if input.MinAmount < 0 || input.MaxAmount < 0 {
+		return models.ExpenseFilter{}, &ValidationError{Message: "amount range must not be negative"}
+	}
+	if input.MinAmount > 0 && input.MaxAmount > 0 && input.MinAmount > input.MaxAmount {
+		return models.ExpenseFilter{}, &ValidationError{Message: "min_amount must not be greater than max_amount"}
+	}
+	filter.MinAmount = input.MinAmount
+	filter.MaxAmount = input.MaxAmount
+
+	if len(input.Memo) > MemoMaxLen {
+		return models.ExpenseFilter{}, &ValidationError{Message: "memo exceeds maximum length"}
+	}
+	filter.Memo = input.Memo
+
+	if input.Cursor != "" {
+		if filter.After, err = decodeExpenseCursor(filter, input.Cursor); err != nil {
+			return models.ExpenseFilter{}, err
+		}
+	}
+	return filter, nil
+}
+
+// parseCategoryIDs は category_id の繰り返し・カンマ区切りを解釈します。
+func parseCategoryIDs(values []string) ([]int32, error) {
+	return parseIDList(values, "category_id")
+}
+
+// parseIDList は name のクエリパラメータの繰り返し・カンマ区切りを ID の一覧として解釈します。
+func parseIDList(values []string, name string) ([]int32, error) {
+	var ids []int32
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || id <= 0 {
+				return nil, &ValidationError{Message: name + " is invalid"}
+			}
+			ids = append(ids, int32(id))
+		}
+	}
+	return ids, nil
+}
+
+// parseStatusFilter は絞り込み用のステータスを正規化します。空文字は絞り込みなしです。
+func parseStatusFilter(status string) (string, error) {
+	if status == "" {
+		return "", nil
+	}
+	normalized, ok := models.NormalizeStatus(status)
+	if !ok {
+		return "", &ValidationError{Message: "status must be 'planned' or 'confirmed'"}
+	}
+	return normalized, nil
+}
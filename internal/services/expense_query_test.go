@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// listExpensesRepo は FindAll に渡された条件を記録し、result を返す ExpenseRepository です。
+type listExpensesRepo struct {
+	mockRepo
+	filter models.ExpenseFilter
+	result []models.Expense
+	err    error
+}
+
+func (m *listExpensesRepo) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) {
+	m.filter = filter
+	return m.result, m.err
+}
+
+func TestExpenseService_ListExpenses_Filter(t *testing.T) {
+	repo := &listExpensesRepo{}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	_, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{
+		From:        "2025-01-01",
+		To:          "2025-01-31",
+		CategoryIDs: []string{"1,2", "5"},
+		Status:      "Confirmed",
+		MinAmount:   100,
+		MaxAmount:   5000,
+		Memo:        "スタバ",
+		Sort:        "-amount",
+		Limit:       20,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.ExpenseFilter{
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		CategoryIDs: []int32{1, 2, 5},
+		Status:      "confirmed",
+		MinAmount:   100,
+		MaxAmount:   5000,
+		Memo:        "スタバ",
+		Sort:        models.ExpenseSortAmountDesc,
+		Limit:       21,
+	}, repo.filter)
+}
+
+func TestExpenseService_ListExpenses_Defaults(t *testing.T) {
+	repo := &listExpensesRepo{}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	page, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.ExpenseSortSpentAtDesc, repo.filter.Sort)
+	assert.Equal(t, ExpenseListDefaultLimit+1, repo.filter.Limit)
+	assert.NotNil(t, page.Expenses)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestExpenseService_ListExpenses_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		input models.ListExpensesInput
+	}{
+		{name: "limit が大きすぎる", input: models.ListExpensesInput{Limit: 101}},
+		{name: "limit が負", input: models.ListExpensesInput{Limit: -1}},
+		{name: "未知の sort", input: models.ListExpensesInput{Sort: "memo"}},
+		{name: "from の形式不正", input: models.ListExpensesInput{From: "2025/01/01"}},
+		{name: "from が to より後", input: models.ListExpensesInput{From: "2025-02-01", To: "2025-01-01"}},
+		{name: "category_id が数値でない", input: models.ListExpensesInput{CategoryIDs: []string{"food"}}},
+		{name: "category_id が 0", input: models.ListExpensesInput{CategoryIDs: []string{"0"}}},
+		{name: "tag_id が数値でない", input: models.ListExpensesInput{TagIDs: []string{"travel"}}},
+		{name: "存在しない tag_id", input: models.ListExpensesInput{TagIDs: []string{"7"}}},
+		{name: "未知の status", input: models.ListExpensesInput{Status: "paid"}},
+		{name: "金額の範囲が逆", input: models.ListExpensesInput{MinAmount: 500, MaxAmount: 100}},
+		{name: "金額が負", input: models.ListExpensesInput{MinAmount: -1}},
+		{name: "cursor が壊れている", input: models.ListExpensesInput{Cursor: "!!"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewExpenseService(&listExpensesRepo{}, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+			_, err := s.ListExpenses(context.Background(), "user-1", tc.input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+		})
+	}
+}
+
+func TestExpenseService_ListExpenses_Pagination(t *testing.T) {
+	repo := &listExpensesRepo{result: []models.Expense{
+		{ID: 9, Amount: 300, SpentAt: "2025-01-03T00:00:00Z"},
+		{ID: 7, Amount: 200, SpentAt: "2025-01-02T00:00:00Z"},
+		{ID: 4, Amount: 100, SpentAt: "2025-01-01T00:00:00Z"},
+	}}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	page, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Expenses, 2)
+	require.NotNil(t, page.NextCursor)
+
+	// next_cursor を渡すと最後の支出の続きから取得する
+	repo.result = nil
+	_, err = s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{Limit: 2, Cursor: *page.NextCursor})
+	require.NoError(t, err)
+	require.NotNil(t, repo.filter.After)
+	assert.Equal(t, models.ExpenseCursor{SpentAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 200, ID: 7}, *repo.filter.After)
+
+	// 並び順を変えた場合は使えない
+	_, err = s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{Sort: "amount", Cursor: *page.NextCursor})
+	var ve *ValidationError
+	assert.ErrorAs(t, err, &ve)
+}
+
+func TestExpenseService_ListExpenses_CursorBoundToFilters(t *testing.T) {
+	repo := &listExpensesRepo{result: []models.Expense{
+		{ID: 9, Amount: 300, SpentAt: "2025-01-03T00:00:00Z"},
+		{ID: 7, Amount: 200, SpentAt: "2025-01-02T00:00:00Z"},
+	}}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+	input := models.ListExpensesInput{From: "2025-01-01", CategoryIDs: []string{"2", "3"}, Memo: "lunch", Limit: 1}
+
+	page, err := s.ListExpenses(context.Background(), "user-1", input)
+	require.NoError(t, err)
+	require.NotNil(t, page.NextCursor)
+	repo.result = nil
+
+	t.Run("同じ条件なら ID の順序や件数が違っても続きを取得できる", func(t *testing.T) {
+		_, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{From: "2025-01-01", CategoryIDs: []string{"3,2"}, Memo: "lunch", Limit: 20, Cursor: *page.NextCursor})
+		require.NoError(t, err)
+		require.NotNil(t, repo.filter.After)
+		assert.Equal(t, 9, repo.filter.After.ID)
+	})
+
+	changed := []struct {
+		name  string
+		input models.ListExpensesInput
+	}{
+		{name: "期間を変えた", input: models.ListExpensesInput{From: "2025-01-02", CategoryIDs: []string{"2", "3"}, Memo: "lunch"}},
+		{name: "カテゴリを変えた", input: models.ListExpensesInput{From: "2025-01-01", CategoryIDs: []string{"2"}, Memo: "lunch"}},
+		{name: "メモの検索語を外した", input: models.ListExpensesInput{From: "2025-01-01", CategoryIDs: []string{"2", "3"}}},
+		{name: "金額の範囲を加えた", input: models.ListExpensesInput{From: "2025-01-01", CategoryIDs: []string{"2", "3"}, Memo: "lunch", MinAmount: 100}},
+	}
+	for _, tc := range changed {
+		t.Run(tc.name+"ら使えない", func(t *testing.T) {
+			tc.input.Cursor = *page.NextCursor
+			_, err := s.ListExpenses(context.Background(), "user-1", tc.input)
+			var ve *ValidationError
+			require.ErrorAs(t, err, &ve)
+			assert.Equal(t, "cursor does not match the filters", ve.Message)
+		})
+	}
+}
+
+func TestExpenseService_ListExpenses_RepositoryError(t *testing.T) {
+	repo := &listExpensesRepo{err: errors.New("db down")}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	_, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{})
+	var ie *InternalError
+	assert.ErrorAs(t, err, &ie)
+}
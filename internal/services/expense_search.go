@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"money-buddy-backend/internal/models"
+)
+
+const (
+	// ExpenseSearchDefaultLimit は検索で limit を省略したときの件数です。
+	ExpenseSearchDefaultLimit = 20
+	// ExpenseSearchMaxLimit は検索で一度に返す最大件数です。
+	ExpenseSearchMaxLimit = 50
+	// ExpenseSearchQueryMaxLen は検索語全体の最大文字数です。
+	ExpenseSearchQueryMaxLen = 100
+	// ExpenseSearchMaxTerms は空白で区切った検索語の最大数です。
+	ExpenseSearchMaxTerms = 10
+)
+
+func (s *expenseService) SearchExpenses(ctx context.Context, userID string, input models.SearchExpensesInput) ([]models.ExpenseSearchResult, error) {
+	q := strings.TrimSpace(input.Q)
+	if q == "" {
+		return nil, &ValidationError{Message: "q must be provided"}
+	}
+	if utf8.RuneCountInString(q) > ExpenseSearchQueryMaxLen {
+		return nil, &ValidationError{Message: "q exceeds maximum length"}
+	}
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return nil, &ValidationError{Message: "q must be provided"}
+	}
+	if len(terms) > ExpenseSearchMaxTerms {
+		return nil, &ValidationError{Message: "q has too many terms"}
+	}
+
+	if input.Limit < 0 || input.Limit > ExpenseSearchMaxLimit {
+		return nil, &ValidationError{Message: "limit must be between 1 and 50"}
+	}
+	filter := models.ExpenseSearchFilter{Terms: terms, Limit: input.Limit}
+	if filter.Limit == 0 {
+		filter.Limit = ExpenseSearchDefaultLimit
+	}
+	var err error
+	if filter.CategoryIDs, err = parseCategoryIDs(input.CategoryIDs); err != nil {
+		return nil, err
+	}
+	if filter.Status, err = parseStatusFilter(input.Status); err != nil {
+		return nil, err
+	}
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return []models.ExpenseSearchResult{}, nil
+		}
+		return nil, err
+	}
+
+	results, err := s.repo.SearchExpenses(ctx, ledgerID, filter)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	refs := make([]*models.Expense, 0, len(results))
+	for i := range results {
+		results[i].Highlight = highlightMemo(results[i].Memo, terms)
+		refs = append(refs, &results[i].Expense)
+	}
+	if err := s.attachExpenseTags(ctx, userID, refs); err != nil {
+		return nil, err
+	}
+	if err := s.attachExpensePayees(ctx, refs); err != nil {
+		return nil, err
+	}
+	if err := s.attachExpensePaymentAccounts(ctx, refs); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.ExpenseSearchResult{}
+	}
+	return results, nil
+}
+
+// searchTerms は検索語を空白で区切り、DB と同じ規則で正規化できない（空になる）語と重複を取り除きます。
+func searchTerms(q string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.Fields(q) {
+		key := string(searchRunesOf(normalizeForSearch(term)))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// searchRune は検索用に正規化した 1 文字と、元の文字列での位置（バイト）です。
+type searchRune struct {
+	r          rune
+	start, end int
+}
+
+// normalizeForSearch は db/schema/expenses.sql の expense_memo_normalize と同じく
+// NFKC・小文字化・空白の除去を行い、各文字が元の文字列のどこに由来するかを記録します。
+func normalizeForSearch(s string) []searchRune {
+	var out []searchRune
+	var it norm.Iter
+	it.InitString(norm.NFKC, s)
+	for !it.Done() {
+		start := it.Pos()
+		segment := it.Next()
+		end := it.Pos()
+		for _, r := range strings.ToLower(string(segment)) {
+			if unicode.IsSpace(r) {
+				continue
+			}
+			out = append(out, searchRune{r: r, start: start, end: end})
+		}
+	}
+	return out
+}
+
+func searchRunesOf(rs []searchRune) []rune {
+	out := make([]rune, len(rs))
+	for i, r := range rs {
+		out[i] = r.r
+	}
+	return out
+}
+
+// highlightMemo は memo を terms に一致した部分とそれ以外に分割します。
+func highlightMemo(memo string, terms []string) []models.MemoSegment {
+	if memo == "" {
+		return []models.MemoSegment{}
+	}
+
+	normalized := normalizeForSearch(memo)
+	haystack := searchRunesOf(normalized)
+	matched := make([]bool, len(memo))
+	for _, term := range terms {
+		needle := searchRunesOf(normalizeForSearch(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			if !runesEqual(haystack[i:i+len(needle)], needle) {
+				continue
+			}
+			for b := normalized[i].start; b < normalized[i+len(needle)-1].end; b++ {
+				matched[b] = true
+			}
+		}
+	}
+
+	var segments []models.MemoSegment
+	start := 0
+	for i := 1; i <= len(memo); i++ {
+		if i < len(memo) && matched[i] == matched[start] {
+			continue
+		}
+		segments = append(segments, models.MemoSegment{Text: memo[start:i], Match: matched[start]})
+		start = i
+	}
+	return segments
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// searchExpensesRepo は SearchExpenses に渡された条件を記録し、result を返す ExpenseRepository です。
+type searchExpensesRepo struct {
+	mockRepo
+	filter models.ExpenseSearchFilter
+	result []models.ExpenseSearchResult
+}
+
+func (m *searchExpensesRepo) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	m.filter = filter
+	return m.result, nil
+}
+
+func TestExpenseService_SearchExpenses(t *testing.T) {
+	repo := &searchExpensesRepo{result: []models.ExpenseSearchResult{
+		{Expense: models.Expense{ID: 1, Memo: "スタバでラテ"}, Score: 0.5},
+	}}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	results, err := s.SearchExpenses(context.Background(), "user-1", models.SearchExpensesInput{
+		Q:           " スタバ　ラテ スタバ ",
+		CategoryIDs: []string{"2"},
+		Status:      "Planned",
+	})
+	require.NoError(t, err)
+
+	// 重複した語は 1 つにまとめ、全角空白でも区切る
+	assert.Equal(t, models.ExpenseSearchFilter{
+		Terms:       []string{"スタバ", "ラテ"},
+		CategoryIDs: []int32{2},
+		Status:      "planned",
+		Limit:       ExpenseSearchDefaultLimit,
+	}, repo.filter)
+	require.Len(t, results, 1)
+	assert.Equal(t, []models.MemoSegment{
+		{Text: "スタバ", Match: true},
+		{Text: "で"},
+		{Text: "ラテ", Match: true},
+	}, results[0].Highlight)
+}
+
+func TestExpenseService_SearchExpenses_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		input models.SearchExpensesInput
+	}{
+		{name: "q が空", input: models.SearchExpensesInput{Q: "  "}},
+		{name: "q が長すぎる", input: models.SearchExpensesInput{Q: strings.Repeat("あ", ExpenseSearchQueryMaxLen+1)}},
+		{name: "語が多すぎる", input: models.SearchExpensesInput{Q: "a b c d e f g h i j k"}},
+		{name: "limit が大きすぎる", input: models.SearchExpensesInput{Q: "a", Limit: ExpenseSearchMaxLimit + 1}},
+		{name: "未知の status", input: models.SearchExpensesInput{Q: "a", Status: "paid"}},
+		{name: "category_id が数値でない", input: models.SearchExpensesInput{Q: "a", CategoryIDs: []string{"food"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewExpenseService(&searchExpensesRepo{}, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+			_, err := s.SearchExpenses(context.Background(), "user-1", tc.input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+		})
+	}
+}
+
+func TestHighlightMemo(t *testing.T) {
+	cases := []struct {
+		name  string
+		memo  string
+		terms []string
+		want  []models.MemoSegment
+	}{
+		{
+			name:  "半角カナ・全角英数も正規化して一致させる",
+			memo:  "ｽﾀﾊﾞ ＣＯＦＦＥＥ",
+			terms: []string{"スタバ", "coffee"},
+			want: []models.MemoSegment{
+				{Text: "ｽﾀﾊﾞ", Match: true},
+				{Text: " "},
+				{Text: "ＣＯＦＦＥＥ", Match: true},
+			},
+		},
+		{
+			name:  "空白をまたいで一致する",
+			memo:  "東京 駅",
+			terms: []string{"東京駅"},
+			want:  []models.MemoSegment{{Text: "東京 駅", Match: true}},
+		},
+		{
+			name:  "重なる一致はまとめる",
+			memo:  "ランチ代",
+			terms: []string{"ランチ", "チ代"},
+			want:  []models.MemoSegment{{Text: "ランチ代", Match: true}},
+		},
+		{
+			name:  "一致しない",
+			memo:  "家賃",
+			terms: []string{"電気"},
+			want:  []models.MemoSegment{{Text: "家賃"}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, highlightMemo(tc.memo, tc.terms))
+		})
+	}
+}
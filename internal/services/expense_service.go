@@ -16,68 +16,57 @@ const (
 	BusinessMaxAmount = 1000000000
 	// MemoMaxLen はメモの最大長
 	MemoMaxLen = 5000
+	// ExpenseListDefaultLimit は一覧で limit を省略したときの件数です。
+	ExpenseListDefaultLimit = 50
+	// ExpenseListMaxLimit は一覧で一度に返す最大件数です。
+	ExpenseListMaxLimit = 100
 )
 
 type ExpenseService interface {
-	CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error)
-	ListExpenses(userID string) ([]models.Expense, error)
-	DeleteExpense(userID string, id int) error
-	UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error)
+	CreateExpense(ctx context.Context, userID string, input models.CreateExpenseInput) (models.Expense, error)
+	// ListExpenses は使用中の家計簿の支出を条件で絞り込み、1 ページ分返します。
+	ListExpenses(ctx context.Context, userID string, input models.ListExpensesInput) (models.ExpensePage, error)
+	// SearchExpenses は使用中の家計簿の支出をメモの全文検索で探し、一致度の高い順に返します。
+	SearchExpenses(ctx context.Context, userID string, input models.SearchExpensesInput) ([]models.ExpenseSearchResult, error)
+	// DeleteExpense は支出をゴミ箱に移します。version が nil でなければ、現在のバージョンと
+	// 一致しない場合に VersionConflictError を返します。
+	DeleteExpense(ctx context.Context, userID string, id int, version *int) error
+	// UpdateExpense は支出を置き換えます。input.Version が nil でなければ、現在のバージョンと
+	// 一致しない場合に VersionConflictError を返します。
+	UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error)
+	// PatchExpense は patch で指定した項目だけを変更します。変更後の内容は登録時と同じ検証と
+	// UpdateExpense と同じステータス遷移のルールを通します。version の扱いは DeleteExpense と同じです。
+	PatchExpense(ctx context.Context, userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error)
+	// ConfirmExpense は planned の支出を confirmed にします。実際の金額・日付を指定でき、予定していた値は残します。
+	ConfirmExpense(ctx context.Context, userID string, id int, input models.ConfirmExpenseInput) (models.Expense, error)
+	// ConfirmExpenses は複数の支出をまとめて確定します。1 件でも確定できなければどれも確定しません。
+	ConfirmExpenses(ctx context.Context, userID string, input models.ConfirmExpensesInput) ([]models.Expense, error)
+	// BulkExpenses は作成・更新・削除の操作を 1 つのトランザクションで順に適用します。
+	// 1 件でも失敗した場合はすべてを取り消し、操作ごとの結果とともに ErrBulkExpensesFailed を返します。
+	BulkExpenses(ctx context.Context, userID string, input models.BulkExpensesInput) ([]models.BulkExpenseResult, error)
 }
 
 type expenseService struct {
-	repo         repositories.ExpenseRepository
-	categoryRepo repositories.CategoryRepository
+	repo               repositories.ExpenseRepository
+	categoryRepo       repositories.CategoryRepository
+	tagRepo            repositories.TagRepository
+	payeeRepo          repositories.PayeeRepository
+	paymentAccountRepo repositories.PaymentAccountRepository
+	ledgerRepo         repositories.LedgerRepository
+	auditRepo          repositories.AuditRepository
+	txManager          TxManager
 }
 
-func NewExpenseService(repo repositories.ExpenseRepository, categoryRepo repositories.CategoryRepository) ExpenseService {
-	return &expenseService{repo: repo, categoryRepo: categoryRepo}
+func NewExpenseService(repo repositories.ExpenseRepository, categoryRepo repositories.CategoryRepository, tagRepo repositories.TagRepository, payeeRepo repositories.PayeeRepository, paymentAccountRepo repositories.PaymentAccountRepository, ledgerRepo repositories.LedgerRepository, auditRepo repositories.AuditRepository, txManager TxManager) ExpenseService {
+	return &expenseService{repo: repo, categoryRepo: categoryRepo, tagRepo: tagRepo, payeeRepo: payeeRepo, paymentAccountRepo: paymentAccountRepo, ledgerRepo: ledgerRepo, auditRepo: auditRepo, txManager: txManager}
 }
 
-func (s *expenseService) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
-	// 金額チェック: 入力が存在するかをまず確認し、その後業務上の制約を確認する
-	if input.Amount == nil {
-		return models.Expense{}, &ValidationError{Message: "amount must be provided"}
-	}
-	if *input.Amount <= 0 {
-		return models.Expense{}, &ValidationError{Message: "amount must be greater than 0"}
-	}
-	if *input.Amount > BusinessMaxAmount {
-		return models.Expense{}, &ValidationError{Message: "amount exceeds maximum allowed"}
-	}
-
-	// カテゴリID チェック
-	if input.CategoryID == nil {
-		return models.Expense{}, &ValidationError{Message: "category_id must be provided"}
+func (s *expenseService) CreateExpense(ctx context.Context, userID string, input models.CreateExpenseInput) (models.Expense, error) {
+	if err := validateExpenseFields(input.Amount, input.CategoryID, input.SpentAt, input.Memo); err != nil {
+		return models.Expense{}, err
 	}
-	if *input.CategoryID <= 0 {
-		return models.Expense{}, &ValidationError{Message: "category_id must be greater than 0"}
-	}
-
-	// SpentAt の非空チェック
-	if input.SpentAt == "" {
-		return models.Expense{}, &ValidationError{Message: "spent_at must be provided"}
-	}
-
-	// 日付フォーマットの検証（RFC3339 をまず試し、失敗したら日付のみフォーマットを試す）
-	var spentAt time.Time
-	var err error
-	spentAt, err = time.Parse(time.RFC3339, input.SpentAt)
-	if err != nil {
-		spentAt, err = time.Parse("2006-01-02", input.SpentAt)
-		if err != nil {
-			return models.Expense{}, &ValidationError{Message: "spent_at is invalid"}
-		}
-		// 日付のみの場合は UTC の 00:00 として扱う
-		spentAt = time.Date(spentAt.Year(), spentAt.Month(), spentAt.Day(), 0, 0, 0, 0, time.UTC)
-	}
-	if spentAt.IsZero() {
-		return models.Expense{}, &ValidationError{Message: "spent_at must be a non-zero time"}
-	}
-
-	// Memo 長チェック
-	if len(input.Memo) > MemoMaxLen {
-		return models.Expense{}, &ValidationError{Message: "memo exceeds maximum length"}
+	if err := validateExpenseSplits(*input.Amount, input.Splits); err != nil {
+		return models.Expense{}, err
 	}
 
 	// Status の検証（任意入力、指定されている場合のみチェック）
@@ -91,7 +80,7 @@ func (s *expenseService) CreateExpense(userID string, input models.CreateExpense
 	}
 
 	// カテゴリ存在チェック（CategoryExists を用いる）
-	exists, err := s.categoryRepo.CategoryExists(context.Background(), int32(*input.CategoryID))
+	exists, err := s.categoryRepo.CategoryExists(ctx, int32(*input.CategoryID))
 	if err != nil {
 		// リポジトリ/DB からのエラーは内部エラーとして扱う
 		return models.Expense{}, &InternalError{Message: "internal error"}
@@ -99,9 +88,38 @@ func (s *expenseService) CreateExpense(userID string, input models.CreateExpense
 	if !exists {
 		return models.Expense{}, &ValidationError{Message: "category_id is invalid"}
 	}
+	if err := s.checkSplitCategories(ctx, input.Splits); err != nil {
+		return models.Expense{}, err
+	}
+	tags, err := s.checkExpenseTags(ctx, userID, input.TagIDs)
+	if err != nil {
+		return models.Expense{}, err
+	}
+	payee, err := s.checkExpensePayee(ctx, userID, input.PayeeID, nil)
+	if err != nil {
+		return models.Expense{}, err
+	}
+	account, err := s.checkExpensePaymentAccount(ctx, userID, input.PaymentAccountID, nil)
+	if err != nil {
+		return models.Expense{}, err
+	}
 
-	exp, err := s.repo.CreateExpense(userID, input)
+	// 支出はユーザーが使用中の家計簿に登録する（viewer は登録できない）
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
 	if err != nil {
+		return models.Expense{}, err
+	}
+
+	// 作成と操作の記録を 1 つのトランザクションで行う
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	exp, err := s.repo.CreateExpense(txCtx, ledgerID, userID, input)
+	if err != nil {
+		_ = tx.Rollback()
 		// sql.ErrNoRows -> NotFoundError
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Expense{}, &NotFoundError{Message: "expense not found"}
@@ -118,39 +136,235 @@ func (s *expenseService) CreateExpense(userID string, input models.CreateExpense
 		// その他は内部エラーとしてラップして返す
 		return models.Expense{}, &InternalError{Message: "internal error"}
 	}
+	if len(tags) > 0 {
+		if err := s.setExpenseTags(txCtx, userID, &exp, tags); err != nil {
+			_ = tx.Rollback()
+			return models.Expense{}, err
+		}
+	}
+	if payee != nil {
+		if err := s.setExpensePayee(txCtx, &exp, payee); err != nil {
+			_ = tx.Rollback()
+			return models.Expense{}, err
+		}
+	}
+	if account != nil {
+		if err := s.setExpensePaymentAccount(txCtx, &exp, account); err != nil {
+			_ = tx.Rollback()
+			return models.Expense{}, err
+		}
+	}
+
+	event := expenseAuditEvent(userID, ledgerID, models.AuditActionExpenseCreate, exp.ID)
+	if err := recordAudit(txCtx, s.auditRepo, event, nil, expenseAuditFields(exp)); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
 
 	return exp, nil
 }
 
-func (s *expenseService) ListExpenses(userID string) ([]models.Expense, error) {
-	return s.repo.FindAll(userID)
+func (s *expenseService) ListExpenses(ctx context.Context, userID string, input models.ListExpensesInput) (models.ExpensePage, error) {
+	filter, err := expenseFilterFromInput(input)
+	if err != nil {
+		return models.ExpensePage{}, err
+	}
+	limit := filter.Limit
+	// 次のページの有無を判定するため 1 件多く取得する
+	filter.Limit++
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		// 初期設定前など家計簿が無い場合は空の一覧を返す
+		if errors.Is(err, ErrNoActiveLedger) {
+			return models.ExpensePage{Expenses: []models.Expense{}}, nil
+		}
+		return models.ExpensePage{}, err
+	}
+	// 他のユーザーのタグでは絞り込めない
+	if len(filter.TagIDs) > 0 {
+		tags, err := s.tagRepo.ListTagsByIDs(ctx, userID, filter.TagIDs)
+		if err != nil {
+			return models.ExpensePage{}, &InternalError{Message: "internal error"}
+		}
+		owned := make(map[int32]bool, len(tags))
+		for _, t := range tags {
+			owned[int32(t.ID)] = true
+		}
+		for _, id := range filter.TagIDs {
+			if !owned[id] {
+				return models.ExpensePage{}, &ValidationError{Message: "tag_id is invalid"}
+			}
+		}
+	}
+
+	expenses, err := s.repo.FindAll(ctx, ledgerID, filter)
+	if err != nil {
+		return models.ExpensePage{}, &InternalError{Message: "internal error"}
+	}
+
+	page := models.ExpensePage{Expenses: expenses}
+	if len(expenses) > limit {
+		page.Expenses = expenses[:limit]
+		next, err := encodeExpenseCursor(filter, page.Expenses[limit-1])
+		if err != nil {
+			return models.ExpensePage{}, &InternalError{Message: "internal error"}
+		}
+		page.NextCursor = &next
+	}
+	refs := make([]*models.Expense, 0, len(page.Expenses))
+	for i := range page.Expenses {
+		refs = append(refs, &page.Expenses[i])
+	}
+	if err := s.attachExpenseTags(ctx, userID, refs); err != nil {
+		return models.ExpensePage{}, err
+	}
+	if err := s.attachExpensePayees(ctx, refs); err != nil {
+		return models.ExpensePage{}, err
+	}
+	if err := s.attachExpensePaymentAccounts(ctx, refs); err != nil {
+		return models.ExpensePage{}, err
+	}
+	if page.Expenses == nil {
+		page.Expenses = []models.Expense{}
+	}
+	return page, nil
 }
 
-func (s *expenseService) DeleteExpense(userID string, id int) error {
-	expense, err := s.repo.GetExpenseByID(userID, int32(id))
+func (s *expenseService) DeleteExpense(ctx context.Context, userID string, id int, version *int) error {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	expense, err := s.repo.GetExpenseByID(txCtx, ledgerID, int32(id))
 	if err != nil {
+		_ = tx.Rollback()
 		if errors.Is(err, sql.ErrNoRows) {
 			return &NotFoundError{Message: "expense not found"}
 		}
 		return &InternalError{Message: "internal error"}
 	}
-	if expense == (models.Expense{}) {
+	if expense.ID == 0 {
+		_ = tx.Rollback()
 		return &NotFoundError{Message: "expense not found"}
 	}
+	if version != nil && *version != expense.Version {
+		_ = tx.Rollback()
+		return &VersionConflictError{Message: "expense has been modified", Current: expense}
+	}
+	if err := s.attachExpensePayees(txCtx, []*models.Expense{&expense}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := s.attachExpensePaymentAccounts(txCtx, []*models.Expense{&expense}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := s.repo.DeleteExpense(txCtx, ledgerID, int32(id), version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = s.versionConflict(txCtx, ledgerID, id)
+		}
+		_ = tx.Rollback()
+		return err
+	}
+
+	event := expenseAuditEvent(userID, ledgerID, models.AuditActionExpenseDelete, id)
+	if err := recordAudit(txCtx, s.auditRepo, event, expenseAuditFields(expense), nil); err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
 
-	return s.repo.DeleteExpense(userID, int32(id))
+func (s *expenseService) UpdateExpense(ctx context.Context, userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+	return s.updateExpense(ctx, userID, input.ID, input.Version, func(context.Context, models.Expense) (models.UpdateExpenseInput, error) {
+		return input, nil
+	})
 }
 
-func (s *expenseService) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (s *expenseService) PatchExpense(ctx context.Context, userID string, id int, patch models.ExpensePatch, version *int) (models.Expense, error) {
+	return s.updateExpense(ctx, userID, id, version, func(txCtx context.Context, current models.Expense) (models.UpdateExpenseInput, error) {
+		input := applyExpensePatch(current, patch)
+		if err := validateExpenseFields(input.Amount, input.CategoryID, input.SpentAt, input.Memo); err != nil {
+			return models.UpdateExpenseInput{}, err
+		}
+		if *input.CategoryID != current.Category.ID {
+			exists, err := s.categoryRepo.CategoryExists(txCtx, int32(*input.CategoryID))
+			if err != nil {
+				return models.UpdateExpenseInput{}, &InternalError{Message: "internal error"}
+			}
+			if !exists {
+				return models.UpdateExpenseInput{}, &ValidationError{Message: "category_id is invalid"}
+			}
+		}
+		return input, nil
+	})
+}
+
+// updateExpense は PUT と PATCH に共通の更新処理です。
+// version が nil でなければ現在のバージョンと比べ、build は現在の状態から更新内容を組み立てます。
+// その後にステータス遷移のルールを確認します。
+func (s *expenseService) updateExpense(ctx context.Context, userID string, id int, version *int, build func(txCtx context.Context, current models.Expense) (models.UpdateExpenseInput, error)) (models.Expense, error) {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.Expense{}, err
+	}
+
+	// 更新と操作の記録を 1 つのトランザクションで行い、記録する変更前の状態もその中で読む
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
 	// 現在の状態を取得し、ステータス遷移のバリデーションを行う
-	current, err := s.repo.GetExpenseByID(userID, int32(input.ID))
+	current, err := s.repo.GetExpenseByID(txCtx, ledgerID, int32(id))
 	if err != nil {
-		// テスト仕様に合わせ、見つからない場合も遷移エラーとして扱う
+		_ = tx.Rollback()
+		// 以前は見つからない場合も遷移エラーとして返していたため、ErrInvalidStatusTransition としても判定できるようにする
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.Expense{}, ErrInvalidStatusTransition
+			return models.Expense{}, &NotFoundError{Message: "expense not found", Err: ErrInvalidStatusTransition}
 		}
 		return models.Expense{}, &InternalError{Message: "internal error"}
 	}
+	if version != nil && *version != current.Version {
+		_ = tx.Rollback()
+		return models.Expense{}, &VersionConflictError{Message: "expense has been modified", Current: current}
+	}
+	if err := s.attachExpenseTags(txCtx, userID, []*models.Expense{&current}); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	if err := s.attachExpensePayees(txCtx, []*models.Expense{&current}); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	if err := s.attachExpensePaymentAccounts(txCtx, []*models.Expense{&current}); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+
+	input, err := build(txCtx, current)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
 
 	// 変更後ステータスの決定（未指定なら現状維持）
 	desiredStatus := input.Status
@@ -160,16 +374,197 @@ func (s *expenseService) UpdateExpense(userID string, input models.UpdateExpense
 		if normalized, ok := models.NormalizeStatus(desiredStatus); ok {
 			desiredStatus = normalized
 		} else {
+			_ = tx.Rollback()
 			return models.Expense{}, &ValidationError{Message: "status must be 'planned' or 'confirmed'"}
 		}
 	}
 
 	// 遷移ルール: confirmed → planned は禁止
 	if strings.ToLower(current.Status) == "confirmed" && desiredStatus == "planned" {
+		_ = tx.Rollback()
 		return models.Expense{}, ErrInvalidStatusTransition
 	}
 
+	// 内訳は PUT・PATCH のどちらでも、変更後の金額と合計が一致しなければならない
+	if err := validateExpenseSplits(*input.Amount, input.Splits); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	if err := s.checkSplitCategories(txCtx, input.Splits); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	// 返金の合計は支出の金額を超えられないため、返金済みの額より少なくはできない
+	if *input.Amount < current.RefundedAmount {
+		_ = tx.Rollback()
+		return models.Expense{}, &ValidationError{Message: "amount must not be less than the refunded amount"}
+	}
+	tags, err := s.checkExpenseTags(txCtx, userID, input.TagIDs)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	payee, err := s.checkExpensePayee(txCtx, userID, input.PayeeID, current.Payee)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	account, err := s.checkExpensePaymentAccount(txCtx, userID, input.PaymentAccountID, current.PaymentAccount)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+
 	// リポジトリに渡す前に正規化済みステータスをセット
 	input.Status = desiredStatus
-	return s.repo.UpdateExpense(userID, input)
+	// 読み取ってから更新するまでに他の端末が更新した場合も、リポジトリがバージョンを比べて更新しない
+	input.Version = version
+	updated, err := s.repo.UpdateExpense(txCtx, ledgerID, input)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = s.versionConflict(txCtx, ledgerID, id)
+		}
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	if err := s.setExpenseTags(txCtx, userID, &updated, tags); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	if err := s.setExpensePayee(txCtx, &updated, payee); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+	if err := s.setExpensePaymentAccount(txCtx, &updated, account); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, err
+	}
+
+	event := expenseAuditEvent(userID, ledgerID, models.AuditActionExpenseUpdate, input.ID)
+	if err := recordAudit(txCtx, s.auditRepo, event, expenseAuditFields(current), expenseAuditFields(updated)); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	return updated, nil
+}
+
+// versionConflict は条件付きの更新・削除が行われなかったときのエラーを、現在の状態を読み直して作ります。
+// 支出が削除されていた場合は NotFoundError を返します。
+func (s *expenseService) versionConflict(ctx context.Context, ledgerID int32, id int) error {
+	current, err := s.repo.GetExpenseByID(ctx, ledgerID, int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &NotFoundError{Message: "expense not found"}
+		}
+		return &InternalError{Message: "internal error"}
+	}
+	return &VersionConflictError{Message: "expense has been modified", Current: current}
+}
+
+// applyExpensePatch は current に patch を重ねた更新内容を返します。
+func applyExpensePatch(current models.Expense, patch models.ExpensePatch) models.UpdateExpenseInput {
+	amount := current.Amount
+	categoryID := current.Category.ID
+	input := models.UpdateExpenseInput{
+		ID:         current.ID,
+		Amount:     &amount,
+		CategoryID: &categoryID,
+		Memo:       current.Memo,
+		SpentAt:    current.SpentAt,
+	}
+	if patch.Amount != nil {
+		input.Amount = patch.Amount
+	}
+	if patch.CategoryID != nil {
+		input.CategoryID = patch.CategoryID
+	}
+	if patch.Memo != nil {
+		input.Memo = *patch.Memo
+	}
+	if patch.SpentAt != nil {
+		input.SpentAt = *patch.SpentAt
+	}
+	if patch.Status != nil {
+		input.Status = *patch.Status
+	}
+	if patch.Splits != nil {
+		input.Splits = *patch.Splits
+	} else {
+		input.Splits = expenseSplitInputs(current.Splits)
+	}
+	if patch.TagIDs != nil {
+		input.TagIDs = *patch.TagIDs
+	} else {
+		input.TagIDs = expenseTagIDs(current.Tags)
+	}
+	if patch.PayeeID != nil {
+		// 0 は null の指定で、支払先を外す
+		if *patch.PayeeID != 0 {
+			input.PayeeID = patch.PayeeID
+		}
+	} else if current.Payee != nil {
+		payeeID := current.Payee.ID
+		input.PayeeID = &payeeID
+	}
+	if patch.PaymentAccountID != nil {
+		// 0 は null の指定で、支払い手段を外す
+		if *patch.PaymentAccountID != 0 {
+			input.PaymentAccountID = patch.PaymentAccountID
+		}
+	} else if current.PaymentAccount != nil {
+		accountID := current.PaymentAccount.ID
+		input.PaymentAccountID = &accountID
+	}
+	return input
+}
+
+// validateExpenseFields は支出の登録・部分更新で共通の入力チェックです（カテゴリの存在は確認しません）。
+func validateExpenseFields(amount *int, categoryID *int, spentAtStr string, memo string) error {
+	// 金額チェック: 入力が存在するかをまず確認し、その後業務上の制約を確認する
+	if amount == nil {
+		return &ValidationError{Message: "amount must be provided"}
+	}
+	if *amount <= 0 {
+		return &ValidationError{Message: "amount must be greater than 0"}
+	}
+	if *amount > BusinessMaxAmount {
+		return &ValidationError{Message: "amount exceeds maximum allowed"}
+	}
+
+	// カテゴリID チェック
+	if categoryID == nil {
+		return &ValidationError{Message: "category_id must be provided"}
+	}
+	if *categoryID <= 0 {
+		return &ValidationError{Message: "category_id must be greater than 0"}
+	}
+
+	// SpentAt の非空チェック
+	if spentAtStr == "" {
+		return &ValidationError{Message: "spent_at must be provided"}
+	}
+
+	// 日付フォーマットの検証（RFC3339 をまず試し、失敗したら日付のみフォーマットを試す）
+	spentAt, err := time.Parse(time.RFC3339, spentAtStr)
+	if err != nil {
+		spentAt, err = time.Parse("2006-01-02", spentAtStr)
+		if err != nil {
+			return &ValidationError{Message: "spent_at is invalid"}
+		}
+		// 日付のみの場合は UTC の 00:00 として扱う
+		spentAt = time.Date(spentAt.Year(), spentAt.Month(), spentAt.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if spentAt.IsZero() {
+		return &ValidationError{Message: "spent_at must be a non-zero time"}
+	}
+
+	// Memo 長チェック
+	if len(memo) > MemoMaxLen {
+		return &ValidationError{Message: "memo exceeds maximum length"}
+	}
+	return nil
 }
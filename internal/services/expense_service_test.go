@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"money-buddy-backend/internal/models"
 )
@@ -17,23 +19,31 @@ type mockRepo struct {
 	in     models.CreateExpenseInput
 }
 
-func (m *mockRepo) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+func (m *mockRepo) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
 	m.called = true
 	m.in = input
 	return models.Expense{ID: 1, Amount: *input.Amount, Memo: input.Memo, SpentAt: input.SpentAt, Category: models.Category{ID: *input.CategoryID, Name: ""}}, nil
 }
 
-func (m *mockRepo) FindAll(userID string) ([]models.Expense, error) {
+func (m *mockRepo) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockRepo) GetExpenseByID(userID string, id int32) (models.Expense, error) {
+func (m *mockRepo) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockRepo) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	return models.Expense{}, errors.New("not implemented")
+}
+
+func (m *mockRepo) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
-func (m *mockRepo) DeleteExpense(userID string, id int32) error { return errors.New("not implemented") }
+func (m *mockRepo) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error { return errors.New("not implemented") }
 
-func (m *mockRepo) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *mockRepo) UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
@@ -89,9 +99,9 @@ func TestCreateExpenseValidation(t *testing.T) {
 				exists[int32(*tc.input.CategoryID)] = true
 			}
 			cr := &mockCategoryRepo{exists: exists}
-			s := NewExpenseService(m, cr)
+			s := NewExpenseService(m, cr, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
 
-			out, err := s.CreateExpense("test-user", tc.input)
+			out, err := s.CreateExpense(context.Background(), "test-user", tc.input)
 
 			if tc.wantErr {
 				if !assert.Error(t, err, "expected error for case %s", tc.name) {
@@ -135,9 +145,9 @@ func TestCreateExpense_DBErrorMapping(t *testing.T) {
 			t.Parallel()
 			m := &mockRepoErr{returnErr: tc.repoErr}
 			cr := &mockCategoryRepo{exists: map[int32]bool{1: true}}
-			s := NewExpenseService(m, cr)
+			s := NewExpenseService(m, cr, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
 
-			_, err := s.CreateExpense("test-user", validInput)
+			_, err := s.CreateExpense(context.Background(), "test-user", validInput)
 			if !assert.Error(t, err) {
 				return
 			}
@@ -174,19 +184,27 @@ type mockRepoErr struct {
 	returnErr error
 }
 
-func (m *mockRepoErr) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+func (m *mockRepoErr) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, m.returnErr
 }
 
-func (m *mockRepoErr) FindAll(userID string) ([]models.Expense, error) { return nil, errors.New("not implemented") }
+func (m *mockRepoErr) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) { return nil, errors.New("not implemented") }
+
+func (m *mockRepoErr) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockRepoErr) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	return models.Expense{}, errors.New("not implemented")
+}
 
-func (m *mockRepoErr) GetExpenseByID(userID string, id int32) (models.Expense, error) {
+func (m *mockRepoErr) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
-func (m *mockRepoErr) DeleteExpense(userID string, id int32) error { return errors.New("not implemented") }
+func (m *mockRepoErr) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error { return errors.New("not implemented") }
 
-func (m *mockRepoErr) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *mockRepoErr) UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
@@ -200,9 +218,9 @@ func TestCreateExpense_CategoryExistsError(t *testing.T) {
 
 	m := &mockRepo{}
 	cr := &mockCategoryRepo{err: errors.New("db error")}
-	s := NewExpenseService(m, cr)
+	s := NewExpenseService(m, cr, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
 
-	_, err := s.CreateExpense("test-user", input)
+	_, err := s.CreateExpense(context.Background(), "test-user", input)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -256,9 +274,9 @@ func TestCreateExpense_StatusValidation(t *testing.T) {
 				exists[int32(*tc.input.CategoryID)] = true
 			}
 			cr := &mockCategoryRepo{exists: exists}
-			s := NewExpenseService(m, cr)
+			s := NewExpenseService(m, cr, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
 
-			_, err := s.CreateExpense("test-user", tc.input)
+			_, err := s.CreateExpense(context.Background(), "test-user", tc.input)
 
 			if tc.wantErr {
 				if !assert.Error(t, err) {
@@ -283,26 +301,34 @@ type mockDeleteRepo struct {
 	returnErr error
 }
 
-func (m *mockDeleteRepo) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+func (m *mockDeleteRepo) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
-func (m *mockDeleteRepo) FindAll(userID string) ([]models.Expense, error) {
+func (m *mockDeleteRepo) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockDeleteRepo) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockDeleteRepo) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	return models.Expense{}, errors.New("not implemented")
+}
+
 // DeleteExpense is the method under test expectation
-func (m *mockDeleteRepo) DeleteExpense(userID string, id int32) error {
+func (m *mockDeleteRepo) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error {
 	m.called = true
 	m.deletedID = id
 	return m.returnErr
 }
 
-func (m *mockDeleteRepo) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *mockDeleteRepo) UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
-func (m *mockDeleteRepo) GetExpenseByID(userID string, id int32) (models.Expense, error) {
+func (m *mockDeleteRepo) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
 	// simulate existence: 9999 -> not found, others exist
 	if id == 9999 {
 		return models.Expense{}, sqlErrNoRows()
@@ -321,9 +347,9 @@ func TestDeleteExpense_Success(t *testing.T) {
 	// category repo is unused for delete
 	cr := &mockCategoryRepo{}
 	// Construct concrete service to allow calling DeleteExpense (to be implemented)
-	s := &expenseService{repo: repo, categoryRepo: cr}
+	s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
-	err := s.DeleteExpense("test-user", 1)
+	err := s.DeleteExpense(context.Background(), "test-user", 1, nil)
 	assert.NoError(t, err)
 	assert.True(t, repo.called, "repo should be called")
 	assert.Equal(t, int32(1), repo.deletedID)
@@ -334,9 +360,9 @@ func TestDeleteExpense_NotFound(t *testing.T) {
 
 	repo := &mockDeleteRepo{returnErr: sqlErrNoRows()}
 	cr := &mockCategoryRepo{}
-	s := &expenseService{repo: repo, categoryRepo: cr}
+	s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
-	err := s.DeleteExpense("test-user", 9999)
+	err := s.DeleteExpense(context.Background(), "test-user", 9999, nil)
 	var nfe *NotFoundError
 	if !assert.ErrorAs(t, err, &nfe) {
 		return
@@ -360,9 +386,9 @@ func TestDeleteExpense_StatusAgnostic(t *testing.T) {
 
 			repo := &mockDeleteRepo{returnErr: nil}
 			cr := &mockCategoryRepo{}
-			s := &expenseService{repo: repo, categoryRepo: cr}
+			s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
-			err := s.DeleteExpense("test-user", tc.id)
+			err := s.DeleteExpense(context.Background(), "test-user", tc.id, nil)
 			assert.NoError(t, err)
 			assert.True(t, repo.called)
 			assert.Equal(t, int32(tc.id), repo.deletedID)
@@ -379,25 +405,49 @@ type mockUpdateRepo struct {
 	getErr    error
 }
 
-func (m *mockUpdateRepo) CreateExpense(userID string, input models.CreateExpenseInput) (models.Expense, error) {
+func (m *mockUpdateRepo) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
 	return models.Expense{}, errors.New("not implemented")
 }
 
-func (m *mockUpdateRepo) FindAll(userID string) ([]models.Expense, error) {
+func (m *mockUpdateRepo) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockUpdateRepo) GetExpenseByID(userID string, id int32) (models.Expense, error) {
+func (m *mockUpdateRepo) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+// ConfirmExpense confirms the current expense, keeping the planned amount and date
+func (m *mockUpdateRepo) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	m.called = true
+	if m.returnErr != nil {
+		return models.Expense{}, m.returnErr
+	}
+	e := m.current
+	plannedAmount, plannedSpentAt, confirmedAt := e.Amount, e.SpentAt, "2025-03-02T09:00:00Z"
+	e.PlannedAmount, e.PlannedSpentAt, e.ConfirmedAt = &plannedAmount, &plannedSpentAt, &confirmedAt
+	if input.Amount != nil {
+		e.Amount = *input.Amount
+	}
+	if input.SpentAt != "" {
+		e.SpentAt = input.SpentAt
+	}
+	e.Status = "confirmed"
+	m.current = e
+	return e, nil
+}
+
+func (m *mockUpdateRepo) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
 	if m.getErr != nil {
 		return models.Expense{}, m.getErr
 	}
 	return m.current, nil
 }
 
-func (m *mockUpdateRepo) DeleteExpense(userID string, id int32) error { return errors.New("not implemented") }
+func (m *mockUpdateRepo) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error { return errors.New("not implemented") }
 
 // UpdateExpense updates fields; if Status is empty, keep current status
-func (m *mockUpdateRepo) UpdateExpense(userID string, input models.UpdateExpenseInput) (models.Expense, error) {
+func (m *mockUpdateRepo) UpdateExpense(ctx context.Context, ledgerID int32, input models.UpdateExpenseInput) (models.Expense, error) {
 	m.called = true
 	m.in = input
 	if m.returnErr != nil {
@@ -428,7 +478,7 @@ func TestUpdateExpense_NormalCases(t *testing.T) {
 
 		repo := &mockUpdateRepo{current: models.Expense{ID: 1, Amount: 100, Memo: "old", SpentAt: "2025-01-01", Status: "planned", Category: models.Category{ID: 1}}}
 		cr := &mockCategoryRepo{}
-		s := &expenseService{repo: repo, categoryRepo: cr}
+		s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
 		input := models.UpdateExpenseInput{
 			ID:         1,
@@ -438,7 +488,7 @@ func TestUpdateExpense_NormalCases(t *testing.T) {
 			SpentAt:    "2025-02-01",
 			Status:     "confirmed",
 		}
-		out, err := s.UpdateExpense("test-user", input)
+		out, err := s.UpdateExpense(context.Background(), "test-user", input)
 
 		assert.NoError(t, err)
 		assert.True(t, repo.called)
@@ -455,7 +505,7 @@ func TestUpdateExpense_NormalCases(t *testing.T) {
 
 		repo := &mockUpdateRepo{current: models.Expense{ID: 2, Amount: 300, Memo: "c-old", SpentAt: "2025-03-01", Status: "confirmed", Category: models.Category{ID: 3}}}
 		cr := &mockCategoryRepo{}
-		s := &expenseService{repo: repo, categoryRepo: cr}
+		s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
 		input := models.UpdateExpenseInput{
 			ID:         2,
@@ -465,7 +515,7 @@ func TestUpdateExpense_NormalCases(t *testing.T) {
 			SpentAt:    "2025-03-15",
 			Status:     "", // no change
 		}
-		out, err := s.UpdateExpense("test-user", input)
+		out, err := s.UpdateExpense(context.Background(), "test-user", input)
 
 		assert.NoError(t, err)
 		assert.True(t, repo.called)
@@ -482,7 +532,7 @@ func TestUpdateExpense_NormalCases(t *testing.T) {
 
 		repo := &mockUpdateRepo{current: models.Expense{ID: 3, Amount: 500, Memo: "p-old", SpentAt: "2025-04-01", Status: "planned", Category: models.Category{ID: 5}}}
 		cr := &mockCategoryRepo{}
-		s := &expenseService{repo: repo, categoryRepo: cr}
+		s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
 		input := models.UpdateExpenseInput{
 			ID:         3,
@@ -492,7 +542,7 @@ func TestUpdateExpense_NormalCases(t *testing.T) {
 			SpentAt:    "2025-04-10",
 			Status:     "", // no change
 		}
-		out, err := s.UpdateExpense("test-user", input)
+		out, err := s.UpdateExpense(context.Background(), "test-user", input)
 
 		assert.NoError(t, err)
 		assert.True(t, repo.called)
@@ -509,7 +559,7 @@ func TestUpdateExpense_InvalidTransition_ConfirmedToPlanned(t *testing.T) {
 
 	repo := &mockUpdateRepo{current: models.Expense{ID: 100, Amount: 1000, Memo: "confirmed item", SpentAt: "2025-05-01", Status: "confirmed", Category: models.Category{ID: 10}}}
 	cr := &mockCategoryRepo{}
-	s := &expenseService{repo: repo, categoryRepo: cr}
+	s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
 	input := models.UpdateExpenseInput{
 		ID:         100,
@@ -520,7 +570,7 @@ func TestUpdateExpense_InvalidTransition_ConfirmedToPlanned(t *testing.T) {
 		Status:     "planned",
 	}
 
-	_, err := s.UpdateExpense("test-user", input)
+	_, err := s.UpdateExpense(context.Background(), "test-user", input)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -537,7 +587,7 @@ func TestUpdateExpense_NotFound(t *testing.T) {
 
 	repo := &mockUpdateRepo{getErr: sqlErrNoRows()}
 	cr := &mockCategoryRepo{}
-	s := &expenseService{repo: repo, categoryRepo: cr}
+	s := &expenseService{repo: repo, categoryRepo: cr, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
 
 	input := models.UpdateExpenseInput{
 		ID:         9999,
@@ -548,7 +598,7 @@ func TestUpdateExpense_NotFound(t *testing.T) {
 		Status:     "planned",
 	}
 
-	_, err := s.UpdateExpense("test-user", input)
+	_, err := s.UpdateExpense(context.Background(), "test-user", input)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -559,3 +609,338 @@ func TestUpdateExpense_NotFound(t *testing.T) {
 	// Update が呼ばれないこと
 	assert.False(t, repo.called)
 }
+
+// 存在しない支出の更新は NotFoundError になり、ハンドラーが 404 にする（PUT・PATCH とも）。
+// 以前の ErrInvalidStatusTransition としての判定（TestUpdateExpense_NotFound）も残す
+func TestUpdateExpense_NotFoundError(t *testing.T) {
+	t.Parallel()
+
+	newService := func(repo *mockUpdateRepo) *expenseService {
+		return &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+	}
+	memo := "x"
+
+	t.Run("PUT", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{getErr: sqlErrNoRows()}
+		_, err := newService(repo).UpdateExpense(context.Background(), "test-user", models.UpdateExpenseInput{
+			ID:         9999,
+			Amount:     intPtr(500),
+			CategoryID: intPtr(1),
+			SpentAt:    "2025-06-01",
+			Status:     "planned",
+		})
+		var nfe *NotFoundError
+		require.ErrorAs(t, err, &nfe)
+		assert.Equal(t, "expense not found", nfe.Message)
+		assert.False(t, repo.called)
+	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{getErr: sqlErrNoRows()}
+		_, err := newService(repo).PatchExpense(context.Background(), "test-user", 9999, models.ExpensePatch{Memo: &memo}, nil)
+		var nfe *NotFoundError
+		require.ErrorAs(t, err, &nfe)
+		assert.Equal(t, "expense not found", nfe.Message)
+		assert.False(t, repo.called)
+	})
+}
+
+func TestPatchExpense(t *testing.T) {
+	t.Parallel()
+
+	current := models.Expense{ID: 5, Amount: 800, Memo: "lunch", SpentAt: "2025-03-01T00:00:00Z", Status: "planned", Category: models.Category{ID: 2}}
+	strPtr := func(s string) *string { return &s }
+
+	t.Run("指定した項目だけを変更し、memo の null で消す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{current: current}
+		s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+		out, err := s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{Memo: strPtr(""), Status: strPtr("Confirmed")}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, models.UpdateExpenseInput{
+			ID:         5,
+			Amount:     intPtr(800),
+			CategoryID: intPtr(2),
+			Memo:       "",
+			SpentAt:    "2025-03-01T00:00:00Z",
+			Status:     "confirmed",
+		}, repo.in)
+		assert.Equal(t, "", out.Memo)
+		assert.Equal(t, "confirmed", out.Status)
+	})
+
+	t.Run("変更後の内容を検証する", func(t *testing.T) {
+		t.Parallel()
+
+		patches := []models.ExpensePatch{
+			{Amount: intPtr(0)},
+			{Amount: intPtr(BusinessMaxAmount + 1)},
+			{CategoryID: intPtr(-1)},
+			{SpentAt: strPtr("yesterday")},
+			{Memo: strPtr(strings.Repeat("a", MemoMaxLen+1))},
+			{Status: strPtr("paid")},
+		}
+		for _, patch := range patches {
+			repo := &mockUpdateRepo{current: current}
+			s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+			_, err := s.PatchExpense(context.Background(), "test-user", 5, patch, nil)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			assert.False(t, repo.called)
+		}
+	})
+
+	t.Run("カテゴリを変える場合は存在を確認する", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{current: current}
+		s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{exists: map[int32]bool{3: true}}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+		out, err := s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{CategoryID: intPtr(3)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, out.Category.ID)
+		assert.Equal(t, "lunch", out.Memo)
+
+		_, err = s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{CategoryID: intPtr(4)}, nil)
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("confirmed から planned には戻せない", func(t *testing.T) {
+		t.Parallel()
+
+		confirmed := current
+		confirmed.Status = "confirmed"
+		repo := &mockUpdateRepo{current: confirmed}
+		s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+		_, err := s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{Status: strPtr("planned")}, nil)
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		assert.False(t, repo.called)
+	})
+}
+
+func TestUpdateExpense_IfMatch(t *testing.T) {
+	t.Parallel()
+
+	current := models.Expense{ID: 5, Amount: 800, SpentAt: "2025-03-01T00:00:00Z", Status: "planned", Category: models.Category{ID: 2}, Version: 3}
+	input := func(version int) models.UpdateExpenseInput {
+		return models.UpdateExpenseInput{ID: 5, Amount: intPtr(900), CategoryID: intPtr(2), SpentAt: "2025-03-01", Version: &version}
+	}
+
+	t.Run("バージョンが一致すればリポジトリにも渡して更新する", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{current: current}
+		s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+		_, err := s.UpdateExpense(context.Background(), "test-user", input(3))
+		require.NoError(t, err)
+		require.NotNil(t, repo.in.Version)
+		assert.Equal(t, 3, *repo.in.Version)
+	})
+
+	t.Run("古いバージョンなら現在の状態とともに VersionConflictError", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{current: current}
+		s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+		_, err := s.UpdateExpense(context.Background(), "test-user", input(2))
+		var ce *VersionConflictError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, current, ce.Current)
+		assert.False(t, repo.called)
+	})
+
+	t.Run("読み取った後に他で更新された場合も VersionConflictError", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{current: current, returnErr: sql.ErrNoRows}
+		s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+		_, err := s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{Amount: intPtr(900)}, intPtr(3))
+		var ce *VersionConflictError
+		assert.ErrorAs(t, err, &ce)
+	})
+}
+
+func TestDeleteExpense_IfMatch(t *testing.T) {
+	t.Parallel()
+
+	// mockDeleteRepo の支出はバージョン 0
+	repo := &mockDeleteRepo{}
+	s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+	err := s.DeleteExpense(context.Background(), "test-user", 1, intPtr(1))
+	var ce *VersionConflictError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, 1, ce.Current.ID)
+	assert.False(t, repo.called)
+
+	require.NoError(t, s.DeleteExpense(context.Background(), "test-user", 1, intPtr(0)))
+	assert.True(t, repo.called)
+}
+
+// concurrentlyTrashedRepo は読み取った後、削除する前に他のリクエストでゴミ箱に移された支出を再現します。
+type concurrentlyTrashedRepo struct {
+	mockDeleteRepo
+	trashed bool
+}
+
+func (m *concurrentlyTrashedRepo) DeleteExpense(ctx context.Context, ledgerID int32, id int32, version *int) error {
+	m.trashed = true
+	return sql.ErrNoRows
+}
+
+func (m *concurrentlyTrashedRepo) GetExpenseByID(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
+	if m.trashed {
+		return models.Expense{}, sql.ErrNoRows
+	}
+	return m.mockDeleteRepo.GetExpenseByID(ctx, ledgerID, id)
+}
+
+func TestDeleteExpense_IfMatchTrashedConcurrently(t *testing.T) {
+	t.Parallel()
+
+	repo := &concurrentlyTrashedRepo{}
+	s := &expenseService{repo: repo, categoryRepo: &mockCategoryRepo{}, tagRepo: &memTagRepo{}, payeeRepo: &memPayeeRepo{}, paymentAccountRepo: &memPaymentAccountRepo{}, ledgerRepo: activeLedger(1), auditRepo: &auditRecorder{}, txManager: nopTxManager{}}
+
+	err := s.DeleteExpense(context.Background(), "test-user", 1, intPtr(0))
+	var ne *NotFoundError
+	assert.ErrorAs(t, err, &ne)
+}
+
+// mockLedgerScopedRepo は呼び出し時の家計簿 ID を記録するモック
+type mockLedgerScopedRepo struct {
+	mockRepo
+	ledgerID int32
+	userID   string
+}
+
+func (m *mockLedgerScopedRepo) CreateExpense(ctx context.Context, ledgerID int32, userID string, input models.CreateExpenseInput) (models.Expense, error) {
+	m.ledgerID = ledgerID
+	m.userID = userID
+	return m.mockRepo.CreateExpense(ctx, ledgerID, userID, input)
+}
+
+func (m *mockLedgerScopedRepo) FindAll(ctx context.Context, ledgerID int32, filter models.ExpenseFilter) ([]models.Expense, error) {
+	m.ledgerID = ledgerID
+	return []models.Expense{{ID: 1}}, nil
+}
+
+func (m *mockLedgerScopedRepo) SearchExpenses(ctx context.Context, ledgerID int32, filter models.ExpenseSearchFilter) ([]models.ExpenseSearchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockLedgerScopedRepo) ConfirmExpense(ctx context.Context, ledgerID int32, id int32, input models.ConfirmExpenseInput) (models.Expense, error) {
+	return models.Expense{}, errors.New("not implemented")
+}
+
+func TestExpenseService_ScopedByActiveLedger(t *testing.T) {
+	t.Parallel()
+
+	input := models.CreateExpenseInput{Amount: intPtr(100), CategoryID: intPtr(1), SpentAt: "2020-01-02"}
+
+	t.Run("使用中の家計簿に登録者付きで作成する", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockLedgerScopedRepo{}
+		s := NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(42), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(context.Background(), "test-user", input)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(42), repo.ledgerID)
+		assert.Equal(t, "test-user", repo.userID)
+	})
+
+	t.Run("使用中の家計簿の一覧を返す", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockLedgerScopedRepo{}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(42), &auditRecorder{}, nopTxManager{})
+
+		page, err := s.ListExpenses(context.Background(), "test-user", models.ListExpensesInput{})
+		assert.NoError(t, err)
+		assert.Len(t, page.Expenses, 1)
+		assert.Equal(t, int32(42), repo.ledgerID)
+	})
+
+	t.Run("家計簿が無い場合、作成は ErrNoActiveLedger、一覧は空", func(t *testing.T) {
+		t.Parallel()
+
+		lr := new(ledgerRepoMock)
+		lr.On("GetActiveLedgerID", mock.Anything, "test-user").Return(nil, sql.ErrNoRows)
+		repo := &mockLedgerScopedRepo{}
+		s := NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, lr, &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(context.Background(), "test-user", input)
+		assert.ErrorIs(t, err, ErrNoActiveLedger)
+		assert.False(t, repo.called)
+
+		page, err := s.ListExpenses(context.Background(), "test-user", models.ListExpensesInput{})
+		assert.NoError(t, err)
+		assert.NotNil(t, page.Expenses)
+		assert.Empty(t, page.Expenses)
+	})
+}
+
+func TestExpenseService_ViewerCannotModify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("更新は ForbiddenError", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockUpdateRepo{current: models.Expense{ID: 1, Status: "planned"}}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedgerAs(1, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.UpdateExpense(ctx, "test-user", models.UpdateExpenseInput{ID: 1, Amount: intPtr(200), CategoryID: intPtr(1), SpentAt: "2025-01-01"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		assert.False(t, repo.called)
+	})
+
+	t.Run("削除は ForbiddenError", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockDeleteRepo{}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedgerAs(1, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{})
+
+		err := s.DeleteExpense(ctx, "test-user", 1, nil)
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		assert.False(t, repo.called)
+	})
+
+	t.Run("作成は ForbiddenError", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockLedgerScopedRepo{}
+		s := NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedgerAs(1, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{Amount: intPtr(100), CategoryID: intPtr(1), SpentAt: "2025-01-02"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		assert.False(t, repo.called)
+	})
+
+	t.Run("閲覧はできる", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockLedgerScopedRepo{}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedgerAs(1, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{})
+
+		page, err := s.ListExpenses(ctx, "test-user", models.ListExpensesInput{})
+		assert.NoError(t, err)
+		assert.Len(t, page.Expenses, 1)
+	})
+}
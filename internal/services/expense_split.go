@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"money-buddy-backend/internal/models"
+)
+
+// ExpenseSplitMaxLines は 1 件の支出に付けられる内訳の最大数です。
+const ExpenseSplitMaxLines = 50
+
+// expenseSplitInputs は現在の内訳を、そのまま保存し直すための入力に変換します。
+func expenseSplitInputs(splits []models.ExpenseSplit) []models.ExpenseSplitInput {
+	if len(splits) == 0 {
+		return nil
+	}
+	inputs := make([]models.ExpenseSplitInput, 0, len(splits))
+	for _, sp := range splits {
+		categoryID := sp.Category.ID
+		amount := sp.Amount
+		inputs = append(inputs, models.ExpenseSplitInput{CategoryID: &categoryID, Amount: &amount, Memo: sp.Memo})
+	}
+	return inputs
+}
+
+// validateExpenseSplits は内訳の入力チェックです。内訳がある場合、金額の合計は支出の金額 amount と
+// 一致しなければなりません（カテゴリの存在は確認しません）。
+func validateExpenseSplits(amount int, splits []models.ExpenseSplitInput) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	if len(splits) > ExpenseSplitMaxLines {
+		return &ValidationError{Message: fmt.Sprintf("splits must not exceed %d lines", ExpenseSplitMaxLines)}
+	}
+	total := 0
+	for i, sp := range splits {
+		if sp.CategoryID == nil || *sp.CategoryID <= 0 {
+			return &ValidationError{Message: fmt.Sprintf("splits[%d]: category_id must be greater than 0", i)}
+		}
+		if sp.Amount == nil || *sp.Amount <= 0 {
+			return &ValidationError{Message: fmt.Sprintf("splits[%d]: amount must be greater than 0", i)}
+		}
+		if len(sp.Memo) > MemoMaxLen {
+			return &ValidationError{Message: fmt.Sprintf("splits[%d]: memo exceeds maximum length", i)}
+		}
+		total += *sp.Amount
+	}
+	if total != amount {
+		return &ValidationError{Message: "splits must add up to amount"}
+	}
+	return nil
+}
+
+// checkSplitCategories は内訳のカテゴリが存在することを確認します。
+func (s *expenseService) checkSplitCategories(ctx context.Context, splits []models.ExpenseSplitInput) error {
+	checked := make(map[int]bool, len(splits))
+	for i, sp := range splits {
+		if checked[*sp.CategoryID] {
+			continue
+		}
+		exists, err := s.categoryRepo.CategoryExists(ctx, int32(*sp.CategoryID))
+		if err != nil {
+			return &InternalError{Message: "internal error"}
+		}
+		if !exists {
+			return &ValidationError{Message: fmt.Sprintf("splits[%d]: category_id is invalid", i)}
+		}
+		checked[*sp.CategoryID] = true
+	}
+	return nil
+}
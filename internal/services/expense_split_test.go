@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+func splitInput(categoryID, amount int) models.ExpenseSplitInput {
+	return models.ExpenseSplitInput{CategoryID: intPtr(categoryID), Amount: intPtr(amount)}
+}
+
+func TestCreateExpense_Splits(t *testing.T) {
+	cases := []struct {
+		name       string
+		splits     []models.ExpenseSplitInput
+		wantMsg    string
+		wantCalled bool
+	}{
+		{name: "合計が金額と一致すれば登録できる", splits: []models.ExpenseSplitInput{splitInput(1, 700), splitInput(2, 300)}, wantCalled: true},
+		{name: "合計が金額と一致しない", splits: []models.ExpenseSplitInput{splitInput(1, 700), splitInput(2, 200)}, wantMsg: "splits must add up to amount"},
+		{name: "金額が 0 の行", splits: []models.ExpenseSplitInput{splitInput(1, 1000), splitInput(2, 0)}, wantMsg: "splits[1]: amount must be greater than 0"},
+		{name: "カテゴリが無い行", splits: []models.ExpenseSplitInput{{Amount: intPtr(1000)}}, wantMsg: "splits[0]: category_id must be greater than 0"},
+		{name: "存在しないカテゴリ", splits: []models.ExpenseSplitInput{splitInput(1, 500), splitInput(99, 500)}, wantMsg: "splits[1]: category_id is invalid"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &mockRepo{}
+			cr := &mockCategoryRepo{exists: map[int32]bool{1: true, 2: true}}
+			s := NewExpenseService(m, cr, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+			_, err := s.CreateExpense(context.Background(), "test-user", models.CreateExpenseInput{
+				Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01", Splits: tc.splits,
+			})
+
+			if tc.wantMsg != "" {
+				var ve *ValidationError
+				require.ErrorAs(t, err, &ve)
+				assert.Equal(t, tc.wantMsg, ve.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.splits, m.in.Splits)
+			}
+			assert.Equal(t, tc.wantCalled, m.called)
+		})
+	}
+
+	t.Run("行数の上限を超える", func(t *testing.T) {
+		splits := make([]models.ExpenseSplitInput, ExpenseSplitMaxLines+1)
+		for i := range splits {
+			splits[i] = splitInput(1, 1)
+		}
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(context.Background(), "test-user", models.CreateExpenseInput{
+			Amount: intPtr(len(splits)), CategoryID: intPtr(1), SpentAt: "2025-05-01", Splits: splits,
+		})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.False(t, m.called)
+	})
+}
+
+func TestPatchExpense_Splits(t *testing.T) {
+	current := func() models.Expense {
+		return models.Expense{
+			ID: 5, Amount: 1000, Category: models.Category{ID: 1}, SpentAt: "2025-05-01T00:00:00Z", Status: "confirmed",
+			Splits: []models.ExpenseSplit{
+				{Category: models.Category{ID: 1}, Amount: 700, Memo: "food"},
+				{Category: models.Category{ID: 2}, Amount: 300},
+			},
+		}
+	}
+	categories := &mockCategoryRepo{exists: map[int32]bool{1: true, 2: true}}
+
+	t.Run("内訳を指定しなければ現在の内訳を保つ", func(t *testing.T) {
+		repo := &mockUpdateRepo{current: current()}
+		s := NewExpenseService(repo, categories, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		memo := "updated"
+		_, err := s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{Memo: &memo}, nil)
+		require.NoError(t, err)
+		require.Len(t, repo.in.Splits, 2)
+		assert.Equal(t, 700, *repo.in.Splits[0].Amount)
+		assert.Equal(t, "food", repo.in.Splits[0].Memo)
+		assert.Equal(t, 2, *repo.in.Splits[1].CategoryID)
+	})
+
+	t.Run("内訳を変えずに金額だけ変えるとエラー", func(t *testing.T) {
+		repo := &mockUpdateRepo{current: current()}
+		s := NewExpenseService(repo, categories, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.PatchExpense(context.Background(), "test-user", 5, models.ExpensePatch{Amount: intPtr(1200)}, nil)
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "splits must add up to amount", ve.Message)
+		assert.False(t, repo.called)
+	})
+
+	t.Run("null で内訳を無くす", func(t *testing.T) {
+		var patch models.ExpensePatch
+		require.NoError(t, json.Unmarshal([]byte(`{"splits":null,"amount":1200}`), &patch))
+		repo := &mockUpdateRepo{current: current()}
+		s := NewExpenseService(repo, categories, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.PatchExpense(context.Background(), "test-user", 5, patch, nil)
+		require.NoError(t, err)
+		assert.True(t, repo.called)
+		assert.Empty(t, repo.in.Splits)
+	})
+}
+
+func TestConfirmExpense_Splits(t *testing.T) {
+	planned := models.Expense{
+		ID: 3, Amount: 1000, Status: "planned",
+		Splits: []models.ExpenseSplit{
+			{Category: models.Category{ID: 1}, Amount: 600},
+			{Category: models.Category{ID: 2}, Amount: 400},
+		},
+	}
+
+	t.Run("金額を変えずに確定できる", func(t *testing.T) {
+		repo := &mockUpdateRepo{current: planned}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		out, err := s.ConfirmExpense(context.Background(), "test-user", 3, models.ConfirmExpenseInput{Amount: intPtr(1000)})
+		require.NoError(t, err)
+		assert.Equal(t, "confirmed", out.Status)
+	})
+
+	t.Run("金額を変えるとエラー", func(t *testing.T) {
+		repo := &mockUpdateRepo{current: planned}
+		s := NewExpenseService(repo, &mockCategoryRepo{}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.ConfirmExpense(context.Background(), "test-user", 3, models.ConfirmExpenseInput{Amount: intPtr(1200)})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.False(t, repo.called)
+	})
+}
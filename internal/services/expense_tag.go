@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"money-buddy-backend/internal/models"
+)
+
+// ExpenseTagMaxCount は 1 件の支出に 1 人のユーザーが付けられるタグの最大数です。
+const ExpenseTagMaxCount = 20
+
+// checkExpenseTags は支出に付けるタグの入力チェックです。重複を取り除き、すべて呼び出し元の
+// タグであることを確認して、付けるタグを名前順に返します。
+func (s *expenseService) checkExpenseTags(ctx context.Context, userID string, tagIDs []int) ([]models.Tag, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+	seen := make(map[int]bool, len(tagIDs))
+	ids := make([]int32, 0, len(tagIDs))
+	for i, id := range tagIDs {
+		if id <= 0 {
+			return nil, &ValidationError{Message: fmt.Sprintf("tag_ids[%d] must be greater than 0", i)}
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, int32(id))
+	}
+	if len(ids) > ExpenseTagMaxCount {
+		return nil, &ValidationError{Message: fmt.Sprintf("tag_ids must not exceed %d tags", ExpenseTagMaxCount)}
+	}
+
+	tags, err := s.tagRepo.ListTagsByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	// 他のユーザーのタグは存在しないものとして扱う
+	if len(tags) != len(ids) {
+		return nil, &ValidationError{Message: "tag_ids contains an unknown tag"}
+	}
+	return tags, nil
+}
+
+// attachExpenseTags は呼び出し元が付けたタグを expenses に設定します。
+func (s *expenseService) attachExpenseTags(ctx context.Context, userID string, expenses []*models.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	ids := make([]int32, 0, len(expenses))
+	for _, e := range expenses {
+		ids = append(ids, int32(e.ID))
+	}
+	tags, err := s.tagRepo.ListExpenseTags(ctx, userID, ids)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	for _, e := range expenses {
+		e.Tags = tags[int32(e.ID)]
+	}
+	return nil
+}
+
+// setExpenseTags は呼び出し元がその支出に付けたタグを tags に置き換え、expense に設定します。
+func (s *expenseService) setExpenseTags(ctx context.Context, userID string, expense *models.Expense, tags []models.Tag) error {
+	ids := make([]int32, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, int32(t.ID))
+	}
+	if err := s.tagRepo.SetExpenseTags(ctx, userID, int32(expense.ID), ids); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	expense.Tags = tags
+	return nil
+}
+
+// expenseTagIDs は現在のタグを、そのまま付け直すための入力に変換します。
+func expenseTagIDs(tags []models.Tag) []int {
+	if len(tags) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
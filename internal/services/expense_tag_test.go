@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+func TestCreateExpense_Tags(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("タグを付けて登録する", func(t *testing.T) {
+		tags := &memTagRepo{}
+		travel := tags.add("test-user", "旅行")
+		osaka := tags.add("test-user", "大阪")
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, tags, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		out, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01", TagIDs: []int{travel.ID, osaka.ID, travel.ID},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []models.Tag{travel, osaka}, out.Tags)
+		assert.ElementsMatch(t, []int32{1, 2}, tags.links[1])
+	})
+
+	t.Run("他のユーザーのタグは付けられない", func(t *testing.T) {
+		tags := &memTagRepo{}
+		other := tags.add("other-user", "旅行")
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, tags, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01", TagIDs: []int{other.ID},
+		})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "tag_ids contains an unknown tag", ve.Message)
+		assert.False(t, m.called)
+	})
+
+	t.Run("0 以下の ID", func(t *testing.T) {
+		m := &mockRepo{}
+		s := NewExpenseService(m, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateExpense(ctx, "test-user", models.CreateExpenseInput{
+			Amount: intPtr(1000), CategoryID: intPtr(1), SpentAt: "2025-05-01", TagIDs: []int{0},
+		})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "tag_ids[0] must be greater than 0", ve.Message)
+	})
+}
+
+func TestPatchExpense_Tags(t *testing.T) {
+	ctx := context.Background()
+	setup := func() (*memTagRepo, *mockUpdateRepo, ExpenseService) {
+		tags := &memTagRepo{}
+		tags.add("test-user", "旅行")
+		tags.add("test-user", "大阪")
+		tags.add("other-user", "出張")
+		tags.links = map[int32][]int32{5: {1, 3}}
+		repo := &mockUpdateRepo{current: models.Expense{ID: 5, Amount: 1000, Category: models.Category{ID: 1}, SpentAt: "2025-05-01T00:00:00Z", Status: "confirmed"}}
+		return tags, repo, NewExpenseService(repo, &mockCategoryRepo{}, tags, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+	}
+
+	t.Run("タグを指定しなければ現在のタグを保つ", func(t *testing.T) {
+		tags, _, s := setup()
+
+		memo := "updated"
+		out, err := s.PatchExpense(ctx, "test-user", 5, models.ExpensePatch{Memo: &memo}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Tag{{ID: 1, Name: "旅行"}}, out.Tags)
+		assert.ElementsMatch(t, []int32{1, 3}, tags.links[5])
+	})
+
+	t.Run("タグを置き換えても他のユーザーのタグは残る", func(t *testing.T) {
+		tags, _, s := setup()
+
+		out, err := s.PatchExpense(ctx, "test-user", 5, models.ExpensePatch{TagIDs: &[]int{2}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Tag{{ID: 2, Name: "大阪"}}, out.Tags)
+		assert.ElementsMatch(t, []int32{2, 3}, tags.links[5])
+	})
+
+	t.Run("null でタグを外す", func(t *testing.T) {
+		var patch models.ExpensePatch
+		require.NoError(t, json.Unmarshal([]byte(`{"tag_ids":null}`), &patch))
+		tags, _, s := setup()
+
+		out, err := s.PatchExpense(ctx, "test-user", 5, patch, nil)
+		require.NoError(t, err)
+		assert.Empty(t, out.Tags)
+		assert.Equal(t, []int32{3}, tags.links[5])
+	})
+}
+
+func TestListExpenses_Tags(t *testing.T) {
+	tags := &memTagRepo{}
+	travel := tags.add("user-1", "旅行")
+	tags.links = map[int32][]int32{2: {1}}
+	repo := &listExpensesRepo{result: []models.Expense{{ID: 1}, {ID: 2}}}
+	s := NewExpenseService(repo, &mockCategoryRepo{}, tags, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	page, err := s.ListExpenses(context.Background(), "user-1", models.ListExpensesInput{TagIDs: []string{"1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1}, repo.filter.TagIDs)
+	require.Len(t, page.Expenses, 2)
+	assert.Empty(t, page.Expenses[0].Tags)
+	assert.Equal(t, []models.Tag{travel}, page.Expenses[1].Tags)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// ExpenseTrashService は削除した支出のゴミ箱を扱います。
+// 支出は DELETE /expenses/:id でゴミ箱に移り、保存期間を過ぎると PurgeExpired で完全に削除されます。
+type ExpenseTrashService interface {
+	// ListTrash は使用中の家計簿のゴミ箱の支出を、ゴミ箱に移した日時が新しい順に返します。
+	ListTrash(ctx context.Context, userID string) ([]models.TrashedExpense, error)
+	// RestoreExpense はゴミ箱の支出を元に戻し、戻した支出を返します。
+	RestoreExpense(ctx context.Context, userID string, id int) (models.Expense, error)
+	// PurgeExpired は保存期間を過ぎたゴミ箱の支出を家計簿を問わず削除し、削除した件数を返します。
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type expenseTrashService struct {
+	repo       repositories.ExpenseTrashRepository
+	ledgerRepo repositories.LedgerRepository
+	auditRepo  repositories.AuditRepository
+	txManager  TxManager
+	retention  time.Duration
+	now        func() time.Time
+}
+
+func NewExpenseTrashService(repo repositories.ExpenseTrashRepository, ledgerRepo repositories.LedgerRepository, auditRepo repositories.AuditRepository, txManager TxManager, retention time.Duration) ExpenseTrashService {
+	return &expenseTrashService{
+		repo:       repo,
+		ledgerRepo: ledgerRepo,
+		auditRepo:  auditRepo,
+		txManager:  txManager,
+		retention:  retention,
+		now:        time.Now,
+	}
+}
+
+func (s *expenseTrashService) ListTrash(ctx context.Context, userID string) ([]models.TrashedExpense, error) {
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return []models.TrashedExpense{}, nil
+		}
+		return nil, err
+	}
+
+	expenses, err := s.repo.ListTrashed(ctx, ledgerID)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	for i := range expenses {
+		deletedAt, err := time.Parse(time.RFC3339, expenses[i].DeletedAt)
+		if err != nil {
+			return nil, &InternalError{Message: "internal error"}
+		}
+		expenses[i].PurgeAt = deletedAt.Add(s.retention).Format(time.RFC3339)
+	}
+	if expenses == nil {
+		expenses = []models.TrashedExpense{}
+	}
+	return expenses, nil
+}
+
+func (s *expenseTrashService) RestoreExpense(ctx context.Context, userID string, id int) (models.Expense, error) {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.Expense{}, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	restored, err := s.repo.Restore(txCtx, ledgerID, int32(id))
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Expense{}, &NotFoundError{Message: "expense not found in trash"}
+		}
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+
+	event := expenseAuditEvent(userID, ledgerID, models.AuditActionExpenseRestore, id)
+	if err := recordAudit(txCtx, s.auditRepo, event, nil, expenseAuditFields(restored)); err != nil {
+		_ = tx.Rollback()
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	return restored, nil
+}
+
+func (s *expenseTrashService) PurgeExpired(ctx context.Context) (int64, error) {
+	before := s.now().UTC().Add(-s.retention)
+
+	// 行レベルセキュリティの設定はトランザクションの開始時に行われるため、1 件の DELETE でもトランザクションで実行する
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	txCtx := tx.Context(ctx)
+
+	n, err := s.repo.PurgeTrashed(txCtx, before)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type trashRepoMock struct{ mock.Mock }
+
+func (m *trashRepoMock) ListTrashed(ctx context.Context, ledgerID int32) ([]models.TrashedExpense, error) {
+	args := m.Called(ctx, ledgerID)
+	expenses, _ := args.Get(0).([]models.TrashedExpense)
+	return expenses, args.Error(1)
+}
+
+func (m *trashRepoMock) Restore(ctx context.Context, ledgerID int32, id int32) (models.Expense, error) {
+	args := m.Called(ctx, ledgerID, id)
+	return args.Get(0).(models.Expense), args.Error(1)
+}
+
+func (m *trashRepoMock) PurgeTrashed(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestExpenseTrashService_ListTrash(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("保存期間から完全に削除される日時を付ける", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		repo.On("ListTrashed", ctx, int32(10)).Return([]models.TrashedExpense{
+			{Expense: models.Expense{ID: 2}, DeletedAt: "2025-05-02T10:00:00Z"},
+			{Expense: models.Expense{ID: 1}, DeletedAt: "2025-05-01T09:30:00Z"},
+		}, nil)
+		s := NewExpenseTrashService(repo, activeLedger(10), &auditRecorder{}, nopTxManager{}, 30*24*time.Hour)
+
+		got, err := s.ListTrash(ctx, "test-user")
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "2025-06-01T10:00:00Z", got[0].PurgeAt)
+		assert.Equal(t, "2025-05-31T09:30:00Z", got[1].PurgeAt)
+	})
+
+	t.Run("ゴミ箱が空なら空配列", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		repo.On("ListTrashed", ctx, int32(10)).Return(nil, nil)
+		s := NewExpenseTrashService(repo, activeLedger(10), &auditRecorder{}, nopTxManager{}, time.Hour)
+
+		got, err := s.ListTrash(ctx, "test-user")
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+
+	t.Run("使用中の家計簿が無ければ空配列", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetActiveLedgerID", mock.Anything, "test-user").Return(nil, sql.ErrNoRows)
+		repo := new(trashRepoMock)
+		s := NewExpenseTrashService(repo, lr, &auditRecorder{}, nopTxManager{}, time.Hour)
+
+		got, err := s.ListTrash(ctx, "test-user")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		repo.AssertNotCalled(t, "ListTrashed", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExpenseTrashService_RestoreExpense(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("元に戻して操作を記録する", func(t *testing.T) {
+		restored := models.Expense{ID: 5, Amount: 1200, Memo: "lunch", SpentAt: "2025-05-01T00:00:00Z", Status: "confirmed", Category: models.Category{ID: 3}}
+		repo := new(trashRepoMock)
+		repo.On("Restore", ctx, int32(10), int32(5)).Return(restored, nil)
+		audit := &auditRecorder{}
+		s := NewExpenseTrashService(repo, activeLedger(10), audit, nopTxManager{}, time.Hour)
+
+		got, err := s.RestoreExpense(ctx, "test-user", 5)
+		require.NoError(t, err)
+		assert.Equal(t, restored, got)
+		require.Len(t, audit.events, 1)
+		assert.Equal(t, models.AuditActionExpenseRestore, audit.events[0].Action)
+		assert.Equal(t, "5", audit.events[0].EntityID)
+		assert.Equal(t, models.AuditChange{Before: nil, After: 1200}, audit.events[0].Changes["amount"])
+	})
+
+	t.Run("ゴミ箱に無ければ NotFoundError", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		repo.On("Restore", ctx, int32(10), int32(5)).Return(models.Expense{}, sql.ErrNoRows)
+		audit := &auditRecorder{}
+		s := NewExpenseTrashService(repo, activeLedger(10), audit, nopTxManager{}, time.Hour)
+
+		_, err := s.RestoreExpense(ctx, "test-user", 5)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		assert.Empty(t, audit.events)
+	})
+
+	t.Run("閲覧者は元に戻せない", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		s := NewExpenseTrashService(repo, activeLedgerAs(10, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{}, time.Hour)
+
+		_, err := s.RestoreExpense(ctx, "test-user", 5)
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("記録に失敗したらロールバックする", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		repo.On("Restore", ctx, int32(10), int32(5)).Return(models.Expense{ID: 5, Amount: 1}, nil)
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		s := NewExpenseTrashService(repo, activeLedger(10), &auditRecorder{createErr: errors.New("db down")}, tm, time.Hour)
+
+		_, err := s.RestoreExpense(ctx, "test-user", 5)
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+	})
+}
+
+func TestExpenseTrashService_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("保存期間より前に削除した支出を消す", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		repo.On("PurgeTrashed", ctx, now.Add(-30*24*time.Hour)).Return(int64(4), nil)
+		tx.On("Commit").Return(nil).Once()
+
+		s := NewExpenseTrashService(repo, new(ledgerRepoMock), &auditRecorder{}, tm, 30*24*time.Hour).(*expenseTrashService)
+		s.now = func() time.Time { return now }
+
+		n, err := s.PurgeExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), n)
+		repo.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("削除に失敗したらロールバックする", func(t *testing.T) {
+		repo := new(trashRepoMock)
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		repo.On("PurgeTrashed", ctx, mock.Anything).Return(int64(0), errors.New("db down"))
+		tx.On("Rollback").Return(nil).Once()
+
+		s := NewExpenseTrashService(repo, new(ledgerRepoMock), &auditRecorder{}, tm, time.Hour)
+
+		_, err := s.PurgeExpired(ctx)
+		assert.Error(t, err)
+		tx.AssertExpectations(t)
+	})
+}
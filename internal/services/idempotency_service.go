@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// IdempotencyService は Idempotency-Key 付きのリクエストのレスポンスを保存し、再送に同じレスポンスを返すために使います。
+// キーはユーザーごとに区別され、保存期間を過ぎると PurgeExpired で削除されます。
+type IdempotencyService interface {
+	// Lookup は同じキーのリクエストに返したレスポンスを返します。まだ無ければ nil を返します。
+	// キーが別の内容のリクエストで使われていた場合は ErrIdempotencyKeyReused を返します。
+	Lookup(ctx context.Context, userID string, key string, requestHash string) (*models.IdempotentResponse, error)
+	// Save はリクエストに返すレスポンスを保存します。呼び出し元のトランザクション内で呼び、リクエストの処理と一緒に確定させます。
+	// 同じキーのリクエストが並行して処理され先に保存されていた場合は ErrIdempotencyKeyInProgress を返します。
+	Save(ctx context.Context, userID string, key string, requestHash string, response models.IdempotentResponse) error
+	// PurgeExpired は保存期間を過ぎたキーをユーザーを問わず削除し、削除した件数を返します。
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	repo      repositories.IdempotencyKeyRepository
+	txManager TxManager
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyKeyRepository, txManager TxManager, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{
+		repo:      repo,
+		txManager: txManager,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+func (s *idempotencyService) Lookup(ctx context.Context, userID string, key string, requestHash string) (*models.IdempotentResponse, error) {
+	stored, err := s.repo.GetKey(ctx, userID, key, s.now().UTC())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if stored.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return &stored.Response, nil
+}
+
+func (s *idempotencyService) Save(ctx context.Context, userID string, key string, requestHash string, response models.IdempotentResponse) error {
+	now := s.now().UTC()
+	saved, err := s.repo.SaveKey(ctx, models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		Response:    response,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !saved {
+		return ErrIdempotencyKeyInProgress
+	}
+	return nil
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	// 行レベルセキュリティの設定はトランザクションの開始時に行われるため、1 件の DELETE でもトランザクションで実行する
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	txCtx := tx.Context(ctx)
+
+	n, err := s.repo.PurgeExpired(txCtx, s.now().UTC())
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type idempotencyRepoMock struct{ mock.Mock }
+
+func (m *idempotencyRepoMock) GetKey(ctx context.Context, userID string, key string, now time.Time) (models.IdempotencyKey, error) {
+	args := m.Called(ctx, userID, key, now)
+	return args.Get(0).(models.IdempotencyKey), args.Error(1)
+}
+
+func (m *idempotencyRepoMock) SaveKey(ctx context.Context, key models.IdempotencyKey) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *idempotencyRepoMock) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestIdempotencyService_Lookup(t *testing.T) {
+	ctx := context.Background()
+	stored := models.IdempotencyKey{
+		UserID:      "test-user",
+		Key:         "k1",
+		RequestHash: "hash-a",
+		Response:    models.IdempotentResponse{Status: 201, Body: []byte(`{"expense":{"id":1}}`)},
+	}
+
+	t.Run("同じ内容なら保存したレスポンスを返す", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		repo.On("GetKey", ctx, "test-user", "k1", mock.AnythingOfType("time.Time")).Return(stored, nil)
+		s := NewIdempotencyService(repo, nopTxManager{}, time.Hour)
+
+		got, err := s.Lookup(ctx, "test-user", "k1", "hash-a")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, stored.Response, *got)
+	})
+
+	t.Run("別の内容なら ErrIdempotencyKeyReused", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		repo.On("GetKey", ctx, "test-user", "k1", mock.AnythingOfType("time.Time")).Return(stored, nil)
+		s := NewIdempotencyService(repo, nopTxManager{}, time.Hour)
+
+		got, err := s.Lookup(ctx, "test-user", "k1", "hash-b")
+		assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+		assert.Nil(t, got)
+	})
+
+	t.Run("未使用のキーなら nil", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		repo.On("GetKey", ctx, "test-user", "k1", mock.AnythingOfType("time.Time")).Return(models.IdempotencyKey{}, sql.ErrNoRows)
+		s := NewIdempotencyService(repo, nopTxManager{}, time.Hour)
+
+		got, err := s.Lookup(ctx, "test-user", "k1", "hash-a")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("有効期限はサーバーの時刻を UTC にして判定する", func(t *testing.T) {
+		jst := time.FixedZone("JST", 9*60*60)
+		now := time.Date(2025, 6, 1, 9, 0, 0, 0, jst)
+		repo := new(idempotencyRepoMock)
+		repo.On("GetKey", ctx, "test-user", "k1", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Return(stored, nil)
+		s := NewIdempotencyService(repo, nopTxManager{}, time.Hour).(*idempotencyService)
+		s.now = func() time.Time { return now }
+
+		_, err := s.Lookup(ctx, "test-user", "k1", "hash-a")
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("取得に失敗したら InternalError", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		repo.On("GetKey", ctx, "test-user", "k1", mock.AnythingOfType("time.Time")).Return(models.IdempotencyKey{}, errors.New("db down"))
+		s := NewIdempotencyService(repo, nopTxManager{}, time.Hour)
+
+		_, err := s.Lookup(ctx, "test-user", "k1", "hash-a")
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+}
+
+func TestIdempotencyService_Save(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	response := models.IdempotentResponse{Status: 201, Headers: map[string]string{"ETag": `"1"`}, Body: []byte(`{}`)}
+	want := models.IdempotencyKey{
+		UserID:      "test-user",
+		Key:         "k1",
+		RequestHash: "hash-a",
+		Response:    response,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(24 * time.Hour),
+	}
+
+	t.Run("保存期間の終わりを付けて保存する", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		repo.On("SaveKey", ctx, want).Return(true, nil)
+		s := NewIdempotencyService(repo, nopTxManager{}, 24*time.Hour).(*idempotencyService)
+		s.now = func() time.Time { return now }
+
+		require.NoError(t, s.Save(ctx, "test-user", "k1", "hash-a", response))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("先に保存されていたら ErrIdempotencyKeyInProgress", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		repo.On("SaveKey", ctx, want).Return(false, nil)
+		s := NewIdempotencyService(repo, nopTxManager{}, 24*time.Hour).(*idempotencyService)
+		s.now = func() time.Time { return now }
+
+		err := s.Save(ctx, "test-user", "k1", "hash-a", response)
+		assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+	})
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("期限切れのキーを消す", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		repo.On("PurgeExpired", ctx, now).Return(int64(3), nil)
+		tx.On("Commit").Return(nil).Once()
+
+		s := NewIdempotencyService(repo, tm, time.Hour).(*idempotencyService)
+		s.now = func() time.Time { return now }
+
+		n, err := s.PurgeExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("削除に失敗したらロールバックする", func(t *testing.T) {
+		repo := new(idempotencyRepoMock)
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		repo.On("PurgeExpired", ctx, mock.Anything).Return(int64(0), errors.New("db down"))
+		tx.On("Rollback").Return(nil).Once()
+
+		s := NewIdempotencyService(repo, tm, time.Hour)
+
+		_, err := s.PurgeExpired(ctx)
+		assert.Error(t, err)
+		tx.AssertExpectations(t)
+	})
+}
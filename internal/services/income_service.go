@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// IncomeSourceMaxLen は収入源の最大文字数です。
+const IncomeSourceMaxLen = 50
+
+// IncomeService は使用中の家計簿の収入を扱います。登録・更新・削除は同じトランザクションで操作履歴に残します。
+type IncomeService interface {
+	// ListIncomes は収入を受け取った日の新しい順に返します。使用中の家計簿が無ければ空の一覧を返します。
+	ListIncomes(ctx context.Context, userID string, input models.ListIncomesInput) ([]models.Income, error)
+	// CreateIncome は呼び出し元が受け取った収入として登録します。
+	CreateIncome(ctx context.Context, userID string, input models.IncomeInput) (models.Income, error)
+	// UpdateIncome は収入を input の内容に置き換えます。支出と同じく confirmed から planned には戻せません。
+	UpdateIncome(ctx context.Context, userID string, id int, input models.IncomeInput) (models.Income, error)
+	DeleteIncome(ctx context.Context, userID string, id int) error
+	// MonthlySummary は 1 か月分の収入を集計します。使用中の家計簿が無ければ 0 の集計を返します。
+	MonthlySummary(ctx context.Context, userID string, input models.IncomeSummaryInput) (models.IncomeSummary, error)
+}
+
+type incomeService struct {
+	repo       repositories.IncomeRepository
+	ledgerRepo repositories.LedgerRepository
+	auditRepo  repositories.AuditRepository
+	txManager  TxManager
+	now        func() time.Time
+}
+
+func NewIncomeService(repo repositories.IncomeRepository, ledgerRepo repositories.LedgerRepository, auditRepo repositories.AuditRepository, txManager TxManager) IncomeService {
+	return &incomeService{repo: repo, ledgerRepo: ledgerRepo, auditRepo: auditRepo, txManager: txManager, now: time.Now}
+}
+
+func (s *incomeService) ListIncomes(ctx context.Context, userID string, input models.ListIncomesInput) ([]models.Income, error) {
+	var filter models.IncomeFilter
+	var err error
+	if input.From != "" {
+		if filter.From, err = time.Parse("2006-01-02", input.From); err != nil {
+			return nil, &ValidationError{Message: "from must be YYYY-MM-DD"}
+		}
+	}
+	if input.To != "" {
+		to, err := time.Parse("2006-01-02", input.To)
+		if err != nil {
+			return nil, &ValidationError{Message: "to must be YYYY-MM-DD"}
+		}
+		// To の日を含めるため、翌日より前を返す
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, &ValidationError{Message: "from must not be after to"}
+	}
+	if input.Status != "" {
+		status, ok := models.NormalizeStatus(input.Status)
+		if !ok {
+			return nil, &ValidationError{Message: "status must be 'planned' or 'confirmed'"}
+		}
+		filter.Status = status
+	}
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return []models.Income{}, nil
+		}
+		return nil, err
+	}
+
+	incomes, err := s.repo.ListIncomes(ctx, ledgerID, filter)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if incomes == nil {
+		incomes = []models.Income{}
+	}
+	return incomes, nil
+}
+
+func (s *incomeService) CreateIncome(ctx context.Context, userID string, input models.IncomeInput) (models.Income, error) {
+	income, err := validateIncome(input)
+	if err != nil {
+		return models.Income{}, err
+	}
+	if income.Status == "" {
+		income.Status = string(models.StatusConfirmed)
+	}
+
+	// 収入は支出と同じくユーザーが使用中の家計簿に登録する（viewer は登録できない）
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.Income{}, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	created, err := s.repo.CreateIncome(txCtx, ledgerID, userID, income)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+
+	event := incomeAuditEvent(userID, ledgerID, models.AuditActionIncomeCreate, created.ID)
+	if err := recordAudit(txCtx, s.auditRepo, event, nil, incomeAuditFields(created)); err != nil {
+		_ = tx.Rollback()
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+	return created, nil
+}
+
+func (s *incomeService) UpdateIncome(ctx context.Context, userID string, id int, input models.IncomeInput) (models.Income, error) {
+	income, err := validateIncome(input)
+	if err != nil {
+		return models.Income{}, err
+	}
+	income.ID = id
+
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.Income{}, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	current, err := s.repo.GetIncome(txCtx, ledgerID, int32(id))
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Income{}, &NotFoundError{Message: "income not found"}
+		}
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+	if income.Status == "" {
+		income.Status = current.Status
+	}
+	// 遷移ルール: 支出と同じく confirmed → planned は禁止
+	if current.Status == string(models.StatusConfirmed) && income.Status == string(models.StatusPlanned) {
+		_ = tx.Rollback()
+		return models.Income{}, ErrInvalidStatusTransition
+	}
+
+	updated, err := s.repo.UpdateIncome(txCtx, ledgerID, income)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+
+	event := incomeAuditEvent(userID, ledgerID, models.AuditActionIncomeUpdate, id)
+	if err := recordAudit(txCtx, s.auditRepo, event, incomeAuditFields(current), incomeAuditFields(updated)); err != nil {
+		_ = tx.Rollback()
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Income{}, &InternalError{Message: "internal error"}
+	}
+	return updated, nil
+}
+
+func (s *incomeService) DeleteIncome(ctx context.Context, userID string, id int) error {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	// 記録する削除前の状態を同じトランザクションで読む
+	current, err := s.repo.GetIncome(txCtx, ledgerID, int32(id))
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return &NotFoundError{Message: "income not found"}
+		}
+		return &InternalError{Message: "internal error"}
+	}
+	deleted, err := s.repo.DeleteIncome(txCtx, ledgerID, int32(id))
+	if err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+	if !deleted {
+		_ = tx.Rollback()
+		return &NotFoundError{Message: "income not found"}
+	}
+
+	event := incomeAuditEvent(userID, ledgerID, models.AuditActionIncomeDelete, id)
+	if err := recordAudit(txCtx, s.auditRepo, event, incomeAuditFields(current), nil); err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+func (s *incomeService) MonthlySummary(ctx context.Context, userID string, input models.IncomeSummaryInput) (models.IncomeSummary, error) {
+	now := s.now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if input.Month != "" {
+		var err error
+		if month, err = time.Parse("2006-01", input.Month); err != nil {
+			return models.IncomeSummary{}, &ValidationError{Message: "month must be YYYY-MM"}
+		}
+	}
+	label := month.Format("2006-01")
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return models.IncomeSummary{Month: label}, nil
+		}
+		return models.IncomeSummary{}, err
+	}
+
+	summary, err := s.repo.MonthlySummary(ctx, ledgerID, month)
+	if err != nil {
+		return models.IncomeSummary{}, &InternalError{Message: "internal error"}
+	}
+	summary.Month = label
+	return summary, nil
+}
+
+// validateIncome は収入の登録・更新で共通の入力チェックです。前後の空白を取り除いた収入源と、
+// 正規化したステータス（省略時は空文字列）を返します。
+func validateIncome(input models.IncomeInput) (models.Income, error) {
+	source := strings.TrimSpace(input.Source)
+	if source == "" {
+		return models.Income{}, &ValidationError{Message: "source must be provided"}
+	}
+	if utf8.RuneCountInString(source) > IncomeSourceMaxLen {
+		return models.Income{}, &ValidationError{Message: "source exceeds maximum length"}
+	}
+	if input.Amount == nil {
+		return models.Income{}, &ValidationError{Message: "amount must be provided"}
+	}
+	if *input.Amount <= 0 {
+		return models.Income{}, &ValidationError{Message: "amount must be greater than 0"}
+	}
+	if *input.Amount > BusinessMaxAmount {
+		return models.Income{}, &ValidationError{Message: "amount exceeds maximum allowed"}
+	}
+	receivedAt, err := time.Parse("2006-01-02", input.ReceivedAt)
+	if err != nil {
+		return models.Income{}, &ValidationError{Message: "received_at must be YYYY-MM-DD"}
+	}
+
+	income := models.Income{Source: source, Amount: *input.Amount, ReceivedAt: receivedAt.Format("2006-01-02")}
+	if input.Status != "" {
+		status, ok := models.NormalizeStatus(input.Status)
+		if !ok {
+			return models.Income{}, &ValidationError{Message: "status must be 'planned' or 'confirmed'"}
+		}
+		income.Status = status
+	}
+	return income, nil
+}
+
+// incomeAuditFields は収入の記録対象の項目です。受け取ったメンバーは変わらないため含めません。
+func incomeAuditFields(i models.Income) map[string]any {
+	return map[string]any{
+		"source":      i.Source,
+		"amount":      i.Amount,
+		"received_at": i.ReceivedAt,
+		"status":      i.Status,
+	}
+}
+
+func incomeAuditEvent(userID string, ledgerID int32, action string, incomeID int) models.AuditEvent {
+	return ledgerAuditEvent(userID, ledgerID, action, models.AuditEntityIncome, strconv.Itoa(incomeID))
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type memIncome struct {
+	ledgerID int32
+	income   models.Income
+}
+
+// memIncomeRepo は収入をメモリに持つ IncomeRepository です。ゼロ値は空のリポジトリです。
+type memIncomeRepo struct {
+	incomes []memIncome
+	filter  models.IncomeFilter
+	summary models.IncomeSummary
+	month   time.Time
+	err     error
+}
+
+func (m *memIncomeRepo) CreateIncome(ctx context.Context, ledgerID int32, userID string, income models.Income) (models.Income, error) {
+	if m.err != nil {
+		return models.Income{}, m.err
+	}
+	income.ID = len(m.incomes) + 1
+	income.UserID = userID
+	m.incomes = append(m.incomes, memIncome{ledgerID: ledgerID, income: income})
+	return income, nil
+}
+
+func (m *memIncomeRepo) find(ledgerID int32, id int32) (int, bool) {
+	for i, it := range m.incomes {
+		if it.ledgerID == ledgerID && it.income.ID == int(id) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m *memIncomeRepo) GetIncome(ctx context.Context, ledgerID int32, id int32) (models.Income, error) {
+	if m.err != nil {
+		return models.Income{}, m.err
+	}
+	i, ok := m.find(ledgerID, id)
+	if !ok {
+		return models.Income{}, sql.ErrNoRows
+	}
+	return m.incomes[i].income, nil
+}
+
+func (m *memIncomeRepo) ListIncomes(ctx context.Context, ledgerID int32, filter models.IncomeFilter) ([]models.Income, error) {
+	m.filter = filter
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []models.Income
+	for _, it := range m.incomes {
+		if it.ledgerID == ledgerID {
+			out = append(out, it.income)
+		}
+	}
+	return out, nil
+}
+
+func (m *memIncomeRepo) UpdateIncome(ctx context.Context, ledgerID int32, income models.Income) (models.Income, error) {
+	if m.err != nil {
+		return models.Income{}, m.err
+	}
+	i, ok := m.find(ledgerID, int32(income.ID))
+	if !ok {
+		return models.Income{}, sql.ErrNoRows
+	}
+	income.UserID = m.incomes[i].income.UserID
+	m.incomes[i].income = income
+	return income, nil
+}
+
+func (m *memIncomeRepo) DeleteIncome(ctx context.Context, ledgerID int32, id int32) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	i, ok := m.find(ledgerID, id)
+	if !ok {
+		return false, nil
+	}
+	m.incomes = append(m.incomes[:i], m.incomes[i+1:]...)
+	return true, nil
+}
+
+func (m *memIncomeRepo) MonthlySummary(ctx context.Context, ledgerID int32, month time.Time) (models.IncomeSummary, error) {
+	m.month = month
+	return m.summary, m.err
+}
+
+func TestIncomeService_CreateIncome(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("呼び出し元の収入として、省略したステータスは confirmed で登録する", func(t *testing.T) {
+		repo := &memIncomeRepo{}
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		got, err := s.CreateIncome(ctx, "user-1", models.IncomeInput{Source: " 賞与 ", Amount: intPtr(400000), ReceivedAt: "2025-06-30"})
+		require.NoError(t, err)
+		assert.Equal(t, models.Income{ID: 1, UserID: "user-1", Source: "賞与", Amount: 400000, ReceivedAt: "2025-06-30", Status: "confirmed"}, got)
+		assert.Equal(t, int32(1), repo.incomes[0].ledgerID)
+	})
+
+	t.Run("viewer は登録できない", func(t *testing.T) {
+		repo := &memIncomeRepo{}
+		s := NewIncomeService(repo, activeLedgerAs(1, models.LedgerRoleViewer), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.CreateIncome(ctx, "user-1", models.IncomeInput{Source: "給与", Amount: intPtr(300000), ReceivedAt: "2025-06-25"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		assert.Empty(t, repo.incomes)
+	})
+
+	cases := []struct {
+		name  string
+		input models.IncomeInput
+		want  string
+	}{
+		{name: "収入源が空白だけ", input: models.IncomeInput{Source: " ", Amount: intPtr(1), ReceivedAt: "2025-06-25"}, want: "source must be provided"},
+		{name: "収入源が長すぎる", input: models.IncomeInput{Source: strings.Repeat("あ", IncomeSourceMaxLen+1), Amount: intPtr(1), ReceivedAt: "2025-06-25"}, want: "source exceeds maximum length"},
+		{name: "金額が 0", input: models.IncomeInput{Source: "給与", Amount: intPtr(0), ReceivedAt: "2025-06-25"}, want: "amount must be greater than 0"},
+		{name: "金額が大きすぎる", input: models.IncomeInput{Source: "給与", Amount: intPtr(BusinessMaxAmount + 1), ReceivedAt: "2025-06-25"}, want: "amount exceeds maximum allowed"},
+		{name: "日付の形式が不正", input: models.IncomeInput{Source: "給与", Amount: intPtr(1), ReceivedAt: "2025/06/25"}, want: "received_at must be YYYY-MM-DD"},
+		{name: "不明なステータス", input: models.IncomeInput{Source: "給与", Amount: intPtr(1), ReceivedAt: "2025-06-25", Status: "paid"}, want: "status must be 'planned' or 'confirmed'"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewIncomeService(&memIncomeRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+			_, err := s.CreateIncome(ctx, "user-1", tc.input)
+			var ve *ValidationError
+			require.ErrorAs(t, err, &ve)
+			assert.Equal(t, tc.want, ve.Message)
+		})
+	}
+}
+
+func TestIncomeService_UpdateIncome(t *testing.T) {
+	ctx := context.Background()
+	setup := func(status string) *memIncomeRepo {
+		return &memIncomeRepo{incomes: []memIncome{{ledgerID: 1, income: models.Income{ID: 1, UserID: "user-2", Source: "賞与", Amount: 400000, ReceivedAt: "2025-06-30", Status: status}}}}
+	}
+
+	t.Run("予定の収入を確定にする。受け取ったメンバーは変わらない", func(t *testing.T) {
+		repo := setup("planned")
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		got, err := s.UpdateIncome(ctx, "user-1", 1, models.IncomeInput{Source: "賞与", Amount: intPtr(380000), ReceivedAt: "2025-07-01", Status: "confirmed"})
+		require.NoError(t, err)
+		assert.Equal(t, models.Income{ID: 1, UserID: "user-2", Source: "賞与", Amount: 380000, ReceivedAt: "2025-07-01", Status: "confirmed"}, got)
+	})
+
+	t.Run("ステータスを省略すると現在のステータスのまま", func(t *testing.T) {
+		repo := setup("planned")
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		got, err := s.UpdateIncome(ctx, "user-1", 1, models.IncomeInput{Source: "賞与", Amount: intPtr(400000), ReceivedAt: "2025-06-30"})
+		require.NoError(t, err)
+		assert.Equal(t, "planned", got.Status)
+	})
+
+	t.Run("確定済みの収入は予定に戻せない", func(t *testing.T) {
+		repo := setup("confirmed")
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, tm)
+
+		_, err := s.UpdateIncome(ctx, "user-1", 1, models.IncomeInput{Source: "賞与", Amount: intPtr(400000), ReceivedAt: "2025-06-30", Status: "planned"})
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+		assert.Equal(t, "confirmed", repo.incomes[0].income.Status)
+	})
+
+	t.Run("他の家計簿の収入は NotFoundError", func(t *testing.T) {
+		s := NewIncomeService(setup("planned"), activeLedger(2), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.UpdateIncome(ctx, "user-1", 1, models.IncomeInput{Source: "賞与", Amount: intPtr(400000), ReceivedAt: "2025-06-30"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+}
+
+func TestIncomeService_DeleteIncome(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("削除する", func(t *testing.T) {
+		repo := &memIncomeRepo{incomes: []memIncome{{ledgerID: 1, income: models.Income{ID: 1}}}}
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		require.NoError(t, s.DeleteIncome(ctx, "user-1", 1))
+		assert.Empty(t, repo.incomes)
+	})
+
+	t.Run("無ければ NotFoundError", func(t *testing.T) {
+		s := NewIncomeService(&memIncomeRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		err := s.DeleteIncome(ctx, "user-1", 9)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+}
+
+func TestIncomeService_RecordsAudit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("登録", func(t *testing.T) {
+		rec := &auditRecorder{}
+		s := NewIncomeService(&memIncomeRepo{}, activeLedger(4), rec, nopTxManager{})
+
+		_, err := s.CreateIncome(ctx, "user-1", models.IncomeInput{Source: "給与", Amount: intPtr(300000), ReceivedAt: "2025-06-25"})
+		require.NoError(t, err)
+		require.Len(t, rec.events, 1)
+		ev := rec.events[0]
+		assert.Equal(t, models.AuditActionIncomeCreate, ev.Action)
+		assert.Equal(t, models.AuditEntityIncome, ev.EntityType)
+		assert.Equal(t, "1", ev.EntityID)
+		assert.Equal(t, "user-1", *ev.ActorID)
+		assert.Equal(t, 4, *ev.LedgerID)
+		assert.Equal(t, models.AuditChange{Before: nil, After: 300000}, ev.Changes["amount"])
+		assert.Equal(t, models.AuditChange{Before: nil, After: "confirmed"}, ev.Changes["status"])
+	})
+
+	t.Run("更新は変更された項目だけを記録する", func(t *testing.T) {
+		rec := &auditRecorder{}
+		repo := &memIncomeRepo{incomes: []memIncome{{ledgerID: 4, income: models.Income{ID: 3, UserID: "user-2", Source: "賞与", Amount: 400000, ReceivedAt: "2025-06-30", Status: "planned"}}}}
+		s := NewIncomeService(repo, activeLedger(4), rec, nopTxManager{})
+
+		_, err := s.UpdateIncome(ctx, "user-1", 3, models.IncomeInput{Source: "賞与", Amount: intPtr(400000), ReceivedAt: "2025-06-30", Status: "confirmed"})
+		require.NoError(t, err)
+		require.Len(t, rec.events, 1)
+		assert.Equal(t, models.AuditActionIncomeUpdate, rec.events[0].Action)
+		assert.Equal(t, "3", rec.events[0].EntityID)
+		assert.Equal(t, map[string]models.AuditChange{"status": {Before: "planned", After: "confirmed"}}, rec.events[0].Changes)
+	})
+
+	t.Run("削除は変更前の値を記録する", func(t *testing.T) {
+		rec := &auditRecorder{}
+		repo := &memIncomeRepo{incomes: []memIncome{{ledgerID: 4, income: models.Income{ID: 3, Source: "賞与", Amount: 400000, ReceivedAt: "2025-06-30", Status: "planned"}}}}
+		s := NewIncomeService(repo, activeLedger(4), rec, nopTxManager{})
+
+		require.NoError(t, s.DeleteIncome(ctx, "user-1", 3))
+		require.Len(t, rec.events, 1)
+		assert.Equal(t, models.AuditActionIncomeDelete, rec.events[0].Action)
+		assert.Equal(t, models.AuditChange{Before: 400000, After: nil}, rec.events[0].Changes["amount"])
+	})
+
+	t.Run("記録に失敗すると削除をロールバックする", func(t *testing.T) {
+		repo := &memIncomeRepo{incomes: []memIncome{{ledgerID: 4, income: models.Income{ID: 3}}}}
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		s := NewIncomeService(repo, activeLedger(4), &auditRecorder{createErr: errors.New("db down")}, tm)
+
+		err := s.DeleteIncome(ctx, "user-1", 3)
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+	})
+}
+
+func TestIncomeService_ListIncomes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("to の日を含めて絞り込む", func(t *testing.T) {
+		repo := &memIncomeRepo{}
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		got, err := s.ListIncomes(ctx, "user-1", models.ListIncomesInput{From: "2025-06-01", To: "2025-06-30", Status: "Planned"})
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Equal(t, models.IncomeFilter{
+			From:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			Status: "planned",
+		}, repo.filter)
+	})
+
+	t.Run("家計簿が無ければ空の一覧", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetActiveLedgerID", mock.Anything, "user-1").Return(nil, sql.ErrNoRows)
+		s := NewIncomeService(&memIncomeRepo{}, lr, &auditRecorder{}, nopTxManager{})
+
+		got, err := s.ListIncomes(ctx, "user-1", models.ListIncomesInput{})
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+
+	t.Run("from が to より後", func(t *testing.T) {
+		s := NewIncomeService(&memIncomeRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.ListIncomes(ctx, "user-1", models.ListIncomesInput{From: "2025-06-02", To: "2025-06-01"})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+}
+
+func TestIncomeService_MonthlySummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("month を省略すると今月", func(t *testing.T) {
+		repo := &memIncomeRepo{summary: models.IncomeSummary{Income: 700000, ReceivedIncome: 400000, ExpectedIncome: 600000}}
+		s := NewIncomeService(repo, activeLedger(1), &auditRecorder{}, nopTxManager{}).(*incomeService)
+		s.now = func() time.Time { return time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC) }
+
+		got, err := s.MonthlySummary(ctx, "user-1", models.IncomeSummaryInput{})
+		require.NoError(t, err)
+		assert.Equal(t, models.IncomeSummary{Month: "2025-06", Income: 700000, ReceivedIncome: 400000, ExpectedIncome: 600000}, got)
+		assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), repo.month)
+	})
+
+	t.Run("家計簿が無ければ 0 の集計", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetActiveLedgerID", mock.Anything, "user-1").Return(nil, sql.ErrNoRows)
+		s := NewIncomeService(&memIncomeRepo{}, lr, &auditRecorder{}, nopTxManager{})
+
+		got, err := s.MonthlySummary(ctx, "user-1", models.IncomeSummaryInput{Month: "2025-01"})
+		require.NoError(t, err)
+		assert.Equal(t, models.IncomeSummary{Month: "2025-01"}, got)
+	})
+
+	t.Run("month の形式が不正", func(t *testing.T) {
+		s := NewIncomeService(&memIncomeRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.MonthlySummary(ctx, "user-1", models.IncomeSummaryInput{Month: "2025-6"})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("リポジトリのエラーは InternalError", func(t *testing.T) {
+		s := NewIncomeService(&memIncomeRepo{err: errors.New("db down")}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+		_, err := s.MonthlySummary(ctx, "user-1", models.IncomeSummaryInput{Month: "2025-01"})
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+}
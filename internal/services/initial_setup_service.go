@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strconv"
 
 	"money-buddy-backend/internal/models"
 	"money-buddy-backend/internal/repositories"
@@ -15,13 +17,17 @@ type InitialSetupService interface {
 type initialSetupService struct {
 	userRepo      repositories.UserRepository
 	fixedCostRepo repositories.FixedCostRepository
+	ledgerRepo    repositories.LedgerRepository
+	auditRepo     repositories.AuditRepository
 	txManager     TxManager
 }
 
-func NewInitialSetupService(userRepo repositories.UserRepository, fixedCostRepo repositories.FixedCostRepository, txManager TxManager) InitialSetupService {
+func NewInitialSetupService(userRepo repositories.UserRepository, fixedCostRepo repositories.FixedCostRepository, ledgerRepo repositories.LedgerRepository, auditRepo repositories.AuditRepository, txManager TxManager) InitialSetupService {
 	return &initialSetupService{
 		userRepo:      userRepo,
 		fixedCostRepo: fixedCostRepo,
+		ledgerRepo:    ledgerRepo,
+		auditRepo:     auditRepo,
 		txManager:     txManager,
 	}
 }
@@ -49,6 +55,9 @@ func (s *initialSetupService) CompleteInitialSetup(ctx context.Context, userID s
 
 	txCtx := tx.Context(ctx)
 
+	// 記録する変更前の設定。ユーザーを新規作成する場合は nil
+	var previousSettings map[string]any
+
 	user, err := s.userRepo.GetUserByID(txCtx, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -60,18 +69,80 @@ func (s *initialSetupService) CompleteInitialSetup(ctx context.Context, userID s
 			_ = tx.Rollback()
 			return err
 		}
-	} else if user != (models.User{}) {
+	} else if user.ID != "" {
+		previousSettings = userAuditFields(user.Income, user.SavingGoal)
 		if err := s.userRepo.UpdateUserSettings(txCtx, userID, income, savingGoal); err != nil {
 			_ = tx.Rollback()
 			return err
 		}
 	}
 
-	if err := s.fixedCostRepo.DeleteFixedCostsByUser(txCtx, userID); err != nil {
+	// 固定費は使用中の家計簿に登録する。初回は個人用の家計簿を作成する
+	previousFixedCosts := []models.FixedCostInput{}
+	ledgerID, err := s.ledgerRepo.GetActiveLedgerID(txCtx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			_ = tx.Rollback()
+			return err
+		}
+		ledger, err := createLedgerForUser(txCtx, s.ledgerRepo, userID, DefaultLedgerName)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		ledgerID = int32(ledger.ID)
+	} else {
+		// 共有家計簿の固定費を置き換えるため、viewer には許可しない
+		role, err := s.ledgerRepo.GetMemberRole(txCtx, ledgerID, userID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if role == models.LedgerRoleViewer {
+			_ = tx.Rollback()
+			return &ForbiddenError{Message: "viewers cannot modify this ledger"}
+		}
+
+		current, err := s.fixedCostRepo.ListFixedCostsByLedger(txCtx, ledgerID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		for _, fc := range current {
+			previousFixedCosts = append(previousFixedCosts, models.FixedCostInput{Name: fc.Name, Amount: fc.Amount})
+		}
+	}
+
+	if err := s.fixedCostRepo.DeleteFixedCostsByLedger(txCtx, ledgerID); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
-	if err := s.fixedCostRepo.BulkCreateFixedCosts(txCtx, userID, fixedCosts); err != nil {
+	if err := s.fixedCostRepo.BulkCreateFixedCosts(txCtx, ledgerID, userID, fixedCosts); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := s.userRepo.MarkSetupCompleted(txCtx, userID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	setupEvent := models.AuditEvent{
+		ActorID:    &userID,
+		Action:     models.AuditActionSetupComplete,
+		EntityType: models.AuditEntityUser,
+		EntityID:   userID,
+	}
+	if err := recordAudit(txCtx, s.auditRepo, setupEvent, previousSettings, userAuditFields(income, savingGoal)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	newFixedCosts := append([]models.FixedCostInput{}, fixedCosts...)
+	fixedCostsEvent := ledgerAuditEvent(userID, ledgerID, models.AuditActionFixedCostsReplace, models.AuditEntityFixedCosts, strconv.Itoa(int(ledgerID)))
+	if err := recordAudit(txCtx, s.auditRepo, fixedCostsEvent,
+		map[string]any{"fixed_costs": previousFixedCosts},
+		map[string]any{"fixed_costs": newFixedCosts},
+	); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
@@ -82,3 +153,8 @@ func (s *initialSetupService) CompleteInitialSetup(ctx context.Context, userID s
 
 	return nil
 }
+
+// userAuditFields は初期設定で記録するユーザー設定の項目です。
+func userAuditFields(income, savingGoal int) map[string]any {
+	return map[string]any{"income": income, "saving_goal": savingGoal}
+}
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"money-buddy-backend/internal/models"
 )
@@ -60,39 +62,93 @@ func (m *userRepoMock) UpdateUserSettings(ctx context.Context, id string, income
 	return args.Error(0)
 }
 
-func (m *fixedCostRepoMock) CreateFixedCost(ctx context.Context, userID string, name string, amount int) (models.FixedCost, error) {
-	args := m.Called(ctx, userID, name, amount)
+func (m *userRepoMock) ProvisionUser(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *userRepoMock) MarkSetupCompleted(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *userRepoMock) GetOnboardingProgress(ctx context.Context, id string) (models.OnboardingProgress, error) {
+	args := m.Called(ctx, id)
+	if p, ok := args.Get(0).(models.OnboardingProgress); ok {
+		return p, args.Error(1)
+	}
+	return models.OnboardingProgress{}, args.Error(1)
+}
+
+func (m *userRepoMock) RequestDeletion(ctx context.Context, id string, scheduledAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, scheduledAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *userRepoMock) CancelDeletion(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *userRepoMock) ListUsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, now, limit)
+	if ids, ok := args.Get(0).([]string); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *userRepoMock) LockUserForDeletion(ctx context.Context, id string, now time.Time) (time.Time, error) {
+	args := m.Called(ctx, id, now)
+	if t, ok := args.Get(0).(time.Time); ok {
+		return t, args.Error(1)
+	}
+	return time.Time{}, args.Error(1)
+}
+
+func (m *userRepoMock) IsDisabled(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *userRepoMock) DeleteUser(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *fixedCostRepoMock) CreateFixedCost(ctx context.Context, ledgerID int32, userID string, name string, amount int) (models.FixedCost, error) {
+	args := m.Called(ctx, ledgerID, userID, name, amount)
 	if fc, ok := args.Get(0).(models.FixedCost); ok {
 		return fc, args.Error(1)
 	}
 	return models.FixedCost{}, args.Error(1)
 }
 
-func (m *fixedCostRepoMock) ListFixedCostsByUser(ctx context.Context, userID string) ([]models.FixedCost, error) {
-	args := m.Called(ctx, userID)
+func (m *fixedCostRepoMock) ListFixedCostsByLedger(ctx context.Context, ledgerID int32) ([]models.FixedCost, error) {
+	args := m.Called(ctx, ledgerID)
 	if list, ok := args.Get(0).([]models.FixedCost); ok {
 		return list, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *fixedCostRepoMock) DeleteFixedCostsByUser(ctx context.Context, userID string) error {
-	args := m.Called(ctx, userID)
+func (m *fixedCostRepoMock) DeleteFixedCostsByLedger(ctx context.Context, ledgerID int32) error {
+	args := m.Called(ctx, ledgerID)
 	return args.Error(0)
 }
 
-func (m *fixedCostRepoMock) BulkCreateFixedCosts(ctx context.Context, userID string, fixedCosts []models.FixedCostInput) error {
-	args := m.Called(ctx, userID, fixedCosts)
+func (m *fixedCostRepoMock) BulkCreateFixedCosts(ctx context.Context, ledgerID int32, userID string, fixedCosts []models.FixedCostInput) error {
+	args := m.Called(ctx, ledgerID, userID, fixedCosts)
 	return args.Error(0)
 }
 
-func (m *fixedCostRepoMock) UpdateFixedCost(ctx context.Context, id int32, userID string, name string, amount int) error {
-	args := m.Called(ctx, id, userID, name, amount)
+func (m *fixedCostRepoMock) UpdateFixedCost(ctx context.Context, id int32, ledgerID int32, name string, amount int) error {
+	args := m.Called(ctx, id, ledgerID, name, amount)
 	return args.Error(0)
 }
 
-func (m *fixedCostRepoMock) DeleteFixedCost(ctx context.Context, id int32, userID string) error {
-	args := m.Called(ctx, id, userID)
+func (m *fixedCostRepoMock) DeleteFixedCost(ctx context.Context, id int32, ledgerID int32) error {
+	args := m.Called(ctx, id, ledgerID)
 	return args.Error(0)
 }
 
@@ -108,7 +164,7 @@ func TestCompleteInitialSetup(t *testing.T) {
 		income       int
 		savingGoal   int
 		fixedCosts   []models.FixedCostInput
-		setupMocks   func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, calls *[]string)
+		setupMocks   func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string)
 		wantErr      bool
 		wantValidate bool
 		wantCommit   bool
@@ -120,33 +176,79 @@ func TestCompleteInitialSetup(t *testing.T) {
 			income:     300000,
 			savingGoal: 50000,
 			fixedCosts: validFixedCosts,
-			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, calls *[]string) {
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
 				tm.On("Begin", mock.Anything).Run(func(args mock.Arguments) { *calls = append(*calls, "begin") }).Return(tx, nil)
 				ur.On("GetUserByID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_user") }).Return(models.User{}, sql.ErrNoRows)
 				ur.On("CreateUser", mock.Anything, userID, 300000, 50000).Run(func(args mock.Arguments) { *calls = append(*calls, "create_user") }).Return(nil)
-				fr.On("DeleteFixedCostsByUser", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(nil)
-				fr.On("BulkCreateFixedCosts", mock.Anything, userID, validFixedCosts).Run(func(args mock.Arguments) { *calls = append(*calls, "bulk_create") }).Return(nil)
+				lr.On("GetActiveLedgerID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_ledger") }).Return(nil, sql.ErrNoRows)
+				lr.On("CreateLedger", mock.Anything, DefaultLedgerName).Run(func(args mock.Arguments) { *calls = append(*calls, "create_ledger") }).Return(models.Ledger{ID: 3}, nil)
+				lr.On("AddMember", mock.Anything, int32(3), userID, models.LedgerRoleOwner).Run(func(args mock.Arguments) { *calls = append(*calls, "add_member") }).Return(true, nil)
+				lr.On("SetActiveLedger", mock.Anything, userID, int32(3)).Run(func(args mock.Arguments) { *calls = append(*calls, "set_active") }).Return(nil)
+				fr.On("DeleteFixedCostsByLedger", mock.Anything, int32(3)).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(nil)
+				fr.On("BulkCreateFixedCosts", mock.Anything, int32(3), userID, validFixedCosts).Run(func(args mock.Arguments) { *calls = append(*calls, "bulk_create") }).Return(nil)
+				ur.On("MarkSetupCompleted", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "mark_setup") }).Return(nil)
+				tx.On("Commit").Run(func(args mock.Arguments) { *calls = append(*calls, "commit") }).Return(nil)
+			},
+			wantCommit:   true,
+			wantRollback: false,
+			wantCalls:    []string{"begin", "get_user", "create_user", "get_ledger", "create_ledger", "add_member", "set_active", "delete_fixed", "bulk_create", "mark_setup", "commit"},
+		},
+		{
+			name:       "既存ユーザーは使用中の家計簿の固定費を置き換える",
+			income:     300000,
+			savingGoal: 50000,
+			fixedCosts: validFixedCosts,
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
+				tm.On("Begin", mock.Anything).Run(func(args mock.Arguments) { *calls = append(*calls, "begin") }).Return(tx, nil)
+				ur.On("GetUserByID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_user") }).Return(models.User{ID: userID}, nil)
+				ur.On("UpdateUserSettings", mock.Anything, userID, 300000, 50000).Run(func(args mock.Arguments) { *calls = append(*calls, "update_user") }).Return(nil)
+				lr.On("GetActiveLedgerID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_ledger") }).Return(int32(8), nil)
+				lr.On("GetMemberRole", mock.Anything, int32(8), userID).Return(models.LedgerRoleEditor, nil)
+				fr.On("ListFixedCostsByLedger", mock.Anything, int32(8)).Run(func(args mock.Arguments) { *calls = append(*calls, "list_fixed") }).Return([]models.FixedCost{}, nil)
+				fr.On("DeleteFixedCostsByLedger", mock.Anything, int32(8)).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(nil)
+				fr.On("BulkCreateFixedCosts", mock.Anything, int32(8), userID, validFixedCosts).Run(func(args mock.Arguments) { *calls = append(*calls, "bulk_create") }).Return(nil)
+				ur.On("MarkSetupCompleted", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "mark_setup") }).Return(nil)
 				tx.On("Commit").Run(func(args mock.Arguments) { *calls = append(*calls, "commit") }).Return(nil)
 			},
 			wantCommit:   true,
 			wantRollback: false,
-			wantCalls:    []string{"begin", "get_user", "create_user", "delete_fixed", "bulk_create", "commit"},
+			wantCalls:    []string{"begin", "get_user", "update_user", "get_ledger", "list_fixed", "delete_fixed", "bulk_create", "mark_setup", "commit"},
+		},
+		{
+			name:       "viewer は共有家計簿の固定費を置き換えられない",
+			income:     300000,
+			savingGoal: 50000,
+			fixedCosts: validFixedCosts,
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
+				tm.On("Begin", mock.Anything).Run(func(args mock.Arguments) { *calls = append(*calls, "begin") }).Return(tx, nil)
+				ur.On("GetUserByID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_user") }).Return(models.User{ID: userID}, nil)
+				ur.On("UpdateUserSettings", mock.Anything, userID, 300000, 50000).Run(func(args mock.Arguments) { *calls = append(*calls, "update_user") }).Return(nil)
+				lr.On("GetActiveLedgerID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_ledger") }).Return(int32(8), nil)
+				lr.On("GetMemberRole", mock.Anything, int32(8), userID).Return(models.LedgerRoleViewer, nil)
+				tx.On("Rollback").Run(func(args mock.Arguments) { *calls = append(*calls, "rollback") }).Return(nil)
+			},
+			wantErr:      true,
+			wantCommit:   false,
+			wantRollback: true,
+			wantCalls:    []string{"begin", "get_user", "update_user", "get_ledger", "rollback"},
 		},
 		{
-			name:         "income が 0 以下でエラー",
-			income:       0,
-			savingGoal:   0,
-			fixedCosts:   validFixedCosts,
-			setupMocks:   func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, calls *[]string) {},
+			name:       "income が 0 以下でエラー",
+			income:     0,
+			savingGoal: 0,
+			fixedCosts: validFixedCosts,
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
+			},
 			wantErr:      true,
 			wantValidate: true,
 		},
 		{
-			name:         "固定費 amount が 0 以下でエラー",
-			income:       100,
-			savingGoal:   0,
-			fixedCosts:   []models.FixedCostInput{{Name: "rent", Amount: 0}},
-			setupMocks:   func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, calls *[]string) {},
+			name:       "固定費 amount が 0 以下でエラー",
+			income:     100,
+			savingGoal: 0,
+			fixedCosts: []models.FixedCostInput{{Name: "rent", Amount: 0}},
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
+			},
 			wantErr:      true,
 			wantValidate: true,
 		},
@@ -155,35 +257,41 @@ func TestCompleteInitialSetup(t *testing.T) {
 			income:     100,
 			savingGoal: 0,
 			fixedCosts: validFixedCosts,
-			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, calls *[]string) {
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
 				tm.On("Begin", mock.Anything).Run(func(args mock.Arguments) { *calls = append(*calls, "begin") }).Return(tx, nil)
 				ur.On("GetUserByID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_user") }).Return(models.User{ID: userID}, nil)
 				ur.On("UpdateUserSettings", mock.Anything, userID, 100, 0).Run(func(args mock.Arguments) { *calls = append(*calls, "update_user") }).Return(nil)
-				fr.On("DeleteFixedCostsByUser", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(errors.New("delete failed"))
+				lr.On("GetActiveLedgerID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_ledger") }).Return(int32(8), nil)
+				lr.On("GetMemberRole", mock.Anything, int32(8), userID).Return(models.LedgerRoleEditor, nil)
+				fr.On("ListFixedCostsByLedger", mock.Anything, int32(8)).Run(func(args mock.Arguments) { *calls = append(*calls, "list_fixed") }).Return([]models.FixedCost{}, nil)
+				fr.On("DeleteFixedCostsByLedger", mock.Anything, int32(8)).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(errors.New("delete failed"))
 				tx.On("Rollback").Run(func(args mock.Arguments) { *calls = append(*calls, "rollback") }).Return(nil)
 			},
 			wantErr:      true,
 			wantCommit:   false,
 			wantRollback: true,
-			wantCalls:    []string{"begin", "get_user", "update_user", "delete_fixed", "rollback"},
+			wantCalls:    []string{"begin", "get_user", "update_user", "get_ledger", "list_fixed", "delete_fixed", "rollback"},
 		},
 		{
 			name:       "fixed_costs 作成失敗で rollback",
 			income:     100,
 			savingGoal: 0,
 			fixedCosts: validFixedCosts,
-			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, calls *[]string) {
+			setupMocks: func(tx *txMock, tm *txManagerMock, ur *userRepoMock, fr *fixedCostRepoMock, lr *ledgerRepoMock, calls *[]string) {
 				tm.On("Begin", mock.Anything).Run(func(args mock.Arguments) { *calls = append(*calls, "begin") }).Return(tx, nil)
 				ur.On("GetUserByID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_user") }).Return(models.User{ID: userID}, nil)
 				ur.On("UpdateUserSettings", mock.Anything, userID, 100, 0).Run(func(args mock.Arguments) { *calls = append(*calls, "update_user") }).Return(nil)
-				fr.On("DeleteFixedCostsByUser", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(nil)
-				fr.On("BulkCreateFixedCosts", mock.Anything, userID, validFixedCosts).Run(func(args mock.Arguments) { *calls = append(*calls, "bulk_create") }).Return(errors.New("bulk failed"))
+				lr.On("GetActiveLedgerID", mock.Anything, userID).Run(func(args mock.Arguments) { *calls = append(*calls, "get_ledger") }).Return(int32(8), nil)
+				lr.On("GetMemberRole", mock.Anything, int32(8), userID).Return(models.LedgerRoleEditor, nil)
+				fr.On("ListFixedCostsByLedger", mock.Anything, int32(8)).Run(func(args mock.Arguments) { *calls = append(*calls, "list_fixed") }).Return([]models.FixedCost{}, nil)
+				fr.On("DeleteFixedCostsByLedger", mock.Anything, int32(8)).Run(func(args mock.Arguments) { *calls = append(*calls, "delete_fixed") }).Return(nil)
+				fr.On("BulkCreateFixedCosts", mock.Anything, int32(8), userID, validFixedCosts).Run(func(args mock.Arguments) { *calls = append(*calls, "bulk_create") }).Return(errors.New("bulk failed"))
 				tx.On("Rollback").Run(func(args mock.Arguments) { *calls = append(*calls, "rollback") }).Return(nil)
 			},
 			wantErr:      true,
 			wantCommit:   false,
 			wantRollback: true,
-			wantCalls:    []string{"begin", "get_user", "update_user", "delete_fixed", "bulk_create", "rollback"},
+			wantCalls:    []string{"begin", "get_user", "update_user", "get_ledger", "list_fixed", "delete_fixed", "bulk_create", "rollback"},
 		},
 	}
 
@@ -196,13 +304,14 @@ func TestCompleteInitialSetup(t *testing.T) {
 			tm := &txManagerMock{}
 			ur := &userRepoMock{}
 			fr := &fixedCostRepoMock{}
+			lr := &ledgerRepoMock{}
 			calls := []string{}
 
 			if tc.setupMocks != nil {
-				tc.setupMocks(tx, tm, ur, fr, &calls)
+				tc.setupMocks(tx, tm, ur, fr, lr, &calls)
 			}
 
-			s := NewInitialSetupService(ur, fr, tm)
+			s := NewInitialSetupService(ur, fr, lr, &auditRecorder{}, tm)
 			err := s.CompleteInitialSetup(context.Background(), userID, tc.income, tc.savingGoal, tc.fixedCosts)
 
 			if tc.wantErr {
@@ -233,7 +342,50 @@ func TestCompleteInitialSetup(t *testing.T) {
 			tm.AssertExpectations(t)
 			ur.AssertExpectations(t)
 			fr.AssertExpectations(t)
+			lr.AssertExpectations(t)
 			tx.AssertExpectations(t)
 		})
 	}
 }
+
+func TestCompleteInitialSetup_RecordsAudit(t *testing.T) {
+	userID := "user-1"
+	fixedCosts := []models.FixedCostInput{{Name: "rent", Amount: 80000}}
+
+	tx := &txMock{}
+	tm := &txManagerMock{}
+	ur := &userRepoMock{}
+	fr := &fixedCostRepoMock{}
+	lr := &ledgerRepoMock{}
+	rec := &auditRecorder{}
+
+	tm.On("Begin", mock.Anything).Return(tx, nil)
+	ur.On("GetUserByID", mock.Anything, userID).Return(models.User{ID: userID, Income: 250000, SavingGoal: 50000}, nil)
+	ur.On("UpdateUserSettings", mock.Anything, userID, 300000, 50000).Return(nil)
+	lr.On("GetActiveLedgerID", mock.Anything, userID).Return(int32(8), nil)
+	lr.On("GetMemberRole", mock.Anything, int32(8), userID).Return(models.LedgerRoleOwner, nil)
+	fr.On("ListFixedCostsByLedger", mock.Anything, int32(8)).Return([]models.FixedCost{{ID: 1, Name: "rent", Amount: 75000}}, nil)
+	fr.On("DeleteFixedCostsByLedger", mock.Anything, int32(8)).Return(nil)
+	fr.On("BulkCreateFixedCosts", mock.Anything, int32(8), userID, fixedCosts).Return(nil)
+	ur.On("MarkSetupCompleted", mock.Anything, userID).Return(nil)
+	tx.On("Commit").Return(nil)
+
+	s := NewInitialSetupService(ur, fr, lr, rec, tm)
+	require.NoError(t, s.CompleteInitialSetup(context.Background(), userID, 300000, 50000, fixedCosts))
+
+	require.Len(t, rec.events, 2)
+
+	setup := rec.events[0]
+	assert.Equal(t, models.AuditActionSetupComplete, setup.Action)
+	assert.Nil(t, setup.LedgerID)
+	assert.Equal(t, map[string]models.AuditChange{"income": {Before: 250000, After: 300000}}, setup.Changes)
+
+	replaced := rec.events[1]
+	assert.Equal(t, models.AuditActionFixedCostsReplace, replaced.Action)
+	assert.Equal(t, "8", replaced.EntityID)
+	assert.Equal(t, 8, *replaced.LedgerID)
+	assert.Equal(t, models.AuditChange{
+		Before: []models.FixedCostInput{{Name: "rent", Amount: 75000}},
+		After:  fixedCosts,
+	}, replaced.Changes["fixed_costs"])
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+const (
+	// LedgerNameMaxLen は家計簿名の最大長
+	LedgerNameMaxLen = 100
+	// DefaultLedgerName は初期設定時に作成する家計簿の名前
+	DefaultLedgerName = "My ledger"
+	// inviteCodeBytes は招待コードの乱数バイト数（base32 で 16 文字）
+	inviteCodeBytes = 10
+)
+
+type LedgerService interface {
+	// CreateLedger は家計簿を作成し、作成者をメンバーに加えてアクティブにします。
+	CreateLedger(ctx context.Context, userID string, input models.CreateLedgerInput) (models.Ledger, error)
+	ListLedgers(ctx context.Context, userID string) ([]models.Ledger, error)
+	// CreateInvite は一度だけ使える招待コードを発行します。
+	CreateInvite(ctx context.Context, userID string, ledgerID int) (models.LedgerInvite, error)
+	// JoinLedger は招待コードで家計簿に参加し、参加した家計簿をアクティブにします。
+	JoinLedger(ctx context.Context, userID string, input models.JoinLedgerInput) (models.Ledger, error)
+	// LeaveLedger は家計簿から退出します。最後のメンバーが退出した家計簿はデータごと削除されます。
+	LeaveLedger(ctx context.Context, userID string, ledgerID int) error
+	// SwitchLedger は支出・固定費の読み書きに使う家計簿を切り替えます。
+	SwitchLedger(ctx context.Context, userID string, input models.SwitchLedgerInput) (models.Ledger, error)
+
+	ListMembers(ctx context.Context, userID string, ledgerID int) ([]models.LedgerMember, error)
+	// UpdateMemberRole は他のメンバーのロールを editor / viewer に変更します。owner のみ実行できます。
+	UpdateMemberRole(ctx context.Context, userID string, ledgerID int, memberID string, input models.UpdateLedgerMemberRoleInput) (models.LedgerMember, error)
+	// TransferOwnership は owner を他のメンバーに譲り、自分は editor になります。
+	TransferOwnership(ctx context.Context, userID string, ledgerID int, input models.TransferLedgerOwnershipInput) (models.Ledger, error)
+}
+
+type ledgerService struct {
+	repo      repositories.LedgerRepository
+	txManager TxManager
+	inviteTTL time.Duration
+	now       func() time.Time
+}
+
+func NewLedgerService(repo repositories.LedgerRepository, txManager TxManager, inviteTTL time.Duration) LedgerService {
+	return &ledgerService{
+		repo:      repo,
+		txManager: txManager,
+		inviteTTL: inviteTTL,
+		now:       time.Now,
+	}
+}
+
+func (s *ledgerService) CreateLedger(ctx context.Context, userID string, input models.CreateLedgerInput) (models.Ledger, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.Ledger{}, &ValidationError{Message: "name must be provided"}
+	}
+	if len(name) > LedgerNameMaxLen {
+		return models.Ledger{}, &ValidationError{Message: "name exceeds maximum length"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	ledger, err := createLedgerForUser(tx.Context(ctx), s.repo, userID, name)
+	if err != nil {
+		_ = tx.Rollback()
+		// users への外部キー違反は初期設定前のユーザー
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return models.Ledger{}, &NotFoundError{Message: "user not found"}
+		}
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+	return ledger, nil
+}
+
+func (s *ledgerService) ListLedgers(ctx context.Context, userID string) ([]models.Ledger, error) {
+	return s.repo.ListLedgersByUser(ctx, userID)
+}
+
+func (s *ledgerService) CreateInvite(ctx context.Context, userID string, ledgerID int) (models.LedgerInvite, error) {
+	if err := requireLedgerOwner(ctx, s.repo, int32(ledgerID), userID); err != nil {
+		return models.LedgerInvite{}, err
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return models.LedgerInvite{}, &InternalError{Message: "internal error"}
+	}
+	expiresAt := s.now().UTC().Add(s.inviteTTL)
+
+	if err := s.repo.CreateInvite(ctx, int32(ledgerID), userID, hashInviteCode(code), expiresAt); err != nil {
+		return models.LedgerInvite{}, &InternalError{Message: "internal error"}
+	}
+
+	return models.LedgerInvite{
+		LedgerID:  ledgerID,
+		Code:      code,
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *ledgerService) JoinLedger(ctx context.Context, userID string, input models.JoinLedgerInput) (models.Ledger, error) {
+	code := normalizeInviteCode(input.Code)
+	if code == "" {
+		return models.Ledger{}, &ValidationError{Message: "code must be provided"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	txCtx := tx.Context(ctx)
+
+	ledgerID, err := s.repo.ClaimInvite(txCtx, hashInviteCode(code), userID, s.now().UTC())
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Ledger{}, &NotFoundError{Message: "invite code is invalid or expired"}
+		}
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return models.Ledger{}, &NotFoundError{Message: "user not found"}
+		}
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	// 招待で参加したメンバーは editor。閲覧のみにする場合は owner がロールを変更する
+	added, err := s.repo.AddMember(txCtx, ledgerID, userID, models.LedgerRoleEditor)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+	if !added {
+		// 招待コードを消費しないようロールバックする
+		_ = tx.Rollback()
+		return models.Ledger{}, &ValidationError{Message: "already a member of this ledger"}
+	}
+
+	if err := s.repo.SetActiveLedger(txCtx, userID, ledgerID); err != nil {
+		_ = tx.Rollback()
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	ledger, err := s.repo.GetLedgerForMember(ctx, ledgerID, userID)
+	if err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+	return ledger, nil
+}
+
+func (s *ledgerService) LeaveLedger(ctx context.Context, userID string, ledgerID int) error {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+
+	txCtx := tx.Context(ctx)
+
+	role, err := memberRole(txCtx, s.repo, int32(ledgerID), userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	members, err := s.repo.CountMembers(txCtx, int32(ledgerID))
+	if err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+	// owner 不在の家計簿を作らないよう、他のメンバーがいる間は先に owner を譲ってもらう
+	if role == models.LedgerRoleOwner && members > 1 {
+		_ = tx.Rollback()
+		return &ForbiddenError{Message: "transfer ownership before leaving the ledger"}
+	}
+
+	removed, err := s.repo.RemoveMember(txCtx, int32(ledgerID), userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+	if !removed {
+		_ = tx.Rollback()
+		return &NotFoundError{Message: "ledger not found"}
+	}
+
+	if err := s.repo.ReassignActiveLedger(txCtx, userID, int32(ledgerID)); err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+
+	// 誰もいなくなった家計簿は支出・固定費ごと削除する
+	if members == 1 {
+		if err := s.repo.DeleteLedger(txCtx, int32(ledgerID)); err != nil {
+			_ = tx.Rollback()
+			return &InternalError{Message: "internal error"}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+func (s *ledgerService) SwitchLedger(ctx context.Context, userID string, input models.SwitchLedgerInput) (models.Ledger, error) {
+	if input.LedgerID == nil {
+		return models.Ledger{}, &ValidationError{Message: "ledger_id must be provided"}
+	}
+
+	ledger, err := s.repo.GetLedgerForMember(ctx, int32(*input.LedgerID), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Ledger{}, &NotFoundError{Message: "ledger not found"}
+		}
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := s.repo.SetActiveLedger(ctx, userID, int32(ledger.ID)); err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	ledger.Active = true
+	return ledger, nil
+}
+
+func (s *ledgerService) ListMembers(ctx context.Context, userID string, ledgerID int) ([]models.LedgerMember, error) {
+	if _, err := memberRole(ctx, s.repo, int32(ledgerID), userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, int32(ledgerID))
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	return members, nil
+}
+
+func (s *ledgerService) UpdateMemberRole(ctx context.Context, userID string, ledgerID int, memberID string, input models.UpdateLedgerMemberRoleInput) (models.LedgerMember, error) {
+	role := strings.ToLower(strings.TrimSpace(input.Role))
+	switch role {
+	case models.LedgerRoleEditor, models.LedgerRoleViewer:
+	case models.LedgerRoleOwner:
+		return models.LedgerMember{}, &ValidationError{Message: "use ownership transfer to change the owner"}
+	default:
+		return models.LedgerMember{}, &ValidationError{Message: "role must be 'editor' or 'viewer'"}
+	}
+	if memberID == userID {
+		return models.LedgerMember{}, &ValidationError{Message: "cannot change your own role"}
+	}
+
+	if err := requireLedgerOwner(ctx, s.repo, int32(ledgerID), userID); err != nil {
+		return models.LedgerMember{}, err
+	}
+
+	member, err := s.repo.UpdateMemberRole(ctx, int32(ledgerID), memberID, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.LedgerMember{}, &NotFoundError{Message: "member not found"}
+		}
+		return models.LedgerMember{}, &InternalError{Message: "internal error"}
+	}
+	return member, nil
+}
+
+func (s *ledgerService) TransferOwnership(ctx context.Context, userID string, ledgerID int, input models.TransferLedgerOwnershipInput) (models.Ledger, error) {
+	newOwner := strings.TrimSpace(input.UserID)
+	if newOwner == "" {
+		return models.Ledger{}, &ValidationError{Message: "user_id must be provided"}
+	}
+	if newOwner == userID {
+		return models.Ledger{}, &ValidationError{Message: "you are already the owner"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	txCtx := tx.Context(ctx)
+
+	if err := requireLedgerOwner(txCtx, s.repo, int32(ledgerID), userID); err != nil {
+		_ = tx.Rollback()
+		return models.Ledger{}, err
+	}
+
+	if _, err := s.repo.UpdateMemberRole(txCtx, int32(ledgerID), newOwner, models.LedgerRoleOwner); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Ledger{}, &NotFoundError{Message: "member not found"}
+		}
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+	if _, err := s.repo.UpdateMemberRole(txCtx, int32(ledgerID), userID, models.LedgerRoleEditor); err != nil {
+		_ = tx.Rollback()
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+
+	ledger, err := s.repo.GetLedgerForMember(ctx, int32(ledgerID), userID)
+	if err != nil {
+		return models.Ledger{}, &InternalError{Message: "internal error"}
+	}
+	return ledger, nil
+}
+
+// createLedgerForUser は家計簿を作成し、userID を唯一のメンバーとしてアクティブにします。
+// 呼び出し側のトランザクション内で実行してください。
+func createLedgerForUser(ctx context.Context, repo repositories.LedgerRepository, userID string, name string) (models.Ledger, error) {
+	ledger, err := repo.CreateLedger(ctx, name)
+	if err != nil {
+		return models.Ledger{}, err
+	}
+	if _, err := repo.AddMember(ctx, int32(ledger.ID), userID, models.LedgerRoleOwner); err != nil {
+		return models.Ledger{}, err
+	}
+	if err := repo.SetActiveLedger(ctx, userID, int32(ledger.ID)); err != nil {
+		return models.Ledger{}, err
+	}
+
+	ledger.MemberCount = 1
+	ledger.Active = true
+	ledger.Role = models.LedgerRoleOwner
+	return ledger, nil
+}
+
+// activeLedgerID はユーザーが使用中の家計簿を返します。未選択の場合は ErrNoActiveLedger を返します。
+func activeLedgerID(ctx context.Context, repo repositories.LedgerRepository, userID string) (int32, error) {
+	id, err := repo.GetActiveLedgerID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNoActiveLedger
+		}
+		return 0, &InternalError{Message: "internal error"}
+	}
+	return id, nil
+}
+
+// writableLedgerID はユーザーが使用中の家計簿を、編集権限を確認したうえで返します。
+// viewer の場合は ForbiddenError を返します。
+func writableLedgerID(ctx context.Context, repo repositories.LedgerRepository, userID string) (int32, error) {
+	ledgerID, err := activeLedgerID(ctx, repo, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	role, err := repo.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		// 直前に退出した場合
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNoActiveLedger
+		}
+		return 0, &InternalError{Message: "internal error"}
+	}
+	if role == models.LedgerRoleViewer {
+		return 0, &ForbiddenError{Message: "viewers cannot modify this ledger"}
+	}
+	return ledgerID, nil
+}
+
+// memberRole はメンバーのロールを返します。メンバーでない場合は NotFoundError を返します。
+func memberRole(ctx context.Context, repo repositories.LedgerRepository, ledgerID int32, userID string) (string, error) {
+	role, err := repo.GetMemberRole(ctx, ledgerID, userID)
+	if err != nil {
+		// 他人の家計簿の存在を明かさないよう、非メンバーには 404 を返す
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", &NotFoundError{Message: "ledger not found"}
+		}
+		return "", &InternalError{Message: "internal error"}
+	}
+	return role, nil
+}
+
+// requireLedgerOwner は userID が家計簿の owner であることを確認します。
+func requireLedgerOwner(ctx context.Context, repo repositories.LedgerRepository, ledgerID int32, userID string) error {
+	role, err := memberRole(ctx, repo, ledgerID, userID)
+	if err != nil {
+		return err
+	}
+	if role != models.LedgerRoleOwner {
+		return &ForbiddenError{Message: "only the ledger owner can do this"}
+	}
+	return nil
+}
+
+// generateInviteCode は読み上げやすいよう 4 文字ごとにハイフンで区切った招待コードを生成します。
+func generateInviteCode() (string, error) {
+	b := make([]byte, inviteCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+
+	var sb strings.Builder
+	for i := 0; i < len(raw); i += 4 {
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(raw[i : i+4])
+	}
+	return sb.String(), nil
+}
+
+// normalizeInviteCode は大文字・小文字や区切り文字の違いを吸収します。
+func normalizeInviteCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// hashInviteCode は DB に保存する招待コードのハッシュを返します（平文は保存しない）。
+func hashInviteCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeInviteCode(code)))
+	return hex.EncodeToString(sum[:])
+}
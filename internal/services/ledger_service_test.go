@@ -0,0 +1,563 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type ledgerRepoMock struct{ mock.Mock }
+
+func (m *ledgerRepoMock) CreateLedger(ctx context.Context, name string) (models.Ledger, error) {
+	args := m.Called(ctx, name)
+	if l, ok := args.Get(0).(models.Ledger); ok {
+		return l, args.Error(1)
+	}
+	return models.Ledger{}, args.Error(1)
+}
+
+func (m *ledgerRepoMock) ListLedgersByUser(ctx context.Context, userID string) ([]models.Ledger, error) {
+	args := m.Called(ctx, userID)
+	if list, ok := args.Get(0).([]models.Ledger); ok {
+		return list, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ledgerRepoMock) GetLedgerForMember(ctx context.Context, ledgerID int32, userID string) (models.Ledger, error) {
+	args := m.Called(ctx, ledgerID, userID)
+	if l, ok := args.Get(0).(models.Ledger); ok {
+		return l, args.Error(1)
+	}
+	return models.Ledger{}, args.Error(1)
+}
+
+func (m *ledgerRepoMock) DeleteLedger(ctx context.Context, ledgerID int32) error {
+	args := m.Called(ctx, ledgerID)
+	return args.Error(0)
+}
+
+func (m *ledgerRepoMock) AddMember(ctx context.Context, ledgerID int32, userID string, role string) (bool, error) {
+	args := m.Called(ctx, ledgerID, userID, role)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ledgerRepoMock) RemoveMember(ctx context.Context, ledgerID int32, userID string) (bool, error) {
+	args := m.Called(ctx, ledgerID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ledgerRepoMock) CountMembers(ctx context.Context, ledgerID int32) (int, error) {
+	args := m.Called(ctx, ledgerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ledgerRepoMock) ListMembers(ctx context.Context, ledgerID int32) ([]models.LedgerMember, error) {
+	args := m.Called(ctx, ledgerID)
+	if list, ok := args.Get(0).([]models.LedgerMember); ok {
+		return list, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ledgerRepoMock) GetMemberRole(ctx context.Context, ledgerID int32, userID string) (string, error) {
+	args := m.Called(ctx, ledgerID, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *ledgerRepoMock) UpdateMemberRole(ctx context.Context, ledgerID int32, userID string, role string) (models.LedgerMember, error) {
+	args := m.Called(ctx, ledgerID, userID, role)
+	if member, ok := args.Get(0).(models.LedgerMember); ok {
+		return member, args.Error(1)
+	}
+	return models.LedgerMember{}, args.Error(1)
+}
+
+func (m *ledgerRepoMock) GetActiveLedgerID(ctx context.Context, userID string) (int32, error) {
+	args := m.Called(ctx, userID)
+	if id, ok := args.Get(0).(int32); ok {
+		return id, args.Error(1)
+	}
+	return 0, args.Error(1)
+}
+
+func (m *ledgerRepoMock) SetActiveLedger(ctx context.Context, userID string, ledgerID int32) error {
+	args := m.Called(ctx, userID, ledgerID)
+	return args.Error(0)
+}
+
+func (m *ledgerRepoMock) ReassignActiveLedger(ctx context.Context, userID string, ledgerID int32) error {
+	args := m.Called(ctx, userID, ledgerID)
+	return args.Error(0)
+}
+
+func (m *ledgerRepoMock) CreateInvite(ctx context.Context, ledgerID int32, createdBy string, codeHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, ledgerID, createdBy, codeHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *ledgerRepoMock) ClaimInvite(ctx context.Context, codeHash string, userID string, now time.Time) (int32, error) {
+	args := m.Called(ctx, codeHash, userID, now)
+	if id, ok := args.Get(0).(int32); ok {
+		return id, args.Error(1)
+	}
+	return 0, args.Error(1)
+}
+
+// activeLedger は使用中の家計簿として id を返す ledgerRepoMock を作ります。ロールは editor です。
+func activeLedger(id int32) *ledgerRepoMock {
+	return activeLedgerAs(id, models.LedgerRoleEditor)
+}
+
+func activeLedgerAs(id int32, role string) *ledgerRepoMock {
+	lr := new(ledgerRepoMock)
+	lr.On("GetActiveLedgerID", mock.Anything, mock.Anything).Return(id, nil)
+	lr.On("GetMemberRole", mock.Anything, id, mock.Anything).Return(role, nil).Maybe()
+	return lr
+}
+
+func TestLedgerService_CreateLedger(t *testing.T) {
+	ctx := context.Background()
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	lr := new(ledgerRepoMock)
+
+	tm.On("Begin", ctx).Return(tx, nil)
+	lr.On("CreateLedger", ctx, "Family").Return(models.Ledger{ID: 5, Name: "Family"}, nil)
+	lr.On("AddMember", ctx, int32(5), "user-1", models.LedgerRoleOwner).Return(true, nil)
+	lr.On("SetActiveLedger", ctx, "user-1", int32(5)).Return(nil)
+	tx.On("Commit").Return(nil)
+
+	s := NewLedgerService(lr, tm, time.Hour)
+	got, err := s.CreateLedger(ctx, "user-1", models.CreateLedgerInput{Name: " Family "})
+	require.NoError(t, err)
+	assert.Equal(t, 5, got.ID)
+	assert.Equal(t, 1, got.MemberCount)
+	assert.True(t, got.Active)
+	assert.Equal(t, models.LedgerRoleOwner, got.Role)
+	lr.AssertExpectations(t)
+	tx.AssertExpectations(t)
+}
+
+func TestLedgerService_CreateLedger_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		input models.CreateLedgerInput
+	}{
+		{name: "名前が空", input: models.CreateLedgerInput{Name: "  "}},
+		{name: "名前が長すぎる", input: models.CreateLedgerInput{Name: strings.Repeat("a", 101)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := new(txManagerMock)
+			s := NewLedgerService(new(ledgerRepoMock), tm, time.Hour)
+
+			_, err := s.CreateLedger(context.Background(), "user-1", tc.input)
+
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			tm.AssertNotCalled(t, "Begin", mock.Anything)
+		})
+	}
+}
+
+func TestLedgerService_CreateInvite(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("owner は招待コードを発行でき、ハッシュのみ保存される", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleOwner, nil)
+		var storedHash string
+		lr.On("CreateInvite", ctx, int32(5), "user-1", mock.AnythingOfType("string"), now.Add(48*time.Hour)).
+			Run(func(args mock.Arguments) { storedHash = args.String(3) }).
+			Return(nil)
+
+		s := NewLedgerService(lr, new(txManagerMock), 48*time.Hour).(*ledgerService)
+		s.now = func() time.Time { return now }
+
+		invite, err := s.CreateInvite(ctx, "user-1", 5)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-05-03T12:00:00Z", invite.ExpiresAt)
+		assert.Len(t, invite.Code, 19) // XXXX-XXXX-XXXX-XXXX
+		assert.Equal(t, hashInviteCode(invite.Code), storedHash)
+		// 入力揺れ（小文字・ハイフン無し）でも同じハッシュになる
+		assert.Equal(t, storedHash, hashInviteCode(strings.ToLower(strings.ReplaceAll(invite.Code, "-", ""))))
+	})
+
+	t.Run("メンバーでなければ NotFoundError", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "stranger").Return("", sql.ErrNoRows)
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+
+		_, err := s.CreateInvite(ctx, "stranger", 5)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		lr.AssertNotCalled(t, "CreateInvite", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("owner 以外は ForbiddenError", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "user-2").Return(models.LedgerRoleEditor, nil)
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+
+		_, err := s.CreateInvite(ctx, "user-2", 5)
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		lr.AssertNotCalled(t, "CreateInvite", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLedgerService_JoinLedger(t *testing.T) {
+	ctx := context.Background()
+	code := "ABCD-EFGH-IJKL-MNOP"
+
+	t.Run("招待コードで参加し、参加した家計簿をアクティブにする", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("ClaimInvite", ctx, hashInviteCode(code), "user-2", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Return(int32(5), nil)
+		lr.On("AddMember", ctx, int32(5), "user-2", models.LedgerRoleEditor).Return(true, nil)
+		lr.On("SetActiveLedger", ctx, "user-2", int32(5)).Return(nil)
+		tx.On("Commit").Return(nil)
+		lr.On("GetLedgerForMember", ctx, int32(5), "user-2").Return(models.Ledger{ID: 5, MemberCount: 2, Active: true}, nil)
+
+		s := NewLedgerService(lr, tm, time.Hour).(*ledgerService)
+		// 有効期限はサーバーの時刻を UTC にして判定する
+		s.now = func() time.Time { return time.Date(2025, 6, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)) }
+		got, err := s.JoinLedger(ctx, "user-2", models.JoinLedgerInput{Code: " abcd-efgh-ijkl-mnop "})
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.MemberCount)
+		lr.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("無効・期限切れ・使用済みのコードは NotFoundError", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("ClaimInvite", ctx, mock.Anything, "user-2", mock.Anything).Return(nil, sql.ErrNoRows)
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		_, err := s.JoinLedger(ctx, "user-2", models.JoinLedgerInput{Code: code})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("既にメンバーならコードを消費せずにロールバックする", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("ClaimInvite", ctx, mock.Anything, "user-1", mock.Anything).Return(int32(5), nil)
+		lr.On("AddMember", ctx, int32(5), "user-1", models.LedgerRoleEditor).Return(false, nil)
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		_, err := s.JoinLedger(ctx, "user-1", models.JoinLedgerInput{Code: code})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+		tx.AssertCalled(t, "Rollback")
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("コードが空なら ValidationError", func(t *testing.T) {
+		tm := new(txManagerMock)
+		s := NewLedgerService(new(ledgerRepoMock), tm, time.Hour)
+
+		_, err := s.JoinLedger(ctx, "user-1", models.JoinLedgerInput{Code: " - "})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+		tm.AssertNotCalled(t, "Begin", mock.Anything)
+	})
+}
+
+func TestLedgerService_LeaveLedger(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name       string
+		role       string
+		members    int
+		wantDelete bool
+	}{
+		{name: "他のメンバーが残る場合は家計簿を残す", role: models.LedgerRoleEditor, members: 2, wantDelete: false},
+		{name: "最後のメンバーが退出したら家計簿を削除する", role: models.LedgerRoleOwner, members: 1, wantDelete: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := new(txManagerMock)
+			tx := new(txMock)
+			lr := new(ledgerRepoMock)
+			tm.On("Begin", ctx).Return(tx, nil)
+			lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(tc.role, nil)
+			lr.On("CountMembers", ctx, int32(5)).Return(tc.members, nil)
+			lr.On("RemoveMember", ctx, int32(5), "user-1").Return(true, nil)
+			lr.On("ReassignActiveLedger", ctx, "user-1", int32(5)).Return(nil)
+			if tc.wantDelete {
+				lr.On("DeleteLedger", ctx, int32(5)).Return(nil)
+			}
+			tx.On("Commit").Return(nil)
+
+			s := NewLedgerService(lr, tm, time.Hour)
+			require.NoError(t, s.LeaveLedger(ctx, "user-1", 5))
+
+			lr.AssertExpectations(t)
+			if !tc.wantDelete {
+				lr.AssertNotCalled(t, "DeleteLedger", mock.Anything, mock.Anything)
+			}
+		})
+	}
+
+	t.Run("他のメンバーがいる owner は ForbiddenError", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleOwner, nil)
+		lr.On("CountMembers", ctx, int32(5)).Return(2, nil)
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		var fe *ForbiddenError
+		assert.ErrorAs(t, s.LeaveLedger(ctx, "user-1", 5), &fe)
+		lr.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("メンバーでなければ NotFoundError", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return("", sql.ErrNoRows)
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		var ne *NotFoundError
+		assert.ErrorAs(t, s.LeaveLedger(ctx, "user-1", 5), &ne)
+	})
+
+	t.Run("途中で失敗したらロールバックする", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleEditor, nil)
+		lr.On("CountMembers", ctx, int32(5)).Return(2, nil)
+		lr.On("RemoveMember", ctx, int32(5), "user-1").Return(true, nil)
+		lr.On("ReassignActiveLedger", ctx, "user-1", int32(5)).Return(errors.New("db down"))
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		require.Error(t, s.LeaveLedger(ctx, "user-1", 5))
+		tx.AssertNotCalled(t, "Commit")
+	})
+}
+
+func TestLedgerService_SwitchLedger(t *testing.T) {
+	ctx := context.Background()
+	id := func(v int) *int { return &v }
+
+	t.Run("メンバーである家計簿に切り替える", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetLedgerForMember", ctx, int32(7), "user-1").Return(models.Ledger{ID: 7, Name: "Family"}, nil)
+		lr.On("SetActiveLedger", ctx, "user-1", int32(7)).Return(nil)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		got, err := s.SwitchLedger(ctx, "user-1", models.SwitchLedgerInput{LedgerID: id(7)})
+		require.NoError(t, err)
+		assert.True(t, got.Active)
+	})
+
+	t.Run("メンバーでない家計簿は NotFoundError", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetLedgerForMember", ctx, int32(7), "user-1").Return(nil, sql.ErrNoRows)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		_, err := s.SwitchLedger(ctx, "user-1", models.SwitchLedgerInput{LedgerID: id(7)})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		lr.AssertNotCalled(t, "SetActiveLedger", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ledger_id が無ければ ValidationError", func(t *testing.T) {
+		s := NewLedgerService(new(ledgerRepoMock), new(txManagerMock), time.Hour)
+		_, err := s.SwitchLedger(ctx, "user-1", models.SwitchLedgerInput{})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+}
+
+func TestLedgerService_ListMembers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("メンバーは一覧を取得できる", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "user-2").Return(models.LedgerRoleViewer, nil)
+		lr.On("ListMembers", ctx, int32(5)).Return([]models.LedgerMember{
+			{UserID: "user-1", Role: models.LedgerRoleOwner},
+			{UserID: "user-2", Role: models.LedgerRoleViewer},
+		}, nil)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		got, err := s.ListMembers(ctx, "user-2", 5)
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("メンバーでなければ NotFoundError", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "stranger").Return("", sql.ErrNoRows)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		_, err := s.ListMembers(ctx, "stranger", 5)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		lr.AssertNotCalled(t, "ListMembers", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLedgerService_UpdateMemberRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner は他のメンバーを viewer にできる", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleOwner, nil)
+		lr.On("UpdateMemberRole", ctx, int32(5), "user-2", models.LedgerRoleViewer).
+			Return(models.LedgerMember{UserID: "user-2", Role: models.LedgerRoleViewer}, nil)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		got, err := s.UpdateMemberRole(ctx, "user-1", 5, "user-2", models.UpdateLedgerMemberRoleInput{Role: " Viewer "})
+		require.NoError(t, err)
+		assert.Equal(t, models.LedgerRoleViewer, got.Role)
+		lr.AssertExpectations(t)
+	})
+
+	t.Run("owner 以外は ForbiddenError", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "user-2").Return(models.LedgerRoleEditor, nil)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		_, err := s.UpdateMemberRole(ctx, "user-2", 5, "user-3", models.UpdateLedgerMemberRoleInput{Role: "viewer"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		lr.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("対象がメンバーでなければ NotFoundError", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleOwner, nil)
+		lr.On("UpdateMemberRole", ctx, int32(5), "stranger", models.LedgerRoleEditor).Return(nil, sql.ErrNoRows)
+
+		s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+		_, err := s.UpdateMemberRole(ctx, "user-1", 5, "stranger", models.UpdateLedgerMemberRoleInput{Role: "editor"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+
+	validation := []struct {
+		name     string
+		memberID string
+		role     string
+	}{
+		{name: "owner への変更は譲渡 API を使う", memberID: "user-2", role: "owner"},
+		{name: "未知のロール", memberID: "user-2", role: "admin"},
+		{name: "自分のロールは変更できない", memberID: "user-1", role: "viewer"},
+	}
+	for _, tc := range validation {
+		t.Run(tc.name, func(t *testing.T) {
+			lr := new(ledgerRepoMock)
+			s := NewLedgerService(lr, new(txManagerMock), time.Hour)
+
+			_, err := s.UpdateMemberRole(ctx, "user-1", 5, tc.memberID, models.UpdateLedgerMemberRoleInput{Role: tc.role})
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			lr.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLedgerService_TransferOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner を譲ると自分は editor になる", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		calls := []string{}
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleOwner, nil)
+		lr.On("UpdateMemberRole", ctx, int32(5), "user-2", models.LedgerRoleOwner).
+			Run(func(args mock.Arguments) { calls = append(calls, "promote") }).
+			Return(models.LedgerMember{UserID: "user-2", Role: models.LedgerRoleOwner}, nil)
+		lr.On("UpdateMemberRole", ctx, int32(5), "user-1", models.LedgerRoleEditor).
+			Run(func(args mock.Arguments) { calls = append(calls, "demote") }).
+			Return(models.LedgerMember{UserID: "user-1", Role: models.LedgerRoleEditor}, nil)
+		tx.On("Commit").Run(func(args mock.Arguments) { calls = append(calls, "commit") }).Return(nil)
+		lr.On("GetLedgerForMember", ctx, int32(5), "user-1").Return(models.Ledger{ID: 5, Role: models.LedgerRoleEditor}, nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		got, err := s.TransferOwnership(ctx, "user-1", 5, models.TransferLedgerOwnershipInput{UserID: "user-2"})
+		require.NoError(t, err)
+		assert.Equal(t, models.LedgerRoleEditor, got.Role)
+		assert.Equal(t, []string{"promote", "demote", "commit"}, calls)
+		lr.AssertExpectations(t)
+	})
+
+	t.Run("譲渡先がメンバーでなければロールバックして NotFoundError", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("GetMemberRole", ctx, int32(5), "user-1").Return(models.LedgerRoleOwner, nil)
+		lr.On("UpdateMemberRole", ctx, int32(5), "stranger", models.LedgerRoleOwner).Return(nil, sql.ErrNoRows)
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		_, err := s.TransferOwnership(ctx, "user-1", 5, models.TransferLedgerOwnershipInput{UserID: "stranger"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("owner 以外は ForbiddenError", func(t *testing.T) {
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		lr := new(ledgerRepoMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		lr.On("GetMemberRole", ctx, int32(5), "user-2").Return(models.LedgerRoleEditor, nil)
+		tx.On("Rollback").Return(nil)
+
+		s := NewLedgerService(lr, tm, time.Hour)
+		_, err := s.TransferOwnership(ctx, "user-2", 5, models.TransferLedgerOwnershipInput{UserID: "user-3"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		lr.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("自分自身には譲れない", func(t *testing.T) {
+		tm := new(txManagerMock)
+		s := NewLedgerService(new(ledgerRepoMock), tm, time.Hour)
+
+		_, err := s.TransferOwnership(ctx, "user-1", 5, models.TransferLedgerOwnershipInput{UserID: "user-1"})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+		tm.AssertNotCalled(t, "Begin", mock.Anything)
+	})
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+const (
+	// EmailMaxLen はメールアドレスの最大長
+	EmailMaxLen = 254
+	// PasswordMinLen はパスワードの最小文字数
+	PasswordMinLen = 8
+	// PasswordMaxLen はパスワードの最大文字数
+	PasswordMaxLen = 128
+)
+
+// AccessTokenIssuer はローカル認証のアクセストークンを発行します（auth.LocalTokens）。
+type AccessTokenIssuer interface {
+	IssueAccessToken(userID string) (string, time.Time, error)
+}
+
+// LocalAuthService はセルフホスト向けのメールアドレスとパスワードによる認証です（AUTH_PROVIDER=local）。
+// 発行したアクセストークンは AuthMiddleware で検証され、以降の処理にはユーザー ID だけが渡ります。
+// ChangePassword 以外は呼び出し元が未認証のため、行レベルセキュリティの対象外のコンテキストで呼び出します。
+type LocalAuthService interface {
+	Signup(ctx context.Context, input models.SignupInput) (models.AuthTokens, error)
+	// Login は資格情報が正しくない場合、メールアドレスの登録有無によらず ErrInvalidCredentials を返します。
+	Login(ctx context.Context, input models.LoginInput) (models.AuthTokens, error)
+	// Refresh はリフレッシュトークンを失効させ、新しいトークンを発行します。
+	// 失効済みのトークンが使われた場合は漏洩とみなし、同じログインのトークンをすべて失効させます。
+	Refresh(ctx context.Context, refreshToken string) (models.AuthTokens, error)
+	// Logout はリフレッシュトークンと同じログインのトークンをすべて失効させます。
+	// 不明なトークンでもエラーにしません。
+	Logout(ctx context.Context, refreshToken string) error
+	// ChangePassword はパスワードを変更し、他の端末のログインをすべて失効させます。
+	// 呼び出し元には新しいトークンを返します。
+	ChangePassword(ctx context.Context, userID string, input models.ChangePasswordInput) (models.AuthTokens, error)
+}
+
+type localAuthService struct {
+	accountRepo repositories.LocalAccountRepository
+	tokenRepo   repositories.RefreshTokenRepository
+	userRepo    repositories.UserRepository
+	issuer      AccessTokenIssuer
+	txManager   TxManager
+	refreshTTL  time.Duration
+	now         func() time.Time
+}
+
+func NewLocalAuthService(
+	accountRepo repositories.LocalAccountRepository,
+	tokenRepo repositories.RefreshTokenRepository,
+	userRepo repositories.UserRepository,
+	issuer AccessTokenIssuer,
+	txManager TxManager,
+	refreshTTL time.Duration,
+) LocalAuthService {
+	return &localAuthService{
+		accountRepo: accountRepo,
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		issuer:      issuer,
+		txManager:   txManager,
+		refreshTTL:  refreshTTL,
+		now:         time.Now,
+	}
+}
+
+func (s *localAuthService) Signup(ctx context.Context, input models.SignupInput) (models.AuthTokens, error) {
+	email, err := normalizeEmail(input.Email)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	if err := validatePassword("password", input.Password); err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	hash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	userID, err := auth.NewLocalUserID()
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	if _, err := s.userRepo.ProvisionUser(txCtx, userID); err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	created, err := s.accountRepo.CreateAccount(txCtx, userID, email, hash)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	if !created {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, ErrEmailTaken
+	}
+	tokens, err := s.startSession(txCtx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	return tokens, nil
+}
+
+func (s *localAuthService) Login(ctx context.Context, input models.LoginInput) (models.AuthTokens, error) {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	account, err := s.accountRepo.GetAccountByEmail(txCtx, email)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			auth.SimulatePasswordCheck(input.Password)
+			return models.AuthTokens{}, ErrInvalidCredentials
+		}
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	ok, err := auth.VerifyPassword(input.Password, account.PasswordHash)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	if !ok {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, ErrInvalidCredentials
+	}
+	if err := s.rejectDisabled(txCtx, account.UserID); err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, err
+	}
+	tokens, err := s.startSession(txCtx, account.UserID)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	return tokens, nil
+}
+
+func (s *localAuthService) Refresh(ctx context.Context, refreshToken string) (models.AuthTokens, error) {
+	if refreshToken == "" {
+		return models.AuthTokens{}, ErrInvalidCredentials
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	current, err := s.tokenRepo.GetTokenByHashForUpdate(txCtx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AuthTokens{}, ErrInvalidCredentials
+		}
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	if current.Revoked {
+		// 交換済みのトークンが再び使われた: 正規の利用者と攻撃者のどちらが先かは区別できないため、
+		// 同じログインのトークンをすべて失効させて再ログインを求める
+		if err := s.tokenRepo.RevokeFamily(txCtx, current.FamilyID); err != nil {
+			_ = tx.Rollback()
+			return models.AuthTokens{}, &InternalError{Message: "internal error"}
+		}
+		if err := tx.Commit(); err != nil {
+			return models.AuthTokens{}, &InternalError{Message: "internal error"}
+		}
+		return models.AuthTokens{}, ErrInvalidCredentials
+	}
+	if !s.now().Before(current.ExpiresAt) {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, ErrInvalidCredentials
+	}
+	if err := s.rejectDisabled(txCtx, current.UserID); err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, err
+	}
+
+	if err := s.tokenRepo.RevokeToken(txCtx, int32(current.ID)); err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	tokens, err := s.issueTokens(txCtx, current.UserID, current.FamilyID)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	return tokens, nil
+}
+
+func (s *localAuthService) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	current, err := s.tokenRepo.GetTokenByHashForUpdate(txCtx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return &InternalError{Message: "internal error"}
+	}
+	if err := s.tokenRepo.RevokeFamily(txCtx, current.FamilyID); err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+func (s *localAuthService) ChangePassword(ctx context.Context, userID string, input models.ChangePasswordInput) (models.AuthTokens, error) {
+	if err := validatePassword("new_password", input.NewPassword); err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	account, err := s.accountRepo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AuthTokens{}, &NotFoundError{Message: "local account not found"}
+		}
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	ok, err := auth.VerifyPassword(input.CurrentPassword, account.PasswordHash)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	if !ok {
+		return models.AuthTokens{}, &ForbiddenError{Message: "current password is incorrect"}
+	}
+
+	hash, err := auth.HashPassword(input.NewPassword)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	if err := s.accountRepo.UpdatePassword(txCtx, userID, hash); err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	if err := s.tokenRepo.RevokeTokensByUser(txCtx, userID); err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	tokens, err := s.startSession(txCtx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.AuthTokens{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	return tokens, nil
+}
+
+func (s *localAuthService) rejectDisabled(ctx context.Context, userID string) error {
+	disabled, err := s.userRepo.IsDisabled(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return &InternalError{Message: "internal error"}
+	}
+	if disabled {
+		return &ForbiddenError{Message: "account disabled"}
+	}
+	return nil
+}
+
+// startSession は新しいログインとしてトークンを発行します。
+func (s *localAuthService) startSession(ctx context.Context, userID string) (models.AuthTokens, error) {
+	familyID, err := auth.NewRefreshTokenFamilyID()
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	return s.issueTokens(ctx, userID, familyID)
+}
+
+func (s *localAuthService) issueTokens(ctx context.Context, userID string, familyID string) (models.AuthTokens, error) {
+	raw, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+	refreshExpiresAt := s.now().Add(s.refreshTTL)
+	if err := s.tokenRepo.CreateToken(ctx, userID, familyID, hash, refreshExpiresAt); err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+
+	accessToken, expiresAt, err := s.issuer.IssueAccessToken(userID)
+	if err != nil {
+		return models.AuthTokens{}, &InternalError{Message: "internal error"}
+	}
+
+	return models.AuthTokens{
+		UserID:                userID,
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int(expiresAt.Sub(s.now()).Seconds()),
+		RefreshToken:          raw,
+		RefreshTokenExpiresAt: refreshExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// normalizeEmail はメールアドレスを検証し、照合用に小文字へ揃えます。
+func normalizeEmail(raw string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(raw))
+	if email == "" {
+		return "", &ValidationError{Message: "email must be provided"}
+	}
+	if len(email) > EmailMaxLen {
+		return "", &ValidationError{Message: "email exceeds maximum length"}
+	}
+	// "Name <a@example.com>" のような表示名付きの形式は受け付けない
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", &ValidationError{Message: "email is invalid"}
+	}
+	return email, nil
+}
+
+func validatePassword(field string, password string) error {
+	n := utf8.RuneCountInString(password)
+	if n < PasswordMinLen || n > PasswordMaxLen {
+		return &ValidationError{Message: field + " must be between 8 and 128 characters"}
+	}
+	return nil
+}
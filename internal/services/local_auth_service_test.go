@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+)
+
+type localAccountRepoMock struct{ mock.Mock }
+
+type refreshTokenRepoMock struct{ mock.Mock }
+
+func (m *localAccountRepoMock) CreateAccount(ctx context.Context, userID string, email string, passwordHash string) (bool, error) {
+	args := m.Called(ctx, userID, email, passwordHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *localAccountRepoMock) GetAccountByEmail(ctx context.Context, email string) (models.LocalAccount, error) {
+	args := m.Called(ctx, email)
+	if a, ok := args.Get(0).(models.LocalAccount); ok {
+		return a, args.Error(1)
+	}
+	return models.LocalAccount{}, args.Error(1)
+}
+
+func (m *localAccountRepoMock) GetAccountByUserID(ctx context.Context, userID string) (models.LocalAccount, error) {
+	args := m.Called(ctx, userID)
+	if a, ok := args.Get(0).(models.LocalAccount); ok {
+		return a, args.Error(1)
+	}
+	return models.LocalAccount{}, args.Error(1)
+}
+
+func (m *localAccountRepoMock) UpdatePassword(ctx context.Context, userID string, passwordHash string) error {
+	args := m.Called(ctx, userID, passwordHash)
+	return args.Error(0)
+}
+
+func (m *refreshTokenRepoMock) CreateToken(ctx context.Context, userID string, familyID string, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, familyID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *refreshTokenRepoMock) GetTokenByHashForUpdate(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if t, ok := args.Get(0).(models.RefreshToken); ok {
+		return t, args.Error(1)
+	}
+	return models.RefreshToken{}, args.Error(1)
+}
+
+func (m *refreshTokenRepoMock) RevokeToken(ctx context.Context, id int32) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *refreshTokenRepoMock) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *refreshTokenRepoMock) RevokeTokensByUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type stubIssuer struct{ now time.Time }
+
+func (s stubIssuer) IssueAccessToken(userID string) (string, time.Time, error) {
+	return "access-" + userID, s.now.Add(15 * time.Minute), nil
+}
+
+var localAuthNow = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestLocalAuthService(accounts *localAccountRepoMock, tokens *refreshTokenRepoMock, users *userRepoMock, tm TxManager) *localAuthService {
+	s := NewLocalAuthService(accounts, tokens, users, stubIssuer{now: localAuthNow}, tm, 30*24*time.Hour).(*localAuthService)
+	s.now = func() time.Time { return localAuthNow }
+	return s
+}
+
+func TestLocalAuthService_Signup(t *testing.T) {
+	ctx := context.Background()
+	accounts := new(localAccountRepoMock)
+	tokens := new(refreshTokenRepoMock)
+	users := new(userRepoMock)
+	s := newTestLocalAuthService(accounts, tokens, users, nopTxManager{})
+
+	var userID, storedHash, storedRefreshHash string
+	users.On("ProvisionUser", ctx, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { userID = args.String(1) }).
+		Return(true, nil)
+	accounts.On("CreateAccount", ctx, mock.AnythingOfType("string"), "alice@example.com", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { storedHash = args.String(3) }).
+		Return(true, nil)
+	tokens.On("CreateToken", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), localAuthNow.Add(30*24*time.Hour)).
+		Run(func(args mock.Arguments) { storedRefreshHash = args.String(3) }).
+		Return(nil)
+
+	out, err := s.Signup(ctx, models.SignupInput{Email: " Alice@Example.com ", Password: "correct horse"})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(userID, "local_"))
+	assert.Equal(t, userID, out.UserID)
+	assert.Equal(t, "access-"+userID, out.AccessToken)
+	assert.Equal(t, "Bearer", out.TokenType)
+	assert.Equal(t, 900, out.ExpiresIn)
+	assert.Equal(t, auth.HashRefreshToken(out.RefreshToken), storedRefreshHash)
+
+	// パスワードは平文で保存しない
+	assert.NotContains(t, storedHash, "correct horse")
+	ok, err := auth.VerifyPassword("correct horse", storedHash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	accounts.AssertExpectations(t)
+	tokens.AssertExpectations(t)
+}
+
+func TestLocalAuthService_Signup_Validation(t *testing.T) {
+	cases := []struct {
+		name  string
+		input models.SignupInput
+	}{
+		{name: "メールアドレスなし", input: models.SignupInput{Password: "correct horse"}},
+		{name: "メールアドレスの形式不正", input: models.SignupInput{Email: "alice", Password: "correct horse"}},
+		{name: "表示名付き", input: models.SignupInput{Email: "Alice <alice@example.com>", Password: "correct horse"}},
+		{name: "メールアドレスが長すぎる", input: models.SignupInput{Email: strings.Repeat("a", 250) + "@example.com", Password: "correct horse"}},
+		{name: "パスワードが短い", input: models.SignupInput{Email: "alice@example.com", Password: "short"}},
+		{name: "パスワードが長すぎる", input: models.SignupInput{Email: "alice@example.com", Password: strings.Repeat("a", 129)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestLocalAuthService(new(localAccountRepoMock), new(refreshTokenRepoMock), new(userRepoMock), nopTxManager{})
+			_, err := s.Signup(context.Background(), tc.input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+		})
+	}
+}
+
+func TestLocalAuthService_Signup_EmailTaken(t *testing.T) {
+	ctx := context.Background()
+	accounts := new(localAccountRepoMock)
+	users := new(userRepoMock)
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	tm.On("Begin", ctx).Return(tx, nil)
+	tx.On("Rollback").Return(nil).Once()
+	users.On("ProvisionUser", ctx, mock.AnythingOfType("string")).Return(true, nil)
+	accounts.On("CreateAccount", ctx, mock.AnythingOfType("string"), "alice@example.com", mock.AnythingOfType("string")).Return(false, nil)
+
+	s := newTestLocalAuthService(accounts, new(refreshTokenRepoMock), users, tm)
+	_, err := s.Signup(ctx, models.SignupInput{Email: "alice@example.com", Password: "correct horse"})
+
+	// 作成したユーザーも含めて取り消す
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	tx.AssertExpectations(t)
+	tx.AssertNotCalled(t, "Commit")
+}
+
+func TestLocalAuthService_Login(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	account := models.LocalAccount{UserID: "local_1", Email: "alice@example.com", PasswordHash: hash}
+
+	t.Run("成功", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		tokens := new(refreshTokenRepoMock)
+		users := new(userRepoMock)
+		accounts.On("GetAccountByEmail", ctx, "alice@example.com").Return(account, nil)
+		users.On("IsDisabled", ctx, "local_1").Return(false, nil)
+		tokens.On("CreateToken", ctx, "local_1", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+		s := newTestLocalAuthService(accounts, tokens, users, nopTxManager{})
+		out, err := s.Login(ctx, models.LoginInput{Email: "ALICE@example.com", Password: "correct horse"})
+		require.NoError(t, err)
+		assert.Equal(t, "local_1", out.UserID)
+		assert.NotEmpty(t, out.RefreshToken)
+	})
+
+	t.Run("パスワード違いと未登録は区別しない", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		accounts.On("GetAccountByEmail", ctx, "alice@example.com").Return(account, nil)
+		accounts.On("GetAccountByEmail", ctx, "bob@example.com").Return(nil, sql.ErrNoRows)
+
+		s := newTestLocalAuthService(accounts, new(refreshTokenRepoMock), new(userRepoMock), nopTxManager{})
+		_, err := s.Login(ctx, models.LoginInput{Email: "alice@example.com", Password: "wrong horse"})
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		_, err = s.Login(ctx, models.LoginInput{Email: "bob@example.com", Password: "correct horse"})
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("利用停止中", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		tokens := new(refreshTokenRepoMock)
+		users := new(userRepoMock)
+		accounts.On("GetAccountByEmail", ctx, "alice@example.com").Return(account, nil)
+		users.On("IsDisabled", ctx, "local_1").Return(true, nil)
+
+		s := newTestLocalAuthService(accounts, tokens, users, nopTxManager{})
+		_, err := s.Login(ctx, models.LoginInput{Email: "alice@example.com", Password: "correct horse"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		tokens.AssertNotCalled(t, "CreateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLocalAuthService_Refresh_Rotates(t *testing.T) {
+	ctx := context.Background()
+	tokens := new(refreshTokenRepoMock)
+	users := new(userRepoMock)
+	current := models.RefreshToken{ID: 7, UserID: "local_1", FamilyID: "family-1", ExpiresAt: localAuthNow.Add(time.Hour)}
+	tokens.On("GetTokenByHashForUpdate", ctx, auth.HashRefreshToken("old-token")).Return(current, nil)
+	users.On("IsDisabled", ctx, "local_1").Return(false, nil)
+	tokens.On("RevokeToken", ctx, int32(7)).Return(nil).Once()
+	tokens.On("CreateToken", ctx, "local_1", "family-1", mock.AnythingOfType("string"), localAuthNow.Add(30*24*time.Hour)).Return(nil).Once()
+
+	s := newTestLocalAuthService(new(localAccountRepoMock), tokens, users, nopTxManager{})
+	out, err := s.Refresh(ctx, "old-token")
+	require.NoError(t, err)
+	assert.NotEqual(t, "old-token", out.RefreshToken)
+	assert.Equal(t, "access-local_1", out.AccessToken)
+	tokens.AssertExpectations(t)
+}
+
+func TestLocalAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	tokens := new(refreshTokenRepoMock)
+	tm := new(txManagerMock)
+	tx := new(txMock)
+	tm.On("Begin", ctx).Return(tx, nil)
+	// 失効の記録はエラーを返しても確定させる
+	tx.On("Commit").Return(nil).Once()
+	tokens.On("GetTokenByHashForUpdate", ctx, auth.HashRefreshToken("used-token")).
+		Return(models.RefreshToken{ID: 7, UserID: "local_1", FamilyID: "family-1", ExpiresAt: localAuthNow.Add(time.Hour), Revoked: true}, nil)
+	tokens.On("RevokeFamily", ctx, "family-1").Return(nil).Once()
+
+	s := newTestLocalAuthService(new(localAccountRepoMock), tokens, new(userRepoMock), tm)
+	_, err := s.Refresh(ctx, "used-token")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	tokens.AssertExpectations(t)
+	tx.AssertExpectations(t)
+	tokens.AssertNotCalled(t, "CreateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLocalAuthService_Refresh_Invalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		token models.RefreshToken
+		err   error
+	}{
+		{name: "未知のトークン", err: sql.ErrNoRows},
+		{name: "期限切れ", token: models.RefreshToken{ID: 7, UserID: "local_1", FamilyID: "family-1", ExpiresAt: localAuthNow}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			tokens := new(refreshTokenRepoMock)
+			tokens.On("GetTokenByHashForUpdate", ctx, auth.HashRefreshToken("token")).Return(tc.token, tc.err)
+
+			s := newTestLocalAuthService(new(localAccountRepoMock), tokens, new(userRepoMock), nopTxManager{})
+			_, err := s.Refresh(ctx, "token")
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+			tokens.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLocalAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	tokens := new(refreshTokenRepoMock)
+	tokens.On("GetTokenByHashForUpdate", ctx, auth.HashRefreshToken("token")).
+		Return(models.RefreshToken{ID: 7, UserID: "local_1", FamilyID: "family-1"}, nil)
+	tokens.On("GetTokenByHashForUpdate", ctx, auth.HashRefreshToken("unknown")).Return(nil, sql.ErrNoRows)
+	tokens.On("RevokeFamily", ctx, "family-1").Return(nil).Once()
+
+	s := newTestLocalAuthService(new(localAccountRepoMock), tokens, new(userRepoMock), nopTxManager{})
+	require.NoError(t, s.Logout(ctx, "token"))
+	require.NoError(t, s.Logout(ctx, "unknown"))
+	tokens.AssertExpectations(t)
+}
+
+func TestLocalAuthService_ChangePassword(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	account := models.LocalAccount{UserID: "local_1", Email: "alice@example.com", PasswordHash: hash}
+
+	t.Run("他の端末のログインを失効させる", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		tokens := new(refreshTokenRepoMock)
+		var newHash string
+		accounts.On("GetAccountByUserID", ctx, "local_1").Return(account, nil)
+		accounts.On("UpdatePassword", ctx, "local_1", mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { newHash = args.String(2) }).
+			Return(nil)
+		tokens.On("RevokeTokensByUser", ctx, "local_1").Return(nil).Once()
+		tokens.On("CreateToken", ctx, "local_1", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).Return(nil).Once()
+
+		s := newTestLocalAuthService(accounts, tokens, new(userRepoMock), nopTxManager{})
+		out, err := s.ChangePassword(ctx, "local_1", models.ChangePasswordInput{CurrentPassword: "correct horse", NewPassword: "battery staple"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, out.RefreshToken)
+
+		ok, err := auth.VerifyPassword("battery staple", newHash)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		tokens.AssertExpectations(t)
+	})
+
+	t.Run("現在のパスワード違い", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		accounts.On("GetAccountByUserID", ctx, "local_1").Return(account, nil)
+
+		s := newTestLocalAuthService(accounts, new(refreshTokenRepoMock), new(userRepoMock), nopTxManager{})
+		_, err := s.ChangePassword(ctx, "local_1", models.ChangePasswordInput{CurrentPassword: "wrong horse", NewPassword: "battery staple"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		accounts.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ローカルアカウントが無い", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		accounts.On("GetAccountByUserID", ctx, "firebase-uid").Return(nil, sql.ErrNoRows)
+
+		s := newTestLocalAuthService(accounts, new(refreshTokenRepoMock), new(userRepoMock), nopTxManager{})
+		_, err := s.ChangePassword(ctx, "firebase-uid", models.ChangePasswordInput{CurrentPassword: "correct horse", NewPassword: "battery staple"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+
+	t.Run("リポジトリのエラー", func(t *testing.T) {
+		ctx := context.Background()
+		accounts := new(localAccountRepoMock)
+		accounts.On("GetAccountByUserID", ctx, "local_1").Return(nil, errors.New("db down"))
+
+		s := newTestLocalAuthService(accounts, new(refreshTokenRepoMock), new(userRepoMock), nopTxManager{})
+		_, err := s.ChangePassword(ctx, "local_1", models.ChangePasswordInput{CurrentPassword: "correct horse", NewPassword: "battery staple"})
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+}
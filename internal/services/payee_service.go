@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+const (
+	// PayeeNameMaxLen は支払先の名前の最大文字数です。
+	PayeeNameMaxLen = 100
+	// PayeeListDefaultLimit は補完の候補で limit を省略したときの件数です。
+	PayeeListDefaultLimit = 10
+	// PayeeListMaxLimit は補完の候補を一度に返す最大件数です。
+	PayeeListMaxLimit = 50
+)
+
+// PayeeService はユーザーごとの支払先を扱います。支出への支払先の付け外しは ExpenseService で行います。
+type PayeeService interface {
+	// ListPayees は名前が input.Prefix で始まる呼び出し元の支払先を、よく・最近使ったものから返します。
+	ListPayees(ctx context.Context, userID string, input models.ListPayeesInput) ([]models.PayeeSuggestion, error)
+	// CreatePayee は支払先を作ります。同じ名前の支払先が既にあれば ErrPayeeNameTaken を返します。
+	CreatePayee(ctx context.Context, userID string, input models.PayeeInput) (models.Payee, error)
+	// RenamePayee は支払先の名前を変えます。支払先が付いたすべての支出に反映されます。
+	// 同じ名前の別の支払先があれば ErrPayeeNameTaken を返します（まとめる場合は MergePayee を使います）。
+	RenamePayee(ctx context.Context, userID string, id int, input models.PayeeInput) (models.Payee, error)
+	// DeletePayee は支払先を削除し、付いていたすべての支出から外します。
+	DeletePayee(ctx context.Context, userID string, id int) error
+	// MergePayee は id の支払先が付いたすべての支出の支払先を input.Into にし、id の支払先を削除します。統合先の支払先を返します。
+	MergePayee(ctx context.Context, userID string, id int, input models.MergePayeeInput) (models.Payee, error)
+	// Totals は使用中の家計簿の支出を支払先ごとに集計します。確定済みの合計の大きい順に返します。
+	Totals(ctx context.Context, userID string, input models.PayeeTotalsInput) ([]models.PayeeTotal, error)
+}
+
+type payeeService struct {
+	repo       repositories.PayeeRepository
+	ledgerRepo repositories.LedgerRepository
+	txManager  TxManager
+}
+
+func NewPayeeService(repo repositories.PayeeRepository, ledgerRepo repositories.LedgerRepository, txManager TxManager) PayeeService {
+	return &payeeService{repo: repo, ledgerRepo: ledgerRepo, txManager: txManager}
+}
+
+func (s *payeeService) ListPayees(ctx context.Context, userID string, input models.ListPayeesInput) ([]models.PayeeSuggestion, error) {
+	limit := PayeeListDefaultLimit
+	if input.Limit != nil {
+		if *input.Limit <= 0 || *input.Limit > PayeeListMaxLimit {
+			return nil, &ValidationError{Message: "limit must be between 1 and 50"}
+		}
+		limit = *input.Limit
+	}
+
+	payees, err := s.repo.ListPayeeSuggestions(ctx, userID, strings.TrimSpace(input.Prefix), int32(limit))
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if payees == nil {
+		payees = []models.PayeeSuggestion{}
+	}
+	return payees, nil
+}
+
+func (s *payeeService) CreatePayee(ctx context.Context, userID string, input models.PayeeInput) (models.Payee, error) {
+	name, err := normalizePayeeName(input.Name)
+	if err != nil {
+		return models.Payee{}, err
+	}
+
+	payee, created, err := s.repo.CreatePayee(ctx, userID, name)
+	if err != nil {
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+	if !created {
+		return models.Payee{}, ErrPayeeNameTaken
+	}
+	return payee, nil
+}
+
+func (s *payeeService) RenamePayee(ctx context.Context, userID string, id int, input models.PayeeInput) (models.Payee, error) {
+	name, err := normalizePayeeName(input.Name)
+	if err != nil {
+		return models.Payee{}, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	if _, err := s.ownedPayees(txCtx, userID, id); err != nil {
+		_ = tx.Rollback()
+		return models.Payee{}, err
+	}
+	// 支払先があることは確認済みなので、更新されなければ同じ名前の別の支払先がある
+	payee, err := s.repo.RenamePayee(txCtx, userID, int32(id), name)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Payee{}, ErrPayeeNameTaken
+		}
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+	return payee, nil
+}
+
+func (s *payeeService) DeletePayee(ctx context.Context, userID string, id int) error {
+	deleted, err := s.repo.DeletePayee(ctx, userID, int32(id))
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !deleted {
+		return &NotFoundError{Message: "payee not found"}
+	}
+	return nil
+}
+
+func (s *payeeService) MergePayee(ctx context.Context, userID string, id int, input models.MergePayeeInput) (models.Payee, error) {
+	if input.Into == nil || *input.Into <= 0 {
+		return models.Payee{}, &ValidationError{Message: "into must be greater than 0"}
+	}
+	into := *input.Into
+	if into == id {
+		return models.Payee{}, &ValidationError{Message: "into must be a different payee"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	payees, err := s.ownedPayees(txCtx, userID, id, into)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Payee{}, err
+	}
+	if err := s.repo.MergePayeeLinks(txCtx, int32(id), int32(into)); err != nil {
+		_ = tx.Rollback()
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+	if _, err := s.repo.DeletePayee(txCtx, userID, int32(id)); err != nil {
+		_ = tx.Rollback()
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Payee{}, &InternalError{Message: "internal error"}
+	}
+	return payees[int32(into)], nil
+}
+
+func (s *payeeService) Totals(ctx context.Context, userID string, input models.PayeeTotalsInput) ([]models.PayeeTotal, error) {
+	var filter models.PayeeTotalsFilter
+	var err error
+	if input.From != "" {
+		if filter.From, err = time.Parse("2006-01-02", input.From); err != nil {
+			return nil, &ValidationError{Message: "from must be YYYY-MM-DD"}
+		}
+	}
+	if input.To != "" {
+		to, err := time.Parse("2006-01-02", input.To)
+		if err != nil {
+			return nil, &ValidationError{Message: "to must be YYYY-MM-DD"}
+		}
+		// To の日を含めるため、翌日より前を集計する
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, &ValidationError{Message: "from must not be after to"}
+	}
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return []models.PayeeTotal{}, nil
+		}
+		return nil, err
+	}
+
+	totals, err := s.repo.Totals(ctx, ledgerID, filter)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if totals == nil {
+		totals = []models.PayeeTotal{}
+	}
+	return totals, nil
+}
+
+// ownedPayees は ids がすべて呼び出し元の支払先であることを確認し、ID ごとの支払先を返します。
+func (s *payeeService) ownedPayees(ctx context.Context, userID string, ids ...int) (map[int32]models.Payee, error) {
+	want := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		want = append(want, int32(id))
+	}
+	payees, err := s.repo.ListPayeesByIDs(ctx, userID, want)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	byID := make(map[int32]models.Payee, len(payees))
+	for _, p := range payees {
+		byID[int32(p.ID)] = p
+	}
+	for _, id := range want {
+		if _, ok := byID[id]; !ok {
+			return nil, &NotFoundError{Message: "payee not found"}
+		}
+	}
+	return byID, nil
+}
+
+// normalizePayeeName は前後の空白を取り除いた支払先の名前を検証します。
+func normalizePayeeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", &ValidationError{Message: "name must be provided"}
+	}
+	if utf8.RuneCountInString(name) > PayeeNameMaxLen {
+		return "", &ValidationError{Message: "name exceeds maximum length"}
+	}
+	return name, nil
+}
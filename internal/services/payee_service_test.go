@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type memPayee struct {
+	id     int32
+	userID string
+	name   string
+}
+
+// memPayeeRepo は支払先と支出への支払先の付与をメモリに持つ PayeeRepository です。ゼロ値は空のリポジトリです。
+type memPayeeRepo struct {
+	payees      []memPayee
+	links       map[int32]int32
+	suggestions []models.PayeeSuggestion
+	prefix      string
+	limit       int32
+	totals      []models.PayeeTotal
+	filter      models.PayeeTotalsFilter
+	err         error
+}
+
+func (m *memPayeeRepo) add(userID string, name string) models.Payee {
+	id := int32(len(m.payees) + 1)
+	m.payees = append(m.payees, memPayee{id: id, userID: userID, name: name})
+	return models.Payee{ID: int(id), Name: name}
+}
+
+func (m *memPayeeRepo) find(userID string, id int32) (int, bool) {
+	for i, p := range m.payees {
+		if p.id == id && p.userID == userID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m *memPayeeRepo) ListPayeeSuggestions(ctx context.Context, userID string, prefix string, limit int32) ([]models.PayeeSuggestion, error) {
+	m.prefix = prefix
+	m.limit = limit
+	return m.suggestions, m.err
+}
+
+func (m *memPayeeRepo) ListPayeesByIDs(ctx context.Context, userID string, ids []int32) ([]models.Payee, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []models.Payee
+	for _, p := range m.payees {
+		if p.userID == userID && contains(ids, p.id) {
+			out = append(out, models.Payee{ID: int(p.id), Name: p.name})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (m *memPayeeRepo) CreatePayee(ctx context.Context, userID string, name string) (models.Payee, bool, error) {
+	if m.err != nil {
+		return models.Payee{}, false, m.err
+	}
+	for _, p := range m.payees {
+		if p.userID == userID && p.name == name {
+			return models.Payee{}, false, nil
+		}
+	}
+	return m.add(userID, name), true, nil
+}
+
+func (m *memPayeeRepo) RenamePayee(ctx context.Context, userID string, id int32, name string) (models.Payee, error) {
+	i, ok := m.find(userID, id)
+	if !ok {
+		return models.Payee{}, sql.ErrNoRows
+	}
+	for _, p := range m.payees {
+		if p.userID == userID && p.name == name && p.id != id {
+			return models.Payee{}, sql.ErrNoRows
+		}
+	}
+	m.payees[i].name = name
+	return models.Payee{ID: int(id), Name: name}, nil
+}
+
+func (m *memPayeeRepo) DeletePayee(ctx context.Context, userID string, id int32) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	i, ok := m.find(userID, id)
+	if !ok {
+		return false, nil
+	}
+	m.payees = append(m.payees[:i], m.payees[i+1:]...)
+	for expenseID, payeeID := range m.links {
+		if payeeID == id {
+			delete(m.links, expenseID)
+		}
+	}
+	return true, nil
+}
+
+func (m *memPayeeRepo) MergePayeeLinks(ctx context.Context, sourceID int32, targetID int32) error {
+	if m.err != nil {
+		return m.err
+	}
+	for expenseID, payeeID := range m.links {
+		if payeeID == sourceID {
+			m.links[expenseID] = targetID
+		}
+	}
+	return nil
+}
+
+func (m *memPayeeRepo) ListExpensePayees(ctx context.Context, expenseIDs []int32) (map[int32]models.Payee, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := make(map[int32]models.Payee)
+	for _, expenseID := range expenseIDs {
+		payeeID, ok := m.links[expenseID]
+		if !ok {
+			continue
+		}
+		for _, p := range m.payees {
+			if p.id == payeeID {
+				out[expenseID] = models.Payee{ID: int(p.id), Name: p.name}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memPayeeRepo) SetExpensePayee(ctx context.Context, expenseID int32, payeeID *int32) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.links == nil {
+		m.links = make(map[int32]int32)
+	}
+	if payeeID == nil {
+		delete(m.links, expenseID)
+		return nil
+	}
+	m.links[expenseID] = *payeeID
+	return nil
+}
+
+func (m *memPayeeRepo) Totals(ctx context.Context, ledgerID int32, filter models.PayeeTotalsFilter) ([]models.PayeeTotal, error) {
+	m.filter = filter
+	return m.totals, m.err
+}
+
+func TestPayeeService_ListPayees(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("前後の空白を除いた prefix で探し、limit の既定は 10", func(t *testing.T) {
+		last := "2025-05-01"
+		repo := &memPayeeRepo{suggestions: []models.PayeeSuggestion{{Payee: models.Payee{ID: 1, Name: "コンビニ"}, UseCount: 3, LastUsedAt: &last}}}
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.ListPayees(ctx, "user-1", models.ListPayeesInput{Prefix: " こん "})
+		require.NoError(t, err)
+		assert.Equal(t, repo.suggestions, got)
+		assert.Equal(t, "こん", repo.prefix)
+		assert.Equal(t, int32(PayeeListDefaultLimit), repo.limit)
+	})
+
+	t.Run("候補が無ければ空の一覧", func(t *testing.T) {
+		s := NewPayeeService(&memPayeeRepo{}, activeLedger(1), nopTxManager{})
+
+		got, err := s.ListPayees(ctx, "user-1", models.ListPayeesInput{})
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+
+	for _, limit := range []int{0, PayeeListMaxLimit + 1} {
+		s := NewPayeeService(&memPayeeRepo{}, activeLedger(1), nopTxManager{})
+
+		_, err := s.ListPayees(ctx, "user-1", models.ListPayeesInput{Limit: intPtr(limit)})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve, "limit=%d", limit)
+	}
+}
+
+func TestPayeeService_CreatePayee(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("前後の空白を除いて作成する", func(t *testing.T) {
+		s := NewPayeeService(&memPayeeRepo{}, activeLedger(1), nopTxManager{})
+
+		payee, err := s.CreatePayee(ctx, "user-1", models.PayeeInput{Name: " コンビニ  "})
+		require.NoError(t, err)
+		assert.Equal(t, "コンビニ", payee.Name)
+	})
+
+	t.Run("同じ名前の支払先があれば ErrPayeeNameTaken", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		repo.add("user-1", "コンビニ")
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.CreatePayee(ctx, "user-1", models.PayeeInput{Name: "コンビニ"})
+		assert.ErrorIs(t, err, ErrPayeeNameTaken)
+	})
+
+	t.Run("長すぎる名前", func(t *testing.T) {
+		s := NewPayeeService(&memPayeeRepo{}, activeLedger(1), nopTxManager{})
+
+		_, err := s.CreatePayee(ctx, "user-1", models.PayeeInput{Name: strings.Repeat("あ", PayeeNameMaxLen+1)})
+		var ve *ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "name exceeds maximum length", ve.Message)
+	})
+}
+
+func TestPayeeService_RenamePayee(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("名前を変える", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		payee := repo.add("user-1", "コンビニ")
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.RenamePayee(ctx, "user-1", payee.ID, models.PayeeInput{Name: "セブンイレブン"})
+		require.NoError(t, err)
+		assert.Equal(t, models.Payee{ID: payee.ID, Name: "セブンイレブン"}, got)
+	})
+
+	t.Run("別の支払先と同じ名前には変えられない", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		payee := repo.add("user-1", "コンビニ")
+		repo.add("user-1", "セブンイレブン")
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.RenamePayee(ctx, "user-1", payee.ID, models.PayeeInput{Name: "セブンイレブン"})
+		assert.ErrorIs(t, err, ErrPayeeNameTaken)
+	})
+
+	t.Run("他のユーザーの支払先は NotFoundError", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		payee := repo.add("user-2", "コンビニ")
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.RenamePayee(ctx, "user-1", payee.ID, models.PayeeInput{Name: "セブンイレブン"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		assert.Equal(t, "コンビニ", repo.payees[0].name)
+	})
+}
+
+func TestPayeeService_DeletePayee(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("支出からも外れる", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		payee := repo.add("user-1", "コンビニ")
+		repo.links = map[int32]int32{5: int32(payee.ID)}
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		require.NoError(t, s.DeletePayee(ctx, "user-1", payee.ID))
+		assert.Empty(t, repo.payees)
+		assert.Empty(t, repo.links)
+	})
+
+	t.Run("無ければ NotFoundError", func(t *testing.T) {
+		s := NewPayeeService(&memPayeeRepo{}, activeLedger(1), nopTxManager{})
+
+		err := s.DeletePayee(ctx, "user-1", 9)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+}
+
+func TestPayeeService_MergePayee(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("統合元が付いた支出を統合先に付け替え、統合元を削除する", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		source := repo.add("user-1", "ｾﾌﾞﾝ")
+		target := repo.add("user-1", "セブンイレブン")
+		repo.links = map[int32]int32{1: int32(source.ID), 2: int32(target.ID)}
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.MergePayee(ctx, "user-1", source.ID, models.MergePayeeInput{Into: intPtr(target.ID)})
+		require.NoError(t, err)
+		assert.Equal(t, target, got)
+		assert.Equal(t, map[int32]int32{1: int32(target.ID), 2: int32(target.ID)}, repo.links)
+		assert.Len(t, repo.payees, 1)
+	})
+
+	t.Run("自分自身には統合できない", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		payee := repo.add("user-1", "コンビニ")
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.MergePayee(ctx, "user-1", payee.ID, models.MergePayeeInput{Into: intPtr(payee.ID)})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("他のユーザーの支払先には統合できない", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		source := repo.add("user-1", "コンビニ")
+		other := repo.add("user-2", "コンビニ")
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.MergePayee(ctx, "user-1", source.ID, models.MergePayeeInput{Into: intPtr(other.ID)})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		assert.Len(t, repo.payees, 2)
+	})
+
+	t.Run("失敗したらロールバックする", func(t *testing.T) {
+		repo := &memPayeeRepo{}
+		source := repo.add("user-1", "ｾﾌﾞﾝ")
+		target := repo.add("user-1", "セブンイレブン")
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		s := NewPayeeService(&failingMergePayeeRepo{memPayeeRepo: repo}, activeLedger(1), tm)
+
+		_, err := s.MergePayee(ctx, "user-1", source.ID, models.MergePayeeInput{Into: intPtr(target.ID)})
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+		assert.Len(t, repo.payees, 2)
+	})
+}
+
+type failingMergePayeeRepo struct {
+	*memPayeeRepo
+}
+
+func (m *failingMergePayeeRepo) MergePayeeLinks(ctx context.Context, sourceID int32, targetID int32) error {
+	return errors.New("db down")
+}
+
+func TestPayeeService_Totals(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("to の日を含めて集計する", func(t *testing.T) {
+		repo := &memPayeeRepo{totals: []models.PayeeTotal{{Payee: models.Payee{ID: 1, Name: "コンビニ"}, ExpenseCount: 40, ConfirmedTotal: 32000}}}
+		s := NewPayeeService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.Totals(ctx, "user-1", models.PayeeTotalsInput{From: "2025-01-01", To: "2025-12-31"})
+		require.NoError(t, err)
+		assert.Equal(t, repo.totals, got)
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), repo.filter.From)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), repo.filter.To)
+	})
+
+	t.Run("家計簿が無ければ空の集計", func(t *testing.T) {
+		lr := new(ledgerRepoMock)
+		lr.On("GetActiveLedgerID", mock.Anything, "user-1").Return(nil, sql.ErrNoRows)
+		s := NewPayeeService(&memPayeeRepo{}, lr, nopTxManager{})
+
+		got, err := s.Totals(ctx, "user-1", models.PayeeTotalsInput{})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	cases := []struct {
+		name  string
+		input models.PayeeTotalsInput
+	}{
+		{name: "from の形式が不正", input: models.PayeeTotalsInput{From: "2025-01"}},
+		{name: "to の形式が不正", input: models.PayeeTotalsInput{To: "12/31/2025"}},
+		{name: "from が to より後", input: models.PayeeTotalsInput{From: "2025-06-02", To: "2025-06-01"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewPayeeService(&memPayeeRepo{}, activeLedger(1), nopTxManager{})
+
+			_, err := s.Totals(ctx, "user-1", tc.input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+		})
+	}
+}
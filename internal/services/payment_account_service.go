@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// PaymentAccountNameMaxLen は支払い手段の名前の最大文字数です。
+const PaymentAccountNameMaxLen = 50
+
+// PaymentAccountService はユーザーごとの支払い手段を扱います。支出への支払い手段の付け外しは ExpenseService で行います。
+type PaymentAccountService interface {
+	// ListPaymentAccounts は呼び出し元の支払い手段を残高とともに名前順で返します。
+	ListPaymentAccounts(ctx context.Context, userID string) ([]models.PaymentAccount, error)
+	// CreatePaymentAccount は支払い手段を作ります。同じ名前の支払い手段が既にあれば ErrPaymentAccountNameTaken を返します。
+	CreatePaymentAccount(ctx context.Context, userID string, input models.CreatePaymentAccountInput) (models.PaymentAccount, error)
+	// UpdatePaymentAccount は input で指定した項目だけを変えます。開始残高を変えると残高も同じだけ変わります。
+	UpdatePaymentAccount(ctx context.Context, userID string, id int, input models.UpdatePaymentAccountInput) (models.PaymentAccount, error)
+	// DeletePaymentAccount は支払い手段を削除し、付いていたすべての支出から外します。
+	DeletePaymentAccount(ctx context.Context, userID string, id int) error
+	// ListEntries は支払い手段で払った確定済みの支出を、支出日の順に差し引いた後の残高とともに返します。
+	ListEntries(ctx context.Context, userID string, id int, input models.PaymentAccountEntriesInput) ([]models.PaymentAccountEntry, error)
+}
+
+type paymentAccountService struct {
+	repo       repositories.PaymentAccountRepository
+	ledgerRepo repositories.LedgerRepository
+	txManager  TxManager
+}
+
+func NewPaymentAccountService(repo repositories.PaymentAccountRepository, ledgerRepo repositories.LedgerRepository, txManager TxManager) PaymentAccountService {
+	return &paymentAccountService{repo: repo, ledgerRepo: ledgerRepo, txManager: txManager}
+}
+
+func (s *paymentAccountService) ListPaymentAccounts(ctx context.Context, userID string) ([]models.PaymentAccount, error) {
+	accounts, err := s.repo.ListPaymentAccounts(ctx, userID)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if accounts == nil {
+		accounts = []models.PaymentAccount{}
+	}
+	return accounts, nil
+}
+
+func (s *paymentAccountService) CreatePaymentAccount(ctx context.Context, userID string, input models.CreatePaymentAccountInput) (models.PaymentAccount, error) {
+	account := models.PaymentAccount{Name: input.Name, Type: input.Type}
+	if input.OpeningBalance != nil {
+		account.OpeningBalance = *input.OpeningBalance
+	}
+	account, err := validatePaymentAccount(account)
+	if err != nil {
+		return models.PaymentAccount{}, err
+	}
+
+	id, created, err := s.repo.CreatePaymentAccount(ctx, userID, account)
+	if err != nil {
+		return models.PaymentAccount{}, &InternalError{Message: "internal error"}
+	}
+	if !created {
+		return models.PaymentAccount{}, ErrPaymentAccountNameTaken
+	}
+	account.ID = int(id)
+	// 作ったばかりの支払い手段で払った支出は無い
+	account.Balance = account.OpeningBalance
+	return account, nil
+}
+
+func (s *paymentAccountService) UpdatePaymentAccount(ctx context.Context, userID string, id int, input models.UpdatePaymentAccountInput) (models.PaymentAccount, error) {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.PaymentAccount{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	current, err := s.getPaymentAccount(txCtx, userID, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.PaymentAccount{}, err
+	}
+	account := current
+	if input.Name != nil {
+		account.Name = *input.Name
+	}
+	if input.Type != nil {
+		account.Type = *input.Type
+	}
+	if input.OpeningBalance != nil {
+		account.OpeningBalance = *input.OpeningBalance
+	}
+	account, err = validatePaymentAccount(account)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.PaymentAccount{}, err
+	}
+
+	// 支払い手段があることは確認済みなので、更新されなければ同じ名前の別の支払い手段がある
+	updated, err := s.repo.UpdatePaymentAccount(txCtx, userID, account)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.PaymentAccount{}, &InternalError{Message: "internal error"}
+	}
+	if !updated {
+		_ = tx.Rollback()
+		return models.PaymentAccount{}, ErrPaymentAccountNameTaken
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.PaymentAccount{}, &InternalError{Message: "internal error"}
+	}
+	account.Balance = current.Balance - current.OpeningBalance + account.OpeningBalance
+	return account, nil
+}
+
+func (s *paymentAccountService) DeletePaymentAccount(ctx context.Context, userID string, id int) error {
+	deleted, err := s.repo.DeletePaymentAccount(ctx, userID, int32(id))
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !deleted {
+		return &NotFoundError{Message: "payment account not found"}
+	}
+	return nil
+}
+
+func (s *paymentAccountService) ListEntries(ctx context.Context, userID string, id int, input models.PaymentAccountEntriesInput) ([]models.PaymentAccountEntry, error) {
+	var filter models.PaymentAccountEntriesFilter
+	var err error
+	if input.From != "" {
+		if filter.From, err = time.Parse("2006-01-02", input.From); err != nil {
+			return nil, &ValidationError{Message: "from must be YYYY-MM-DD"}
+		}
+	}
+	if input.To != "" {
+		to, err := time.Parse("2006-01-02", input.To)
+		if err != nil {
+			return nil, &ValidationError{Message: "to must be YYYY-MM-DD"}
+		}
+		// To の日を含めるため、翌日より前を返す
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, &ValidationError{Message: "from must not be after to"}
+	}
+
+	// 明細が無い場合と区別するため、支払い手段があることを先に確認する
+	if _, err := s.getPaymentAccount(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListEntries(ctx, userID, int32(id), filter)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if entries == nil {
+		entries = []models.PaymentAccountEntry{}
+	}
+	return entries, nil
+}
+
+func (s *paymentAccountService) getPaymentAccount(ctx context.Context, userID string, id int) (models.PaymentAccount, error) {
+	account, err := s.repo.GetPaymentAccount(ctx, userID, int32(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PaymentAccount{}, &NotFoundError{Message: "payment account not found"}
+		}
+		return models.PaymentAccount{}, &InternalError{Message: "internal error"}
+	}
+	return account, nil
+}
+
+// validatePaymentAccount は前後の空白を取り除いた名前と、種類・開始残高を検証します。
+func validatePaymentAccount(account models.PaymentAccount) (models.PaymentAccount, error) {
+	account.Name = strings.TrimSpace(account.Name)
+	if account.Name == "" {
+		return models.PaymentAccount{}, &ValidationError{Message: "name must be provided"}
+	}
+	if utf8.RuneCountInString(account.Name) > PaymentAccountNameMaxLen {
+		return models.PaymentAccount{}, &ValidationError{Message: "name exceeds maximum length"}
+	}
+	if !models.IsPaymentAccountType(account.Type) {
+		return models.PaymentAccount{}, &ValidationError{Message: "type must be one of 'cash', 'credit_card', 'e_money', 'bank_account', 'other'"}
+	}
+	// クレジットカードの未払い残高などマイナスの開始残高も受け付ける
+	if account.OpeningBalance > BusinessMaxAmount || account.OpeningBalance < -BusinessMaxAmount {
+		return models.PaymentAccount{}, &ValidationError{Message: "opening_balance exceeds maximum allowed"}
+	}
+	return account, nil
+}
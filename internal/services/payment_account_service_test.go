@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type memPaymentAccount struct {
+	userID  string
+	account models.PaymentAccount
+}
+
+// memPaymentAccountRepo は支払い手段と支出への支払い手段の付与をメモリに持つ PaymentAccountRepository です。
+// ゼロ値は空のリポジトリです。残高は account.Balance から、付いている支出の spent の金額を引いて返します。
+type memPaymentAccountRepo struct {
+	accounts []memPaymentAccount
+	links    map[int32]int32
+	entries  []models.PaymentAccountEntry
+	filter   models.PaymentAccountEntriesFilter
+	err      error
+
+	// spent は支出 ID ごとの金額です。
+	spent map[int32]int
+}
+
+func (m *memPaymentAccountRepo) add(userID string, name string, accountType string) models.PaymentAccount {
+	account := models.PaymentAccount{ID: len(m.accounts) + 1, Name: name, Type: accountType}
+	m.accounts = append(m.accounts, memPaymentAccount{userID: userID, account: account})
+	return account
+}
+
+func (m *memPaymentAccountRepo) find(userID string, id int32) (int, bool) {
+	for i, a := range m.accounts {
+		if a.account.ID == int(id) && a.userID == userID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m *memPaymentAccountRepo) withBalance(account models.PaymentAccount) models.PaymentAccount {
+	for expenseID, accountID := range m.links {
+		if accountID == int32(account.ID) {
+			account.Balance -= m.spent[expenseID]
+		}
+	}
+	return account
+}
+
+func (m *memPaymentAccountRepo) ListPaymentAccounts(ctx context.Context, userID string) ([]models.PaymentAccount, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []models.PaymentAccount
+	for _, a := range m.accounts {
+		if a.userID == userID {
+			out = append(out, m.withBalance(a.account))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (m *memPaymentAccountRepo) GetPaymentAccount(ctx context.Context, userID string, id int32) (models.PaymentAccount, error) {
+	if m.err != nil {
+		return models.PaymentAccount{}, m.err
+	}
+	i, ok := m.find(userID, id)
+	if !ok {
+		return models.PaymentAccount{}, sql.ErrNoRows
+	}
+	return m.withBalance(m.accounts[i].account), nil
+}
+
+func (m *memPaymentAccountRepo) CreatePaymentAccount(ctx context.Context, userID string, account models.PaymentAccount) (int32, bool, error) {
+	if m.err != nil {
+		return 0, false, m.err
+	}
+	for _, a := range m.accounts {
+		if a.userID == userID && a.account.Name == account.Name {
+			return 0, false, nil
+		}
+	}
+	account.ID = len(m.accounts) + 1
+	account.Balance = account.OpeningBalance
+	m.accounts = append(m.accounts, memPaymentAccount{userID: userID, account: account})
+	return int32(account.ID), true, nil
+}
+
+func (m *memPaymentAccountRepo) UpdatePaymentAccount(ctx context.Context, userID string, account models.PaymentAccount) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	i, ok := m.find(userID, int32(account.ID))
+	if !ok {
+		return false, nil
+	}
+	for _, a := range m.accounts {
+		if a.userID == userID && a.account.Name == account.Name && a.account.ID != account.ID {
+			return false, nil
+		}
+	}
+	current := m.accounts[i].account
+	account.Balance = current.Balance - current.OpeningBalance + account.OpeningBalance
+	m.accounts[i].account = account
+	return true, nil
+}
+
+func (m *memPaymentAccountRepo) DeletePaymentAccount(ctx context.Context, userID string, id int32) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	i, ok := m.find(userID, id)
+	if !ok {
+		return false, nil
+	}
+	m.accounts = append(m.accounts[:i], m.accounts[i+1:]...)
+	for expenseID, accountID := range m.links {
+		if accountID == id {
+			delete(m.links, expenseID)
+		}
+	}
+	return true, nil
+}
+
+func (m *memPaymentAccountRepo) ListExpensePaymentAccounts(ctx context.Context, expenseIDs []int32) (map[int32]models.ExpensePaymentAccount, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := make(map[int32]models.ExpensePaymentAccount)
+	for _, expenseID := range expenseIDs {
+		accountID, ok := m.links[expenseID]
+		if !ok {
+			continue
+		}
+		for _, a := range m.accounts {
+			if a.account.ID == int(accountID) {
+				out[expenseID] = models.ExpensePaymentAccount{ID: a.account.ID, Name: a.account.Name, Type: a.account.Type}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memPaymentAccountRepo) SetExpensePaymentAccount(ctx context.Context, expenseID int32, accountID *int32) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.links == nil {
+		m.links = make(map[int32]int32)
+	}
+	if accountID == nil {
+		delete(m.links, expenseID)
+		return nil
+	}
+	m.links[expenseID] = *accountID
+	return nil
+}
+
+func (m *memPaymentAccountRepo) ListEntries(ctx context.Context, userID string, id int32, filter models.PaymentAccountEntriesFilter) ([]models.PaymentAccountEntry, error) {
+	m.filter = filter
+	return m.entries, m.err
+}
+
+func TestPaymentAccountService_CreatePaymentAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("開始残高を残高として返す", func(t *testing.T) {
+		s := NewPaymentAccountService(&memPaymentAccountRepo{}, activeLedger(1), nopTxManager{})
+
+		got, err := s.CreatePaymentAccount(ctx, "user-1", models.CreatePaymentAccountInput{Name: " 財布 ", Type: models.PaymentAccountTypeCash, OpeningBalance: intPtr(5000)})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentAccount{ID: 1, Name: "財布", Type: "cash", OpeningBalance: 5000, Balance: 5000}, got)
+	})
+
+	t.Run("同じ名前の支払い手段があれば ErrPaymentAccountNameTaken", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		repo.add("user-1", "PayPay", models.PaymentAccountTypeEMoney)
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.CreatePaymentAccount(ctx, "user-1", models.CreatePaymentAccountInput{Name: "PayPay", Type: models.PaymentAccountTypeEMoney})
+		assert.ErrorIs(t, err, ErrPaymentAccountNameTaken)
+	})
+
+	cases := []struct {
+		name  string
+		input models.CreatePaymentAccountInput
+	}{
+		{name: "名前が空白だけ", input: models.CreatePaymentAccountInput{Name: "  ", Type: "cash"}},
+		{name: "不明な種類", input: models.CreatePaymentAccountInput{Name: "財布", Type: "bitcoin"}},
+		{name: "開始残高が大きすぎる", input: models.CreatePaymentAccountInput{Name: "口座", Type: "bank_account", OpeningBalance: intPtr(BusinessMaxAmount + 1)}},
+		{name: "開始残高が小さすぎる", input: models.CreatePaymentAccountInput{Name: "カード", Type: "credit_card", OpeningBalance: intPtr(-BusinessMaxAmount - 1)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewPaymentAccountService(&memPaymentAccountRepo{}, activeLedger(1), nopTxManager{})
+
+			_, err := s.CreatePaymentAccount(ctx, "user-1", tc.input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+		})
+	}
+}
+
+func TestPaymentAccountService_UpdatePaymentAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("開始残高を変えると残高も同じだけ変わる", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		repo.accounts = []memPaymentAccount{{userID: "user-1", account: models.PaymentAccount{ID: 1, Name: "財布", Type: "cash", OpeningBalance: 5000, Balance: 2000}}}
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.UpdatePaymentAccount(ctx, "user-1", 1, models.UpdatePaymentAccountInput{OpeningBalance: intPtr(10000)})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentAccount{ID: 1, Name: "財布", Type: "cash", OpeningBalance: 10000, Balance: 7000}, got)
+	})
+
+	t.Run("別の支払い手段と同じ名前には変えられない", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		account := repo.add("user-1", "財布", models.PaymentAccountTypeCash)
+		repo.add("user-1", "PayPay", models.PaymentAccountTypeEMoney)
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		name := "PayPay"
+		_, err := s.UpdatePaymentAccount(ctx, "user-1", account.ID, models.UpdatePaymentAccountInput{Name: &name})
+		assert.ErrorIs(t, err, ErrPaymentAccountNameTaken)
+	})
+
+	t.Run("他のユーザーの支払い手段は NotFoundError", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		account := repo.add("user-2", "財布", models.PaymentAccountTypeCash)
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		accountType := models.PaymentAccountTypeOther
+		_, err := s.UpdatePaymentAccount(ctx, "user-1", account.ID, models.UpdatePaymentAccountInput{Type: &accountType})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		assert.Equal(t, "cash", repo.accounts[0].account.Type)
+	})
+
+	t.Run("検証に失敗したらロールバックする", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		account := repo.add("user-1", "財布", models.PaymentAccountTypeCash)
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		s := NewPaymentAccountService(repo, activeLedger(1), tm)
+
+		accountType := "bitcoin"
+		_, err := s.UpdatePaymentAccount(ctx, "user-1", account.ID, models.UpdatePaymentAccountInput{Type: &accountType})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+	})
+}
+
+func TestPaymentAccountService_DeletePaymentAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("支出からも外れる", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		account := repo.add("user-1", "財布", models.PaymentAccountTypeCash)
+		repo.links = map[int32]int32{5: int32(account.ID)}
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		require.NoError(t, s.DeletePaymentAccount(ctx, "user-1", account.ID))
+		assert.Empty(t, repo.accounts)
+		assert.Empty(t, repo.links)
+	})
+
+	t.Run("無ければ NotFoundError", func(t *testing.T) {
+		s := NewPaymentAccountService(&memPaymentAccountRepo{}, activeLedger(1), nopTxManager{})
+
+		err := s.DeletePaymentAccount(ctx, "user-1", 9)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+}
+
+func TestPaymentAccountService_ListEntries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("to の日を含めて返す", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{entries: []models.PaymentAccountEntry{{ExpenseID: 3, SpentAt: "2025-06-01", Amount: 800, Balance: 4200}}}
+		account := repo.add("user-1", "財布", models.PaymentAccountTypeCash)
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.ListEntries(ctx, "user-1", account.ID, models.PaymentAccountEntriesInput{From: "2025-06-01", To: "2025-06-30"})
+		require.NoError(t, err)
+		assert.Equal(t, repo.entries, got)
+		assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), repo.filter.From)
+		assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), repo.filter.To)
+	})
+
+	t.Run("他のユーザーの支払い手段は NotFoundError", func(t *testing.T) {
+		repo := &memPaymentAccountRepo{}
+		account := repo.add("user-2", "財布", models.PaymentAccountTypeCash)
+		s := NewPaymentAccountService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.ListEntries(ctx, "user-1", account.ID, models.PaymentAccountEntriesInput{})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+
+	t.Run("from が to より後", func(t *testing.T) {
+		s := NewPaymentAccountService(&memPaymentAccountRepo{}, activeLedger(1), nopTxManager{})
+
+		_, err := s.ListEntries(ctx, "user-1", 1, models.PaymentAccountEntriesInput{From: "2025-06-02", To: "2025-06-01"})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+const (
+	// TokenNameMaxLen はトークン名の最大長
+	TokenNameMaxLen = 100
+	// TokenMaxExpiresInDays は有効期限として指定できる最大日数
+	TokenMaxExpiresInDays = 365
+)
+
+type PersonalAccessTokenService interface {
+	CreateToken(ctx context.Context, userID string, input models.CreatePersonalAccessTokenInput) (models.CreatedPersonalAccessToken, error)
+	ListTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error)
+	RevokeToken(ctx context.Context, userID string, id int) error
+	// Authenticate はトークン文字列を検証し、呼び出し元を返します。
+	// 無効・失効・期限切れのトークンには auth.ErrInvalidToken を返します。
+	Authenticate(ctx context.Context, raw string) (auth.Principal, error)
+}
+
+type personalAccessTokenService struct {
+	repo repositories.PersonalAccessTokenRepository
+	now  func() time.Time
+}
+
+func NewPersonalAccessTokenService(repo repositories.PersonalAccessTokenRepository) PersonalAccessTokenService {
+	return &personalAccessTokenService{repo: repo, now: time.Now}
+}
+
+func (s *personalAccessTokenService) CreateToken(ctx context.Context, userID string, input models.CreatePersonalAccessTokenInput) (models.CreatedPersonalAccessToken, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.CreatedPersonalAccessToken{}, &ValidationError{Message: "name must be provided"}
+	}
+	if len(name) > TokenNameMaxLen {
+		return models.CreatedPersonalAccessToken{}, &ValidationError{Message: "name exceeds maximum length"}
+	}
+
+	if len(input.Scopes) == 0 {
+		return models.CreatedPersonalAccessToken{}, &ValidationError{Message: "scopes must be provided"}
+	}
+	scopes := make([]string, 0, len(input.Scopes))
+	seen := make(map[string]bool, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		if !auth.IsGrantableScope(scope) {
+			return models.CreatedPersonalAccessToken{}, &ValidationError{Message: "scope is invalid: " + scope}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	var expiresAt *time.Time
+	if input.ExpiresInDays != nil {
+		days := *input.ExpiresInDays
+		if days <= 0 || days > TokenMaxExpiresInDays {
+			return models.CreatedPersonalAccessToken{}, &ValidationError{Message: "expires_in_days must be between 1 and 365"}
+		}
+		t := s.now().AddDate(0, 0, days)
+		expiresAt = &t
+	}
+
+	raw, hash, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		return models.CreatedPersonalAccessToken{}, &InternalError{Message: "internal error"}
+	}
+
+	token, err := s.repo.CreateToken(ctx, userID, name, hash, scopes, expiresAt)
+	if err != nil {
+		// users への外部キー違反は初期設定前のユーザー
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return models.CreatedPersonalAccessToken{}, &NotFoundError{Message: "user not found"}
+		}
+		return models.CreatedPersonalAccessToken{}, &InternalError{Message: "internal error"}
+	}
+
+	return models.CreatedPersonalAccessToken{PersonalAccessToken: token, Token: raw}, nil
+}
+
+func (s *personalAccessTokenService) ListTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	return s.repo.ListTokensByUser(ctx, userID)
+}
+
+func (s *personalAccessTokenService) RevokeToken(ctx context.Context, userID string, id int) error {
+	revoked, err := s.repo.RevokeToken(ctx, int32(id), userID)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !revoked {
+		return &NotFoundError{Message: "token not found"}
+	}
+	return nil
+}
+
+func (s *personalAccessTokenService) Authenticate(ctx context.Context, raw string) (auth.Principal, error) {
+	if !auth.IsPersonalAccessToken(raw) {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+
+	token, err := s.repo.GetActiveTokenByHash(ctx, auth.HashPersonalAccessToken(raw))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.Principal{}, auth.ErrInvalidToken
+		}
+		return auth.Principal{}, err
+	}
+
+	// 最終利用日時の記録は認証の成否に影響させない
+	_ = s.repo.TouchToken(ctx, int32(token.ID))
+
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return auth.Principal{UserID: token.UserID, Scopes: scopes, TokenID: token.ID}, nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/auth"
+	"money-buddy-backend/internal/models"
+)
+
+type tokenRepoMock struct{ mock.Mock }
+
+func (m *tokenRepoMock) CreateToken(ctx context.Context, userID string, name string, tokenHash string, scopes []string, expiresAt *time.Time) (models.PersonalAccessToken, error) {
+	args := m.Called(ctx, userID, name, tokenHash, scopes, expiresAt)
+	if t, ok := args.Get(0).(models.PersonalAccessToken); ok {
+		return t, args.Error(1)
+	}
+	return models.PersonalAccessToken{}, args.Error(1)
+}
+
+func (m *tokenRepoMock) ListTokensByUser(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	args := m.Called(ctx, userID)
+	if list, ok := args.Get(0).([]models.PersonalAccessToken); ok {
+		return list, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *tokenRepoMock) GetActiveTokenByHash(ctx context.Context, tokenHash string) (models.PersonalAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if t, ok := args.Get(0).(models.PersonalAccessToken); ok {
+		return t, args.Error(1)
+	}
+	return models.PersonalAccessToken{}, args.Error(1)
+}
+
+func (m *tokenRepoMock) RevokeToken(ctx context.Context, id int32, userID string) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *tokenRepoMock) TouchToken(ctx context.Context, id int32) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestPersonalAccessTokenService_CreateToken_StoresOnlyHash(t *testing.T) {
+	ctx := context.Background()
+	repo := new(tokenRepoMock)
+	s := NewPersonalAccessTokenService(repo)
+
+	var storedHash string
+	repo.On("CreateToken", ctx, "user-1", "import", mock.AnythingOfType("string"), []string{"expenses:read", "expenses:write"}, (*time.Time)(nil)).
+		Run(func(args mock.Arguments) { storedHash = args.String(3) }).
+		Return(models.PersonalAccessToken{ID: 1, Name: "import"}, nil)
+
+	out, err := s.CreateToken(ctx, "user-1", models.CreatePersonalAccessTokenInput{
+		Name:   " import ",
+		Scopes: []string{"expenses:read", "expenses:write", "expenses:read"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(out.Token, auth.PersonalAccessTokenPrefix))
+	assert.NotEqual(t, out.Token, storedHash)
+	assert.Equal(t, auth.HashPersonalAccessToken(out.Token), storedHash)
+	repo.AssertExpectations(t)
+}
+
+func TestPersonalAccessTokenService_CreateToken_Validation(t *testing.T) {
+	days := func(v int) *int { return &v }
+
+	cases := []struct {
+		name  string
+		input models.CreatePersonalAccessTokenInput
+	}{
+		{name: "名前が空", input: models.CreatePersonalAccessTokenInput{Name: " ", Scopes: []string{"expenses:read"}}},
+		{name: "名前が長すぎる", input: models.CreatePersonalAccessTokenInput{Name: strings.Repeat("a", 101), Scopes: []string{"expenses:read"}}},
+		{name: "スコープが空", input: models.CreatePersonalAccessTokenInput{Name: "x"}},
+		{name: "未知のスコープ", input: models.CreatePersonalAccessTokenInput{Name: "x", Scopes: []string{"admin"}}},
+		{name: "トークン管理スコープは付与できない", input: models.CreatePersonalAccessTokenInput{Name: "x", Scopes: []string{auth.ScopeTokensManage}}},
+		{name: "有効期限が0日", input: models.CreatePersonalAccessTokenInput{Name: "x", Scopes: []string{"expenses:read"}, ExpiresInDays: days(0)}},
+		{name: "有効期限が上限超過", input: models.CreatePersonalAccessTokenInput{Name: "x", Scopes: []string{"expenses:read"}, ExpiresInDays: days(366)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(tokenRepoMock)
+			s := NewPersonalAccessTokenService(repo)
+
+			_, err := s.CreateToken(context.Background(), "user-1", tc.input)
+
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			repo.AssertNotCalled(t, "CreateToken")
+		})
+	}
+}
+
+func TestPersonalAccessTokenService_RevokeToken_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := new(tokenRepoMock)
+	repo.On("RevokeToken", ctx, int32(9), "user-1").Return(false, nil)
+	s := NewPersonalAccessTokenService(repo)
+
+	err := s.RevokeToken(ctx, "user-1", 9)
+
+	var ne *NotFoundError
+	assert.ErrorAs(t, err, &ne)
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	raw := auth.PersonalAccessTokenPrefix + "abc"
+
+	t.Run("有効なトークンはスコープ付きの呼び出し元を返し、最終利用日時を記録する", func(t *testing.T) {
+		repo := new(tokenRepoMock)
+		repo.On("GetActiveTokenByHash", ctx, auth.HashPersonalAccessToken(raw)).
+			Return(models.PersonalAccessToken{ID: 7, UserID: "user-1", Scopes: []string{"expenses:read"}}, nil)
+		repo.On("TouchToken", ctx, int32(7)).Return(nil)
+		s := NewPersonalAccessTokenService(repo)
+
+		p, err := s.Authenticate(ctx, raw)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", p.UserID)
+		assert.Equal(t, 7, p.TokenID)
+		assert.True(t, p.HasScope("expenses:read"))
+		assert.False(t, p.HasScope("expenses:write"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("失効・期限切れ・未知のトークンは ErrInvalidToken", func(t *testing.T) {
+		repo := new(tokenRepoMock)
+		repo.On("GetActiveTokenByHash", ctx, mock.Anything).Return(nil, sql.ErrNoRows)
+		s := NewPersonalAccessTokenService(repo)
+
+		_, err := s.Authenticate(ctx, raw)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("DB エラーはそのまま返す", func(t *testing.T) {
+		repo := new(tokenRepoMock)
+		repo.On("GetActiveTokenByHash", ctx, mock.Anything).Return(nil, errors.New("db down"))
+		s := NewPersonalAccessTokenService(repo)
+
+		_, err := s.Authenticate(ctx, raw)
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, auth.ErrInvalidToken))
+	})
+}
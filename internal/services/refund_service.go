@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// RefundService は支出の返金（返品・注文のキャンセルなど）を扱います。
+// 家計簿のメンバーなら誰でも参照でき、記録・削除は編集者以上ができます。返金できるのは確定済みの支出だけで、
+// 返金の合計は支出の金額を超えられません。ゴミ箱の支出の返金は扱いません。
+// 返金の記録・取り消しは、支出の返金額の変更として同じトランザクションで操作履歴に残します。
+type RefundService interface {
+	// ListRefunds は支出の返金を返金日の順に返します。
+	ListRefunds(ctx context.Context, userID string, expenseID int) ([]models.ExpenseRefund, error)
+	// CreateRefund は支出の返金を記録します。金額を省略すると、まだ返金されていない残りの全額を返金します。
+	CreateRefund(ctx context.Context, userID string, expenseID int, input models.ExpenseRefundInput) (models.ExpenseRefund, error)
+	// DeleteRefund は返金を取り消します。
+	DeleteRefund(ctx context.Context, userID string, expenseID int, id int) error
+}
+
+type refundService struct {
+	repo        repositories.RefundRepository
+	expenseRepo repositories.ExpenseRepository
+	ledgerRepo  repositories.LedgerRepository
+	auditRepo   repositories.AuditRepository
+	txManager   TxManager
+}
+
+func NewRefundService(repo repositories.RefundRepository, expenseRepo repositories.ExpenseRepository, ledgerRepo repositories.LedgerRepository, auditRepo repositories.AuditRepository, txManager TxManager) RefundService {
+	return &refundService{repo: repo, expenseRepo: expenseRepo, ledgerRepo: ledgerRepo, auditRepo: auditRepo, txManager: txManager}
+}
+
+func (s *refundService) ListRefunds(ctx context.Context, userID string, expenseID int) ([]models.ExpenseRefund, error) {
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getExpense(ctx, ledgerID, expenseID); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.repo.ListRefunds(ctx, ledgerID, int32(expenseID))
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if refunds == nil {
+		refunds = []models.ExpenseRefund{}
+	}
+	return refunds, nil
+}
+
+func (s *refundService) CreateRefund(ctx context.Context, userID string, expenseID int, input models.ExpenseRefundInput) (models.ExpenseRefund, error) {
+	if input.Amount != nil {
+		if *input.Amount <= 0 {
+			return models.ExpenseRefund{}, &ValidationError{Message: "amount must be greater than 0"}
+		}
+		if *input.Amount > BusinessMaxAmount {
+			return models.ExpenseRefund{}, &ValidationError{Message: "amount exceeds maximum allowed"}
+		}
+	}
+	refundedAt, err := time.Parse("2006-01-02", input.RefundedAt)
+	if err != nil {
+		return models.ExpenseRefund{}, &ValidationError{Message: "refunded_at must be YYYY-MM-DD"}
+	}
+	if len(input.Memo) > MemoMaxLen {
+		return models.ExpenseRefund{}, &ValidationError{Message: "memo exceeds maximum length"}
+	}
+
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return models.ExpenseRefund{}, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.ExpenseRefund{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	expense, err := s.getExpense(txCtx, ledgerID, expenseID)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, err
+	}
+	if expense.Status != string(models.StatusConfirmed) {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, &ValidationError{Message: "only confirmed expenses can be refunded"}
+	}
+	if spentAt, err := time.Parse(time.RFC3339, expense.SpentAt); err == nil && refundedAt.Before(spentAt) {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, &ValidationError{Message: "refunded_at must not be before spent_at"}
+	}
+	remaining := expense.Amount - expense.RefundedAmount
+	if remaining <= 0 {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, &ValidationError{Message: "expense has already been fully refunded"}
+	}
+	amount := remaining
+	if input.Amount != nil {
+		amount = *input.Amount
+	}
+	if amount > remaining {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, &ValidationError{Message: fmt.Sprintf("amount exceeds the refundable amount (%d)", remaining)}
+	}
+
+	refund, created, err := s.repo.CreateRefund(txCtx, ledgerID, models.ExpenseRefund{
+		ExpenseID:  expenseID,
+		Amount:     amount,
+		RefundedAt: refundedAt.Format("2006-01-02"),
+		Memo:       input.Memo,
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, &InternalError{Message: "internal error"}
+	}
+	// 読み取ってから記録するまでに他の返金が記録されたか、支出が変更された
+	if !created {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, &ValidationError{Message: "refunds must not exceed the expense amount"}
+	}
+	if err := s.recordExpenseAudit(txCtx, userID, ledgerID, models.AuditActionExpenseRefund, expense); err != nil {
+		_ = tx.Rollback()
+		return models.ExpenseRefund{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.ExpenseRefund{}, &InternalError{Message: "internal error"}
+	}
+	return refund, nil
+}
+
+func (s *refundService) DeleteRefund(ctx context.Context, userID string, expenseID int, id int) error {
+	ledgerID, err := writableLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	expense, err := s.getExpense(txCtx, ledgerID, expenseID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	deleted, err := s.repo.DeleteRefund(txCtx, ledgerID, int32(expenseID), int32(id))
+	if err != nil {
+		_ = tx.Rollback()
+		return &InternalError{Message: "internal error"}
+	}
+	if !deleted {
+		_ = tx.Rollback()
+		return &NotFoundError{Message: "refund not found"}
+	}
+	if err := s.recordExpenseAudit(txCtx, userID, ledgerID, models.AuditActionExpenseRefundDelete, expense); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+// recordExpenseAudit は返金の記録・取り消しの前の支出 before と、読み直した現在の支出の差分を操作履歴に残します。
+func (s *refundService) recordExpenseAudit(ctx context.Context, userID string, ledgerID int32, action string, before models.Expense) error {
+	after, err := s.getExpense(ctx, ledgerID, before.ID)
+	if err != nil {
+		return err
+	}
+	event := expenseAuditEvent(userID, ledgerID, action, before.ID)
+	if err := recordAudit(ctx, s.auditRepo, event, expenseAuditFields(before), expenseAuditFields(after)); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+// getExpense は家計簿にあり、ゴミ箱に無い支出を返します。
+func (s *refundService) getExpense(ctx context.Context, ledgerID int32, expenseID int) (models.Expense, error) {
+	expense, err := s.expenseRepo.GetExpenseByID(ctx, ledgerID, int32(expenseID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Expense{}, &NotFoundError{Message: "expense not found"}
+		}
+		return models.Expense{}, &InternalError{Message: "internal error"}
+	}
+	return expense, nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+// memRefundRepo は支出 1 件の返金を保持し、AddExpenseRefundedAmount と同じ条件で返金の合計を更新します。
+type memRefundRepo struct {
+	expense *mockUpdateRepo
+	refunds []models.ExpenseRefund
+	// reject は読み取った後に他の返金が記録された場合のように、記録を断ります。
+	reject bool
+}
+
+func (m *memRefundRepo) ListRefunds(ctx context.Context, ledgerID int32, expenseID int32) ([]models.ExpenseRefund, error) {
+	var out []models.ExpenseRefund
+	for _, r := range m.refunds {
+		if r.ExpenseID == int(expenseID) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (m *memRefundRepo) CreateRefund(ctx context.Context, ledgerID int32, refund models.ExpenseRefund) (models.ExpenseRefund, bool, error) {
+	e := &m.expense.current
+	if m.reject || e.Status != "confirmed" || e.RefundedAmount+refund.Amount > e.Amount {
+		return models.ExpenseRefund{}, false, nil
+	}
+	e.RefundedAmount += refund.Amount
+	e.Version++
+	refund.ID = len(m.refunds) + 1
+	m.refunds = append(m.refunds, refund)
+	return refund, true, nil
+}
+
+func (m *memRefundRepo) DeleteRefund(ctx context.Context, ledgerID int32, expenseID int32, id int32) (bool, error) {
+	for i, r := range m.refunds {
+		if r.ID == int(id) && r.ExpenseID == int(expenseID) {
+			m.expense.current.RefundedAmount -= r.Amount
+			m.expense.current.Version++
+			m.refunds = append(m.refunds[:i], m.refunds[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestRefundService(ledger *ledgerRepoMock, expense models.Expense) (RefundService, *memRefundRepo, *auditRecorder) {
+	repo := &memRefundRepo{expense: &mockUpdateRepo{current: expense}}
+	audit := &auditRecorder{}
+	return NewRefundService(repo, repo.expense, ledger, audit, nopTxManager{}), repo, audit
+}
+
+func confirmedExpense() models.Expense {
+	return models.Expense{ID: 5, Amount: 3000, SpentAt: "2025-06-01T00:00:00Z", Status: "confirmed", Version: 1}
+}
+
+func TestRefundService_CreateRefund(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("一部を返金する", func(t *testing.T) {
+		s, repo, audit := newTestRefundService(activeLedger(1), confirmedExpense())
+
+		r, err := s.CreateRefund(ctx, "user-1", 5, models.ExpenseRefundInput{Amount: intPtr(1200), RefundedAt: "2025-06-03", Memo: "返品"})
+		require.NoError(t, err)
+		assert.Equal(t, models.ExpenseRefund{ID: 1, ExpenseID: 5, Amount: 1200, RefundedAt: "2025-06-03", Memo: "返品"}, r)
+		assert.Equal(t, 1200, repo.expense.current.RefundedAmount)
+		assert.Equal(t, 2, repo.expense.current.Version)
+
+		require.Len(t, audit.events, 1)
+		assert.Equal(t, models.AuditActionExpenseRefund, audit.events[0].Action)
+		assert.Equal(t, "5", audit.events[0].EntityID)
+		assert.Equal(t, map[string]models.AuditChange{"refunded_amount": {Before: nil, After: 1200}}, audit.events[0].Changes)
+	})
+
+	t.Run("金額を省略すると残りの全額を返金する", func(t *testing.T) {
+		expense := confirmedExpense()
+		expense.RefundedAmount = 1000
+		s, repo, _ := newTestRefundService(activeLedger(1), expense)
+
+		r, err := s.CreateRefund(ctx, "user-1", 5, models.ExpenseRefundInput{RefundedAt: "2025-06-10"})
+		require.NoError(t, err)
+		assert.Equal(t, 2000, r.Amount)
+		assert.Equal(t, 3000, repo.expense.current.RefundedAmount)
+	})
+
+	cases := []struct {
+		name     string
+		expense  func(e *models.Expense)
+		input    models.ExpenseRefundInput
+		wantMsg  string
+		rejected bool
+	}{
+		{name: "返金の合計が支出の金額を超える", expense: func(e *models.Expense) { e.RefundedAmount = 2500 }, input: models.ExpenseRefundInput{Amount: intPtr(600), RefundedAt: "2025-06-03"}, wantMsg: "amount exceeds the refundable amount (500)"},
+		{name: "全額返金済み", expense: func(e *models.Expense) { e.RefundedAmount = 3000 }, input: models.ExpenseRefundInput{RefundedAt: "2025-06-03"}, wantMsg: "expense has already been fully refunded"},
+		{name: "予定の支出", expense: func(e *models.Expense) { e.Status = "planned" }, input: models.ExpenseRefundInput{RefundedAt: "2025-06-03"}, wantMsg: "only confirmed expenses can be refunded"},
+		{name: "支出日より前", input: models.ExpenseRefundInput{RefundedAt: "2025-05-31"}, wantMsg: "refunded_at must not be before spent_at"},
+		{name: "日付の形式", input: models.ExpenseRefundInput{RefundedAt: "2025/06/03"}, wantMsg: "refunded_at must be YYYY-MM-DD"},
+		{name: "金額が 0", input: models.ExpenseRefundInput{Amount: intPtr(0), RefundedAt: "2025-06-03"}, wantMsg: "amount must be greater than 0"},
+		{name: "同時に記録された返金と合わせて超える", input: models.ExpenseRefundInput{Amount: intPtr(3000), RefundedAt: "2025-06-03"}, wantMsg: "refunds must not exceed the expense amount", rejected: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expense := confirmedExpense()
+			if tc.expense != nil {
+				tc.expense(&expense)
+			}
+			s, repo, audit := newTestRefundService(activeLedger(1), expense)
+			repo.reject = tc.rejected
+
+			_, err := s.CreateRefund(ctx, "user-1", 5, tc.input)
+			var ve *ValidationError
+			require.ErrorAs(t, err, &ve)
+			assert.Equal(t, tc.wantMsg, ve.Message)
+			assert.Empty(t, repo.refunds)
+			assert.Equal(t, expense.RefundedAmount, repo.expense.current.RefundedAmount)
+			assert.Empty(t, audit.events)
+		})
+	}
+
+	t.Run("支出が無い", func(t *testing.T) {
+		s, _, _ := newTestRefundService(activeLedger(1), confirmedExpense())
+		s.(*refundService).expenseRepo = &mockUpdateRepo{getErr: sqlErrNoRows()}
+
+		_, err := s.CreateRefund(ctx, "user-1", 9, models.ExpenseRefundInput{RefundedAt: "2025-06-03"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+
+	t.Run("閲覧者は返金を記録できない", func(t *testing.T) {
+		s, repo, _ := newTestRefundService(activeLedgerAs(1, models.LedgerRoleViewer), confirmedExpense())
+
+		_, err := s.CreateRefund(ctx, "user-1", 5, models.ExpenseRefundInput{RefundedAt: "2025-06-03"})
+		var fe *ForbiddenError
+		assert.ErrorAs(t, err, &fe)
+		assert.Empty(t, repo.refunds)
+	})
+}
+
+func TestRefundService_ListAndDelete(t *testing.T) {
+	ctx := context.Background()
+	s, repo, audit := newTestRefundService(activeLedger(1), confirmedExpense())
+
+	refunds, err := s.ListRefunds(ctx, "user-1", 5)
+	require.NoError(t, err)
+	assert.NotNil(t, refunds)
+	assert.Empty(t, refunds)
+
+	_, err = s.CreateRefund(ctx, "user-1", 5, models.ExpenseRefundInput{Amount: intPtr(1000), RefundedAt: "2025-06-02"})
+	require.NoError(t, err)
+	refunds, err = s.ListRefunds(ctx, "user-1", 5)
+	require.NoError(t, err)
+	assert.Len(t, refunds, 1)
+
+	require.NoError(t, s.DeleteRefund(ctx, "user-1", 5, 1))
+	assert.Equal(t, 0, repo.expense.current.RefundedAmount)
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, models.AuditActionExpenseRefundDelete, audit.events[1].Action)
+	assert.Equal(t, map[string]models.AuditChange{"refunded_amount": {Before: 1000, After: nil}}, audit.events[1].Changes)
+
+	err = s.DeleteRefund(ctx, "user-1", 5, 1)
+	var ne *NotFoundError
+	assert.ErrorAs(t, err, &ne)
+	assert.Len(t, audit.events, 2)
+}
+
+func TestUpdateExpense_BelowRefundedAmount(t *testing.T) {
+	expense := confirmedExpense()
+	expense.Category = models.Category{ID: 1}
+	expense.RefundedAmount = 1000
+	repo := &mockUpdateRepo{current: expense}
+	s := NewExpenseService(repo, &mockCategoryRepo{exists: map[int32]bool{1: true}}, &memTagRepo{}, &memPayeeRepo{}, &memPaymentAccountRepo{}, activeLedger(1), &auditRecorder{}, nopTxManager{})
+
+	_, err := s.PatchExpense(context.Background(), "user-1", 5, models.ExpensePatch{Amount: intPtr(999)}, nil)
+	var ve *ValidationError
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, "amount must not be less than the refunded amount", ve.Message)
+	assert.False(t, repo.called)
+
+	_, err = s.PatchExpense(context.Background(), "user-1", 5, models.ExpensePatch{Amount: intPtr(1000)}, nil)
+	require.NoError(t, err)
+}
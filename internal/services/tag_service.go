@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"money-buddy-backend/internal/models"
+	"money-buddy-backend/internal/repositories"
+)
+
+// TagNameMaxLen はタグ名の最大文字数です。
+const TagNameMaxLen = 50
+
+// TagService はユーザーごとのタグを扱います。支出へのタグの付け外しは ExpenseService で行います。
+type TagService interface {
+	// ListTags は呼び出し元のタグを名前順に返します。
+	ListTags(ctx context.Context, userID string) ([]models.Tag, error)
+	// CreateTag はタグを作ります。同じ名前のタグが既にあれば ErrTagNameTaken を返します。
+	CreateTag(ctx context.Context, userID string, input models.TagInput) (models.Tag, error)
+	// RenameTag はタグの名前を変えます。タグが付いたすべての支出に反映されます。
+	// 同じ名前の別のタグがあれば ErrTagNameTaken を返します（まとめる場合は MergeTag を使います）。
+	RenameTag(ctx context.Context, userID string, id int, input models.TagInput) (models.Tag, error)
+	// DeleteTag はタグを削除し、付いていたすべての支出から外します。
+	DeleteTag(ctx context.Context, userID string, id int) error
+	// MergeTag は id のタグが付いたすべての支出に input.Into のタグを付け、id のタグを削除します。統合先のタグを返します。
+	MergeTag(ctx context.Context, userID string, id int, input models.MergeTagInput) (models.Tag, error)
+	// Report は使用中の家計簿の支出を、呼び出し元のタグと月ごとに集計します。月の古い順・タグの名前順に返します。
+	Report(ctx context.Context, userID string, input models.TagReportInput) ([]models.TagMonthlyTotal, error)
+}
+
+type tagService struct {
+	repo       repositories.TagRepository
+	ledgerRepo repositories.LedgerRepository
+	txManager  TxManager
+}
+
+func NewTagService(repo repositories.TagRepository, ledgerRepo repositories.LedgerRepository, txManager TxManager) TagService {
+	return &tagService{repo: repo, ledgerRepo: ledgerRepo, txManager: txManager}
+}
+
+func (s *tagService) ListTags(ctx context.Context, userID string) ([]models.Tag, error) {
+	tags, err := s.repo.ListTags(ctx, userID)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+	return tags, nil
+}
+
+func (s *tagService) CreateTag(ctx context.Context, userID string, input models.TagInput) (models.Tag, error) {
+	name, err := normalizeTagName(input.Name)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	tag, created, err := s.repo.CreateTag(ctx, userID, name)
+	if err != nil {
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+	if !created {
+		return models.Tag{}, ErrTagNameTaken
+	}
+	return tag, nil
+}
+
+func (s *tagService) RenameTag(ctx context.Context, userID string, id int, input models.TagInput) (models.Tag, error) {
+	name, err := normalizeTagName(input.Name)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	if _, err := s.ownedTags(txCtx, userID, id); err != nil {
+		_ = tx.Rollback()
+		return models.Tag{}, err
+	}
+	// タグがあることは確認済みなので、更新されなければ同じ名前の別のタグがある
+	tag, err := s.repo.RenameTag(txCtx, userID, int32(id), name)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Tag{}, ErrTagNameTaken
+		}
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+	return tag, nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, userID string, id int) error {
+	deleted, err := s.repo.DeleteTag(ctx, userID, int32(id))
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !deleted {
+		return &NotFoundError{Message: "tag not found"}
+	}
+	return nil
+}
+
+func (s *tagService) MergeTag(ctx context.Context, userID string, id int, input models.MergeTagInput) (models.Tag, error) {
+	if input.Into == nil || *input.Into <= 0 {
+		return models.Tag{}, &ValidationError{Message: "into must be greater than 0"}
+	}
+	into := *input.Into
+	if into == id {
+		return models.Tag{}, &ValidationError{Message: "into must be a different tag"}
+	}
+
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+	txCtx := tx.Context(ctx)
+
+	tags, err := s.ownedTags(txCtx, userID, id, into)
+	if err != nil {
+		_ = tx.Rollback()
+		return models.Tag{}, err
+	}
+	if err := s.repo.MergeTagLinks(txCtx, int32(id), int32(into)); err != nil {
+		_ = tx.Rollback()
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+	if _, err := s.repo.DeleteTag(txCtx, userID, int32(id)); err != nil {
+		_ = tx.Rollback()
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Tag{}, &InternalError{Message: "internal error"}
+	}
+	return tags[int32(into)], nil
+}
+
+func (s *tagService) Report(ctx context.Context, userID string, input models.TagReportInput) ([]models.TagMonthlyTotal, error) {
+	var filter models.TagReportFilter
+	var err error
+	if input.From != "" {
+		if filter.From, err = time.Parse("2006-01", input.From); err != nil {
+			return nil, &ValidationError{Message: "from must be YYYY-MM"}
+		}
+	}
+	if input.To != "" {
+		to, err := time.Parse("2006-01", input.To)
+		if err != nil {
+			return nil, &ValidationError{Message: "to must be YYYY-MM"}
+		}
+		// To の月を含めるため、翌月の初日より前を集計する
+		filter.To = to.AddDate(0, 1, 0)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, &ValidationError{Message: "from must not be after to"}
+	}
+
+	ledgerID, err := activeLedgerID(ctx, s.ledgerRepo, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveLedger) {
+			return []models.TagMonthlyTotal{}, nil
+		}
+		return nil, err
+	}
+
+	totals, err := s.repo.MonthlyTotals(ctx, userID, ledgerID, filter)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	if totals == nil {
+		totals = []models.TagMonthlyTotal{}
+	}
+	return totals, nil
+}
+
+// ownedTags は ids がすべて呼び出し元のタグであることを確認し、ID ごとのタグを返します。
+func (s *tagService) ownedTags(ctx context.Context, userID string, ids ...int) (map[int32]models.Tag, error) {
+	want := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		want = append(want, int32(id))
+	}
+	tags, err := s.repo.ListTagsByIDs(ctx, userID, want)
+	if err != nil {
+		return nil, &InternalError{Message: "internal error"}
+	}
+	byID := make(map[int32]models.Tag, len(tags))
+	for _, t := range tags {
+		byID[int32(t.ID)] = t
+	}
+	for _, id := range want {
+		if _, ok := byID[id]; !ok {
+			return nil, &NotFoundError{Message: "tag not found"}
+		}
+	}
+	return byID, nil
+}
+
+// normalizeTagName は前後の空白を取り除いたタグ名を検証します。
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", &ValidationError{Message: "name must be provided"}
+	}
+	if utf8.RuneCountInString(name) > TagNameMaxLen {
+		return "", &ValidationError{Message: "name exceeds maximum length"}
+	}
+	return name, nil
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"money-buddy-backend/internal/models"
+)
+
+type memTag struct {
+	id     int32
+	userID string
+	name   string
+}
+
+// memTagRepo はタグと支出へのタグの付与をメモリに持つ TagRepository です。ゼロ値は空のリポジトリです。
+type memTagRepo struct {
+	tags   []memTag
+	links  map[int32][]int32
+	totals []models.TagMonthlyTotal
+	filter models.TagReportFilter
+	err    error
+}
+
+func (m *memTagRepo) add(userID string, name string) models.Tag {
+	id := int32(len(m.tags) + 1)
+	m.tags = append(m.tags, memTag{id: id, userID: userID, name: name})
+	return models.Tag{ID: int(id), Name: name}
+}
+
+func (m *memTagRepo) find(userID string, id int32) (int, bool) {
+	for i, t := range m.tags {
+		if t.id == id && t.userID == userID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m *memTagRepo) ListTags(ctx context.Context, userID string) ([]models.Tag, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []models.Tag
+	for _, t := range m.tags {
+		if t.userID == userID {
+			out = append(out, models.Tag{ID: int(t.id), Name: t.name})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (m *memTagRepo) ListTagsByIDs(ctx context.Context, userID string, ids []int32) ([]models.Tag, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []models.Tag
+	for _, t := range m.tags {
+		for _, id := range ids {
+			if t.id == id && t.userID == userID {
+				out = append(out, models.Tag{ID: int(t.id), Name: t.name})
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (m *memTagRepo) CreateTag(ctx context.Context, userID string, name string) (models.Tag, bool, error) {
+	if m.err != nil {
+		return models.Tag{}, false, m.err
+	}
+	for _, t := range m.tags {
+		if t.userID == userID && t.name == name {
+			return models.Tag{}, false, nil
+		}
+	}
+	return m.add(userID, name), true, nil
+}
+
+func (m *memTagRepo) RenameTag(ctx context.Context, userID string, id int32, name string) (models.Tag, error) {
+	i, ok := m.find(userID, id)
+	if !ok {
+		return models.Tag{}, sql.ErrNoRows
+	}
+	for _, t := range m.tags {
+		if t.userID == userID && t.name == name && t.id != id {
+			return models.Tag{}, sql.ErrNoRows
+		}
+	}
+	m.tags[i].name = name
+	return models.Tag{ID: int(id), Name: name}, nil
+}
+
+func (m *memTagRepo) DeleteTag(ctx context.Context, userID string, id int32) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	i, ok := m.find(userID, id)
+	if !ok {
+		return false, nil
+	}
+	m.tags = append(m.tags[:i], m.tags[i+1:]...)
+	for expenseID, tagIDs := range m.links {
+		m.links[expenseID] = without(tagIDs, id)
+	}
+	return true, nil
+}
+
+func (m *memTagRepo) MergeTagLinks(ctx context.Context, sourceID int32, targetID int32) error {
+	if m.err != nil {
+		return m.err
+	}
+	for expenseID, tagIDs := range m.links {
+		if contains(tagIDs, sourceID) && !contains(tagIDs, targetID) {
+			m.links[expenseID] = append(tagIDs, targetID)
+		}
+	}
+	return nil
+}
+
+func (m *memTagRepo) ListExpenseTags(ctx context.Context, userID string, expenseIDs []int32) (map[int32][]models.Tag, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := make(map[int32][]models.Tag)
+	for _, expenseID := range expenseIDs {
+		for _, tagID := range m.links[expenseID] {
+			if i, ok := m.find(userID, tagID); ok {
+				out[expenseID] = append(out[expenseID], models.Tag{ID: int(tagID), Name: m.tags[i].name})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memTagRepo) SetExpenseTags(ctx context.Context, userID string, expenseID int32, tagIDs []int32) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.links == nil {
+		m.links = make(map[int32][]int32)
+	}
+	// 他のユーザーのタグは残す
+	var kept []int32
+	for _, tagID := range m.links[expenseID] {
+		if _, ok := m.find(userID, tagID); !ok {
+			kept = append(kept, tagID)
+		}
+	}
+	m.links[expenseID] = append(kept, tagIDs...)
+	return nil
+}
+
+func (m *memTagRepo) MonthlyTotals(ctx context.Context, userID string, ledgerID int32, filter models.TagReportFilter) ([]models.TagMonthlyTotal, error) {
+	m.filter = filter
+	return m.totals, m.err
+}
+
+func contains(ids []int32, id int32) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func without(ids []int32, id int32) []int32 {
+	var out []int32
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func TestTagService_CreateTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("前後の空白を除いて作成する", func(t *testing.T) {
+		repo := &memTagRepo{}
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		tag, err := s.CreateTag(ctx, "user-1", models.TagInput{Name: "  大阪旅行 "})
+		require.NoError(t, err)
+		assert.Equal(t, "大阪旅行", tag.Name)
+	})
+
+	t.Run("同じ名前のタグがあれば ErrTagNameTaken", func(t *testing.T) {
+		repo := &memTagRepo{}
+		repo.add("user-1", "大阪旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.CreateTag(ctx, "user-1", models.TagInput{Name: "大阪旅行"})
+		assert.ErrorIs(t, err, ErrTagNameTaken)
+	})
+
+	t.Run("他のユーザーと同じ名前は作れる", func(t *testing.T) {
+		repo := &memTagRepo{}
+		repo.add("user-2", "大阪旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.CreateTag(ctx, "user-1", models.TagInput{Name: "大阪旅行"})
+		assert.NoError(t, err)
+	})
+
+	cases := []struct {
+		name    string
+		input   string
+		wantMsg string
+	}{
+		{name: "空白だけ", input: "   ", wantMsg: "name must be provided"},
+		{name: "長すぎる", input: strings.Repeat("あ", TagNameMaxLen+1), wantMsg: "name exceeds maximum length"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewTagService(&memTagRepo{}, activeLedger(1), nopTxManager{})
+
+			_, err := s.CreateTag(ctx, "user-1", models.TagInput{Name: tc.input})
+			var ve *ValidationError
+			require.ErrorAs(t, err, &ve)
+			assert.Equal(t, tc.wantMsg, ve.Message)
+		})
+	}
+}
+
+func TestTagService_RenameTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("名前を変える", func(t *testing.T) {
+		repo := &memTagRepo{}
+		tag := repo.add("user-1", "旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.RenameTag(ctx, "user-1", tag.ID, models.TagInput{Name: "大阪旅行"})
+		require.NoError(t, err)
+		assert.Equal(t, models.Tag{ID: tag.ID, Name: "大阪旅行"}, got)
+	})
+
+	t.Run("別のタグと同じ名前には変えられない", func(t *testing.T) {
+		repo := &memTagRepo{}
+		tag := repo.add("user-1", "旅行")
+		repo.add("user-1", "大阪旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.RenameTag(ctx, "user-1", tag.ID, models.TagInput{Name: "大阪旅行"})
+		assert.ErrorIs(t, err, ErrTagNameTaken)
+	})
+
+	t.Run("他のユーザーのタグは NotFoundError", func(t *testing.T) {
+		repo := &memTagRepo{}
+		tag := repo.add("user-2", "旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.RenameTag(ctx, "user-1", tag.ID, models.TagInput{Name: "大阪旅行"})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		assert.Equal(t, "旅行", repo.tags[0].name)
+	})
+}
+
+func TestTagService_DeleteTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("支出からも外れる", func(t *testing.T) {
+		repo := &memTagRepo{}
+		tag := repo.add("user-1", "旅行")
+		repo.links = map[int32][]int32{5: {int32(tag.ID)}}
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		require.NoError(t, s.DeleteTag(ctx, "user-1", tag.ID))
+		assert.Empty(t, repo.tags)
+		assert.Empty(t, repo.links[5])
+	})
+
+	t.Run("無ければ NotFoundError", func(t *testing.T) {
+		s := NewTagService(&memTagRepo{}, activeLedger(1), nopTxManager{})
+
+		err := s.DeleteTag(ctx, "user-1", 9)
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+	})
+}
+
+func TestTagService_MergeTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("統合元のタグが付いた支出に統合先のタグを付け、統合元を削除する", func(t *testing.T) {
+		repo := &memTagRepo{}
+		source := repo.add("user-1", "大阪")
+		target := repo.add("user-1", "大阪旅行")
+		repo.links = map[int32][]int32{
+			1: {int32(source.ID)},
+			2: {int32(source.ID), int32(target.ID)},
+			3: {int32(target.ID)},
+		}
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.MergeTag(ctx, "user-1", source.ID, models.MergeTagInput{Into: intPtr(target.ID)})
+		require.NoError(t, err)
+		assert.Equal(t, target, got)
+		assert.Equal(t, []int32{int32(target.ID)}, repo.links[1])
+		assert.Equal(t, []int32{int32(target.ID)}, repo.links[2])
+		assert.Equal(t, []int32{int32(target.ID)}, repo.links[3])
+		tags, _ := repo.ListTags(ctx, "user-1")
+		assert.Equal(t, []models.Tag{target}, tags)
+	})
+
+	t.Run("自分自身には統合できない", func(t *testing.T) {
+		repo := &memTagRepo{}
+		tag := repo.add("user-1", "旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.MergeTag(ctx, "user-1", tag.ID, models.MergeTagInput{Into: intPtr(tag.ID)})
+		var ve *ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("他のユーザーのタグには統合できない", func(t *testing.T) {
+		repo := &memTagRepo{}
+		source := repo.add("user-1", "旅行")
+		other := repo.add("user-2", "旅行")
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		_, err := s.MergeTag(ctx, "user-1", source.ID, models.MergeTagInput{Into: intPtr(other.ID)})
+		var ne *NotFoundError
+		assert.ErrorAs(t, err, &ne)
+		assert.Len(t, repo.tags, 2)
+	})
+
+	t.Run("失敗したらロールバックする", func(t *testing.T) {
+		repo := &memTagRepo{}
+		source := repo.add("user-1", "大阪")
+		target := repo.add("user-1", "大阪旅行")
+		tm := new(txManagerMock)
+		tx := new(txMock)
+		tm.On("Begin", ctx).Return(tx, nil)
+		tx.On("Rollback").Return(nil).Once()
+		failing := &failingMergeTagRepo{memTagRepo: repo}
+		s := NewTagService(failing, activeLedger(1), tm)
+
+		_, err := s.MergeTag(ctx, "user-1", source.ID, models.MergeTagInput{Into: intPtr(target.ID)})
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+		tx.AssertExpectations(t)
+		tx.AssertNotCalled(t, "Commit")
+		assert.Len(t, repo.tags, 2)
+	})
+}
+
+type failingMergeTagRepo struct {
+	*memTagRepo
+}
+
+func (m *failingMergeTagRepo) MergeTagLinks(ctx context.Context, sourceID int32, targetID int32) error {
+	return errors.New("db down")
+}
+
+func TestTagService_Report(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("to の月を含めて集計する", func(t *testing.T) {
+		repo := &memTagRepo{totals: []models.TagMonthlyTotal{{Tag: models.Tag{ID: 1, Name: "大阪旅行"}, Month: "2025-05", ExpenseCount: 2, ConfirmedTotal: 12000}}}
+		s := NewTagService(repo, activeLedger(1), nopTxManager{})
+
+		got, err := s.Report(ctx, "user-1", models.TagReportInput{From: "2025-04", To: "2025-06"})
+		require.NoError(t, err)
+		assert.Equal(t, repo.totals, got)
+		assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), repo.filter.From)
+		assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), repo.filter.To)
+	})
+
+	t.Run("同じ月を指定できる", func(t *testing.T) {
+		s := NewTagService(&memTagRepo{}, activeLedger(1), nopTxManager{})
+
+		got, err := s.Report(ctx, "user-1", models.TagReportInput{From: "2025-05", To: "2025-05"})
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+	})
+
+	cases := []struct {
+		name  string
+		input models.TagReportInput
+	}{
+		{name: "from の形式が不正", input: models.TagReportInput{From: "2025-05-01"}},
+		{name: "to の形式が不正", input: models.TagReportInput{To: "5/2025"}},
+		{name: "from が to より後", input: models.TagReportInput{From: "2025-06", To: "2025-05"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewTagService(&memTagRepo{}, activeLedger(1), nopTxManager{})
+
+			_, err := s.Report(ctx, "user-1", tc.input)
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+		})
+	}
+}
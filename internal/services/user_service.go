@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"money-buddy-backend/internal/models"
 	"money-buddy-backend/internal/repositories"
@@ -9,15 +11,25 @@ import (
 
 type UserService interface {
 	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	// GetCurrentUser は認証済みユーザーをオンボーディングの状態付きで返します。
+	GetCurrentUser(ctx context.Context, userID string) (*models.User, error)
+	// Provision は初回アクセスのユーザーを作成し、個人用の家計簿を使用中にします。
+	// 既に存在するユーザーには何もしません。
+	Provision(ctx context.Context, userID string) error
+	// IsDisabled は運用者がアカウントの利用を停止しているかどうかを返します。
+	// まだ作成されていないユーザーは停止されていないものとして扱います。
+	IsDisabled(ctx context.Context, userID string) (bool, error)
 }
 
 type userService struct {
-	userRepo repositories.UserRepository
+	userRepo   repositories.UserRepository
+	ledgerRepo repositories.LedgerRepository
 }
 
-func NewUserService(userRepo repositories.UserRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, ledgerRepo repositories.LedgerRepository) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:   userRepo,
+		ledgerRepo: ledgerRepo,
 	}
 }
 
@@ -28,3 +40,78 @@ func (s *userService) GetUserByID(ctx context.Context, userID string) (*models.U
 	}
 	return &user, nil
 }
+
+func (s *userService) GetCurrentUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		// 作成直後に退会処理で削除された場合のみ起こりうる
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Message: "user not found"}
+		}
+		return nil, &InternalError{Message: "internal error"}
+	}
+
+	progress, err := s.userRepo.GetOnboardingProgress(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Message: "user not found"}
+		}
+		return nil, &InternalError{Message: "internal error"}
+	}
+	user.Onboarding = buildOnboarding(progress)
+
+	return &user, nil
+}
+
+func (s *userService) Provision(ctx context.Context, userID string) error {
+	created, err := s.userRepo.ProvisionUser(ctx, userID)
+	if err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	if !created {
+		return nil
+	}
+	// 最初のリクエストが支出の登録などでも記録先があるように、作成と同時に個人用の家計簿を用意する
+	if _, err := createLedgerForUser(ctx, s.ledgerRepo, userID, DefaultLedgerName); err != nil {
+		return &InternalError{Message: "internal error"}
+	}
+	return nil
+}
+
+func (s *userService) IsDisabled(ctx context.Context, userID string) (bool, error) {
+	disabled, err := s.userRepo.IsDisabled(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, &InternalError{Message: "internal error"}
+	}
+	return disabled, nil
+}
+
+// buildOnboarding は各ステップの達成状況から OnboardingSteps の順に完了・未完了を振り分けます。
+func buildOnboarding(p models.OnboardingProgress) models.Onboarding {
+	done := map[string]bool{
+		models.OnboardingStepSettings:     p.SetupCompleted,
+		models.OnboardingStepLedger:       p.HasLedger,
+		models.OnboardingStepFixedCosts:   p.HasFixedCosts,
+		models.OnboardingStepFirstExpense: p.HasExpenses,
+	}
+
+	o := models.Onboarding{
+		Status:         models.OnboardingStatusNeedsSetup,
+		StepsCompleted: []string{},
+		StepsRemaining: []string{},
+	}
+	if p.SetupCompleted {
+		o.Status = models.OnboardingStatusCompleted
+	}
+	for _, step := range models.OnboardingSteps {
+		if done[step] {
+			o.StepsCompleted = append(o.StepsCompleted, step)
+		} else {
+			o.StepsRemaining = append(o.StepsRemaining, step)
+		}
+	}
+	return o
+}
@@ -2,17 +2,23 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"money-buddy-backend/internal/models"
 )
 
 type mockUserRepo struct {
-	getUserByIDFunc func(ctx context.Context, id string) (models.User, error)
+	getUserByIDFunc           func(ctx context.Context, id string) (models.User, error)
+	provisionUserFunc         func(ctx context.Context, id string) (bool, error)
+	getOnboardingProgressFunc func(ctx context.Context, id string) (models.OnboardingProgress, error)
+	isDisabledFunc            func(ctx context.Context, id string) (bool, error)
 }
 
 func (m *mockUserRepo) CreateUser(ctx context.Context, id string, income int, savingGoal int) error {
@@ -30,6 +36,51 @@ func (m *mockUserRepo) UpdateUserSettings(ctx context.Context, id string, income
 	return errors.New("not implemented")
 }
 
+func (m *mockUserRepo) ProvisionUser(ctx context.Context, id string) (bool, error) {
+	if m.provisionUserFunc != nil {
+		return m.provisionUserFunc(ctx, id)
+	}
+	return false, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) MarkSetupCompleted(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockUserRepo) GetOnboardingProgress(ctx context.Context, id string) (models.OnboardingProgress, error) {
+	if m.getOnboardingProgressFunc != nil {
+		return m.getOnboardingProgressFunc(ctx, id)
+	}
+	return models.OnboardingProgress{}, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) RequestDeletion(ctx context.Context, id string, scheduledAt time.Time) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) CancelDeletion(ctx context.Context, id string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) ListUsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) LockUserForDeletion(ctx context.Context, id string, now time.Time) (time.Time, error) {
+	return time.Time{}, errors.New("not implemented")
+}
+
+func (m *mockUserRepo) DeleteUser(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockUserRepo) IsDisabled(ctx context.Context, id string) (bool, error) {
+	if m.isDisabledFunc != nil {
+		return m.isDisabledFunc(ctx, id)
+	}
+	return false, errors.New("not implemented")
+}
+
 func TestUserService_GetUserByID_Success(t *testing.T) {
 	expectedUser := models.User{
 		ID:         "test-user",
@@ -46,7 +97,7 @@ func TestUserService_GetUserByID_Success(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 	user, err := service.GetUserByID(context.Background(), "test-user")
 
 	require.NoError(t, err)
@@ -63,7 +114,7 @@ func TestUserService_GetUserByID_NotFound(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 	user, err := service.GetUserByID(context.Background(), "non-existent-user")
 
 	require.Error(t, err)
@@ -78,10 +129,169 @@ func TestUserService_GetUserByID_RepositoryError(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(repo)
+	service := NewUserService(repo, nil)
 	user, err := service.GetUserByID(context.Background(), "test-user")
 
 	require.Error(t, err)
 	require.Nil(t, user)
 	assert.Contains(t, err.Error(), "database connection error")
 }
+
+func TestUserService_Provision(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("初回アクセスならユーザーと個人用の家計簿を作成する", func(t *testing.T) {
+		repo := &mockUserRepo{
+			provisionUserFunc: func(ctx context.Context, id string) (bool, error) {
+				assert.Equal(t, "new-user", id)
+				return true, nil
+			},
+		}
+		lr := new(ledgerRepoMock)
+		lr.On("CreateLedger", ctx, DefaultLedgerName).Return(models.Ledger{ID: 3}, nil)
+		lr.On("AddMember", ctx, int32(3), "new-user", models.LedgerRoleOwner).Return(true, nil)
+		lr.On("SetActiveLedger", ctx, "new-user", int32(3)).Return(nil)
+
+		require.NoError(t, NewUserService(repo, lr).Provision(ctx, "new-user"))
+		lr.AssertExpectations(t)
+	})
+
+	t.Run("既存のユーザーには何もしない", func(t *testing.T) {
+		repo := &mockUserRepo{
+			provisionUserFunc: func(ctx context.Context, id string) (bool, error) { return false, nil },
+		}
+		lr := new(ledgerRepoMock)
+
+		require.NoError(t, NewUserService(repo, lr).Provision(ctx, "test-user"))
+		lr.AssertNotCalled(t, "CreateLedger", mock.Anything, mock.Anything)
+	})
+
+	t.Run("作成に失敗したら InternalError", func(t *testing.T) {
+		repo := &mockUserRepo{
+			provisionUserFunc: func(ctx context.Context, id string) (bool, error) {
+				return false, errors.New("database connection error")
+			},
+		}
+
+		err := NewUserService(repo, new(ledgerRepoMock)).Provision(ctx, "test-user")
+
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+
+	t.Run("家計簿の作成に失敗したら InternalError", func(t *testing.T) {
+		repo := &mockUserRepo{
+			provisionUserFunc: func(ctx context.Context, id string) (bool, error) { return true, nil },
+		}
+		lr := new(ledgerRepoMock)
+		lr.On("CreateLedger", ctx, DefaultLedgerName).Return(models.Ledger{}, errors.New("database connection error"))
+
+		err := NewUserService(repo, lr).Provision(ctx, "new-user")
+
+		var ie *InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+}
+
+func TestUserService_GetCurrentUser_NeedsSetup(t *testing.T) {
+	repo := &mockUserRepo{
+		getUserByIDFunc: func(ctx context.Context, id string) (models.User, error) {
+			return models.User{ID: id}, nil
+		},
+		getOnboardingProgressFunc: func(ctx context.Context, id string) (models.OnboardingProgress, error) {
+			return models.OnboardingProgress{}, nil
+		},
+	}
+
+	user, err := NewUserService(repo, nil).GetCurrentUser(context.Background(), "new-user")
+
+	require.NoError(t, err)
+	assert.Nil(t, user.SetupCompletedAt)
+	assert.Equal(t, models.OnboardingStatusNeedsSetup, user.Onboarding.Status)
+	assert.Empty(t, user.Onboarding.StepsCompleted)
+	assert.Equal(t, models.OnboardingSteps, user.Onboarding.StepsRemaining)
+}
+
+func TestUserService_GetCurrentUser_OnboardingSteps(t *testing.T) {
+	completedAt := "2025-02-01T00:00:00Z"
+	repo := &mockUserRepo{
+		getUserByIDFunc: func(ctx context.Context, id string) (models.User, error) {
+			return models.User{ID: id, Income: 300000, SetupCompletedAt: &completedAt}, nil
+		},
+		getOnboardingProgressFunc: func(ctx context.Context, id string) (models.OnboardingProgress, error) {
+			return models.OnboardingProgress{SetupCompleted: true, HasLedger: true, HasExpenses: true}, nil
+		},
+	}
+
+	user, err := NewUserService(repo, nil).GetCurrentUser(context.Background(), "test-user")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.OnboardingStatusCompleted, user.Onboarding.Status)
+	assert.Equal(t, []string{models.OnboardingStepSettings, models.OnboardingStepLedger, models.OnboardingStepFirstExpense}, user.Onboarding.StepsCompleted)
+	assert.Equal(t, []string{models.OnboardingStepFixedCosts}, user.Onboarding.StepsRemaining)
+}
+
+func TestUserService_GetCurrentUser_Errors(t *testing.T) {
+	cases := []struct {
+		name         string
+		getErr       error
+		wantNotFound bool
+	}{
+		{name: "取得に失敗したら InternalError", getErr: errors.New("database connection error")},
+		{name: "作成直後に削除されていたら NotFoundError", getErr: sql.ErrNoRows, wantNotFound: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockUserRepo{
+				getUserByIDFunc: func(ctx context.Context, id string) (models.User, error) {
+					return models.User{}, tc.getErr
+				},
+			}
+
+			user, err := NewUserService(repo, nil).GetCurrentUser(context.Background(), "test-user")
+
+			require.Nil(t, user)
+			if tc.wantNotFound {
+				var ne *NotFoundError
+				assert.ErrorAs(t, err, &ne)
+			} else {
+				var ie *InternalError
+				assert.ErrorAs(t, err, &ie)
+			}
+		})
+	}
+}
+
+func TestUserService_IsDisabled(t *testing.T) {
+	cases := []struct {
+		name         string
+		repoDisabled bool
+		repoErr      error
+		want         bool
+		wantErr      bool
+	}{
+		{name: "利用停止中", repoDisabled: true, want: true},
+		{name: "利用可能", repoDisabled: false, want: false},
+		{name: "未作成のユーザーは停止されていない", repoErr: sql.ErrNoRows, want: false},
+		{name: "DB エラーは InternalError", repoErr: errors.New("database connection error"), wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockUserRepo{
+				isDisabledFunc: func(ctx context.Context, id string) (bool, error) { return tc.repoDisabled, tc.repoErr },
+			}
+
+			disabled, err := NewUserService(repo, nil).IsDisabled(context.Background(), "test-user")
+
+			if tc.wantErr {
+				var ie *InternalError
+				assert.ErrorAs(t, err, &ie)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, disabled)
+		})
+	}
+}